@@ -1,22 +1,63 @@
 package cmd
 
 import (
+	"os"
+
+	"github.com/lin-snow/ech0/internal/backup"
 	"github.com/lin-snow/ech0/internal/cli"
 	"github.com/spf13/cobra"
 )
 
+// backupPassphraseEnv 是备份口令的环境变量，避免口令出现在命令行历史中
+const backupPassphraseEnv = "ECH0_BACKUP_PASSPHRASE"
+
+var (
+	backupPassphrase  string // 备份使用的口令
+	backupRecipient   string // 备份使用的 age 公钥
+	restoreIdentity   string // 恢复使用的 age 私钥或私钥文件路径
+	restorePassphrase string // 恢复使用的口令
+	restoreDryRun     bool   // 只预检，不执行恢复
+	restoreYes        bool   // 跳过恢复前的确认
+)
+
 // backupCmd 是备份数据的命令
 var backupCmd = &cobra.Command{
 	Use:   "backup",
 	Short: "备份数据",
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.DoBackup()
+		passphrase := backupPassphrase
+		if passphrase == "" && backupRecipient == "" {
+			passphrase = os.Getenv(backupPassphraseEnv)
+		}
+
+		cli.DoBackup(backup.Encryption{
+			Passphrase: passphrase,
+			Recipient:  backupRecipient,
+		})
+	},
+}
+
+// backupListCmd 是列出备份的命令
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出所有备份",
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.DoBackupList()
+	},
+}
+
+// backupKeygenCmd 是生成备份加密密钥的命令
+var backupKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "生成用于加密备份的 age 密钥对",
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.DoBackupKeygen()
 	},
 }
 
 // restoreCmd 是恢复数据的命令
 var restoreCmd = &cobra.Command{
-	Use:   "restore",
+	Use:   "restore <备份文件名或路径>",
 	Short: "恢复数据",
 	Run: func(cmd *cobra.Command, args []string) {
 		// 获取待恢复的备份文件路径
@@ -25,12 +66,41 @@ var restoreCmd = &cobra.Command{
 			return
 		}
 
-		cli.DoRestore(args[0])
+		passphrase := restorePassphrase
+		if passphrase == "" {
+			passphrase = os.Getenv(backupPassphraseEnv)
+		}
+
+		decryption := backup.Decryption{
+			Passphrase: passphrase,
+			Identity:   restoreIdentity,
+		}
+		if restoreDryRun {
+			cli.DoRestorePreview(args[0], decryption)
+			return
+		}
+
+		cli.DoRestore(args[0], decryption, restoreYes)
 	},
 }
 
 // init 函数用于初始化根命令和子命令
 func init() {
+	backupCmd.Flags().
+		StringVar(&backupPassphrase, "passphrase", "", "使用口令加密备份（也可通过 "+backupPassphraseEnv+" 环境变量设置）")
+	backupCmd.Flags().
+		StringVar(&backupRecipient, "recipient", "", "使用 age 公钥（age1...）加密备份")
+	restoreCmd.Flags().
+		StringVar(&restorePassphrase, "passphrase", "", "加密备份的口令（也可通过 "+backupPassphraseEnv+" 环境变量设置）")
+	restoreCmd.Flags().
+		StringVar(&restoreIdentity, "identity", "", "加密备份的 age 私钥或私钥文件路径")
+	restoreCmd.Flags().
+		BoolVar(&restoreDryRun, "dry-run", false, "只预检备份并对比当前数据，不执行恢复")
+	restoreCmd.Flags().
+		BoolVarP(&restoreYes, "yes", "y", false, "预检后不再确认，直接执行恢复")
+
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupKeygenCmd)
 	rootCmd.AddCommand(backupCmd)
 	rootCmd.AddCommand(restoreCmd)
 }
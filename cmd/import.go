@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/lin-snow/ech0/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var importSource string // 导入的数据来源，为空时自动识别
+
+// importCmd 是从其他平台导入数据的命令
+var importCmd = &cobra.Command{
+	Use:   "import <文件路径>",
+	Short: "从 Ech0 导出包或 Memos、Mastodon、Twitter/X 的归档导入数据",
+	Run: func(cmd *cobra.Command, args []string) {
+		// 获取待导入的文件路径
+		if len(args) < 1 {
+			_ = cmd.Help()
+			return
+		}
+
+		cli.DoImport(args[0], importSource)
+	},
+}
+
+// init 函数用于初始化根命令和子命令
+func init() {
+	importCmd.Flags().
+		StringVar(&importSource, "source", "", "数据来源：ech0、memos、mastodon 或 twitter，为空时自动识别")
+
+	rootCmd.AddCommand(importCmd)
+}
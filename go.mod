@@ -3,6 +3,7 @@ module github.com/lin-snow/ech0
 go 1.26.0
 
 require (
+	filippo.io/age v1.2.1
 	github.com/SherClockHolmes/webpush-go v1.4.0
 	github.com/chai2010/webp v1.4.0
 	github.com/charmbracelet/bubbles v1.0.0
@@ -47,6 +48,7 @@ require (
 	golang.org/x/text v0.34.0
 	google.golang.org/genai v1.48.0
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/driver/sqlite v1.6.0
 	gorm.io/gorm v1.31.1
 )
@@ -214,5 +216,4 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20260226221140-a57be14db171 // indirect
 	google.golang.org/grpc v1.79.1 // indirect
 	google.golang.org/protobuf v1.36.11 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,178 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	settingModel "github.com/lin-snow/ech0/internal/model/setting"
+)
+
+// ArchiveInfo 备份压缩包信息
+type ArchiveInfo struct {
+	Name      string    `json:"name"`       // 文件名
+	Size      int64     `json:"size"`       // 文件大小（字节）
+	CreatedAt time.Time `json:"created_at"` // 备份时间
+	Encrypted bool      `json:"encrypted"`  // 是否已加密
+}
+
+// ListBackups 列出备份目录中的所有备份，按时间从新到旧排序
+func ListBackups() ([]ArchiveInfo, error) {
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []ArchiveInfo{}, nil
+		}
+		return nil, fmt.Errorf("读取备份目录失败: %w", err)
+	}
+
+	archives := make([]ArchiveInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		createdAt, ok := parseBackupTime(entry.Name())
+		if !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		archives = append(archives, ArchiveInfo{
+			Name:      entry.Name(),
+			Size:      info.Size(),
+			CreatedAt: createdAt,
+			Encrypted: strings.HasSuffix(entry.Name(), encryptedSuffix),
+		})
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].CreatedAt.After(archives[j].CreatedAt)
+	})
+
+	return archives, nil
+}
+
+// ResolveBackup 根据备份文件名返回其路径，只允许访问备份目录中的备份文件
+func ResolveBackup(name string) (string, error) {
+	if name != filepath.Base(name) {
+		return "", errors.New(commonModel.INVALID_BACKUP_NAME)
+	}
+	if _, ok := parseBackupTime(name); !ok {
+		return "", errors.New(commonModel.INVALID_BACKUP_NAME)
+	}
+
+	path := filepath.Join(backupDir, name)
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return "", errors.New(commonModel.BACKUP_NOT_FOUND)
+	}
+
+	return path, nil
+}
+
+// DeleteBackup 删除指定的备份文件
+func DeleteBackup(name string) error {
+	path, err := ResolveBackup(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// ApplyRetention 按保留策略清理旧备份，返回被删除的备份文件名
+func ApplyRetention(policy settingModel.BackupRetention) ([]string, error) {
+	if policy.IsZero() {
+		policy = settingModel.DefaultBackupRetention()
+	}
+
+	archives, err := ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	keep := retainedBackups(archives, policy)
+
+	var deleted []string
+	for _, archive := range archives {
+		if keep[archive.Name] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(backupDir, archive.Name)); err != nil {
+			return deleted, fmt.Errorf("删除旧备份失败: %w", err)
+		}
+		deleted = append(deleted, archive.Name)
+	}
+
+	return deleted, nil
+}
+
+// retainedBackups 计算需要保留的备份，archives 需按时间从新到旧排序
+func retainedBackups(
+	archives []ArchiveInfo,
+	policy settingModel.BackupRetention,
+) map[string]bool {
+	keep := make(map[string]bool, len(archives))
+
+	// 最新的一份始终保留
+	if len(archives) > 0 {
+		keep[archives[0].Name] = true
+	}
+
+	for i, archive := range archives {
+		if i < policy.KeepLast {
+			keep[archive.Name] = true
+		}
+	}
+
+	// 每个时间段保留最新的一份，最多保留 limit 个时间段
+	keepPeriods := func(limit int, period func(t time.Time) string) {
+		seen := make(map[string]bool)
+		for _, archive := range archives {
+			if len(seen) >= limit {
+				return
+			}
+			key := period(archive.CreatedAt.Local())
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			keep[archive.Name] = true
+		}
+	}
+
+	keepPeriods(policy.KeepDaily, func(t time.Time) string {
+		return t.Format("2006-01-02")
+	})
+	keepPeriods(policy.KeepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	})
+	keepPeriods(policy.KeepMonthly, func(t time.Time) string {
+		return t.Format("2006-01")
+	})
+
+	return keep
+}
+
+// parseBackupTime 从备份文件名中解析备份时间
+func parseBackupTime(name string) (time.Time, bool) {
+	prefix := backupFileName + "_"
+	// 加密备份的文件名为 .zip.age
+	stamp := strings.TrimSuffix(name, encryptedSuffix)
+	if !strings.HasPrefix(stamp, prefix) || !strings.HasSuffix(stamp, ".zip") {
+		return time.Time{}, false
+	}
+
+	stamp = strings.TrimSuffix(strings.TrimPrefix(stamp, prefix), ".zip")
+	createdAt, err := time.ParseInLocation(timeLayout, stamp, time.UTC)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return createdAt, true
+}
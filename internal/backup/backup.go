@@ -1,12 +1,13 @@
 package backup
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/lin-snow/ech0/internal/config"
 	"github.com/lin-snow/ech0/internal/database"
 	fileUtil "github.com/lin-snow/ech0/internal/util/file"
 	logUtil "github.com/lin-snow/ech0/internal/util/log"
@@ -22,100 +23,78 @@ const (
 )
 
 // ExecuteBackup 执行备份
-func ExecuteBackup() (string, string, error) {
+// 数据库先通过 VACUUM INTO 导出为一致性快照，再与数据目录一并打包，并附带校验清单
+// 启用加密时输出 age 格式的 .zip.age 文件
+func ExecuteBackup(encryption Encryption) (string, string, error) {
 	backupTime := time.Now().UTC().Format(timeLayout)
-	backupFileName := fmt.Sprintf("%s_%s.zip", backupFileName, backupTime) // 暂时不开启多备份，每次只保留最新的一份备份
+	backupFileName := fmt.Sprintf("%s_%s.zip", backupFileName, backupTime) // 旧备份由保留策略清理，见 ApplyRetention
 	backupPath := fmt.Sprintf("%s/%s", backupDir, backupFileName)
 
-	return backupPath, backupFileName, fileUtil.ZipDirectoryWithOptions(
-		dataDir,
-		backupPath,
-		fileUtil.ZipOptions{
-			ExcludePatterns: []string{excludeFile},
-		},
-	)
-}
-
-// ExecuteRestore 执行恢复
-func ExecuteRestore(backupFilePath string) error {
-	// 检查备份文件是否存在
-	if !fileUtil.FileExists(backupFilePath) {
-		return errors.New("备份文件不存在: " + backupFilePath)
-	}
-
-	previousLock := database.IsWriteLocked()
-	if !previousLock {
-		database.EnableWriteLock()
-		defer database.DisableWriteLock()
-	}
-
-	logUtil.CloseLogger()
-	defer logUtil.ReopenLogger()
-
-	// 解压备份文件到数据目录
-	if err := fileUtil.UnzipFile(backupFilePath, dataDir); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// ExcuteRestoreOnline 在线恢复备份
-func ExcuteRestoreOnline(filePath string, timeStamp int64) error {
-	// 检查备份文件是否存在
-	if !fileUtil.FileExists(filePath) {
-		return errors.New("备份文件不存在: " + filePath)
-	}
-
-	// 启用写锁，阻止新的写操作
-	previousLock := database.IsWriteLocked()
-	if !previousLock {
-		database.EnableWriteLock()
-		defer database.DisableWriteLock()
+	// 导出数据库快照到临时目录
+	snapshotDir := filepath.Join("temp", "backup_"+backupTime)
+	if err := os.MkdirAll(snapshotDir, 0o755); err != nil {
+		return backupPath, backupFileName, err
 	}
-
-	// 关闭 Logger，释放文件句柄
-	logUtil.CloseLogger()
-	defer logUtil.ReopenLogger()
-
-	// 解压备份文件到数据目录 （./temp/snapshot_时间戳）
-	extractPath := fmt.Sprintf("temp/snapshot_%d", timeStamp)
 	defer func() {
-		if err := os.RemoveAll(extractPath); err != nil {
-			logUtil.GetLogger().
-				Warn("Failed to cleanup extracted snapshot temp directory",
-					zap.String("path", extractPath),
-					zap.String("error", err.Error()))
-		}
-		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		if err := os.RemoveAll(snapshotDir); err != nil {
 			logUtil.GetLogger().
-				Warn("Failed to cleanup uploaded snapshot zip",
-					zap.String("path", filePath),
+				Warn("Failed to cleanup database snapshot temp directory",
+					zap.String("path", snapshotDir),
 					zap.String("error", err.Error()))
 		}
 	}()
 
-	if err := fileUtil.UnzipFile(filePath, extractPath); err != nil {
-		return err
+	dbName := filepath.Base(config.Config.Database.Path)
+	snapshotPath := filepath.Join(snapshotDir, dbName)
+	if err := database.SnapshotDatabase(snapshotPath); err != nil {
+		return backupPath, backupFileName, fmt.Errorf("导出数据库快照失败: %w", err)
 	}
 
-	tempDbPath := filepath.Join(extractPath, "ech0.db")
+	snapshotInfo, err := database.InspectDatabase(snapshotPath)
+	if err != nil {
+		return backupPath, backupFileName, fmt.Errorf("读取数据库快照失败: %w", err)
+	}
 
-	// 热切换到临时数据库
-	if err := database.HotChangeDatabase(tempDbPath); err != nil {
-		return err
+	// 快照在压缩包中的路径与数据库文件相对数据目录的路径保持一致
+	dbEntry := dbName
+	if relPath, err := filepath.Rel(dataDir, config.Config.Database.Path); err == nil &&
+		!strings.HasPrefix(relPath, "..") {
+		dbEntry = filepath.ToSlash(relPath)
 	}
 
-	// 复制备份覆盖到正式数据目录
-	dataPath := "data"
-	if err := fileUtil.CopyDirectory(extractPath, dataPath); err != nil {
-		return err
+	manifest := newManifest(dbEntry, snapshotInfo)
+
+	// 先写入临时文件，完成后再重命名，避免中断的备份被当作有效备份
+	tempBackupPath := backupPath + ".tmp"
+	if err := fileUtil.ZipDirectoryWithOptions(
+		dataDir,
+		tempBackupPath,
+		fileUtil.ZipOptions{
+			// 跳过正在使用的数据库文件及其 WAL/SHM，改用快照
+			ExcludePatterns: []string{excludeFile, dbName + "-wal", dbName + "-shm", dbName + "-journal"},
+			ExtraFiles:      map[string]string{dbEntry: snapshotPath},
+			FileCallback:    manifest.addFile,
+			Finalize:        manifest.writeTo,
+		},
+	); err != nil {
+		_ = os.Remove(tempBackupPath)
+		return backupPath, backupFileName, err
 	}
 
-	// 热切换回正式数据库
-	if err := database.HotChangeDatabase("data/ech0.db"); err != nil {
-		return err
+	if !encryption.Enabled() {
+		return backupPath, backupFileName, os.Rename(tempBackupPath, backupPath)
+	}
+
+	// 加密后删除明文压缩包，备份目录中只保留密文
+	defer func() {
+		_ = os.Remove(tempBackupPath)
+	}()
+	backupPath += encryptedSuffix
+	backupFileName += encryptedSuffix
+	tempEncryptedPath := backupPath + ".tmp"
+	if err := encryptFile(tempBackupPath, tempEncryptedPath, encryption); err != nil {
+		return backupPath, backupFileName, err
 	}
 
-	return nil
+	return backupPath, backupFileName, os.Rename(tempEncryptedPath, backupPath)
 }
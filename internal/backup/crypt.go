@@ -0,0 +1,209 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	settingModel "github.com/lin-snow/ech0/internal/model/setting"
+)
+
+const (
+	// encryptedSuffix 加密备份的文件后缀，文件为 age 格式，可用 age 命令行工具解密
+	encryptedSuffix = ".age"
+	// ageVersionLine age 加密文件的首行
+	ageVersionLine = "age-encryption.org/v1\n"
+)
+
+// Encryption 备份加密参数，均为空时不加密
+type Encryption struct {
+	Passphrase string // 加密口令
+	Recipient  string // age 公钥（age1...）
+}
+
+// Decryption 备份解密参数，可同时提供口令与私钥，任一匹配即可解密
+type Decryption struct {
+	Passphrase string // 解密口令
+	Identity   string // age 私钥（AGE-SECRET-KEY-1...），允许包含 age-keygen 输出的注释行
+}
+
+// EncryptionFromSetting 根据备份加密设置生成加密参数
+func EncryptionFromSetting(setting settingModel.BackupEncryption) Encryption {
+	switch setting.Mode {
+	case string(commonModel.BACKUP_ENCRYPT_PASSPHRASE):
+		return Encryption{Passphrase: setting.Passphrase}
+	case string(commonModel.BACKUP_ENCRYPT_X25519):
+		return Encryption{Recipient: setting.Recipient}
+	default:
+		return Encryption{}
+	}
+}
+
+// Enabled 是否需要加密
+func (e Encryption) Enabled() bool {
+	return e.Passphrase != "" || e.Recipient != ""
+}
+
+// recipient 返回加密使用的接收者，公钥优先
+func (e Encryption) recipient() (age.Recipient, error) {
+	if e.Recipient != "" {
+		return age.ParseX25519Recipient(strings.TrimSpace(e.Recipient))
+	}
+	return age.NewScryptRecipient(e.Passphrase)
+}
+
+// identities 返回解密可用的身份
+func (d Decryption) identities() ([]age.Identity, error) {
+	var identities []age.Identity
+	if d.Identity != "" {
+		parsed, err := age.ParseIdentities(strings.NewReader(d.Identity))
+		if err != nil {
+			return nil, fmt.Errorf("无效的私钥: %w", err)
+		}
+		identities = append(identities, parsed...)
+	}
+	if d.Passphrase != "" {
+		identity, err := age.NewScryptIdentity(d.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, nil
+}
+
+// GenerateIdentity 生成用于加密备份的 age 私钥，公钥通过 Recipient 获取
+func GenerateIdentity() (*age.X25519Identity, error) {
+	return age.GenerateX25519Identity()
+}
+
+// IsEncrypted 判断备份文件是否已加密
+func IsEncrypted(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	prefix := make([]byte, len(ageVersionLine))
+	if _, err := io.ReadFull(file, prefix); err != nil {
+		return false
+	}
+	return string(prefix) == ageVersionLine
+}
+
+// ContentType 根据备份文件名返回下载与上传时使用的 Content-Type
+func ContentType(name string) string {
+	if strings.HasSuffix(name, encryptedSuffix) {
+		return "application/octet-stream"
+	}
+	return "application/zip"
+}
+
+// encryptFile 将 src 加密写入 dst
+func encryptFile(src, dst string, encryption Encryption) error {
+	recipient, err := encryption.recipient()
+	if err != nil {
+		return fmt.Errorf("无效的加密设置: %w", err)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if err := encryptStream(out, in, recipient); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return fmt.Errorf("加密备份失败: %w", err)
+	}
+	return out.Close()
+}
+
+// encryptStream 将 src 加密写入 dst
+func encryptStream(dst io.Writer, src io.Reader, recipient age.Recipient) error {
+	writer, err := age.Encrypt(dst, recipient)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(writer, src); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// decryptStream 将 src 解密写入 dst
+func decryptStream(dst io.Writer, src io.Reader, identities ...age.Identity) error {
+	reader, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, reader)
+	return err
+}
+
+// decryptForRestore 如果备份已加密，解密到临时文件并返回其路径与清理函数；未加密时原样返回
+func decryptForRestore(path string, decryption Decryption) (string, func(), error) {
+	noop := func() {}
+	if !IsEncrypted(path) {
+		return path, noop, nil
+	}
+
+	identities, err := decryption.identities()
+	if err != nil {
+		return "", noop, err
+	}
+	if len(identities) == 0 {
+		return "", noop, errors.New(commonModel.BACKUP_KEY_REQUIRED)
+	}
+
+	if err := os.MkdirAll("temp", 0o755); err != nil {
+		return "", noop, err
+	}
+	plainPath := filepath.Join("temp", fmt.Sprintf("decrypted_%d.zip", time.Now().UnixNano()))
+	cleanup := func() {
+		_ = os.Remove(plainPath)
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return "", noop, err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(plainPath)
+	if err != nil {
+		return "", noop, err
+	}
+	err = decryptStream(out, in, identities...)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return "", noop, errors.New(commonModel.BACKUP_KEY_MISMATCH)
+		}
+		return "", noop, fmt.Errorf("解密备份失败: %w", err)
+	}
+
+	return plainPath, cleanup, nil
+}
@@ -0,0 +1,161 @@
+package backup
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/lin-snow/ech0/internal/database"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+)
+
+const (
+	manifestFileName      = "ech0_manifest.json" // 备份清单文件名
+	manifestFormatVersion = 1                    // 清单格式版本
+)
+
+// Manifest 备份清单，记录快照的版本信息与文件校验和
+type Manifest struct {
+	FormatVersion int              `json:"format_version"`
+	AppVersion    string           `json:"app_version"`
+	SchemaVersion string           `json:"schema_version"`
+	CreatedAt     time.Time        `json:"created_at"`
+	Database      string           `json:"database"`   // 数据库快照在备份中的路径
+	RowCounts     map[string]int64 `json:"row_counts"` // 快照中各数据表行数
+	Files         []ManifestFile   `json:"files"`
+}
+
+// ManifestFile 备份中单个文件的校验信息
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// newManifest 根据数据库快照信息创建清单
+func newManifest(databaseEntry string, info database.SnapshotInfo) *Manifest {
+	return &Manifest{
+		FormatVersion: manifestFormatVersion,
+		AppVersion:    commonModel.FullVersion,
+		SchemaVersion: info.SchemaVersion,
+		CreatedAt:     time.Now().UTC(),
+		Database:      databaseEntry,
+		RowCounts:     info.RowCounts,
+		Files:         []ManifestFile{},
+	}
+}
+
+// addFile 记录已写入备份的文件
+func (m *Manifest) addFile(name string, size int64, checksum string) {
+	m.Files = append(m.Files, ManifestFile{Path: name, Size: size, SHA256: checksum})
+}
+
+// writeTo 将清单写入备份压缩包
+func (m *Manifest) writeTo(zipWriter *zip.Writer) error {
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	writer, err := zipWriter.Create(manifestFileName)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(content)
+	return err
+}
+
+// VerifyBackup 校验备份压缩包的完整性
+// 旧版本备份没有清单时返回 nil，由调用方决定是否继续
+func VerifyBackup(backupFilePath string) (*Manifest, error) {
+	reader, err := zip.OpenReader(backupFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开备份文件失败: %w", err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	entries := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		entries[file.Name] = file
+	}
+
+	manifestEntry, ok := entries[manifestFileName]
+	if !ok {
+		return nil, nil
+	}
+
+	manifest, err := readManifest(manifestEntry)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.FormatVersion > manifestFormatVersion {
+		return nil, fmt.Errorf("不支持的备份清单版本: %d", manifest.FormatVersion)
+	}
+
+	for _, expected := range manifest.Files {
+		entry, ok := entries[expected.Path]
+		if !ok {
+			return nil, errors.New("备份文件缺失: " + expected.Path)
+		}
+		size, checksum, err := checksumZipEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		if size != expected.Size || checksum != expected.SHA256 {
+			return nil, errors.New("备份文件校验失败: " + expected.Path)
+		}
+	}
+
+	if manifest.Database != "" {
+		if _, ok := entries[manifest.Database]; !ok {
+			return nil, errors.New("备份中缺少数据库快照: " + manifest.Database)
+		}
+	}
+
+	return manifest, nil
+}
+
+// readManifest 读取压缩包中的清单
+func readManifest(entry *zip.File) (*Manifest, error) {
+	reader, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	var manifest Manifest
+	if err := json.NewDecoder(reader).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("解析备份清单失败: %w", err)
+	}
+	return &manifest, nil
+}
+
+// checksumZipEntry 计算压缩包条目的大小与 SHA-256
+func checksumZipEntry(entry *zip.File) (int64, string, error) {
+	reader, err := entry.Open()
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, reader)
+	if err != nil {
+		return 0, "", fmt.Errorf("读取备份文件 %s 失败: %w", entry.Name, err)
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,167 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	settingModel "github.com/lin-snow/ech0/internal/model/setting"
+	httpUtil "github.com/lin-snow/ech0/internal/util/http"
+	storageUtil "github.com/lin-snow/ech0/internal/util/storage"
+)
+
+// NewDestinationStorage 根据异地备份设置创建对应的对象存储
+func NewDestinationStorage(dest settingModel.BackupDestination) (storageUtil.ObjectStorage, error) {
+	switch dest.Type {
+	case string(commonModel.S3_FILE):
+		return storageUtil.NewMinioStorage(
+			httpUtil.TrimURL(dest.Endpoint),
+			dest.AccessKey,
+			dest.SecretKey,
+			dest.BucketName,
+			dest.Region,
+			dest.Provider,
+			dest.UseSSL,
+		)
+	case string(commonModel.LOCAL_FILE):
+		return storageUtil.NewLocalObjectStorage(dest.LocalPath)
+	default:
+		return nil, errors.New(commonModel.INVALID_BACKUP_DEST)
+	}
+}
+
+// UploadBackup 将备份上传到异地存储，并回读校验 SHA-256，返回对象名
+// S3 存储在文件较大时由 minio 自动分片上传，本地目录先写临时文件再重命名
+func UploadBackup(
+	ctx context.Context,
+	store storageUtil.ObjectStorage,
+	prefix, backupPath string,
+) (string, error) {
+	objectName := remoteObjectName(prefix, filepath.Base(backupPath))
+
+	checksum, err := fileChecksum(backupPath)
+	if err != nil {
+		return objectName, err
+	}
+
+	file, err := os.Open(backupPath)
+	if err != nil {
+		return objectName, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if err := store.Upload(ctx, objectName, file, ContentType(objectName)); err != nil {
+		return objectName, err
+	}
+
+	// 回读校验，避免存储端静默损坏
+	reader, err := store.Download(ctx, objectName)
+	if err != nil {
+		return objectName, fmt.Errorf("校验异地备份失败: %w", err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return objectName, fmt.Errorf("校验异地备份失败: %w", err)
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != checksum {
+		_ = store.DeleteObject(ctx, objectName)
+		return objectName, errors.New("异地备份校验和不一致: " + objectName)
+	}
+
+	return objectName, nil
+}
+
+// ApplyRemoteRetention 按保留策略清理异地存储中的旧备份，返回被删除的对象名
+func ApplyRemoteRetention(
+	ctx context.Context,
+	store storageUtil.ObjectStorage,
+	prefix string,
+	policy settingModel.BackupRetention,
+) ([]string, error) {
+	if policy.IsZero() {
+		policy = settingModel.DefaultBackupRetention()
+	}
+
+	dir := strings.Trim(prefix, "/")
+	listPrefix := dir
+	if listPrefix != "" {
+		listPrefix += "/"
+	}
+
+	objects, err := store.ListObjects(ctx, listPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只处理前缀目录下直接存放的备份文件
+	archives := make([]ArchiveInfo, 0, len(objects))
+	for _, object := range objects {
+		if strings.TrimSuffix(object, path.Base(object)) != listPrefix {
+			continue
+		}
+		createdAt, ok := parseBackupTime(path.Base(object))
+		if !ok {
+			continue
+		}
+		archives = append(archives, ArchiveInfo{Name: object, CreatedAt: createdAt})
+	}
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].CreatedAt.After(archives[j].CreatedAt)
+	})
+
+	keep := retainedBackups(archives, policy)
+
+	var deleted []string
+	for _, archive := range archives {
+		if keep[archive.Name] {
+			continue
+		}
+		if err := store.DeleteObject(ctx, archive.Name); err != nil {
+			return deleted, fmt.Errorf("删除异地旧备份失败: %w", err)
+		}
+		deleted = append(deleted, archive.Name)
+	}
+
+	return deleted, nil
+}
+
+// remoteObjectName 拼接异地存储中的对象名
+func remoteObjectName(prefix, name string) string {
+	dir := strings.Trim(prefix, "/")
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+// fileChecksum 计算文件的 SHA-256
+func fileChecksum(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
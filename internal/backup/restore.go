@@ -0,0 +1,354 @@
+package backup
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lin-snow/ech0/internal/config"
+	"github.com/lin-snow/ech0/internal/database"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	fileUtil "github.com/lin-snow/ech0/internal/util/file"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	"go.uber.org/zap"
+)
+
+const (
+	restoreDirPrefix   = "restore_"             // 待确认恢复的暂存目录前缀
+	restorePreviewFile = "restore_preview.json" // 暂存目录中的预检结果
+	restoreDataDir     = "data"                 // 暂存目录中解压的数据目录
+	migratedDBName     = "migrated.db"          // 暂存目录中迁移后的数据库副本
+	restoreTTL         = 30 * time.Minute       // 预检结果有效期
+)
+
+// restoreMutex 保证同一时间只有一个恢复在提交
+var restoreMutex sync.Mutex
+
+// RestorePreview 恢复预检结果，对比备份与当前数据，确认后通过 Token 提交恢复
+type RestorePreview struct {
+	Token           string         `json:"token"`             // 提交或取消恢复时使用的令牌
+	ExpiresAt       time.Time      `json:"expires_at"`        // 令牌过期时间
+	Encrypted       bool           `json:"encrypted"`         // 备份是否已加密
+	Verified        bool           `json:"verified"`          // 是否已通过清单校验（旧版本备份没有清单）
+	AppVersion      string         `json:"app_version"`       // 备份时的版本，旧版本备份为空
+	CurrentVersion  string         `json:"current_version"`   // 当前版本
+	BackupCreatedAt *time.Time     `json:"backup_created_at"` // 备份时间，旧版本备份为空
+	Database        string         `json:"database"`          // 数据库文件在数据目录中的路径
+	BackupSchema    string         `json:"backup_schema"`     // 备份数据库的表结构指纹
+	CurrentSchema   string         `json:"current_schema"`    // 当前数据库的表结构指纹
+	AddedTables     []string       `json:"added_tables"`      // 迁移时为备份新建的数据表（备份来自旧版本）
+	AddedColumns    []string       `json:"added_columns"`     // 迁移时为备份新增的列（备份来自旧版本）
+	UnknownTables   []string       `json:"unknown_tables"`    // 当前版本不认识的数据表（备份可能来自新版本）
+	UnknownColumns  []string       `json:"unknown_columns"`   // 当前版本不认识的列（备份可能来自新版本）
+	Counts          []RestoreCount `json:"counts"`            // 主要数据的数量对比
+	Files           int            `json:"files"`             // 备份中除数据库外的文件数量
+	FilesSize       int64          `json:"files_size"`        // 备份中除数据库外的文件总大小（字节）
+}
+
+// RestoreCount 单项数据在备份与当前数据库中的数量
+type RestoreCount struct {
+	Name    string `json:"name"`    // 数据项名称
+	Backup  int64  `json:"backup"`  // 备份中的数量
+	Current int64  `json:"current"` // 当前的数量
+}
+
+// restoreCountTables 预检时对比数量的数据项及其数据表
+var restoreCountTables = []struct {
+	Name  string
+	Table string
+}{
+	{Name: "echos", Table: "echos"},
+	{Name: "users", Table: "users"},
+	{Name: "media", Table: "media"},
+	{Name: "settings", Table: "key_values"},
+}
+
+// PrepareRestore 恢复第一阶段：解密、校验并解压备份，在副本上执行迁移，对比备份与当前数据
+// 只写入临时目录，不会改动正在使用的数据库与数据目录
+func PrepareRestore(filePath string, decryption Decryption) (*RestorePreview, error) {
+	if !fileUtil.FileExists(filePath) {
+		return nil, errors.New("备份文件不存在: " + filePath)
+	}
+
+	cleanupExpiredRestores()
+
+	plainPath, cleanup, err := decryptForRestore(filePath, decryption)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	manifest, err := VerifyBackup(plainPath)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newRestoreToken()
+	if err != nil {
+		return nil, err
+	}
+	stageDir := restoreStageDir(token)
+	preview, err := stageRestore(stageDir, plainPath, manifest)
+	if err != nil {
+		_ = os.RemoveAll(stageDir)
+		return nil, err
+	}
+
+	preview.Token = token
+	preview.ExpiresAt = time.Now().UTC().Add(restoreTTL)
+	preview.Encrypted = plainPath != filePath
+
+	content, err := json.Marshal(preview)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(stageDir, restorePreviewFile), content, 0o600)
+	}
+	if err != nil {
+		_ = os.RemoveAll(stageDir)
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// stageRestore 将备份解压到暂存目录并生成预检结果
+func stageRestore(stageDir, backupFilePath string, manifest *Manifest) (*RestorePreview, error) {
+	extractPath := filepath.Join(stageDir, restoreDataDir)
+	if err := fileUtil.UnzipFile(backupFilePath, extractPath); err != nil {
+		return nil, err
+	}
+	// 清单仅用于校验，不复制到数据目录
+	if err := os.Remove(filepath.Join(extractPath, manifestFileName)); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	preview := &RestorePreview{
+		CurrentVersion: commonModel.FullVersion,
+		Database:       filepath.Base(config.Config.Database.Path),
+	}
+	if manifest != nil {
+		preview.Verified = true
+		preview.AppVersion = manifest.AppVersion
+		preview.BackupCreatedAt = &manifest.CreatedAt
+		preview.Database = manifest.Database
+	}
+
+	dbPath := filepath.Join(extractPath, filepath.FromSlash(preview.Database))
+	if !fileUtil.FileExists(dbPath) {
+		return nil, errors.New("备份中缺少数据库文件: " + preview.Database)
+	}
+
+	// 以只读方式读取备份中的数据库
+	backupInfo, err := database.InspectDatabase(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("读取备份数据库失败: %w", err)
+	}
+
+	// 在副本上执行迁移，提交恢复时使用迁移后的副本
+	migratedPath := filepath.Join(stageDir, migratedDBName)
+	if err := fileUtil.CopyFile(dbPath, migratedPath); err != nil {
+		return nil, err
+	}
+	if err := database.MigrateDatabaseFile(migratedPath); err != nil {
+		return nil, fmt.Errorf("备份数据库迁移失败: %w", err)
+	}
+	migratedInfo, err := database.InspectDatabase(migratedPath)
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移后的数据库失败: %w", err)
+	}
+
+	currentInfo, err := database.InspectCurrentDatabase()
+	if err != nil {
+		return nil, fmt.Errorf("读取当前数据库失败: %w", err)
+	}
+
+	preview.BackupSchema = backupInfo.SchemaVersion
+	preview.CurrentSchema = currentInfo.SchemaVersion
+	preview.AddedTables, preview.AddedColumns = diffColumns(migratedInfo.Columns, backupInfo.Columns)
+	preview.UnknownTables, preview.UnknownColumns = diffColumns(backupInfo.Columns, currentInfo.Columns)
+
+	for _, item := range restoreCountTables {
+		preview.Counts = append(preview.Counts, RestoreCount{
+			Name:    item.Name,
+			Backup:  migratedInfo.RowCounts[item.Table],
+			Current: currentInfo.RowCounts[item.Table],
+		})
+	}
+
+	if err := filepath.Walk(extractPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || path == dbPath {
+			return nil
+		}
+		preview.Files++
+		preview.FilesSize += info.Size()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// CommitRestore 恢复第二阶段：使用预检时迁移好的数据库与解压的数据覆盖数据目录
+func CommitRestore(token string) error {
+	restoreMutex.Lock()
+	defer restoreMutex.Unlock()
+
+	stageDir, preview, err := loadRestore(token)
+	if err != nil {
+		return err
+	}
+	defer removeRestoreStage(stageDir)
+
+	extractPath := filepath.Join(stageDir, restoreDataDir)
+	tempDbPath := filepath.Join(extractPath, filepath.FromSlash(preview.Database))
+
+	// 使用迁移后的副本替换备份中的数据库
+	if err := os.Rename(filepath.Join(stageDir, migratedDBName), tempDbPath); err != nil {
+		return err
+	}
+
+	// 命令行离线恢复时没有打开的数据库连接，直接覆盖数据目录
+	if !database.IsOpened() {
+		return fileUtil.CopyDirectory(extractPath, dataDir)
+	}
+
+	// 启用写锁，阻止新的写操作
+	previousLock := database.IsWriteLocked()
+	if !previousLock {
+		database.EnableWriteLock()
+		defer database.DisableWriteLock()
+	}
+
+	// 关闭 Logger，释放文件句柄
+	logUtil.CloseLogger()
+	defer logUtil.ReopenLogger()
+
+	// 热切换到临时数据库
+	if err := database.HotChangeDatabase(tempDbPath); err != nil {
+		return err
+	}
+
+	// 复制备份覆盖到正式数据目录
+	if err := fileUtil.CopyDirectory(extractPath, dataDir); err != nil {
+		return err
+	}
+
+	// 热切换回正式数据库
+	return database.HotChangeDatabase(filepath.Join(dataDir, filepath.FromSlash(preview.Database)))
+}
+
+// DiscardRestore 取消待确认的恢复，删除暂存数据
+func DiscardRestore(token string) error {
+	stageDir, _, err := loadRestore(token)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(stageDir)
+}
+
+// loadRestore 读取待确认恢复的暂存目录与预检结果
+func loadRestore(token string) (string, *RestorePreview, error) {
+	if !isRestoreToken(token) {
+		return "", nil, errors.New(commonModel.RESTORE_PREVIEW_NOT_FOUND)
+	}
+
+	stageDir := restoreStageDir(token)
+	content, err := os.ReadFile(filepath.Join(stageDir, restorePreviewFile))
+	if err != nil {
+		return "", nil, errors.New(commonModel.RESTORE_PREVIEW_NOT_FOUND)
+	}
+
+	var preview RestorePreview
+	if err := json.Unmarshal(content, &preview); err != nil {
+		return "", nil, errors.New(commonModel.RESTORE_PREVIEW_NOT_FOUND)
+	}
+	if time.Now().After(preview.ExpiresAt) {
+		removeRestoreStage(stageDir)
+		return "", nil, errors.New(commonModel.RESTORE_PREVIEW_NOT_FOUND)
+	}
+
+	return stageDir, &preview, nil
+}
+
+// cleanupExpiredRestores 清理过期或不完整的暂存目录
+func cleanupExpiredRestores() {
+	entries, err := os.ReadDir("temp")
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		token, ok := strings.CutPrefix(entry.Name(), restoreDirPrefix)
+		if !entry.IsDir() || !ok || !isRestoreToken(token) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < restoreTTL {
+			continue
+		}
+		removeRestoreStage(restoreStageDir(token))
+	}
+}
+
+// removeRestoreStage 删除暂存目录
+func removeRestoreStage(stageDir string) {
+	if err := os.RemoveAll(stageDir); err != nil {
+		logUtil.GetLogger().
+			Warn("Failed to cleanup restore stage directory",
+				zap.String("path", stageDir),
+				zap.String("error", err.Error()))
+	}
+}
+
+// diffColumns 返回 a 中存在而 b 中不存在的数据表与列
+func diffColumns(a, b map[string][]string) ([]string, []string) {
+	tables := []string{}
+	columns := []string{}
+	for table, tableColumns := range a {
+		otherColumns, ok := b[table]
+		if !ok {
+			tables = append(tables, table)
+			continue
+		}
+		for _, column := range tableColumns {
+			if !slices.Contains(otherColumns, column) {
+				columns = append(columns, table+"."+column)
+			}
+		}
+	}
+	sort.Strings(tables)
+	sort.Strings(columns)
+	return tables, columns
+}
+
+func newRestoreToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func isRestoreToken(token string) bool {
+	if len(token) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(token)
+	return err == nil
+}
+
+func restoreStageDir(token string) string {
+	return filepath.Join("temp", restoreDirPrefix+token)
+}
@@ -0,0 +1,239 @@
+package cache
+
+import (
+	"container/list"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// tmpFileMaxAge 超过该时长的临时文件视为写入中断的残留
+const tmpFileMaxAge = time.Hour
+
+// DiskCache 是有容量上限的磁盘 LRU 缓存，用于存放可以重新生成的文件（如处理后的图片）
+// 访问顺序以文件修改时间持久化，重启后按修改时间恢复
+type DiskCache struct {
+	dir       string
+	mu        sync.Mutex
+	maxBytes  int64
+	size      int64
+	entries   map[string]*list.Element
+	lru       *list.List // 队首为最近访问
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// diskEntry 是缓存中的一个文件
+type diskEntry struct {
+	name string
+	size int64
+}
+
+// DiskCacheStats 磁盘缓存统计
+type DiskCacheStats struct {
+	Hits      uint64 // 命中次数
+	Misses    uint64 // 未命中次数
+	Evictions uint64 // 淘汰的文件数
+	Entries   int    // 缓存的文件数
+	Size      int64  // 已用字节数
+	MaxSize   int64  // 容量上限
+}
+
+// HitRate 返回命中率（0-1）
+func (s DiskCacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// NewDiskCache 创建磁盘缓存，并载入目录中已有的文件
+func NewDiskCache(dir string, maxBytes int64) *DiskCache {
+	c := &DiskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+	_ = os.MkdirAll(dir, 0o755)
+	c.Sync()
+	return c
+}
+
+// Get 返回缓存文件的路径，命中时刷新访问顺序
+func (c *DiskCache) Get(name string) (string, bool) {
+	filePath := filepath.Join(c.dir, name)
+
+	c.mu.Lock()
+	element, ok := c.entries[name]
+	if ok {
+		if _, err := os.Stat(filePath); err != nil {
+			// 文件已被外部删除
+			c.removeElement(element)
+			ok = false
+		} else {
+			c.lru.MoveToFront(element)
+		}
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		c.misses.Add(1)
+		return "", false
+	}
+
+	c.hits.Add(1)
+	now := time.Now()
+	_ = os.Chtimes(filePath, now, now)
+	return filePath, true
+}
+
+// Put 原子化写入缓存文件，超出容量上限时立即淘汰最久未访问的文件
+func (c *DiskCache) Put(name string, data []byte) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+
+	filePath := filepath.Join(c.dir, name)
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[name]; ok {
+		c.removeElement(element)
+	}
+	c.entries[name] = c.lru.PushFront(&diskEntry{name: name, size: int64(len(data))})
+	c.size += int64(len(data))
+	c.evictLocked()
+	return nil
+}
+
+// SetMaxBytes 设置容量上限，在下一次写入或淘汰时生效
+func (c *DiskCache) SetMaxBytes(maxBytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxBytes = maxBytes
+}
+
+// Evict 与磁盘上的文件对账后淘汰最久未访问的文件直到不超过容量上限，返回淘汰的文件数与字节数
+func (c *DiskCache) Evict() (int, int64) {
+	c.Sync()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictLocked()
+}
+
+// Sync 重新扫描缓存目录：载入未登记的文件、移除已被删除的文件并清理残留的临时文件
+func (c *DiskCache) Sync() {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type fileInfo struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	files := make([]fileInfo, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		if strings.HasSuffix(dirEntry.Name(), ".tmp") {
+			if time.Since(info.ModTime()) > tmpFileMaxAge {
+				_ = os.Remove(filepath.Join(c.dir, dirEntry.Name()))
+			}
+			continue
+		}
+		files = append(files, fileInfo{name: dirEntry.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+	// 最近修改的排在前面
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	present := make(map[string]struct{}, len(files))
+	for _, file := range files {
+		present[file.name] = struct{}{}
+		if element, ok := c.entries[file.name]; ok {
+			entry := element.Value.(*diskEntry)
+			c.size += file.size - entry.size
+			entry.size = file.size
+			continue
+		}
+		// 未登记的文件按修改时间插入到已登记文件之后
+		c.entries[file.name] = c.lru.PushBack(&diskEntry{name: file.name, size: file.size})
+		c.size += file.size
+	}
+	for name, element := range c.entries {
+		if _, ok := present[name]; !ok {
+			c.removeElement(element)
+		}
+	}
+}
+
+// Stats 返回缓存统计
+func (c *DiskCache) Stats() DiskCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return DiskCacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   len(c.entries),
+		Size:      c.size,
+		MaxSize:   c.maxBytes,
+	}
+}
+
+// evictLocked 淘汰最久未访问的文件直到不超过容量上限，调用方需持有锁
+func (c *DiskCache) evictLocked() (int, int64) {
+	var count int
+	var freed int64
+	for c.maxBytes > 0 && c.size > c.maxBytes {
+		element := c.lru.Back()
+		if element == nil {
+			break
+		}
+		entry := element.Value.(*diskEntry)
+		if err := os.Remove(filepath.Join(c.dir, entry.name)); err != nil && !os.IsNotExist(err) {
+			break
+		}
+		c.removeElement(element)
+		c.evictions.Add(1)
+		count++
+		freed += entry.size
+	}
+	return count, freed
+}
+
+// removeElement 从索引中移除文件，不删除磁盘上的文件，调用方需持有锁
+func (c *DiskCache) removeElement(element *list.Element) {
+	entry := element.Value.(*diskEntry)
+	c.lru.Remove(element)
+	delete(c.entries, entry.name)
+	c.size -= entry.size
+}
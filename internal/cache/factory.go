@@ -1,8 +1,20 @@
 package cache
 
+import "sync"
+
+const (
+	// ImageCacheDir 是处理后图片的磁盘缓存目录
+	ImageCacheDir = "data/cache/images"
+	// DefaultImageCacheMaxBytes 是图片磁盘缓存的默认容量上限（512 MB）
+	DefaultImageCacheMaxBytes int64 = 512 << 20
+)
+
 // CacheFactory 是一个工厂类，用于创建和管理不同类型的缓存实例
 type CacheFactory struct {
 	cache ICache[string, any] // 通用缓存
+
+	imageCache     *DiskCache // 处理后图片的磁盘缓存，首次使用时创建
+	imageCacheOnce sync.Once
 }
 
 // NewCacheFactory 创建一个新的 CacheFactory 实例，并初始化所需的缓存
@@ -21,3 +33,11 @@ func NewCacheFactory() *CacheFactory {
 func (f *CacheFactory) Cache() ICache[string, any] {
 	return f.cache
 }
+
+// ImageCache 返回处理后图片的磁盘缓存
+func (f *CacheFactory) ImageCache() *DiskCache {
+	f.imageCacheOnce.Do(func() {
+		f.imageCache = NewDiskCache(ImageCacheDir, DefaultImageCacheMaxBytes)
+	})
+	return f.imageCache
+}
@@ -8,16 +8,22 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/charmbracelet/huh"
 	"github.com/lin-snow/ech0/internal/backup"
+	"github.com/lin-snow/ech0/internal/cache"
 	"github.com/lin-snow/ech0/internal/config"
+	"github.com/lin-snow/ech0/internal/database"
+	"github.com/lin-snow/ech0/internal/di"
+	"github.com/lin-snow/ech0/internal/event"
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	"github.com/lin-snow/ech0/internal/server"
 	"github.com/lin-snow/ech0/internal/ssh"
+	"github.com/lin-snow/ech0/internal/transaction"
 	"github.com/lin-snow/ech0/internal/tui"
 )
 
@@ -125,9 +131,9 @@ func DoStopServe() {
 	tui.PrintCLIInfo("🎉 停止服务成功", "Ech0 服务器已停止")
 }
 
-// DoBackup 执行备份
-func DoBackup() {
-	_, backupFileName, err := backup.ExecuteBackup()
+// DoBackup 执行备份，encryption 不为空时输出加密备份
+func DoBackup(encryption backup.Encryption) {
+	_, backupFileName, err := backup.ExecuteBackup(encryption)
 	if err != nil {
 		// 处理错误
 		tui.PrintCLIInfo("😭 执行结果", "备份失败: "+err.Error())
@@ -141,17 +147,193 @@ func DoBackup() {
 	tui.PrintCLIInfo("🎉 备份成功", fullPath)
 }
 
-// DoRestore 执行恢复
-func DoRestore(backupFilePath string) {
-	err := backup.ExecuteRestore(backupFilePath)
+// DoBackupList 列出所有备份
+func DoBackupList() {
+	archives, err := backup.ListBackups()
 	if err != nil {
-		// 处理错误
+		tui.PrintCLIInfo("😭 执行结果", "读取备份列表失败: "+err.Error())
+		return
+	}
+	if len(archives) == 0 {
+		tui.PrintCLIInfo("ℹ️ 备份列表", "暂无备份")
+		return
+	}
+
+	items := make([]tui.CLIInfoItem, 0, len(archives))
+	for _, archive := range archives {
+		title := archive.Name
+		if archive.Encrypted {
+			title = "🔒 " + title
+		}
+		items = append(items, tui.CLIInfoItem{
+			Title: title,
+			Msg: fmt.Sprintf(
+				"%s  %.2f MB",
+				archive.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+				float64(archive.Size)/1024/1024,
+			),
+		})
+	}
+	tui.PrintCLIWithBox(items...)
+}
+
+// DoBackupKeygen 生成用于加密备份的 age 密钥对
+func DoBackupKeygen() {
+	identity, err := backup.GenerateIdentity()
+	if err != nil {
+		tui.PrintCLIInfo("😭 执行结果", "生成密钥失败: "+err.Error())
+		return
+	}
+
+	tui.PrintCLIWithBox(
+		tui.CLIInfoItem{Title: "🔑 公钥（填入备份加密设置）", Msg: identity.Recipient().String()},
+		tui.CLIInfoItem{Title: "🔒 私钥（请离线妥善保管，恢复时使用）", Msg: identity.String()},
+	)
+}
+
+// DoRestore 预检备份并展示与当前数据的对比，确认后执行恢复；参数可以是备份文件路径，也可以是备份目录中的备份文件名
+// 加密的备份使用 decryption 中的口令或私钥解密，私钥可以是私钥文件路径；assumeYes 为 true 时跳过确认
+func DoRestore(backupFilePath string, decryption backup.Decryption, assumeYes bool) {
+	preview, ok := prepareRestore(backupFilePath, decryption)
+	if !ok {
+		return
+	}
+	printRestorePreview(preview)
+
+	confirmed := assumeYes
+	if !confirmed {
+		_ = huh.NewConfirm().
+			Title("确认使用该备份覆盖当前数据？").
+			Affirmative("恢复").
+			Negative("取消").
+			Value(&confirmed).
+			Run()
+	}
+	if !confirmed {
+		_ = backup.DiscardRestore(preview.Token)
+		tui.PrintCLIInfo("⚠️ 跳过", "已取消恢复，当前数据未改动")
+		return
+	}
+
+	if err := backup.CommitRestore(preview.Token); err != nil {
 		tui.PrintCLIInfo("😭 执行结果", "恢复失败: "+err.Error())
 		return
 	}
 	tui.PrintCLIInfo("🎉 恢复成功", "已从备份文件 "+backupFilePath+" 中恢复数据")
 }
 
+// DoRestorePreview 预检备份并打印与当前数据的对比，不改动当前数据
+func DoRestorePreview(backupFilePath string, decryption backup.Decryption) {
+	preview, ok := prepareRestore(backupFilePath, decryption)
+	if !ok {
+		return
+	}
+	defer func() {
+		_ = backup.DiscardRestore(preview.Token)
+	}()
+
+	printRestorePreview(preview)
+}
+
+// prepareRestore 解析备份路径与私钥文件后执行预检
+func prepareRestore(backupFilePath string, decryption backup.Decryption) (*backup.RestorePreview, bool) {
+	if path, err := backup.ResolveBackup(backupFilePath); err == nil {
+		backupFilePath = path
+	}
+
+	if decryption.Identity != "" {
+		if content, err := os.ReadFile(decryption.Identity); err == nil {
+			decryption.Identity = string(content)
+		}
+	}
+
+	preview, err := backup.PrepareRestore(backupFilePath, decryption)
+	if err != nil {
+		tui.PrintCLIInfo("😭 执行结果", "预检失败: "+err.Error())
+		return nil, false
+	}
+	return preview, true
+}
+
+// printRestorePreview 打印备份与当前数据的对比
+func printRestorePreview(preview *backup.RestorePreview) {
+	version := preview.AppVersion
+	if version == "" {
+		version = "未知（旧版本备份）"
+	}
+	items := []tui.CLIInfoItem{
+		{Title: "📦 备份版本", Msg: version + " → 当前 v" + preview.CurrentVersion},
+	}
+	for _, count := range preview.Counts {
+		items = append(items, tui.CLIInfoItem{
+			Title: count.Name,
+			Msg:   fmt.Sprintf("备份 %d / 当前 %d", count.Backup, count.Current),
+		})
+	}
+	items = append(items, tui.CLIInfoItem{
+		Title: "files",
+		Msg:   fmt.Sprintf("%d 个文件，%.2f MB", preview.Files, float64(preview.FilesSize)/1024/1024),
+	})
+	if changes := slices.Concat(preview.AddedTables, preview.AddedColumns); len(changes) > 0 {
+		items = append(items, tui.CLIInfoItem{Title: "🛠️ 迁移将新增", Msg: strings.Join(changes, ", ")})
+	}
+	if unknown := slices.Concat(preview.UnknownTables, preview.UnknownColumns); len(unknown) > 0 {
+		items = append(items, tui.CLIInfoItem{
+			Title: "⚠️ 当前版本不认识的结构（备份可能来自更新的版本）",
+			Msg:   strings.Join(unknown, ", "),
+		})
+	}
+	tui.PrintCLIWithBox(items...)
+}
+
+// DoImport 从 Ech0 导出包或 Memos、Mastodon、Twitter/X 的归档导入数据，source 为空时自动识别
+func DoImport(filePath, source string) {
+	if s != nil || isWebPortInUse() {
+		tui.PrintCLIInfo("⚠️ 警告", "导入数据前请先停止服务器，或在管理后台中导入")
+		return
+	}
+
+	database.InitDatabase()
+	event.InitEventBus()
+	transferService, err := di.BuildTransferService(
+		database.GetDB,
+		cache.NewCacheFactory(),
+		transaction.NewTransactionManagerFactory(database.GetDB),
+		event.GetEventBus,
+	)
+	if err != nil {
+		tui.PrintCLIInfo("😭 执行结果", "导入失败: "+err.Error())
+		return
+	}
+
+	result, err := transferService.RunImport(context.Background(), source, filePath)
+	if result != nil {
+		items := []tui.CLIInfoItem{
+			{Title: "📦 数据来源", Msg: result.Source},
+			{
+				Title: "📝 导入结果",
+				Msg: fmt.Sprintf(
+					"共 %d 条，新导入 %d 条，已存在 %d 条，内容为空 %d 条",
+					result.Total, result.Imported, result.Skipped, result.Invalid,
+				),
+			},
+			{
+				Title: "🖼️ 媒体文件",
+				Msg:   fmt.Sprintf("导入 %d 个，缺失 %d 个", result.Media, result.MissingMedia),
+			},
+		}
+		if len(result.Log) > 0 {
+			items = append(items, tui.CLIInfoItem{Title: "ℹ️ 导入日志", Msg: strings.Join(result.Log, "\n")})
+		}
+		tui.PrintCLIWithBox(items...)
+	}
+	if err != nil {
+		tui.PrintCLIInfo("😭 执行结果", "导入失败: "+err.Error())
+		return
+	}
+	tui.PrintCLIInfo("🎉 导入成功", "已从 "+filePath+" 导入数据")
+}
+
 // DoVersion 打印版本信息
 func DoVersion() {
 	item := struct{ Title, Msg string }{
@@ -247,21 +429,35 @@ func DoTui() {
 			tui.ClearScreen()
 			DoEch0Info()
 		case "backup":
-			DoBackup()
+			DoBackup(backup.Encryption{})
 		case "restore":
 			// 如果服务器已经启动，则先停止服务器
 			if s != nil {
 				tui.PrintCLIInfo("⚠️ 警告", "恢复数据前请先停止服务器")
 			} else {
-				// 获取备份文件路径
+				// 获取备份文件路径，可直接选择备份目录中的备份
 				var path string
-				_ = huh.NewInput().
-					Title("请输入备份文件路径").
-					Value(&path).
-					Run()
+				if archives, err := backup.ListBackups(); err == nil && len(archives) > 0 {
+					options := make([]huh.Option[string], 0, len(archives)+1)
+					for _, archive := range archives {
+						options = append(options, huh.NewOption(archive.Name, archive.Name))
+					}
+					options = append(options, huh.NewOption("手动输入备份文件路径", ""))
+					_ = huh.NewSelect[string]().
+						Title("请选择要恢复的备份").
+						Options(options...).
+						Value(&path).
+						Run()
+				}
+				if path == "" {
+					_ = huh.NewInput().
+						Title("请输入备份文件路径").
+						Value(&path).
+						Run()
+				}
 				path = strings.TrimSpace(path)
 				if path != "" {
-					DoRestore(path)
+					DoRestore(path, promptDecryption(path), false)
 				} else {
 					tui.PrintCLIInfo("⚠️ 跳过", "未输入备份路径")
 				}
@@ -275,3 +471,42 @@ func DoTui() {
 		}
 	}
 }
+
+// promptDecryption 备份已加密时提示输入口令或私钥
+func promptDecryption(path string) backup.Decryption {
+	var decryption backup.Decryption
+	if resolved, err := backup.ResolveBackup(path); err == nil {
+		path = resolved
+	}
+	if !backup.IsEncrypted(path) {
+		return decryption
+	}
+
+	var method string
+	_ = huh.NewSelect[string]().
+		Title("备份已加密，请选择解密方式").
+		Options(
+			huh.NewOption("口令", "passphrase"),
+			huh.NewOption("私钥或私钥文件路径", "identity"),
+		).
+		Value(&method).
+		Run()
+
+	switch method {
+	case "passphrase":
+		_ = huh.NewInput().
+			Title("请输入备份口令").
+			EchoMode(huh.EchoModePassword).
+			Value(&decryption.Passphrase).
+			Run()
+	case "identity":
+		_ = huh.NewInput().
+			Title("请输入私钥（AGE-SECRET-KEY-1...）或私钥文件路径").
+			EchoMode(huh.EchoModePassword).
+			Value(&decryption.Identity).
+			Run()
+		decryption.Identity = strings.TrimSpace(decryption.Identity)
+	}
+
+	return decryption
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/pem"
 	"log"
 	"os"
+	"strings"
 
 	model "github.com/lin-snow/ech0/internal/model/common"
 	"github.com/spf13/viper"
@@ -39,6 +40,8 @@ type AppConfig struct {
 		Port string `yaml:"port"` // 服务器端口
 		Host string `yaml:"host"` // 服务器主机地址
 		Mode string `yaml:"mode"` // 运行模式，可能的值为 "debug" 或 "release"
+		// 受信任的反向代理地址或网段，只有来自这些地址的 X-Forwarded-For 才会被采信
+		TrustedProxies []string `yaml:"trustedproxies"`
 	} `yaml:"server"`
 	Database struct {
 		Type    string `yaml:"type"`    // 数据库类型
@@ -106,6 +109,16 @@ func LoadAppConfig() {
 		panic(model.READ_CONFIG_PANIC + ":" + err.Error())
 	}
 
+	// 从环境变量加载受信任的反向代理，多个地址以逗号分隔
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		Config.Server.TrustedProxies = nil
+		for _, proxy := range strings.Split(proxies, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				Config.Server.TrustedProxies = append(Config.Server.TrustedProxies, proxy)
+			}
+		}
+	}
+
 	// 初始化 JWT_SECRET
 	JWT_SECRET = GetJWTSecret()
 
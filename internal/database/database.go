@@ -9,6 +9,7 @@ import (
 
 	"github.com/lin-snow/ech0/internal/config"
 	authModel "github.com/lin-snow/ech0/internal/model/auth"
+	commentModel "github.com/lin-snow/ech0/internal/model/comment"
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	connectModel "github.com/lin-snow/ech0/internal/model/connect"
 	echoModel "github.com/lin-snow/ech0/internal/model/echo"
@@ -21,6 +22,8 @@ import (
 	userModel "github.com/lin-snow/ech0/internal/model/user"
 	webhookModel "github.com/lin-snow/ech0/internal/model/webhook"
 	util "github.com/lin-snow/ech0/internal/util/err"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	"go.uber.org/zap"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -38,6 +41,12 @@ func GetDB() *gorm.DB {
 	return db.Load().(*gorm.DB)
 }
 
+// IsOpened 判断是否已打开全局数据库连接，命令行离线执行时为 false
+func IsOpened() bool {
+	conn, _ := db.Load().(*gorm.DB)
+	return conn != nil
+}
+
 func SetDB(newDB *gorm.DB) {
 	db.Store(newDB)
 }
@@ -113,10 +122,27 @@ func InitDatabase() {
 			Err: err,
 		})
 	}
+
+	// 建立 Echo 全文索引
+	if err := SetupEchoSearchIndex(); err != nil {
+		util.HandlePanicError(&commonModel.ServerError{
+			Msg: commonModel.MIGRATE_DB_PANIC,
+			Err: err,
+		})
+	}
 }
 
 // MigrateDB 执行数据库迁移
 func MigrateDB() error {
+	return migrateSchema(GetDB())
+}
+
+// migrateSchema 在指定连接上自动建表
+func migrateSchema(conn *gorm.DB) error {
+	if conn == nil {
+		return errors.New(commonModel.DATABASE_NOT_INITED)
+	}
+
 	models := []interface{}{
 		&userModel.User{},
 		&echoModel.Echo{},
@@ -128,7 +154,11 @@ func MigrateDB() error {
 		&userModel.OAuthBinding{},
 		&echoModel.Tag{},
 		&echoModel.EchoTag{},
+		&echoModel.EchoRevision{},
+		&echoModel.EchoLike{},
+		&commentModel.Comment{},
 		&webhookModel.Webhook{},
+		&webhookModel.WebhookDelivery{},
 		&queueModel.DeadLetter{},
 		&settingModel.AccessTokenSetting{},
 		&inboxModel.Inbox{},
@@ -138,12 +168,14 @@ func MigrateDB() error {
 		&fediverseModel.Follow{},
 		&fediverseModel.Follower{},
 		&fediverseModel.InboxStatus{},
+		&fediverseModel.Interaction{},
+		&fediverseModel.Tombstone{},
 
 		// PWA 相关
 		&pwaModel.PushSubscription{},
 	}
 
-	return GetDB().AutoMigrate(
+	return conn.AutoMigrate(
 		models...,
 	)
 }
@@ -174,6 +206,13 @@ func HotChangeDatabase(newDBPath string) error {
 	}
 
 	SetDB(newDB)
+
+	// 切换后的数据库可能来自旧版本备份，确保全文索引存在
+	if err := SetupEchoSearchIndex(); err != nil {
+		logUtil.GetLogger().Error("Failed to setup echo search index",
+			zap.String("error", err.Error()))
+	}
+
 	return nil
 }
 
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// EchoSearchTable Echo 全文索引的 FTS5 虚拟表
+const EchoSearchTable = "echo_fts"
+
+// echoSearchAvailable 当前 SQLite 是否支持 FTS5 并已建立 Echo 全文索引
+var echoSearchAvailable atomic.Bool
+
+// IsEchoSearchAvailable 判断 Echo 全文索引是否可用
+func IsEchoSearchAvailable() bool {
+	return echoSearchAvailable.Load()
+}
+
+// echoSearchTriggers 维护全文索引与 echos、echo_tags、tags 表同步的触发器
+var echoSearchTriggers = map[string]string{
+	"echo_fts_echo_insert": `
+		CREATE TRIGGER IF NOT EXISTS echo_fts_echo_insert AFTER INSERT ON echos BEGIN
+			INSERT INTO echo_fts(rowid, content, tags, username)
+			VALUES (new.id, new.content, '', COALESCE(new.username, ''));
+		END`,
+	"echo_fts_echo_update": `
+		CREATE TRIGGER IF NOT EXISTS echo_fts_echo_update AFTER UPDATE OF content, username ON echos BEGIN
+			UPDATE echo_fts SET content = new.content, username = COALESCE(new.username, '')
+			WHERE rowid = new.id;
+		END`,
+	"echo_fts_echo_delete": `
+		CREATE TRIGGER IF NOT EXISTS echo_fts_echo_delete AFTER DELETE ON echos BEGIN
+			DELETE FROM echo_fts WHERE rowid = old.id;
+		END`,
+	"echo_fts_tag_link": `
+		CREATE TRIGGER IF NOT EXISTS echo_fts_tag_link AFTER INSERT ON echo_tags BEGIN
+			UPDATE echo_fts SET tags = (` + echoTagNamesSQL("new.echo_id") + `)
+			WHERE rowid = new.echo_id;
+		END`,
+	"echo_fts_tag_unlink": `
+		CREATE TRIGGER IF NOT EXISTS echo_fts_tag_unlink AFTER DELETE ON echo_tags BEGIN
+			UPDATE echo_fts SET tags = (` + echoTagNamesSQL("old.echo_id") + `)
+			WHERE rowid = old.echo_id;
+		END`,
+	"echo_fts_tag_rename": `
+		CREATE TRIGGER IF NOT EXISTS echo_fts_tag_rename AFTER UPDATE OF name ON tags BEGIN
+			UPDATE echo_fts SET tags = (` + echoTagNamesSQL("echo_fts.rowid") + `)
+			WHERE rowid IN (SELECT echo_id FROM echo_tags WHERE tag_id = new.id);
+		END`,
+}
+
+// echoTagNamesSQL 拼接某条 Echo 的所有标签名
+func echoTagNamesSQL(echoID string) string {
+	return `SELECT COALESCE(group_concat(t.name, ' '), '') FROM echo_tags et
+		JOIN tags t ON t.id = et.tag_id WHERE et.echo_id = ` + echoID
+}
+
+// SetupEchoSearchIndex 建立 Echo 全文索引，当前 SQLite 不支持 FTS5 时退化为 LIKE 查询
+func SetupEchoSearchIndex() error {
+	db := GetDB()
+	if db == nil {
+		return errors.New(commonModel.DATABASE_NOT_INITED)
+	}
+
+	hasTable := db.Migrator().HasTable(EchoSearchTable)
+	err := db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS echo_fts
+		USING fts5(content, tags, username, tokenize = 'trigram')`).Error
+	if err != nil {
+		echoSearchAvailable.Store(false)
+		// 不支持 FTS5 时移除触发器，否则写入 echos 表会因找不到 fts5 模块而失败
+		for name := range echoSearchTriggers {
+			if dropErr := db.Exec("DROP TRIGGER IF EXISTS " + name).Error; dropErr != nil {
+				return dropErr
+			}
+		}
+		if strings.Contains(err.Error(), "fts5") {
+			logUtil.GetLogger().Warn("SQLite FTS5 is unavailable, falling back to LIKE search",
+				zap.String("error", err.Error()))
+			return nil
+		}
+		return err
+	}
+
+	// 触发器缺失期间的写入不会同步到索引，需要重建
+	rebuild := !hasTable
+	for name, ddl := range echoSearchTriggers {
+		var count int64
+		if err := db.Raw(
+			"SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name = ?",
+			name,
+		).Scan(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			rebuild = true
+			if err := db.Exec(ddl).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if rebuild {
+		if err := RebuildEchoSearchIndex(db); err != nil {
+			return err
+		}
+	}
+
+	echoSearchAvailable.Store(true)
+	return nil
+}
+
+// RebuildEchoSearchIndex 根据 echos 表全量重建全文索引
+func RebuildEchoSearchIndex(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM echo_fts").Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO echo_fts(rowid, content, tags, username)
+			SELECT e.id, e.content, (` + echoTagNamesSQL("e.id") + `), COALESCE(e.username, '')
+			FROM echos e`).Error
+	})
+}
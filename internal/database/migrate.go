@@ -2,14 +2,16 @@ package database
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+	"gorm.io/gorm"
 )
 
 // fixOldEchoLayoutData 为旧数据补充默认的布局值（layout 为 NULL 或空字符串时设为 'waterfall'）
-func fixOldEchoLayoutData() error {
-	db := GetDB()
+func fixOldEchoLayoutData(db *gorm.DB) error {
 	if db == nil {
 		return errors.New(commonModel.DATABASE_NOT_INITED)
 	}
@@ -24,9 +26,67 @@ func fixOldEchoLayoutData() error {
 	return nil
 }
 
+// fixOldEchoStatusData 为旧数据补充默认的发布状态（status 为 NULL 或空字符串时设为 'published'）
+func fixOldEchoStatusData(db *gorm.DB) error {
+	if db == nil {
+		return errors.New(commonModel.DATABASE_NOT_INITED)
+	}
+
+	return db.Model(&echoModel.Echo{}).
+		Where("status IS NULL OR status = ''").
+		Update("status", echoModel.EchoStatusPublished).Error
+}
+
+// migrateLegacyEchoLikes 为旧版直接累加的点赞数补充点赞记录，使点赞数可由点赞记录推导
+// 旧点赞无法追溯来源，统一记为 legacy 身份，可在点赞管理中按类型清理
+func migrateLegacyEchoLikes(db *gorm.DB) error {
+	if db == nil {
+		return errors.New(commonModel.DATABASE_NOT_INITED)
+	}
+
+	var echos []struct {
+		ID       uint
+		FavCount int
+		Liked    int
+	}
+	if err := db.Raw(`
+		SELECT e.id, e.fav_count, (SELECT COUNT(*) FROM echo_likes l WHERE l.echo_id = e.id) AS liked
+		FROM echos e
+		WHERE e.fav_count > 0
+	`).Scan(&echos).Error; err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, echo := range echos {
+		if echo.FavCount <= echo.Liked {
+			continue
+		}
+
+		likes := make([]echoModel.EchoLike, 0, echo.FavCount-echo.Liked)
+		for i := echo.Liked + 1; i <= echo.FavCount; i++ {
+			likes = append(likes, echoModel.EchoLike{
+				EchoID:    echo.ID,
+				Identity:  fmt.Sprintf("%s:%d", echoModel.LikeKindLegacy, i),
+				Kind:      echoModel.LikeKindLegacy,
+				CreatedAt: now,
+			})
+		}
+		if err := db.CreateInBatches(likes, 500).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MigrateImageToMedia 将 images 表的数据增量同步到 media 表
 func MigrateImageToMedia() error {
-	db := GetDB()
+	return migrateImageToMedia(GetDB())
+}
+
+// migrateImageToMedia 在指定连接上将 images 表的数据增量同步到 media 表
+func migrateImageToMedia(db *gorm.DB) error {
 	if db == nil {
 		return errors.New(commonModel.DATABASE_NOT_INITED)
 	}
@@ -61,14 +121,29 @@ func MigrateImageToMedia() error {
 
 // UpdateMigration 执行旧数据库迁移和数据修复任务
 func UpdateMigration() error {
+	return updateMigration(GetDB())
+}
+
+// updateMigration 在指定连接上执行旧数据库迁移和数据修复任务
+func updateMigration(db *gorm.DB) error {
 	var err error
 
-	err = fixOldEchoLayoutData()
+	err = fixOldEchoLayoutData(db)
+	if err != nil {
+		return err
+	}
+
+	err = fixOldEchoStatusData(db)
+	if err != nil {
+		return err
+	}
+
+	err = migrateLegacyEchoLikes(db)
 	if err != nil {
 		return err
 	}
 
-	err = MigrateImageToMedia()
+	err = migrateImageToMedia(db)
 	if err != nil {
 		return err
 	}
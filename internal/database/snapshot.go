@@ -0,0 +1,160 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lin-snow/ech0/internal/config"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// SnapshotInfo 数据库快照的结构与数据概况
+type SnapshotInfo struct {
+	SchemaVersion string              // 表结构指纹（所有建表语句的 SHA-256 前缀）
+	RowCounts     map[string]int64    // 各数据表行数
+	Columns       map[string][]string // 各数据表的列名
+}
+
+// SnapshotDatabase 使用 VACUUM INTO 将数据库导出为事务一致的快照文件
+// 服务运行时直接使用当前连接，CLI 等未初始化连接的场景会临时打开数据库文件
+func SnapshotDatabase(destPath string) error {
+	conn, _ := db.Load().(*gorm.DB)
+	if conn == nil {
+		tempConn, err := openSQLite(config.Config.Database.Path)
+		if err != nil {
+			return err
+		}
+		defer closeSQLite(tempConn)
+		conn = tempConn
+	}
+
+	// VACUUM INTO 要求目标文件不存在
+	if err := os.Remove(destPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return conn.Exec("VACUUM INTO ?", destPath).Error
+}
+
+// InspectDatabase 以只读方式统计数据库文件的表结构指纹与各表行数
+func InspectDatabase(path string) (SnapshotInfo, error) {
+	conn, err := openSQLiteReadOnly(path)
+	if err != nil {
+		return SnapshotInfo{}, err
+	}
+	defer closeSQLite(conn)
+
+	return inspect(conn)
+}
+
+// InspectCurrentDatabase 统计当前使用中的数据库
+// 服务运行时直接使用当前连接，CLI 等未初始化连接的场景会以只读方式临时打开数据库文件
+func InspectCurrentDatabase() (SnapshotInfo, error) {
+	conn, _ := db.Load().(*gorm.DB)
+	if conn == nil {
+		return InspectDatabase(config.Config.Database.Path)
+	}
+	return inspect(conn)
+}
+
+// MigrateDatabaseFile 在独立连接上对数据库文件执行迁移，不影响全局连接
+func MigrateDatabaseFile(path string) error {
+	conn, err := openSQLite(path)
+	if err != nil {
+		return err
+	}
+	defer closeSQLite(conn)
+
+	if err := migrateSchema(conn); err != nil {
+		return err
+	}
+	return updateMigration(conn)
+}
+
+// inspect 统计连接对应数据库的表结构指纹、各表行数与列名
+func inspect(conn *gorm.DB) (SnapshotInfo, error) {
+	var objects []struct {
+		Type string
+		Name string
+		SQL  string
+	}
+	if err := conn.Raw(
+		"SELECT type, name, sql FROM sqlite_master WHERE sql IS NOT NULL ORDER BY type, name",
+	).Scan(&objects).Error; err != nil {
+		return SnapshotInfo{}, err
+	}
+	if len(objects) == 0 {
+		return SnapshotInfo{}, errors.New(commonModel.DATABASE_NOT_INITED)
+	}
+
+	hash := sha256.New()
+	info := SnapshotInfo{
+		RowCounts: make(map[string]int64),
+		Columns:   make(map[string][]string),
+	}
+	for _, object := range objects {
+		hash.Write([]byte(object.SQL))
+		hash.Write([]byte{'\n'})
+
+		// 只统计普通数据表，跳过 SQLite 内部表与全文索引的影子表
+		if object.Type != "table" ||
+			strings.HasPrefix(object.Name, "sqlite_") ||
+			strings.HasPrefix(object.Name, EchoSearchTable) {
+			continue
+		}
+
+		var count int64
+		if err := conn.Table(object.Name).Count(&count).Error; err != nil {
+			return SnapshotInfo{}, err
+		}
+		info.RowCounts[object.Name] = count
+
+		var columns []string
+		if err := conn.Raw(
+			"SELECT name FROM pragma_table_info(?) ORDER BY cid",
+			object.Name,
+		).Scan(&columns).Error; err != nil {
+			return SnapshotInfo{}, err
+		}
+		info.Columns[object.Name] = columns
+	}
+	info.SchemaVersion = hex.EncodeToString(hash.Sum(nil))[:16]
+
+	return info, nil
+}
+
+// OpenReadOnly 以只读方式打开独立的 SQLite 数据库，用于读取其他程序的数据库文件，用完后需调用 Close
+func OpenReadOnly(path string) (*gorm.DB, error) {
+	return openSQLiteReadOnly(path)
+}
+
+// Close 关闭独立打开的数据库连接
+func Close(conn *gorm.DB) {
+	closeSQLite(conn)
+}
+
+// openSQLite 打开独立的 SQLite 连接，不影响全局连接
+func openSQLite(path string) (*gorm.DB, error) {
+	return gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+}
+
+// openSQLiteReadOnly 以只读方式打开独立的 SQLite 连接
+func openSQLiteReadOnly(path string) (*gorm.DB, error) {
+	return openSQLite("file:" + filepath.ToSlash(path) + "?mode=ro")
+}
+
+// closeSQLite 关闭独立打开的连接
+func closeSQLite(conn *gorm.DB) {
+	if sqlDB, err := conn.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
@@ -1,20 +1,33 @@
 package di
 
 import (
+	"sync"
+
 	"github.com/lin-snow/ech0/internal/cache"
+	"github.com/lin-snow/ech0/internal/fediverse"
 	agentHandler "github.com/lin-snow/ech0/internal/handler/agent"
 	backupHandler "github.com/lin-snow/ech0/internal/handler/backup"
+	commentHandler "github.com/lin-snow/ech0/internal/handler/comment"
 	commonHandler "github.com/lin-snow/ech0/internal/handler/common"
 	connectHandler "github.com/lin-snow/ech0/internal/handler/connect"
 	dashboardHandler "github.com/lin-snow/ech0/internal/handler/dashboard"
 	echoHandler "github.com/lin-snow/ech0/internal/handler/echo"
 	fediverseHandler "github.com/lin-snow/ech0/internal/handler/fediverse"
 	inboxHandler "github.com/lin-snow/ech0/internal/handler/inbox"
+	mediaHandler "github.com/lin-snow/ech0/internal/handler/media"
 	pwaHandler "github.com/lin-snow/ech0/internal/handler/pwa"
 	settingHandler "github.com/lin-snow/ech0/internal/handler/setting"
 	todoHandler "github.com/lin-snow/ech0/internal/handler/todo"
+	transferHandler "github.com/lin-snow/ech0/internal/handler/transfer"
 	userHandler "github.com/lin-snow/ech0/internal/handler/user"
 	webHandler "github.com/lin-snow/ech0/internal/handler/web"
+	"github.com/lin-snow/ech0/internal/middleware"
+	echoRepository "github.com/lin-snow/ech0/internal/repository/echo"
+	fediverseRepository "github.com/lin-snow/ech0/internal/repository/fediverse"
+	keyvalueRepository "github.com/lin-snow/ech0/internal/repository/keyvalue"
+	queueRepository "github.com/lin-snow/ech0/internal/repository/queue"
+	userRepository "github.com/lin-snow/ech0/internal/repository/user"
+	settingService "github.com/lin-snow/ech0/internal/service/setting"
 	"github.com/lin-snow/ech0/internal/transaction"
 )
 
@@ -33,6 +46,11 @@ type Handlers struct {
 	DashboardHandler *dashboardHandler.DashboardHandler
 	AgentHandler     *agentHandler.AgentHandler
 	PwaHandler       *pwaHandler.PwaHandler
+	TransferHandler  *transferHandler.TransferHandler
+	MediaHandler     *mediaHandler.MediaHandler
+	CommentHandler   *commentHandler.CommentHandler
+
+	AccessTokenValidator middleware.AccessTokenValidator // 鉴权中间件使用的令牌校验器
 }
 
 // NewHandlers 创建Handlers实例
@@ -50,6 +68,10 @@ func NewHandlers(
 	dashboardHandler *dashboardHandler.DashboardHandler,
 	agentHandler *agentHandler.AgentHandler,
 	pwaHandler *pwaHandler.PwaHandler,
+	transferHandler *transferHandler.TransferHandler,
+	mediaHandler *mediaHandler.MediaHandler,
+	commentHandler *commentHandler.CommentHandler,
+	settingService settingService.SettingServiceInterface,
 ) *Handlers {
 	return &Handlers{
 		WebHandler:       webHandler,
@@ -65,6 +87,11 @@ func NewHandlers(
 		DashboardHandler: dashboardHandler,
 		AgentHandler:     agentHandler,
 		PwaHandler:       pwaHandler,
+		TransferHandler:  transferHandler,
+		MediaHandler:     mediaHandler,
+		CommentHandler:   commentHandler,
+
+		AccessTokenValidator: settingService,
 	}
 }
 
@@ -73,9 +100,43 @@ func ProvideCache(factory *cache.CacheFactory) cache.ICache[string, any] {
 	return factory.Cache()
 }
 
+// ProvideImageCache 提供处理后图片的磁盘缓存实例给 wire 注入
+func ProvideImageCache(factory *cache.CacheFactory) *cache.DiskCache {
+	return factory.ImageCache()
+}
+
 // ProvideTransactionManager 提供事务管理器实例给 wire 注入
 func ProvideTransactionManager(
 	factory *transaction.TransactionManagerFactory,
 ) transaction.TransactionManager {
 	return factory.TransactionManager()
 }
+
+var (
+	sharedFediverseCore     *fediverse.FediverseCore // 全局唯一的联邦宇宙核心，投递队列与域名限流需全局共享
+	sharedFediverseCoreOnce sync.Once
+)
+
+// ProvideFediverseCore 提供全局唯一的 FediverseCore 实例给 wire 注入
+func ProvideFediverseCore(
+	repo fediverseRepository.FediverseRepositoryInterface,
+	keyvalueRepo keyvalueRepository.KeyValueRepositoryInterface,
+	userRepo userRepository.UserRepositoryInterface,
+	echoRepo echoRepository.EchoRepositoryInterface,
+	c cache.ICache[string, any],
+	queueRepo queueRepository.QueueRepositoryInterface,
+	txManager transaction.TransactionManager,
+) *fediverse.FediverseCore {
+	sharedFediverseCoreOnce.Do(func() {
+		sharedFediverseCore = fediverse.NewFediverseCore(
+			repo,
+			keyvalueRepo,
+			userRepo,
+			echoRepo,
+			c,
+			queueRepo,
+			txManager,
+		)
+	})
+	return sharedFediverseCore
+}
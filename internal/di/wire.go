@@ -7,22 +7,25 @@ import (
 	"github.com/google/wire"
 	"github.com/lin-snow/ech0/internal/cache"
 	"github.com/lin-snow/ech0/internal/event"
-	fediverse "github.com/lin-snow/ech0/internal/fediverse"
 	agentHandler "github.com/lin-snow/ech0/internal/handler/agent"
 	backupHandler "github.com/lin-snow/ech0/internal/handler/backup"
+	commentHandler "github.com/lin-snow/ech0/internal/handler/comment"
 	commonHandler "github.com/lin-snow/ech0/internal/handler/common"
 	connectHandler "github.com/lin-snow/ech0/internal/handler/connect"
 	dashboardHandler "github.com/lin-snow/ech0/internal/handler/dashboard"
 	echoHandler "github.com/lin-snow/ech0/internal/handler/echo"
 	fediverseHandler "github.com/lin-snow/ech0/internal/handler/fediverse"
 	inboxHandler "github.com/lin-snow/ech0/internal/handler/inbox"
+	mediaHandler "github.com/lin-snow/ech0/internal/handler/media"
 	pwaHandler "github.com/lin-snow/ech0/internal/handler/pwa"
 	settingHandler "github.com/lin-snow/ech0/internal/handler/setting"
 	todoHandler "github.com/lin-snow/ech0/internal/handler/todo"
+	transferHandler "github.com/lin-snow/ech0/internal/handler/transfer"
 	userHandler "github.com/lin-snow/ech0/internal/handler/user"
 	webHandler "github.com/lin-snow/ech0/internal/handler/web"
 	"github.com/lin-snow/ech0/internal/metric"
 	"github.com/lin-snow/ech0/internal/monitor"
+	commentRepository "github.com/lin-snow/ech0/internal/repository/comment"
 	commonRepository "github.com/lin-snow/ech0/internal/repository/common"
 	connectRepository "github.com/lin-snow/ech0/internal/repository/connect"
 	echoRepository "github.com/lin-snow/ech0/internal/repository/echo"
@@ -37,15 +40,18 @@ import (
 	webhookRepository "github.com/lin-snow/ech0/internal/repository/webhook"
 	agentService "github.com/lin-snow/ech0/internal/service/agent"
 	backupService "github.com/lin-snow/ech0/internal/service/backup"
+	commentService "github.com/lin-snow/ech0/internal/service/comment"
 	commonService "github.com/lin-snow/ech0/internal/service/common"
 	connectService "github.com/lin-snow/ech0/internal/service/connect"
 	dashboardService "github.com/lin-snow/ech0/internal/service/dashboard"
 	echoService "github.com/lin-snow/ech0/internal/service/echo"
 	fediverseService "github.com/lin-snow/ech0/internal/service/fediverse"
 	inboxService "github.com/lin-snow/ech0/internal/service/inbox"
+	mediaService "github.com/lin-snow/ech0/internal/service/media"
 	pwaService "github.com/lin-snow/ech0/internal/service/pwa"
 	settingService "github.com/lin-snow/ech0/internal/service/setting"
 	todoService "github.com/lin-snow/ech0/internal/service/todo"
+	transferService "github.com/lin-snow/ech0/internal/service/transfer"
 	userService "github.com/lin-snow/ech0/internal/service/user"
 	"github.com/lin-snow/ech0/internal/task"
 	"github.com/lin-snow/ech0/internal/transaction"
@@ -67,6 +73,7 @@ func BuildHandlers(
 		EchoSet,
 		CommonSet,
 		WebhookSet,
+		QueueSet,
 		KeyValueSet,
 		SettingSet,
 		InboxSet,
@@ -80,6 +87,9 @@ func BuildHandlers(
 		FediverseCoreSet,
 		FediverseSet,
 		PwaSet,
+		TransferSet,
+		MediaSet,
+		CommentSet,
 		NewHandlers, // NewHandlers 聚合各个模块的 Handler
 	)
 
@@ -105,6 +115,11 @@ func BuildTasker(
 		InboxSet,
 		TodoSet,
 		ConnectSet,
+		BackupSet,
+		UserSet,
+		FediverseCoreSet,
+		FediverseSet,
+		CommentSet,
 		TaskSet,
 	)
 	return &task.Tasker{}, nil
@@ -134,9 +149,35 @@ func BuildEventRegistrar(
 	return &event.EventRegistrar{}, nil
 }
 
+// BuildTransferService 构建数据迁移服务，供命令行导入使用
+func BuildTransferService(
+	dbProvider func() *gorm.DB,
+	cacheFactory *cache.CacheFactory,
+	tmFactory *transaction.TransactionManagerFactory,
+	ebProvider func() event.IEventBus,
+) (transferService.TransferServiceInterface, error) {
+	wire.Build(
+		CacheSet,
+		TransactionManagerSet,
+		KeyValueSet,
+		UserSet,
+		EchoSet,
+		CommonSet,
+		InboxSet,
+		QueueSet,
+		FediverseCoreSet,
+		FediverseSet,
+		CommentSet,
+		transferService.NewTransferService,
+	)
+
+	return nil, nil
+}
+
 // CacheSet 包含了构建缓存所需的所有 Provider
 var CacheSet = wire.NewSet(
 	ProvideCache,
+	ProvideImageCache,
 )
 
 // TransactionManagerSet 包含了构建事务管理器所需的所有 Provider
@@ -170,6 +211,13 @@ var CommonSet = wire.NewSet(
 	commonHandler.NewCommonHandler,
 )
 
+// CommentSet 包含了构建 CommentHandler 所需的所有 Provider
+var CommentSet = wire.NewSet(
+	commentRepository.NewCommentRepository,
+	commentService.NewCommentService,
+	commentHandler.NewCommentHandler,
+)
+
 // KeyValueSet 包含了构建 KeyValueRepository 所需的所有 Provider
 var KeyValueSet = wire.NewSet(
 	keyvalueRepository.NewKeyValueRepository,
@@ -217,6 +265,7 @@ var AgentSet = wire.NewSet(
 // WebhookSet 包含了构建 WebhookDispatcher 所需的所有 Provider
 var WebhookSet = wire.NewSet(
 	webhookRepository.NewWebhookRepository,
+	event.NewWebhookDispatcher,
 )
 
 // InboxSet 包含了构建 InboxRepository 所需的所有 Provider
@@ -238,7 +287,7 @@ var QueueSet = wire.NewSet(
 
 // FediverseCoreSet 包含了构建 FediverseCore 所需的所有 Provider
 var FediverseCoreSet = wire.NewSet(
-	fediverse.NewFediverseCore,
+	ProvideFediverseCore,
 )
 
 // FediverseSet 包含了构建 Fediverse 所需的所有 Provider
@@ -252,11 +301,11 @@ var FediverseSet = wire.NewSet(
 
 // EventSet 包含了构建 Event 相关所需的所有 Provider
 var EventSet = wire.NewSet(
-	event.NewWebhookDispatcher,
 	event.NewBackupScheduler,
 	event.NewDeadLetterResolver,
 	event.NewAgentProcessor,
 	event.NewInboxDispatcher,
+	event.NewMediaProcessor,
 	event.NewEventHandlers,
 	event.NewEventRegistry,
 )
@@ -277,3 +326,15 @@ var PwaSet = wire.NewSet(
 	pwaService.NewPwaService,
 	pwaHandler.NewPwaHandler,
 )
+
+// TransferSet 包含了构建 TransferHandler 所需的所有 Provider
+var TransferSet = wire.NewSet(
+	transferService.NewTransferService,
+	transferHandler.NewTransferHandler,
+)
+
+// MediaSet 包含了构建 MediaHandler 所需的所有 Provider
+var MediaSet = wire.NewSet(
+	mediaService.NewMediaService,
+	mediaHandler.NewMediaHandler,
+)
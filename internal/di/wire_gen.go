@@ -10,46 +10,52 @@ import (
 	"github.com/google/wire"
 	"github.com/lin-snow/ech0/internal/cache"
 	"github.com/lin-snow/ech0/internal/event"
-	"github.com/lin-snow/ech0/internal/fediverse"
 	handler12 "github.com/lin-snow/ech0/internal/handler/agent"
 	handler9 "github.com/lin-snow/ech0/internal/handler/backup"
+	handler16 "github.com/lin-snow/ech0/internal/handler/comment"
 	handler4 "github.com/lin-snow/ech0/internal/handler/common"
 	handler8 "github.com/lin-snow/ech0/internal/handler/connect"
 	handler11 "github.com/lin-snow/ech0/internal/handler/dashboard"
 	handler3 "github.com/lin-snow/ech0/internal/handler/echo"
 	handler10 "github.com/lin-snow/ech0/internal/handler/fediverse"
 	handler6 "github.com/lin-snow/ech0/internal/handler/inbox"
+	handler15 "github.com/lin-snow/ech0/internal/handler/media"
 	handler13 "github.com/lin-snow/ech0/internal/handler/pwa"
 	handler5 "github.com/lin-snow/ech0/internal/handler/setting"
 	handler7 "github.com/lin-snow/ech0/internal/handler/todo"
+	handler14 "github.com/lin-snow/ech0/internal/handler/transfer"
 	handler2 "github.com/lin-snow/ech0/internal/handler/user"
 	"github.com/lin-snow/ech0/internal/handler/web"
 	"github.com/lin-snow/ech0/internal/metric"
 	"github.com/lin-snow/ech0/internal/monitor"
+	repository8 "github.com/lin-snow/ech0/internal/repository/comment"
 	"github.com/lin-snow/ech0/internal/repository/common"
-	repository9 "github.com/lin-snow/ech0/internal/repository/connect"
+	repository11 "github.com/lin-snow/ech0/internal/repository/connect"
 	repository2 "github.com/lin-snow/ech0/internal/repository/echo"
-	repository5 "github.com/lin-snow/ech0/internal/repository/fediverse"
-	repository7 "github.com/lin-snow/ech0/internal/repository/inbox"
+	repository6 "github.com/lin-snow/ech0/internal/repository/fediverse"
+	repository9 "github.com/lin-snow/ech0/internal/repository/inbox"
 	"github.com/lin-snow/ech0/internal/repository/keyvalue"
-	repository10 "github.com/lin-snow/ech0/internal/repository/pwa"
-	repository11 "github.com/lin-snow/ech0/internal/repository/queue"
+	repository12 "github.com/lin-snow/ech0/internal/repository/pwa"
+	repository5 "github.com/lin-snow/ech0/internal/repository/queue"
 	repository3 "github.com/lin-snow/ech0/internal/repository/setting"
-	repository8 "github.com/lin-snow/ech0/internal/repository/todo"
-	repository6 "github.com/lin-snow/ech0/internal/repository/user"
+	repository10 "github.com/lin-snow/ech0/internal/repository/todo"
+	repository7 "github.com/lin-snow/ech0/internal/repository/user"
 	repository4 "github.com/lin-snow/ech0/internal/repository/webhook"
-	service11 "github.com/lin-snow/ech0/internal/service/agent"
-	service9 "github.com/lin-snow/ech0/internal/service/backup"
+	service12 "github.com/lin-snow/ech0/internal/service/agent"
+	service10 "github.com/lin-snow/ech0/internal/service/backup"
+	service3 "github.com/lin-snow/ech0/internal/service/comment"
 	"github.com/lin-snow/ech0/internal/service/common"
-	service8 "github.com/lin-snow/ech0/internal/service/connect"
-	service10 "github.com/lin-snow/ech0/internal/service/dashboard"
-	service4 "github.com/lin-snow/ech0/internal/service/echo"
-	service3 "github.com/lin-snow/ech0/internal/service/fediverse"
-	service6 "github.com/lin-snow/ech0/internal/service/inbox"
-	service12 "github.com/lin-snow/ech0/internal/service/pwa"
+	service9 "github.com/lin-snow/ech0/internal/service/connect"
+	service11 "github.com/lin-snow/ech0/internal/service/dashboard"
+	service5 "github.com/lin-snow/ech0/internal/service/echo"
+	service4 "github.com/lin-snow/ech0/internal/service/fediverse"
+	service7 "github.com/lin-snow/ech0/internal/service/inbox"
+	service15 "github.com/lin-snow/ech0/internal/service/media"
+	service13 "github.com/lin-snow/ech0/internal/service/pwa"
 	service2 "github.com/lin-snow/ech0/internal/service/setting"
-	service7 "github.com/lin-snow/ech0/internal/service/todo"
-	service5 "github.com/lin-snow/ech0/internal/service/user"
+	service8 "github.com/lin-snow/ech0/internal/service/todo"
+	service14 "github.com/lin-snow/ech0/internal/service/transfer"
+	service6 "github.com/lin-snow/ech0/internal/service/user"
 	"github.com/lin-snow/ech0/internal/task"
 	"github.com/lin-snow/ech0/internal/transaction"
 	"gorm.io/gorm"
@@ -65,42 +71,52 @@ func BuildHandlers(dbProvider func() *gorm.DB, cacheFactory *cache.CacheFactory,
 	echoRepositoryInterface := repository2.NewEchoRepository(dbProvider, iCache)
 	keyValueRepositoryInterface := keyvalue.NewKeyValueRepository(dbProvider, iCache)
 	commonServiceInterface := service.NewCommonService(transactionManager, commonRepositoryInterface, echoRepositoryInterface, keyValueRepositoryInterface, ebProvider)
-	settingRepositoryInterface := repository3.NewSettingRepository(dbProvider)
+	settingRepositoryInterface := repository3.NewSettingRepository(dbProvider, iCache)
 	webhookRepositoryInterface := repository4.NewWebhookRepository(dbProvider)
-	settingServiceInterface := service2.NewSettingService(transactionManager, commonServiceInterface, keyValueRepositoryInterface, settingRepositoryInterface, webhookRepositoryInterface, ebProvider)
-	fediverseRepositoryInterface := repository5.NewFediverseRepository(dbProvider)
-	userRepositoryInterface := repository6.NewUserRepository(dbProvider, iCache)
-	fediverseCore := fediverse.NewFediverseCore(fediverseRepositoryInterface, keyValueRepositoryInterface, userRepositoryInterface, echoRepositoryInterface)
-	fediverseServiceInterface := service3.NewFediverseService(fediverseCore, transactionManager, fediverseRepositoryInterface, userRepositoryInterface, echoRepositoryInterface)
-	echoServiceInterface := service4.NewEchoService(transactionManager, commonServiceInterface, echoRepositoryInterface, commonRepositoryInterface, fediverseServiceInterface, keyValueRepositoryInterface, ebProvider)
-	webHandler := handler.NewWebHandler(settingServiceInterface, echoServiceInterface)
-	userServiceInterface := service5.NewUserService(transactionManager, userRepositoryInterface, settingServiceInterface, ebProvider)
+	queueRepositoryInterface := repository5.NewQueueRepository(dbProvider)
+	webhookDispatcher := event.NewWebhookDispatcher(ebProvider, webhookRepositoryInterface, queueRepositoryInterface, transactionManager)
+	settingServiceInterface := service2.NewSettingService(transactionManager, commonServiceInterface, keyValueRepositoryInterface, settingRepositoryInterface, webhookRepositoryInterface, webhookDispatcher, ebProvider)
+	fediverseRepositoryInterface := repository6.NewFediverseRepository(dbProvider)
+	userRepositoryInterface := repository7.NewUserRepository(dbProvider, iCache)
+	fediverseCore := ProvideFediverseCore(fediverseRepositoryInterface, keyValueRepositoryInterface, userRepositoryInterface, echoRepositoryInterface, iCache, queueRepositoryInterface, transactionManager)
+	commentRepositoryInterface := repository8.NewCommentRepository(dbProvider)
+	commentServiceInterface := service3.NewCommentService(transactionManager, commentRepositoryInterface, echoRepositoryInterface, keyValueRepositoryInterface, commonServiceInterface, ebProvider)
+	fediverseServiceInterface := service4.NewFediverseService(fediverseCore, transactionManager, fediverseRepositoryInterface, userRepositoryInterface, echoRepositoryInterface, commentServiceInterface)
+	echoServiceInterface := service5.NewEchoService(transactionManager, commonServiceInterface, echoRepositoryInterface, commonRepositoryInterface, fediverseServiceInterface, keyValueRepositoryInterface, ebProvider)
+	diskCache := ProvideImageCache(cacheFactory)
+	webHandler := handler.NewWebHandler(settingServiceInterface, echoServiceInterface, diskCache)
+	userServiceInterface := service6.NewUserService(transactionManager, userRepositoryInterface, settingServiceInterface, ebProvider)
 	userHandler := handler2.NewUserHandler(userServiceInterface)
 	echoHandler := handler3.NewEchoHandler(echoServiceInterface)
 	commonHandler := handler4.NewCommonHandler(commonServiceInterface)
 	settingHandler := handler5.NewSettingHandler(settingServiceInterface)
-	inboxRepositoryInterface := repository7.NewInboxRepository(dbProvider)
-	inboxServiceInterface := service6.NewInboxService(transactionManager, commonServiceInterface, inboxRepositoryInterface)
+	inboxRepositoryInterface := repository9.NewInboxRepository(dbProvider)
+	inboxServiceInterface := service7.NewInboxService(transactionManager, commonServiceInterface, inboxRepositoryInterface)
 	inboxHandler := handler6.NewInboxHandler(inboxServiceInterface)
-	todoRepositoryInterface := repository8.NewTodoRepository(dbProvider, iCache)
-	todoServiceInterface := service7.NewTodoService(transactionManager, todoRepositoryInterface, commonServiceInterface)
+	todoRepositoryInterface := repository10.NewTodoRepository(dbProvider, iCache)
+	todoServiceInterface := service8.NewTodoService(transactionManager, todoRepositoryInterface, commonServiceInterface)
 	todoHandler := handler7.NewTodoHandler(todoServiceInterface)
-	connectRepositoryInterface := repository9.NewConnectRepository(dbProvider)
-	connectServiceInterface := service8.NewConnectService(transactionManager, connectRepositoryInterface, echoRepositoryInterface, commonServiceInterface, settingServiceInterface)
+	connectRepositoryInterface := repository11.NewConnectRepository(dbProvider)
+	connectServiceInterface := service9.NewConnectService(transactionManager, connectRepositoryInterface, echoRepositoryInterface, commonServiceInterface, settingServiceInterface)
 	connectHandler := handler8.NewConnectHandler(connectServiceInterface)
-	backupServiceInterface := service9.NewBackupService(commonServiceInterface, ebProvider)
-	backupHandler := handler9.NewBackupHandler(backupServiceInterface)
+	backupServiceInterface := service10.NewBackupService(commonServiceInterface, settingServiceInterface, ebProvider)
+	backupHandler := handler9.NewBackupHandler(backupServiceInterface, settingServiceInterface)
 	fediverseHandler := handler10.NewFediverseHandler(fediverseServiceInterface)
 	metricCollector := metric.NewSystemCollector()
 	monitorMonitor := monitor.NewMonitor(metricCollector)
-	dashboardServiceInterface := service10.NewDashboardService(monitorMonitor, commonServiceInterface)
-	dashboardHandler := handler11.NewDashboardHandler(dashboardServiceInterface)
-	agentServiceInterface := service11.NewAgentService(settingServiceInterface, echoServiceInterface, todoServiceInterface, keyValueRepositoryInterface)
+	dashboardServiceInterface := service11.NewDashboardService(monitorMonitor, commonServiceInterface, diskCache)
+	dashboardHandler := handler11.NewDashboardHandler(dashboardServiceInterface, settingServiceInterface)
+	agentServiceInterface := service12.NewAgentService(settingServiceInterface, echoServiceInterface, todoServiceInterface, keyValueRepositoryInterface)
 	agentHandler := handler12.NewAgentHandler(agentServiceInterface)
-	pwaRepositoryInterface := repository10.NewPwaRepository(dbProvider)
-	pwaServiceInterface := service12.NewPwaService(pwaRepositoryInterface, keyValueRepositoryInterface, inboxServiceInterface, todoServiceInterface, connectServiceInterface)
+	pwaRepositoryInterface := repository12.NewPwaRepository(dbProvider)
+	pwaServiceInterface := service13.NewPwaService(pwaRepositoryInterface, keyValueRepositoryInterface, inboxServiceInterface, todoServiceInterface, connectServiceInterface)
 	pwaHandler := handler13.NewPwaHandler(pwaServiceInterface)
-	handlers := NewHandlers(webHandler, userHandler, echoHandler, commonHandler, settingHandler, inboxHandler, todoHandler, connectHandler, backupHandler, fediverseHandler, dashboardHandler, agentHandler, pwaHandler)
+	transferServiceInterface := service14.NewTransferService(transactionManager, commonServiceInterface, echoServiceInterface, echoRepositoryInterface, userRepositoryInterface, inboxRepositoryInterface)
+	transferHandler := handler14.NewTransferHandler(transferServiceInterface)
+	mediaServiceInterface := service15.NewMediaService(commonServiceInterface, settingServiceInterface, echoRepositoryInterface, diskCache)
+	mediaHandler := handler15.NewMediaHandler(mediaServiceInterface)
+	commentHandler := handler16.NewCommentHandler(commentServiceInterface)
+	handlers := NewHandlers(webHandler, userHandler, echoHandler, commonHandler, settingHandler, inboxHandler, todoHandler, connectHandler, backupHandler, fediverseHandler, dashboardHandler, agentHandler, pwaHandler, transferHandler, mediaHandler, commentHandler, settingServiceInterface)
 	return handlers, nil
 }
 
@@ -111,50 +127,83 @@ func BuildTasker(dbProvider func() *gorm.DB, cacheFactory *cache.CacheFactory, t
 	echoRepositoryInterface := repository2.NewEchoRepository(dbProvider, iCache)
 	keyValueRepositoryInterface := keyvalue.NewKeyValueRepository(dbProvider, iCache)
 	commonServiceInterface := service.NewCommonService(transactionManager, commonRepositoryInterface, echoRepositoryInterface, keyValueRepositoryInterface, ebProvider)
-	settingRepositoryInterface := repository3.NewSettingRepository(dbProvider)
+	fediverseRepositoryInterface := repository6.NewFediverseRepository(dbProvider)
+	userRepositoryInterface := repository7.NewUserRepository(dbProvider, iCache)
+	queueRepositoryInterface := repository5.NewQueueRepository(dbProvider)
+	fediverseCore := ProvideFediverseCore(fediverseRepositoryInterface, keyValueRepositoryInterface, userRepositoryInterface, echoRepositoryInterface, iCache, queueRepositoryInterface, transactionManager)
+	commentRepositoryInterface := repository8.NewCommentRepository(dbProvider)
+	commentServiceInterface := service3.NewCommentService(transactionManager, commentRepositoryInterface, echoRepositoryInterface, keyValueRepositoryInterface, commonServiceInterface, ebProvider)
+	fediverseServiceInterface := service4.NewFediverseService(fediverseCore, transactionManager, fediverseRepositoryInterface, userRepositoryInterface, echoRepositoryInterface, commentServiceInterface)
+	echoServiceInterface := service5.NewEchoService(transactionManager, commonServiceInterface, echoRepositoryInterface, commonRepositoryInterface, fediverseServiceInterface, keyValueRepositoryInterface, ebProvider)
+	settingRepositoryInterface := repository3.NewSettingRepository(dbProvider, iCache)
 	webhookRepositoryInterface := repository4.NewWebhookRepository(dbProvider)
-	settingServiceInterface := service2.NewSettingService(transactionManager, commonServiceInterface, keyValueRepositoryInterface, settingRepositoryInterface, webhookRepositoryInterface, ebProvider)
-	queueRepositoryInterface := repository11.NewQueueRepository(dbProvider)
-	pwaRepositoryInterface := repository10.NewPwaRepository(dbProvider)
-	inboxRepositoryInterface := repository7.NewInboxRepository(dbProvider)
-	inboxServiceInterface := service6.NewInboxService(transactionManager, commonServiceInterface, inboxRepositoryInterface)
-	todoRepositoryInterface := repository8.NewTodoRepository(dbProvider, iCache)
-	todoServiceInterface := service7.NewTodoService(transactionManager, todoRepositoryInterface, commonServiceInterface)
-	connectRepositoryInterface := repository9.NewConnectRepository(dbProvider)
-	connectServiceInterface := service8.NewConnectService(transactionManager, connectRepositoryInterface, echoRepositoryInterface, commonServiceInterface, settingServiceInterface)
-	pwaServiceInterface := service12.NewPwaService(pwaRepositoryInterface, keyValueRepositoryInterface, inboxServiceInterface, todoServiceInterface, connectServiceInterface)
-	tasker := task.NewTasker(commonServiceInterface, settingServiceInterface, ebProvider, queueRepositoryInterface, pwaServiceInterface)
+	webhookDispatcher := event.NewWebhookDispatcher(ebProvider, webhookRepositoryInterface, queueRepositoryInterface, transactionManager)
+	settingServiceInterface := service2.NewSettingService(transactionManager, commonServiceInterface, keyValueRepositoryInterface, settingRepositoryInterface, webhookRepositoryInterface, webhookDispatcher, ebProvider)
+	pwaRepositoryInterface := repository12.NewPwaRepository(dbProvider)
+	inboxRepositoryInterface := repository9.NewInboxRepository(dbProvider)
+	inboxServiceInterface := service7.NewInboxService(transactionManager, commonServiceInterface, inboxRepositoryInterface)
+	todoRepositoryInterface := repository10.NewTodoRepository(dbProvider, iCache)
+	todoServiceInterface := service8.NewTodoService(transactionManager, todoRepositoryInterface, commonServiceInterface)
+	connectRepositoryInterface := repository11.NewConnectRepository(dbProvider)
+	connectServiceInterface := service9.NewConnectService(transactionManager, connectRepositoryInterface, echoRepositoryInterface, commonServiceInterface, settingServiceInterface)
+	pwaServiceInterface := service13.NewPwaService(pwaRepositoryInterface, keyValueRepositoryInterface, inboxServiceInterface, todoServiceInterface, connectServiceInterface)
+	backupServiceInterface := service10.NewBackupService(commonServiceInterface, settingServiceInterface, ebProvider)
+	diskCache := ProvideImageCache(cacheFactory)
+	tasker := task.NewTasker(commonServiceInterface, echoServiceInterface, settingServiceInterface, ebProvider, queueRepositoryInterface, pwaServiceInterface, backupServiceInterface, diskCache)
 	return tasker, nil
 }
 
 func BuildEventRegistrar(dbProvider func() *gorm.DB, ebProvider func() event.IEventBus, cacheFactory *cache.CacheFactory, tmFactory *transaction.TransactionManagerFactory) (*event.EventRegistrar, error) {
 	webhookRepositoryInterface := repository4.NewWebhookRepository(dbProvider)
-	queueRepositoryInterface := repository11.NewQueueRepository(dbProvider)
+	queueRepositoryInterface := repository5.NewQueueRepository(dbProvider)
 	transactionManager := ProvideTransactionManager(tmFactory)
 	webhookDispatcher := event.NewWebhookDispatcher(ebProvider, webhookRepositoryInterface, queueRepositoryInterface, transactionManager)
-	fediverseRepositoryInterface := repository5.NewFediverseRepository(dbProvider)
+	fediverseRepositoryInterface := repository6.NewFediverseRepository(dbProvider)
 	iCache := ProvideCache(cacheFactory)
 	keyValueRepositoryInterface := keyvalue.NewKeyValueRepository(dbProvider, iCache)
-	userRepositoryInterface := repository6.NewUserRepository(dbProvider, iCache)
+	userRepositoryInterface := repository7.NewUserRepository(dbProvider, iCache)
 	echoRepositoryInterface := repository2.NewEchoRepository(dbProvider, iCache)
-	fediverseCore := fediverse.NewFediverseCore(fediverseRepositoryInterface, keyValueRepositoryInterface, userRepositoryInterface, echoRepositoryInterface)
+	fediverseCore := ProvideFediverseCore(fediverseRepositoryInterface, keyValueRepositoryInterface, userRepositoryInterface, echoRepositoryInterface, iCache, queueRepositoryInterface, transactionManager)
 	fediverseAgent := event.NewFediverseAgent(fediverseCore, queueRepositoryInterface, transactionManager)
 	deadLetterResolver := event.NewDeadLetterResolver(queueRepositoryInterface, webhookDispatcher, fediverseAgent)
 	backupScheduler := event.NewBackupScheduler()
-	todoRepositoryInterface := repository8.NewTodoRepository(dbProvider, iCache)
-	inboxRepositoryInterface := repository7.NewInboxRepository(dbProvider)
+	todoRepositoryInterface := repository10.NewTodoRepository(dbProvider, iCache)
+	inboxRepositoryInterface := repository9.NewInboxRepository(dbProvider)
 	agentProcessor := event.NewAgentProcessor(echoRepositoryInterface, todoRepositoryInterface, userRepositoryInterface, keyValueRepositoryInterface, inboxRepositoryInterface)
 	inboxDispatcher := event.NewInboxDispatcher(inboxRepositoryInterface, keyValueRepositoryInterface)
-	eventHandlers := event.NewEventHandlers(webhookDispatcher, deadLetterResolver, fediverseAgent, backupScheduler, agentProcessor, inboxDispatcher)
+	mediaProcessor := event.NewMediaProcessor(echoRepositoryInterface)
+	eventHandlers := event.NewEventHandlers(webhookDispatcher, deadLetterResolver, fediverseAgent, backupScheduler, agentProcessor, inboxDispatcher, mediaProcessor)
 	eventRegistrar := event.NewEventRegistry(ebProvider, eventHandlers)
 	return eventRegistrar, nil
 }
 
+// BuildTransferService 构建数据迁移服务，供命令行导入使用
+func BuildTransferService(dbProvider func() *gorm.DB, cacheFactory *cache.CacheFactory, tmFactory *transaction.TransactionManagerFactory, ebProvider func() event.IEventBus) (service14.TransferServiceInterface, error) {
+	transactionManager := ProvideTransactionManager(tmFactory)
+	commonRepositoryInterface := repository.NewCommonRepository(dbProvider)
+	iCache := ProvideCache(cacheFactory)
+	echoRepositoryInterface := repository2.NewEchoRepository(dbProvider, iCache)
+	keyValueRepositoryInterface := keyvalue.NewKeyValueRepository(dbProvider, iCache)
+	commonServiceInterface := service.NewCommonService(transactionManager, commonRepositoryInterface, echoRepositoryInterface, keyValueRepositoryInterface, ebProvider)
+	fediverseRepositoryInterface := repository6.NewFediverseRepository(dbProvider)
+	userRepositoryInterface := repository7.NewUserRepository(dbProvider, iCache)
+	queueRepositoryInterface := repository5.NewQueueRepository(dbProvider)
+	fediverseCore := ProvideFediverseCore(fediverseRepositoryInterface, keyValueRepositoryInterface, userRepositoryInterface, echoRepositoryInterface, iCache, queueRepositoryInterface, transactionManager)
+	commentRepositoryInterface := repository8.NewCommentRepository(dbProvider)
+	commentServiceInterface := service3.NewCommentService(transactionManager, commentRepositoryInterface, echoRepositoryInterface, keyValueRepositoryInterface, commonServiceInterface, ebProvider)
+	fediverseServiceInterface := service4.NewFediverseService(fediverseCore, transactionManager, fediverseRepositoryInterface, userRepositoryInterface, echoRepositoryInterface, commentServiceInterface)
+	echoServiceInterface := service5.NewEchoService(transactionManager, commonServiceInterface, echoRepositoryInterface, commonRepositoryInterface, fediverseServiceInterface, keyValueRepositoryInterface, ebProvider)
+	inboxRepositoryInterface := repository9.NewInboxRepository(dbProvider)
+	transferServiceInterface := service14.NewTransferService(transactionManager, commonServiceInterface, echoServiceInterface, echoRepositoryInterface, userRepositoryInterface, inboxRepositoryInterface)
+	return transferServiceInterface, nil
+}
+
 // wire.go:
 
 // CacheSet 包含了构建缓存所需的所有 Provider
 var CacheSet = wire.NewSet(
 	ProvideCache,
+	ProvideImageCache,
 )
 
 // TransactionManagerSet 包含了构建事务管理器所需的所有 Provider
@@ -166,14 +215,17 @@ var TransactionManagerSet = wire.NewSet(
 var WebSet = wire.NewSet(handler.NewWebHandler)
 
 // UserSet 包含了构建 UserHandler 所需的所有 Provider
-var UserSet = wire.NewSet(repository6.NewUserRepository, service5.NewUserService, handler2.NewUserHandler)
+var UserSet = wire.NewSet(repository7.NewUserRepository, service6.NewUserService, handler2.NewUserHandler)
 
 // EchoSet 包含了构建 EchoHandler 所需的所有 Provider
-var EchoSet = wire.NewSet(repository2.NewEchoRepository, service4.NewEchoService, handler3.NewEchoHandler)
+var EchoSet = wire.NewSet(repository2.NewEchoRepository, service5.NewEchoService, handler3.NewEchoHandler)
 
 // CommonSet 包含了构建 CommonHandler 所需的所有 Provider
 var CommonSet = wire.NewSet(repository.NewCommonRepository, service.NewCommonService, handler4.NewCommonHandler)
 
+// CommentSet 包含了构建 CommentHandler 所需的所有 Provider
+var CommentSet = wire.NewSet(repository8.NewCommentRepository, service3.NewCommentService, handler16.NewCommentHandler)
+
 // KeyValueSet 包含了构建 KeyValueRepository 所需的所有 Provider
 var KeyValueSet = wire.NewSet(keyvalue.NewKeyValueRepository)
 
@@ -181,40 +233,42 @@ var KeyValueSet = wire.NewSet(keyvalue.NewKeyValueRepository)
 var SettingSet = wire.NewSet(repository3.NewSettingRepository, service2.NewSettingService, handler5.NewSettingHandler)
 
 // TodoSet 包含了构建 TodoHandler 所需的所有 Provider
-var TodoSet = wire.NewSet(repository8.NewTodoRepository, service7.NewTodoService, handler7.NewTodoHandler)
+var TodoSet = wire.NewSet(repository10.NewTodoRepository, service8.NewTodoService, handler7.NewTodoHandler)
 
 // ConnectSet 包含了构建 ConnectHandler 所需的所有 Provider
-var ConnectSet = wire.NewSet(repository9.NewConnectRepository, service8.NewConnectService, handler8.NewConnectHandler)
+var ConnectSet = wire.NewSet(repository11.NewConnectRepository, service9.NewConnectService, handler8.NewConnectHandler)
 
 // BackupSet 包含了构建 BackupHandler 所需的所有 Provider
-var BackupSet = wire.NewSet(handler9.NewBackupHandler, service9.NewBackupService)
+var BackupSet = wire.NewSet(handler9.NewBackupHandler, service10.NewBackupService)
 
 // DashboardSet 包含了构建 DashboardHandler 所需的所有 Provider
-var DashboardSet = wire.NewSet(service10.NewDashboardService, handler11.NewDashboardHandler)
+var DashboardSet = wire.NewSet(service11.NewDashboardService, handler11.NewDashboardHandler)
 
 // AgentSet 包含了构建 AgentHandler 所需的所有 Provider
-var AgentSet = wire.NewSet(service11.NewAgentService, handler12.NewAgentHandler)
+var AgentSet = wire.NewSet(service12.NewAgentService, handler12.NewAgentHandler)
 
 // WebhookSet 包含了构建 WebhookDispatcher 所需的所有 Provider
-var WebhookSet = wire.NewSet(repository4.NewWebhookRepository)
+var WebhookSet = wire.NewSet(repository4.NewWebhookRepository, event.NewWebhookDispatcher)
 
 // InboxSet 包含了构建 InboxRepository 所需的所有 Provider
-var InboxSet = wire.NewSet(repository7.NewInboxRepository, service6.NewInboxService, handler6.NewInboxHandler)
+var InboxSet = wire.NewSet(repository9.NewInboxRepository, service7.NewInboxService, handler6.NewInboxHandler)
 
 // TaskSet 包含了构建 Tasker 所需的所有 Provider
 var TaskSet = wire.NewSet(task.NewTasker)
 
 // QueueSet 包含了构建 Queue 所需的所有 Provider
-var QueueSet = wire.NewSet(repository11.NewQueueRepository)
+var QueueSet = wire.NewSet(repository5.NewQueueRepository)
 
 // FediverseCoreSet 包含了构建 FediverseCore 所需的所有 Provider
-var FediverseCoreSet = wire.NewSet(fediverse.NewFediverseCore)
+var FediverseCoreSet = wire.NewSet(
+	ProvideFediverseCore,
+)
 
 // FediverseSet 包含了构建 Fediverse 所需的所有 Provider
-var FediverseSet = wire.NewSet(repository5.NewFediverseRepository, service3.NewFediverseService, handler10.NewFediverseHandler, event.NewFediverseAgent)
+var FediverseSet = wire.NewSet(repository6.NewFediverseRepository, service4.NewFediverseService, handler10.NewFediverseHandler, event.NewFediverseAgent)
 
 // EventSet 包含了构建 Event 相关所需的所有 Provider
-var EventSet = wire.NewSet(event.NewWebhookDispatcher, event.NewBackupScheduler, event.NewDeadLetterResolver, event.NewAgentProcessor, event.NewInboxDispatcher, event.NewEventHandlers, event.NewEventRegistry)
+var EventSet = wire.NewSet(event.NewBackupScheduler, event.NewDeadLetterResolver, event.NewAgentProcessor, event.NewInboxDispatcher, event.NewMediaProcessor, event.NewEventHandlers, event.NewEventRegistry)
 
 // MetricSet 包含了构建 Metric 相关所需的所有 Provider
 var MetricSet = wire.NewSet(metric.NewSystemCollector)
@@ -223,4 +277,10 @@ var MetricSet = wire.NewSet(metric.NewSystemCollector)
 var MonitorSet = wire.NewSet(monitor.NewMonitor)
 
 // PwaSet 包含了构建 Pwa 相关所需的所有 Provider
-var PwaSet = wire.NewSet(repository10.NewPwaRepository, service12.NewPwaService, handler13.NewPwaHandler)
+var PwaSet = wire.NewSet(repository12.NewPwaRepository, service13.NewPwaService, handler13.NewPwaHandler)
+
+// TransferSet 包含了构建 TransferHandler 所需的所有 Provider
+var TransferSet = wire.NewSet(service14.NewTransferService, handler14.NewTransferHandler)
+
+// MediaSet 包含了构建 MediaHandler 所需的所有 Provider
+var MediaSet = wire.NewSet(service15.NewMediaService, handler15.NewMediaHandler)
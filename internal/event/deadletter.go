@@ -42,7 +42,7 @@ func (dlr *DeadLetterResolver) Handle(ctx context.Context, event *Event) error {
 		deadLetter.Status = queueModel.DeadLetterStatusProcessing
 		deadLetter.RetryCount += 1
 		deadLetter.UpdatedAt = time.Now().UTC()
-		deadLetter.NextRetry = time.Now().UTC().Add(6 * time.Hour)
+		deadLetter.NextRetry = time.Now().UTC().Add(deadLetterRetryDelay(&deadLetter))
 
 		if err := dlr.queueRepo.UpdateDeadLetter(ctx, &deadLetter); err != nil {
 			return fmt.Errorf("failed to update dead letter to processing: %v", err)
@@ -131,7 +131,19 @@ func (dlr *DeadLetterResolver) processDeadLetter(
 		// 处理 push echo federiverse 类型的死信任务
 		return dlr.fa.HandlePushEchoDeadLetter(ctx, deadLetter)
 
+	case queueModel.DeadLetterTypeFediverseDelivery:
+		// 处理单个收件箱投递失败的死信任务
+		return dlr.fa.HandleDeliveryDeadLetter(ctx, deadLetter)
+
 	default:
 		return fmt.Errorf("unknown dead letter type: %s", deadLetter.Type)
 	}
 }
+
+// deadLetterRetryDelay 计算死信任务的下次重试间隔，联邦投递按重试次数指数退避
+func deadLetterRetryDelay(deadLetter *queueModel.DeadLetter) time.Duration {
+	if deadLetter.Type == queueModel.DeadLetterTypeFediverseDelivery {
+		return 30 * time.Minute << min(deadLetter.RetryCount, 6)
+	}
+	return 6 * time.Hour
+}
@@ -4,19 +4,23 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
 	"github.com/lin-snow/ech0/internal/async"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
 	queueModel "github.com/lin-snow/ech0/internal/model/queue"
 	webhookModel "github.com/lin-snow/ech0/internal/model/webhook"
 	queueRepository "github.com/lin-snow/ech0/internal/repository/queue"
 	webhookRepository "github.com/lin-snow/ech0/internal/repository/webhook"
 	"github.com/lin-snow/ech0/internal/transaction"
 	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	webhookSig "github.com/lin-snow/ech0/pkg/webhook"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type WebhookReplayPayload struct {
@@ -64,14 +68,14 @@ func (wd *WebhookDispatcher) Handle(ctx context.Context, e *Event) error {
 		return err
 	}
 	for _, wh := range webhooks {
-		// 可以根据 event type 做过滤
-		// if !wh.ShouldHandle(e.Type) {
-		// 	continue
-		// }
+		// 根据订阅的事件类型与负载过滤条件筛选
+		if !wh.ShouldHandle(string(e.Type)) || !matchWebhookFilter(&wh, e) {
+			continue
+		}
 		wh := wh // 捕获变量
 		// 提交任务到池中异步处理
 		wd.pool.Submit(func() error {
-			wd.Dispatch(ctx, &wh, e)
+			_ = wd.Dispatch(ctx, &wh, e)
 			return nil
 		})
 	}
@@ -79,32 +83,70 @@ func (wd *WebhookDispatcher) Handle(ctx context.Context, e *Event) error {
 	return nil
 }
 
-// Dispatch 负责将事件发送到指定的 webhook
-func (wd *WebhookDispatcher) Dispatch(ctx context.Context, wh *webhookModel.Webhook, e *Event) {
-	// 发送请求，带重试机制
-	err := wd.retryWithBackoff(3, 500*time.Millisecond, func() error {
-		// 构建 HTTP 请求
-		req, err := wd.buildRequest(wh, e)
-		if err != nil {
-			return err
-		}
+// matchWebhookFilter 判断事件负载是否满足 webhook 的过滤条件，不携带 Echo 的事件不做过滤
+func matchWebhookFilter(wh *webhookModel.Webhook, e *Event) bool {
+	echo, ok := e.Payload[EventPayloadEcho].(echoModel.Echo)
+	if !ok {
+		return true
+	}
 
-		// 发送 HTTP 请求
-		resp, err := wd.client.Do(req)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = resp.Body.Close() }()
+	tags := make([]string, 0, len(echo.Tags))
+	for _, tag := range echo.Tags {
+		tags = append(tags, tag.Name)
+	}
 
-		// 处理响应
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			// 成功处理
-			return nil
-		}
+	return wh.Filter.MatchEcho(echo.Private, tags)
+}
+
+// Dispatch 负责将事件发送到指定的 webhook，最终失败时写入死信队列
+func (wd *WebhookDispatcher) Dispatch(
+	ctx context.Context,
+	wh *webhookModel.Webhook,
+	e *Event,
+) error {
+	return wd.dispatch(ctx, wh, e, nil)
+}
+
+// Redeliver 先保存一条等待中的投递记录，再将事件加入投递队列，返回该投递记录
+func (wd *WebhookDispatcher) Redeliver(
+	ctx context.Context,
+	wh *webhookModel.Webhook,
+	e *Event,
+) (*webhookModel.WebhookDelivery, error) {
+	pending := &webhookModel.WebhookDelivery{
+		WebhookID: wh.ID,
+		EventID:   e.ID,
+		EventType: string(e.Type),
+		Attempt:   1,
+		Status:    webhookModel.DeliveryStatusPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	pending.Payload, _ = json.Marshal(e)
+
+	if err := wd.txManager.Run(func(txCtx context.Context) error {
+		return wd.repo.CreateDelivery(txCtx, pending)
+	}); err != nil {
+		return nil, err
+	}
 
-		// 非成功状态码，视为失败
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	whCopy, eCopy, pendingID := *wh, *e, pending.ID
+	wd.pool.Submit(func() error {
+		_ = wd.dispatch(context.Background(), &whCopy, &eCopy, &pendingID)
+		return nil
 	})
+
+	return pending, nil
+}
+
+// dispatch 投递事件，pendingID 不为空时首次尝试的结果写回该等待中的投递记录
+func (wd *WebhookDispatcher) dispatch(
+	ctx context.Context,
+	wh *webhookModel.Webhook,
+	e *Event,
+	pendingID *uint,
+) error {
+	// 发送请求，带重试机制
+	err := wd.deliverWithRetry(ctx, wh, e, pendingID)
 	// 如果最终失败，记录到死信队列
 	if err != nil {
 		// 记录失败日志
@@ -116,8 +158,11 @@ func (wd *WebhookDispatcher) Dispatch(ctx context.Context, wh *webhookModel.Webh
 			queueModel.DeadLetterMetaKey: true, // 标记为死信任务
 		}
 
+		// 死信中不保存密钥，重放时会读取当前密钥重新签名
+		replayWebhook := *wh
+		replayWebhook.Secret = ""
 		payloadData := WebhookReplayPayload{
-			Webhook: *wh,
+			Webhook: replayWebhook,
 			Event:   *e,
 		}
 		payload, _ := json.Marshal(payloadData)
@@ -141,6 +186,118 @@ func (wd *WebhookDispatcher) Dispatch(ctx context.Context, wh *webhookModel.Webh
 				Error("Failed to save dead letter", zap.String("error", err.Error()))
 		}
 	}
+
+	return err
+}
+
+// Ping 向 webhook 发送一次测试事件，不重试也不写入死信队列
+func (wd *WebhookDispatcher) Ping(
+	ctx context.Context,
+	wh *webhookModel.Webhook,
+) *webhookModel.WebhookDelivery {
+	e := NewEvent(EventTypeWebhookPing, EventPayload{
+		EventPayloadInfo: "Ech0 webhook ping",
+		EventPayloadID:   wh.ID,
+	})
+
+	delivery := wd.deliver(wh, e, 1)
+	wd.recordDelivery(ctx, wh, delivery)
+
+	return delivery
+}
+
+// deliverWithRetry 带重试地投递事件，每次尝试都会记录投递日志
+//
+// pendingID 不为空时，首次尝试更新该投递记录而不是新建。
+func (wd *WebhookDispatcher) deliverWithRetry(
+	ctx context.Context,
+	wh *webhookModel.Webhook,
+	e *Event,
+	pendingID *uint,
+) error {
+	attempt := 0
+	return wd.retryWithBackoff(3, 500*time.Millisecond, func() error {
+		attempt++
+		delivery := wd.deliver(wh, e, attempt)
+		if attempt == 1 && pendingID != nil {
+			delivery.ID = *pendingID
+		}
+		wd.recordDelivery(ctx, wh, delivery)
+		if delivery.Status == webhookModel.DeliveryStatusSuccess {
+			return nil
+		}
+		return errors.New(delivery.Error)
+	})
+}
+
+// deliver 执行一次 HTTP 投递，并返回对应的投递记录
+func (wd *WebhookDispatcher) deliver(
+	wh *webhookModel.Webhook,
+	e *Event,
+	attempt int,
+) *webhookModel.WebhookDelivery {
+	delivery := &webhookModel.WebhookDelivery{
+		WebhookID: wh.ID,
+		EventID:   e.ID,
+		EventType: string(e.Type),
+		Attempt:   attempt,
+		Status:    webhookModel.DeliveryStatusFailed,
+		CreatedAt: time.Now().UTC(),
+	}
+	delivery.Payload, _ = json.Marshal(e)
+
+	// 构建 HTTP 请求
+	req, err := wd.buildRequest(wh, e)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+
+	// 发送 HTTP 请求
+	start := time.Now()
+	resp, err := wd.client.Do(req)
+	delivery.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// 记录状态码与截断后的响应体
+	delivery.StatusCode = resp.StatusCode
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookModel.MaxDeliveryResponseBodySize))
+	delivery.ResponseBody = string(body)
+
+	// 处理响应
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		delivery.Status = webhookModel.DeliveryStatusSuccess
+		return delivery
+	}
+
+	// 非成功状态码，视为失败
+	delivery.Error = fmt.Sprintf("unexpected status code: %d", resp.StatusCode)
+	return delivery
+}
+
+// recordDelivery 保存投递记录并更新 webhook 的最近调用状态，已存在的记录直接更新
+func (wd *WebhookDispatcher) recordDelivery(
+	ctx context.Context,
+	wh *webhookModel.Webhook,
+	delivery *webhookModel.WebhookDelivery,
+) {
+	if err := wd.txManager.Run(func(txCtx context.Context) error {
+		save := wd.repo.CreateDelivery
+		if delivery.ID != 0 {
+			save = wd.repo.UpdateDelivery
+		}
+		if err := save(txCtx, delivery); err != nil {
+			return err
+		}
+		return wd.repo.UpdateWebhookStatus(txCtx, wh.ID, delivery.Status, delivery.CreatedAt)
+	}); err != nil {
+		logUtil.GetLogger().
+			Error("Failed to record webhook delivery", zap.Uint("webhook_id", wh.ID), zap.String("error", err.Error()))
+	}
 }
 
 // buildRequest 构建 HTTP 请求(POST)
@@ -150,10 +307,11 @@ func (wd *WebhookDispatcher) buildRequest(
 ) (*http.Request, error) {
 	// 构造 HTTP 请求头
 	headers := make(http.Header)
-	headers.Set("Content-Type", "application/json")  // 内容类型
-	headers.Set("X-Ech0-Event", string(e.Type))      // 事件类型
-	headers.Set("User-Agent", "Ech0-Webhook-Client") // 自定义 User-Agent
-	headers.Set("E-Ech0-Event-ID", e.ID)             // 唯一事件 ID，便于接收方去重，保证幂等性
+	headers.Set("Content-Type", "application/json")     // 内容类型
+	headers.Set(webhookSig.HeaderEvent, string(e.Type)) // 事件类型
+	headers.Set("User-Agent", "Ech0-Webhook-Client")    // 自定义 User-Agent
+	headers.Set(webhookSig.HeaderEventID, e.ID)         // 唯一事件 ID，便于接收方去重，保证幂等性
+	headers.Set("E-Ech0-Event-ID", e.ID)                // 兼容旧版本接收方的事件 ID 头
 
 	// 构造 HTTP 请求体
 	body, err := json.Marshal(e)
@@ -169,6 +327,9 @@ func (wd *WebhookDispatcher) buildRequest(
 	}
 	req.Header = headers
 
+	// 使用 Webhook 密钥对 "时间戳.请求体" 做 HMAC-SHA256 签名
+	webhookSig.SignRequest(req, wh.Secret, body, time.Now())
+
 	// 设置 GetBody 以支持重试
 	req.GetBody = func() (bodyReader io.ReadCloser, err error) {
 		return io.NopCloser(bytes.NewReader(body)), nil
@@ -191,6 +352,9 @@ func (wd *WebhookDispatcher) retryWithBackoff(
 		if err == nil {
 			return nil // 成功
 		}
+		if i == maxRetries-1 {
+			break // 最后一次失败后无需等待
+		}
 		time.Sleep(delay)
 		delay *= 2 // 指数退避
 	}
@@ -212,33 +376,24 @@ func (wd *WebhookDispatcher) HandleDeadLetter(
 	if err := json.Unmarshal(deadLetter.Payload, &payload); err != nil {
 		return fmt.Errorf("failed to unmarshal dead letter payload: %w", err)
 	}
-	webhook := payload.Webhook
 	event := payload.Event
 
-	// 重新发送请求
-	err := wd.retryWithBackoff(3, 500*time.Millisecond, func() error {
-		// 构建 HTTP 请求
-		req, err := wd.buildRequest(&webhook, &event)
-		if err != nil {
-			return err
-		}
-
-		// 发送 HTTP 请求
-		resp, err := wd.client.Do(req)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = resp.Body.Close() }()
-
-		// 处理响应
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			// 成功处理
-			return nil
-		}
+	// 重新读取 webhook，保证使用当前的 URL 与密钥重新签名
+	webhook, err := wd.repo.GetWebhookByID(ctx, payload.Webhook.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// webhook 已被删除，视为已处理，避免死信反复重试
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load webhook %d: %w", payload.Webhook.ID, err)
+	}
+	if !webhook.IsActive {
+		// webhook 已被禁用，不再重放
+		return nil
+	}
 
-		// 非成功状态码，视为失败
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	})
+	// 重新发送请求
+	err = wd.deliverWithRetry(ctx, &webhook, &event, nil)
 	if err != nil {
 		return err
 	}
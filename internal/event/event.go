@@ -21,6 +21,8 @@ const (
 	EventTypeEchoUpdated EventType = "echo.updated" // 更新Echo
 	EventTypeEchoDeleted EventType = "echo.deleted" // 删除Echo
 
+	EventTypeCommentCreated EventType = "comment.created" // 新评论（本站评论或联邦网络回复）
+
 	EventTypeResourceUploaded EventType = "resource.uploaded" // 资源上传
 
 	EventTypeSystemBackup         EventType = "system.backup"                 // 系统快照备份
@@ -30,15 +32,46 @@ const (
 
 	EventTypeDeadLetterRetried EventType = "deadletter.retried" // 死信任务重试
 
+	EventTypeWebhookPing EventType = "webhook.ping" // Webhook 测试投递
+
 	EventTypeInboxClear EventType = "inbox.clear" // 清理Inbox（超过七天的已读消息）
 
 	EventTypeEch0UpdateCheck EventType = "ech0.update" // 检查 Ech0 版本更新
 )
 
+// WebhookEventTypes 可供 Webhook 订阅的事件类型
+var WebhookEventTypes = []EventType{
+	EventTypeUserCreated,
+	EventTypeUserUpdated,
+	EventTypeUserDeleted,
+	EventTypeEchoCreated,
+	EventTypeEchoUpdated,
+	EventTypeEchoDeleted,
+	EventTypeCommentCreated,
+	EventTypeResourceUploaded,
+	EventTypeSystemBackup,
+	EventTypeSystemRestore,
+	EventTypeSystemExport,
+	EventTypeUpdateBackupSchedule,
+	EventTypeInboxClear,
+	EventTypeEch0UpdateCheck,
+}
+
+// IsWebhookEventType 判断事件类型是否可供 Webhook 订阅
+func IsWebhookEventType(eventType string) bool {
+	for _, et := range WebhookEventTypes {
+		if string(et) == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 // 定义事件Payload的常用字段
 const (
 	EventPayloadUser       = "user"
 	EventPayloadEcho       = "echo"
+	EventPayloadComment    = "comment"
 	EventPayloadData       = "data"
 	EventPayloadSchedule   = "schedule"
 	EventPayloadInfo       = "info"
@@ -50,7 +83,11 @@ const (
 	EventPayloadSize       = "size"
 	EventPayloadPath       = "path"
 	EventPayloadFile       = "file"
+	EventPayloadStatus     = "status"
+	EventPayloadError      = "error"
+	EventPayloadTarget     = "destination"
 	EventPayloadDeadLetter = "dead_letter"
+	EventPayloadWasPrivate = "was_private" // 编辑前 Echo 是否为私密
 )
 
 // Event 事件结构体
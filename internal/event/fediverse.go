@@ -18,8 +18,11 @@ import (
 )
 
 type PushEchoReplayPayload struct {
-	Echo echoModel.Echo `json:"echo"`
-	User userModel.User `json:"user"`
+	Echo  echoModel.Echo `json:"echo"`
+	User  userModel.User `json:"user"`
+	Event EventType      `json:"event,omitempty"` // 触发推送的事件类型，为空时视为创建
+
+	WasPrivate bool `json:"was_private,omitempty"` // 编辑前 Echo 是否为私密
 }
 
 // FediverseAgent 处理联邦相关的事件
@@ -46,10 +49,12 @@ func NewFediverseAgent(
 func (fa *FediverseAgent) Handle(ctx context.Context, e *Event) error {
 	// 处理事件，与联邦宇宙交互
 	switch e.Type {
-	case EventTypeEchoCreated:
-		if err := fa.HandleCreateEchoEvent(ctx, e); err != nil {
+	case EventTypeEchoCreated, EventTypeEchoUpdated, EventTypeEchoDeleted:
+		if err := fa.HandleEchoEvent(ctx, e); err != nil {
 			logUtil.GetLogger().
-				Error("Failed to handle create echo event", zap.String("error", err.Error()))
+				Error("Failed to handle echo event",
+					zap.String("event", string(e.Type)),
+					zap.String("error", err.Error()))
 			return nil
 		}
 
@@ -62,10 +67,11 @@ func (fa *FediverseAgent) Handle(ctx context.Context, e *Event) error {
 
 func (fa *FediverseAgent) Wait() {
 	fa.pool.Wait()
+	fa.core.WaitDeliveries()
 }
 
-func (fa *FediverseAgent) HandleCreateEchoEvent(ctx context.Context, e *Event) error {
-	// 将 Echo 推送到联邦宇宙
+// HandleEchoEvent 将 Echo 的创建、编辑与删除推送到联邦宇宙
+func (fa *FediverseAgent) HandleEchoEvent(ctx context.Context, e *Event) error {
 	payload := e.Payload
 	echoData, ok := payload[EventPayloadEcho]
 	if !ok {
@@ -84,10 +90,25 @@ func (fa *FediverseAgent) HandleCreateEchoEvent(ctx context.Context, e *Event) e
 		return nil
 	}
 
+	// 删除的 Echo 先留下墓碑，保证 Object 接口返回 410
+	if e.Type == EventTypeEchoDeleted {
+		if err := fa.txManager.Run(func(ctx context.Context) error {
+			return fa.core.RecordTombstone(ctx, &echo)
+		}); err != nil {
+			logUtil.GetLogger().
+				Error("Failed to record echo tombstone", zap.String("error", err.Error()))
+		}
+	}
+
+	eventType := e.Type
+	wasPrivate, ok := payload[EventPayloadWasPrivate].(bool)
+	if !ok {
+		wasPrivate = echo.Private
+	}
 	fa.pool.Submit(func() error {
 		// 重试机制，最多重试3次，初始延迟1秒
 		return fa.retryWithBackoff(3, time.Second, func() error {
-			if err := fa.core.PushEchoToFediverse(user.ID, echo); err != nil {
+			if err := fa.pushEcho(eventType, user.ID, echo, wasPrivate); err != nil {
 				logUtil.GetLogger().Error(err.Error())
 
 				// 处理失败，记录到死信队列
@@ -96,8 +117,10 @@ func (fa *FediverseAgent) HandleCreateEchoEvent(ctx context.Context, e *Event) e
 				}
 
 				payloadData := PushEchoReplayPayload{
-					Echo: echo,
-					User: user,
+					Echo:       echo,
+					User:       user,
+					Event:      eventType,
+					WasPrivate: wasPrivate,
 				}
 				payload, _ := json.Marshal(payloadData)
 
@@ -126,6 +149,23 @@ func (fa *FediverseAgent) HandleCreateEchoEvent(ctx context.Context, e *Event) e
 	return nil
 }
 
+// pushEcho 根据事件类型推送对应的 Activity
+func (fa *FediverseAgent) pushEcho(
+	eventType EventType,
+	userID uint,
+	echo echoModel.Echo,
+	wasPrivate bool,
+) error {
+	switch eventType {
+	case EventTypeEchoUpdated:
+		return fa.core.PushEchoUpdateToFediverse(userID, echo, wasPrivate)
+	case EventTypeEchoDeleted:
+		return fa.core.PushEchoDeleteToFediverse(userID, echo)
+	default:
+		return fa.core.PushEchoToFediverse(userID, echo)
+	}
+}
+
 func (fa *FediverseAgent) retryWithBackoff(
 	retries int,
 	delay time.Duration,
@@ -157,7 +197,7 @@ func (fa *FediverseAgent) HandlePushEchoDeadLetter(
 
 	// 重试
 	err := fa.retryWithBackoff(3, 1*time.Minute, func() error {
-		return fa.core.PushEchoToFediverse(user.ID, echo)
+		return fa.pushEcho(payload.Event, user.ID, echo, payload.WasPrivate)
 	})
 	if err != nil {
 		return err
@@ -165,3 +205,11 @@ func (fa *FediverseAgent) HandlePushEchoDeadLetter(
 
 	return nil
 }
+
+// HandleDeliveryDeadLetter 重试单个收件箱的投递
+func (fa *FediverseAgent) HandleDeliveryDeadLetter(
+	ctx context.Context,
+	deadLetter *queueModel.DeadLetter,
+) error {
+	return fa.core.RetryDelivery(deadLetter.Payload)
+}
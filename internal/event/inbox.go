@@ -8,8 +8,10 @@ import (
 	"strings"
 	"time"
 
+	commentModel "github.com/lin-snow/ech0/internal/model/comment"
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	inboxModel "github.com/lin-snow/ech0/internal/model/inbox"
+	userModel "github.com/lin-snow/ech0/internal/model/user"
 	inboxRepository "github.com/lin-snow/ech0/internal/repository/inbox"
 	keyvalueRepository "github.com/lin-snow/ech0/internal/repository/keyvalue"
 	githubUtil "github.com/lin-snow/ech0/internal/util/github"
@@ -32,6 +34,8 @@ func (id *InboxDispatcher) Handle(ctx context.Context, e *Event) error {
 		return id.handleEch0UpdateCheck(ctx)
 	case EventTypeInboxClear:
 		return id.handleInboxClear(ctx)
+	case EventTypeCommentCreated:
+		return id.handleCommentCreated(ctx, e)
 	}
 
 	return nil
@@ -118,3 +122,49 @@ func (id *InboxDispatcher) handleInboxClear(ctx context.Context) error {
 
 	return nil
 }
+
+func (id *InboxDispatcher) handleCommentCreated(ctx context.Context, e *Event) error {
+	comment, ok := e.Payload[EventPayloadComment].(commentModel.Comment)
+	if !ok {
+		return nil
+	}
+
+	// 管理员自己的评论无需通知
+	if user, ok := e.Payload[EventPayloadUser].(userModel.User); ok && user.IsAdmin {
+		return nil
+	}
+
+	source := commonModel.UserSource
+	if comment.Source == commentModel.CommentSourceFediverse {
+		source = commonModel.FediverseSource
+	}
+
+	// 通知中只保留评论摘要
+	summary := []rune(strings.TrimSpace(comment.Content))
+	if len(summary) > 100 {
+		summary = append(summary[:100], []rune("...")...)
+	}
+	content := fmt.Sprintf("%s 评论了 Echo #%d：%s", comment.AuthorName, comment.EchoID, string(summary))
+	if comment.Status == commentModel.CommentStatusPending {
+		content = "[待审核] " + content
+	}
+
+	meta, _ := json.Marshal(map[string]any{
+		"comment_id": comment.ID,
+		"echo_id":    comment.EchoID,
+		"parent_id":  comment.ParentID,
+		"status":     comment.Status,
+		"source":     comment.Source,
+	})
+
+	return id.inboxRepo.PostInbox(ctx, &inboxModel.Inbox{
+		Source:    string(source),
+		Content:   content,
+		Type:      string(commonModel.CommentInboxType),
+		Read:      false,
+		ReadCount: 0,
+		ReadAt:    0,
+		Meta:      string(meta),
+		CreatedAt: time.Now().UTC().Unix(),
+	})
+}
@@ -0,0 +1,143 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lin-snow/ech0/internal/async"
+	"github.com/lin-snow/ech0/internal/config"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+	echoRepository "github.com/lin-snow/ech0/internal/repository/echo"
+	fileUtil "github.com/lin-snow/ech0/internal/util/file"
+	imgUtil "github.com/lin-snow/ech0/internal/util/img"
+	storageUtil "github.com/lin-snow/ech0/internal/util/storage"
+	"golang.org/x/sync/singleflight"
+)
+
+// localImagePrefix 是本地图片 URL 的前缀
+const localImagePrefix = "/images/"
+
+// MediaProcessor 为上传的本地图片异步生成响应式尺寸、BlurHash 与主色调
+// 上传时生成并写入清单，发布或更新 Echo 时据此回填媒体；清单不存在时（如旧图片）当场生成
+type MediaProcessor struct {
+	pool     *async.WorkerPool                      // 任务池，限制同时解码的图片数
+	group    singleflight.Group                     // 合并同一图片的并发生成
+	echoRepo echoRepository.EchoRepositoryInterface // Echo 仓储
+}
+
+func NewMediaProcessor(echoRepo echoRepository.EchoRepositoryInterface) *MediaProcessor {
+	return &MediaProcessor{
+		pool:     async.NewWorkerPool(2, 64),
+		echoRepo: echoRepo,
+	}
+}
+
+func (mp *MediaProcessor) Handle(ctx context.Context, e *Event) error {
+	switch e.Type {
+	case EventTypeResourceUploaded:
+		if fileType, _ := e.Payload[EventPayloadType].(commonModel.UploadFileType); fileType != commonModel.ImageType {
+			return nil
+		}
+		if mediaURL, _ := e.Payload[EventPayloadURL].(string); strings.HasPrefix(mediaURL, localImagePrefix) {
+			mp.submit(mediaURL)
+		}
+
+	case EventTypeEchoCreated, EventTypeEchoUpdated:
+		echo, ok := e.Payload[EventPayloadEcho].(echoModel.Echo)
+		if !ok {
+			return nil
+		}
+		for _, media := range echo.Media {
+			if media.MediaSource == echoModel.MediaSourceLocal &&
+				media.MediaType == echoModel.MediaTypeImage &&
+				len(media.Variants) == 0 && media.Blurhash == "" {
+				mp.submit(media.MediaURL)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Wait 等待所有任务完成
+func (mp *MediaProcessor) Wait() {
+	mp.pool.Wait()
+}
+
+// submit 提交图片处理任务
+func (mp *MediaProcessor) submit(mediaURL string) {
+	mp.pool.Submit(func() error {
+		manifest, err, _ := mp.group.Do(mediaURL, func() (any, error) {
+			return mp.ensureVariants(mediaURL)
+		})
+		if err != nil {
+			return err
+		}
+
+		result := manifest.(*storageUtil.ImageVariantManifest)
+		return mp.echoRepo.UpdateMediaVariants(
+			context.Background(),
+			mediaURL,
+			result.Blurhash,
+			result.DominantColor,
+			result.Variants,
+		)
+	})
+}
+
+// ensureVariants 读取已生成的清单，不存在时解码原图生成各尺寸并写入清单
+func (mp *MediaProcessor) ensureVariants(mediaURL string) (*storageUtil.ImageVariantManifest, error) {
+	fileName := strings.TrimPrefix(mediaURL, localImagePrefix)
+	manifest, err := storageUtil.ReadImageVariantManifest(fileName)
+	if err == nil {
+		return manifest, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	// 使用安全的路径验证和清理，防止路径遍历攻击
+	srcPath, err := fileUtil.ValidateAndSanitizePath(config.Config.Upload.ImagePath, mediaURL, localImagePrefix)
+	if err != nil {
+		return nil, err
+	}
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	set, err := imgUtil.GenerateVariants(src, imgUtil.VariantWidths)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest = &storageUtil.ImageVariantManifest{
+		Blurhash:      set.Blurhash,
+		DominantColor: set.DominantColor,
+	}
+	for _, variant := range set.Variants {
+		variantPath, variantURL := storageUtil.ImageVariantFile(fileName, variant.Width, imgUtil.VariantFormat)
+		if err := os.MkdirAll(filepath.Dir(variantPath), 0o750); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(variantPath, variant.Data, 0o644); err != nil {
+			return nil, err
+		}
+		manifest.Variants = append(manifest.Variants, echoModel.MediaVariant{
+			URL:    variantURL,
+			Width:  variant.Width,
+			Height: variant.Height,
+			Format: imgUtil.VariantFormat,
+		})
+	}
+
+	if err := storageUtil.WriteImageVariantManifest(fileName, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
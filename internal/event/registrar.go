@@ -8,6 +8,7 @@ type EventHandlers struct {
 	bs  *BackupScheduler    // 备份事件调度器
 	ap  *AgentProcessor     // Agent事件处理器
 	id  *InboxDispatcher    // Inbox事件处理器
+	mp  *MediaProcessor     // 媒体处理器
 }
 
 // NewEventHandlers 创建一个新的事件处理器集合
@@ -18,8 +19,9 @@ func NewEventHandlers(
 	bs *BackupScheduler,
 	ap *AgentProcessor,
 	id *InboxDispatcher,
+	mp *MediaProcessor,
 ) *EventHandlers {
-	return &EventHandlers{wbd: wbd, dlr: dlr, fa: fa, bs: bs, ap: ap, id: id}
+	return &EventHandlers{wbd: wbd, dlr: dlr, fa: fa, bs: bs, ap: ap, id: id, mp: mp}
 }
 
 // EventRegistrar 事件注册器
@@ -44,10 +46,12 @@ func (er *EventRegistrar) Register() error {
 	if err != nil {
 		return err
 	}
-	err = er.eb.Subscribe(
+	err = er.eb.Subscribes(
 		er.eh.fa.Handle,
 		EventTypeEchoCreated,
-	) // 订阅 EchoCreated 事件，交给 FediverseAgent 处理
+		EventTypeEchoUpdated,
+		EventTypeEchoDeleted,
+	) // 订阅 Echo 创建、更新、删除事件，交给 FediverseAgent 处理
 	if err != nil {
 		return err
 	}
@@ -75,11 +79,23 @@ func (er *EventRegistrar) Register() error {
 		er.eh.id.Handle,
 		EventTypeEch0UpdateCheck,
 		EventTypeInboxClear,
+		EventTypeCommentCreated,
 	) // 订阅 Inbox 事件，交给 InboxDispatcher 处理
 	if err != nil {
 		return err
 	}
 
+	// 订阅资源上传与 Echo 事件，交给 MediaProcessor 生成图片的响应式尺寸
+	err = er.eb.Subscribes(
+		er.eh.mp.Handle,
+		EventTypeResourceUploaded,
+		EventTypeEchoCreated,
+		EventTypeEchoUpdated,
+	)
+	if err != nil {
+		return err
+	}
+
 	// 订阅所有事件，交给 WebhookDispatcher 处理
 	err = er.eb.SubscribeAll(
 		er.eh.wbd.Handle,
@@ -96,4 +112,5 @@ func (er *EventRegistrar) Register() error {
 func (er *EventRegistrar) Wait() {
 	er.eh.wbd.Wait()
 	er.eh.fa.Wait()
+	er.eh.mp.Wait()
 }
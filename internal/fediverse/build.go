@@ -8,6 +8,7 @@ import (
 
 	"github.com/lin-snow/ech0/internal/config"
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
 	model "github.com/lin-snow/ech0/internal/model/fediverse"
 	settingModel "github.com/lin-snow/ech0/internal/model/setting"
 	userModel "github.com/lin-snow/ech0/internal/model/user"
@@ -19,6 +20,20 @@ import (
 // Build
 //==============================================================================
 
+// GetServerURL 获取规范化后的实例地址
+func (core *FediverseCore) GetServerURL() (string, error) {
+	var setting settingModel.SystemSetting
+	settingStr, err := core.keyvalueRepo.GetKeyValue(commonModel.SystemSettingsKey)
+	if err != nil {
+		return "", err
+	}
+	if err := jsonUtil.JSONUnmarshal([]byte(settingStr.(string)), &setting); err != nil {
+		return "", err
+	}
+
+	return NormalizeServerURL(setting.ServerURL)
+}
+
 // BuildActor 构建 Actor 对象
 func (core *FediverseCore) BuildActor(
 	user *userModel.User,
@@ -163,6 +178,102 @@ func (core *FediverseCore) BuildAcceptActivityPayload(
 	return json.Marshal(payload)
 }
 
+// BuildCreateActivityPayload 构建 Create{Note} Activity 的 JSON Payload
+func (core *FediverseCore) BuildCreateActivityPayload(
+	echo *echoModel.Echo,
+	actor *model.Actor,
+	serverURL string,
+) ([]byte, error) {
+	activity := core.ConvertEchoToActivity(echo, actor, serverURL)
+	object := core.ConvertEchoToObject(echo, actor, serverURL)
+
+	activityMap, err := toPayloadMap(activity)
+	if err != nil {
+		return nil, err
+	}
+	objectMap, err := toPayloadMap(object)
+	if err != nil {
+		return nil, err
+	}
+
+	activityMap["object"] = objectMap
+	return json.Marshal(activityMap)
+}
+
+// BuildUpdateActivityPayload 构建 Update{Note} Activity 的 JSON Payload
+func (core *FediverseCore) BuildUpdateActivityPayload(
+	echo *echoModel.Echo,
+	actor *model.Actor,
+	serverURL string,
+	updated time.Time,
+) ([]byte, error) {
+	object := core.ConvertEchoToObject(echo, actor, serverURL)
+
+	objectMap, err := toPayloadMap(object)
+	if err != nil {
+		return nil, err
+	}
+	objectMap["updated"] = updated.Format(time.RFC3339)
+
+	payload := map[string]any{
+		"@context": []any{
+			"https://www.w3.org/ns/activitystreams",
+			"https://w3id.org/security/v1",
+		},
+		"id":        fmt.Sprintf("%s/activities/%d/update/%d", serverURL, echo.ID, updated.UnixNano()),
+		"type":      model.ActivityTypeUpdate,
+		"actor":     actor.ID,
+		"object":    objectMap,
+		"to":        object.To,
+		"cc":        []string{actor.Followers},
+		"published": updated.Format(time.RFC3339),
+	}
+
+	return json.Marshal(payload)
+}
+
+// BuildDeleteActivityPayload 构建 Delete{Tombstone} Activity 的 JSON Payload
+func BuildDeleteActivityPayload(
+	echoID uint,
+	actor *model.Actor,
+	serverURL string,
+	deleted time.Time,
+) ([]byte, error) {
+	if actor == nil {
+		return nil, errors.New("actor is nil")
+	}
+
+	payload := map[string]any{
+		"@context": []any{"https://www.w3.org/ns/activitystreams"},
+		"id":       fmt.Sprintf("%s/activities/%d/delete/%d", serverURL, echoID, deleted.UnixNano()),
+		"type":     model.ActivityTypeDelete,
+		"actor":    actor.ID,
+		"object": map[string]any{
+			"id":   fmt.Sprintf("%s/objects/%d", serverURL, echoID),
+			"type": model.ObjectTypeTombstone,
+		},
+		"to":        []string{"https://www.w3.org/ns/activitystreams#Public"},
+		"cc":        []string{actor.Followers},
+		"published": deleted.Format(time.RFC3339),
+	}
+
+	return json.Marshal(payload)
+}
+
+// toPayloadMap 将结构体按 JSON 标签转换为 map，便于组装嵌套的 Activity
+func toPayloadMap(value any) (map[string]any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]any{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
 // BuildFollowActivityPayload 构建 Follow Activity 的 JSON Payload
 func BuildFollowActivityPayload(
 	actor *model.Actor,
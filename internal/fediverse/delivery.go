@@ -0,0 +1,316 @@
+package fediverse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	model "github.com/lin-snow/ech0/internal/model/fediverse"
+	queueModel "github.com/lin-snow/ech0/internal/model/queue"
+	httpUtil "github.com/lin-snow/ech0/internal/util/http"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	"go.uber.org/zap"
+)
+
+const (
+	// deliveryWorkerCount 投递并发数
+	deliveryWorkerCount = 8
+	// deliveryQueueSize 投递任务队列大小
+	deliveryQueueSize = 256
+	// maxDeliveriesPerHost 同一域名允许的最大并发投递数
+	maxDeliveriesPerHost = 2
+	// deliveryMaxAttempts 单个收件箱的即时投递次数，用尽后转入死信队列
+	deliveryMaxAttempts = 3
+	// deliveryRetryBaseDelay 即时重试的初始退避时间
+	deliveryRetryBaseDelay = 2 * time.Second
+	// inboxResolveConcurrency 解析粉丝收件箱时的并发数
+	inboxResolveConcurrency = 8
+	// deliveryDeadLetterDelay 投递失败转入死信后的首次重试延迟
+	deliveryDeadLetterDelay = 15 * time.Minute
+)
+
+//==============================================================================
+//	Delivery
+//==============================================================================
+
+// enqueueFollowerDeliveries 解析粉丝收件箱并将 Activity 加入投递队列
+//
+// 同一共享收件箱只投递一次；无法解析收件箱的粉丝保留 Actor URL，投递时再解析。
+func (core *FediverseCore) enqueueFollowerDeliveries(
+	actorID string,
+	payload []byte,
+	followers []model.Follower,
+) {
+	inboxes, unresolved := core.resolveFollowerInboxes(followers)
+
+	for _, inbox := range inboxes {
+		core.EnqueueDelivery(model.Delivery{
+			ActorID:  actorID,
+			Inbox:    inbox,
+			Activity: payload,
+		})
+	}
+	for _, recipient := range unresolved {
+		core.EnqueueDelivery(model.Delivery{
+			ActorID:   actorID,
+			Recipient: recipient,
+			Activity:  payload,
+		})
+	}
+}
+
+// resolveFollowerInboxes 并发解析粉丝的收件箱，优先使用共享收件箱并去重
+func (core *FediverseCore) resolveFollowerInboxes(
+	followers []model.Follower,
+) ([]string, []string) {
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		seen       = make(map[string]struct{}, len(followers))
+		inboxes    = make([]string, 0, len(followers))
+		unresolved []string
+		sem        = make(chan struct{}, inboxResolveConcurrency)
+	)
+
+	for _, actorURL := range uniqueFollowerActors(followers) {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(actorURL string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			actor, err := core.FetchRemoteActor(actorURL, false)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// 已注销的 Actor 不再投递
+				if !errors.Is(err, ErrRemoteActorGone) {
+					unresolved = append(unresolved, actorURL)
+				}
+				return
+			}
+
+			inbox := actor.Endpoints.SharedInbox
+			if inbox == "" {
+				inbox = actor.Inbox
+			}
+			if inbox == "" {
+				return
+			}
+			if _, ok := seen[inbox]; ok {
+				return
+			}
+			seen[inbox] = struct{}{}
+			inboxes = append(inboxes, inbox)
+		}(actorURL)
+	}
+	wg.Wait()
+
+	return inboxes, unresolved
+}
+
+// uniqueFollowerActors 提取去重后的粉丝 Actor URL
+func uniqueFollowerActors(followers []model.Follower) []string {
+	seen := make(map[string]struct{}, len(followers))
+	actors := make([]string, 0, len(followers))
+	for _, follower := range followers {
+		if follower.ActorID == "" {
+			continue
+		}
+		if _, ok := seen[follower.ActorID]; ok {
+			continue
+		}
+		seen[follower.ActorID] = struct{}{}
+		actors = append(actors, follower.ActorID)
+	}
+	return actors
+}
+
+// EnqueueDelivery 将投递任务持久化后加入队列，失败时按指数退避重试，仍失败则交由死信队列继续重试
+//
+// 任务先以死信记录的形式落库，投递成功或确定无法投递后删除；进程重启时未完成的任务由死信队列接手。
+func (core *FediverseCore) EnqueueDelivery(delivery model.Delivery) {
+	deadLetter := core.persistDelivery(&delivery)
+
+	core.deliveryPool.Submit(func() error {
+		err := core.deliverWithBackoff(&delivery)
+		if err == nil || !isRetryableDeliveryError(err) {
+			core.removePersistedDelivery(deadLetter)
+			return err
+		}
+
+		core.markDeliveryFailed(deadLetter, &delivery, err)
+		return err
+	})
+}
+
+// RetryDelivery 重试死信队列中的投递任务，只投递一次，失败由死信队列继续退避
+func (core *FediverseCore) RetryDelivery(payload []byte) error {
+	var delivery model.Delivery
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return fmt.Errorf("failed to unmarshal delivery payload: %w", err)
+	}
+
+	err := core.deliver(&delivery)
+	if err != nil && !isRetryableDeliveryError(err) {
+		// 不可重试的错误直接放弃，避免死信反复重试
+		logUtil.GetLogger().Warn("Drop fediverse delivery",
+			zap.String("inbox", delivery.Inbox),
+			zap.String("recipient", delivery.Recipient),
+			zap.String("error", err.Error()))
+		return nil
+	}
+	return err
+}
+
+// WaitDeliveries 等待投递队列中的任务完成
+func (core *FediverseCore) WaitDeliveries() {
+	core.deliveryPool.Wait()
+}
+
+// deliverWithBackoff 按指数退避多次投递
+func (core *FediverseCore) deliverWithBackoff(delivery *model.Delivery) error {
+	delay := deliveryRetryBaseDelay
+
+	var err error
+	for attempt := 1; attempt <= deliveryMaxAttempts; attempt++ {
+		err = core.deliver(delivery)
+		if err == nil || !isRetryableDeliveryError(err) {
+			return err
+		}
+		if attempt < deliveryMaxAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	return err
+}
+
+// deliver 投递一次，按域名限制并发
+func (core *FediverseCore) deliver(delivery *model.Delivery) error {
+	if delivery.Inbox == "" {
+		inbox, err := core.FetchRemoteActorInbox(delivery.Recipient)
+		if err != nil {
+			return fmt.Errorf("fetch inbox for %s: %w", delivery.Recipient, err)
+		}
+		delivery.Inbox = inbox
+	}
+
+	release := core.acquireHostSlot(delivery.Inbox)
+	defer release()
+
+	if err := httpUtil.PostActivity(delivery.Activity, delivery.Inbox, delivery.ActorID); err != nil {
+		return fmt.Errorf("post activity to %s: %w", delivery.Inbox, err)
+	}
+	return nil
+}
+
+// acquireHostSlot 获取目标域名的投递名额，返回释放函数
+func (core *FediverseCore) acquireHostSlot(inbox string) func() {
+	host := inbox
+	if u, err := url.Parse(inbox); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	slots, _ := core.hostSlots.LoadOrStore(host, make(chan struct{}, maxDeliveriesPerHost))
+	ch := slots.(chan struct{})
+	ch <- struct{}{}
+	return func() { <-ch }
+}
+
+// persistDelivery 将投递任务保存到死信队列，首次重试时间延后以留给即时投递，保存失败时返回 nil
+func (core *FediverseCore) persistDelivery(delivery *model.Delivery) *queueModel.DeadLetter {
+	payload, err := json.Marshal(delivery)
+	if err != nil {
+		logUtil.GetLogger().
+			Error("Failed to marshal fediverse delivery", zap.String("error", err.Error()))
+		return nil
+	}
+
+	now := time.Now().UTC()
+	var deadLetter queueModel.DeadLetter
+	deadLetter.SetType(queueModel.DeadLetterTypeFediverseDelivery)
+	deadLetter.Payload = payload
+	deadLetter.RetryCount = 0
+	deadLetter.NextRetry = now.Add(deliveryDeadLetterDelay)
+	deadLetter.CreatedAt = now
+	deadLetter.UpdatedAt = now
+	deadLetter.Status = queueModel.DeadLetterStatusPending
+
+	if err := core.txManager.Run(func(ctx context.Context) error {
+		return core.queueRepo.SaveDeadLetter(ctx, &deadLetter)
+	}); err != nil {
+		logUtil.GetLogger().
+			Error("Failed to persist fediverse delivery", zap.String("error", err.Error()))
+		return nil
+	}
+
+	return &deadLetter
+}
+
+// removePersistedDelivery 投递完成后删除持久化的任务
+func (core *FediverseCore) removePersistedDelivery(deadLetter *queueModel.DeadLetter) {
+	if deadLetter == nil {
+		return
+	}
+
+	if err := core.txManager.Run(func(ctx context.Context) error {
+		return core.queueRepo.DeleteDeadLetter(ctx, deadLetter.ID)
+	}); err != nil {
+		logUtil.GetLogger().
+			Error("Failed to remove fediverse delivery", zap.String("error", err.Error()))
+	}
+}
+
+// markDeliveryFailed 记录即时投递失败的原因，由死信队列稍后重试
+func (core *FediverseCore) markDeliveryFailed(
+	deadLetter *queueModel.DeadLetter,
+	delivery *model.Delivery,
+	cause error,
+) {
+	// 落库失败的任务此时补存一次
+	if deadLetter == nil {
+		if deadLetter = core.persistDelivery(delivery); deadLetter == nil {
+			return
+		}
+	}
+
+	// 已解析的收件箱一并保存，重试时无需再次解析
+	if payload, err := json.Marshal(delivery); err == nil {
+		deadLetter.Payload = payload
+	}
+
+	now := time.Now().UTC()
+	deadLetter.ErrorMsg = cause.Error()
+	deadLetter.NextRetry = now.Add(deliveryDeadLetterDelay)
+	deadLetter.UpdatedAt = now
+
+	if err := core.txManager.Run(func(ctx context.Context) error {
+		return core.queueRepo.UpdateDeadLetter(ctx, deadLetter)
+	}); err != nil {
+		logUtil.GetLogger().
+			Error("Failed to save fediverse delivery dead letter", zap.String("error", err.Error()))
+	}
+}
+
+// isRetryableDeliveryError 判断投递错误是否值得重试
+func isRetryableDeliveryError(err error) bool {
+	if errors.Is(err, ErrRemoteActorGone) {
+		return false
+	}
+
+	var deliveryErr *httpUtil.ActivityDeliveryError
+	if errors.As(err, &deliveryErr) {
+		return deliveryErr.Retryable()
+	}
+
+	// 网络错误等默认可重试
+	return true
+}
@@ -1,10 +1,17 @@
 package fediverse
 
 import (
+	"sync"
+	"time"
+
+	"github.com/lin-snow/ech0/internal/async"
+	"github.com/lin-snow/ech0/internal/cache"
 	echoRepository "github.com/lin-snow/ech0/internal/repository/echo"
 	repository "github.com/lin-snow/ech0/internal/repository/fediverse"
 	keyvalueRepository "github.com/lin-snow/ech0/internal/repository/keyvalue"
+	queueRepository "github.com/lin-snow/ech0/internal/repository/queue"
 	userRepository "github.com/lin-snow/ech0/internal/repository/user"
+	"github.com/lin-snow/ech0/internal/transaction"
 )
 
 // FediverseCore 联邦宇宙核心
@@ -13,6 +20,15 @@ type FediverseCore struct {
 	userRepository userRepository.UserRepositoryInterface
 	echoRepository echoRepository.EchoRepositoryInterface
 	keyvalueRepo   keyvalueRepository.KeyValueRepositoryInterface
+	cache          cache.ICache[string, any]
+	queueRepo      queueRepository.QueueRepositoryInterface // 死信任务仓储
+	txManager      transaction.TransactionManager           // 事务管理器
+	deliveryPool   *async.WorkerPool                        // 投递任务池
+	hostSlots      sync.Map                                 // 每个域名的投递名额
+
+	refreshMu    sync.Mutex           // 保护 refreshedAt
+	refreshedAt  map[string]time.Time // 每个 Actor 最近一次强制重新拉取的时间
+	refreshSweep time.Time            // 上次清理 refreshedAt 的时间
 }
 
 func NewFediverseCore(
@@ -20,11 +36,19 @@ func NewFediverseCore(
 	keyvalueRepo keyvalueRepository.KeyValueRepositoryInterface,
 	userRepository userRepository.UserRepositoryInterface,
 	echoRepository echoRepository.EchoRepositoryInterface,
+	cache cache.ICache[string, any],
+	queueRepo queueRepository.QueueRepositoryInterface,
+	txManager transaction.TransactionManager,
 ) *FediverseCore {
 	return &FediverseCore{
 		repo:           repo,
 		keyvalueRepo:   keyvalueRepo,
 		userRepository: userRepository,
 		echoRepository: echoRepository,
+		cache:          cache,
+		queueRepo:      queueRepo,
+		txManager:      txManager,
+		deliveryPool:   async.NewWorkerPool(deliveryWorkerCount, deliveryQueueSize),
+		refreshedAt:    make(map[string]time.Time),
 	}
 }
@@ -3,45 +3,165 @@ package fediverse
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
+	model "github.com/lin-snow/ech0/internal/model/fediverse"
 	httpUtil "github.com/lin-snow/ech0/internal/util/http"
 )
 
+const (
+	// remoteActorCacheKeyPrefix 远端 Actor 文档缓存前缀
+	remoteActorCacheKeyPrefix = "fediverse:actor"
+	// remoteActorCacheTTL 远端 Actor 文档缓存时长
+	remoteActorCacheTTL = 24 * time.Hour
+	// maxRemoteDocumentSize 远端文档最大读取长度
+	maxRemoteDocumentSize = 1 << 20
+	// actorRefreshCooldown 同一 Actor 强制重新拉取的最小间隔，避免伪造签名反复触发远端请求
+	actorRefreshCooldown = 5 * time.Minute
+)
+
+// ErrRemoteActorGone 远端 Actor 已被删除（410 Gone）
+var ErrRemoteActorGone = errors.New("remote actor is gone")
+
+// fetchClient 拉取远端文档使用的 HTTP 客户端，只允许访问公网地址
+var fetchClient = httpUtil.NewPublicClient(10 * time.Second)
+
 //==============================================================================
 //	Fetch
 //==============================================================================
 
 // FetchRemoteActorInbox 获取远程 Actor 的 Inbox URL
 func (core *FediverseCore) FetchRemoteActorInbox(actorURL string) (string, error) {
+	actor, err := core.FetchRemoteActor(actorURL, false)
+	if err != nil {
+		return "", err
+	}
+
+	if actor.Inbox != "" {
+		return actor.Inbox, nil
+	}
+	if actor.Endpoints.SharedInbox != "" {
+		return actor.Endpoints.SharedInbox, nil
+	}
+
+	return "", errors.New("remote actor inbox not found")
+}
+
+// FetchRemoteActor 获取远端 Actor 文档，refresh 为 true 时跳过缓存重新拉取
+func (core *FediverseCore) FetchRemoteActor(
+	actorURL string,
+	refresh bool,
+) (*model.RemoteActor, error) {
+	// 去掉 keyId 等携带的片段
+	actorURL, _, _ = strings.Cut(actorURL, "#")
 	if actorURL == "" {
-		return "", errors.New("remote actor url is empty")
+		return nil, errors.New("remote actor url is empty")
+	}
+
+	cacheKey := remoteActorCacheKeyPrefix + ":" + actorURL
+	if !refresh {
+		if cached, err := core.cache.Get(cacheKey); err == nil {
+			if actor, ok := cached.(*model.RemoteActor); ok {
+				return actor, nil
+			}
+		}
 	}
 
-	body, err := httpUtil.SendRequest(actorURL, http.MethodGet, httpUtil.Header{
-		Header:  "Accept",
-		Content: "application/activity+json",
-	})
+	body, err := FetchActivityDocument(actorURL)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var resp struct {
-		Inbox     string `json:"inbox"`
-		Endpoints struct {
-			SharedInbox string `json:"sharedInbox"`
-		} `json:"endpoints"`
+	var actor model.RemoteActor
+	if err := json.Unmarshal(body, &actor); err != nil {
+		return nil, err
 	}
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return "", err
+	if actor.ID == "" {
+		return nil, errors.New("remote actor document missing id")
 	}
+	// 文档声明的 id 必须与拉取地址同源，否则任何人都能在自己的服务器上冒充其他 Actor
+	if !sameHost(actor.ID, actorURL) {
+		return nil, errors.New("remote actor id does not match fetched url")
+	}
+
+	core.cache.SetWithTTL(cacheKey, &actor, 1, remoteActorCacheTTL)
+	return &actor, nil
+}
+
+// allowActorRefresh 判断是否允许强制重新拉取 Actor 文档，同一 Actor 在冷却时间内只允许一次
+func (core *FediverseCore) allowActorRefresh(actorURL string) bool {
+	actorURL, _, _ = strings.Cut(actorURL, "#")
+	now := time.Now()
+
+	core.refreshMu.Lock()
+	defer core.refreshMu.Unlock()
 
-	if resp.Inbox != "" {
-		return resp.Inbox, nil
+	// 定期清理过期的记录，避免内存随 keyId 数量增长
+	if now.Sub(core.refreshSweep) >= actorRefreshCooldown {
+		for key, at := range core.refreshedAt {
+			if now.Sub(at) >= actorRefreshCooldown {
+				delete(core.refreshedAt, key)
+			}
+		}
+		core.refreshSweep = now
 	}
-	if resp.Endpoints.SharedInbox != "" {
-		return resp.Endpoints.SharedInbox, nil
+
+	if at, ok := core.refreshedAt[actorURL]; ok && now.Sub(at) < actorRefreshCooldown {
+		return false
 	}
+	core.refreshedAt[actorURL] = now
+	return true
+}
 
-	return "", errors.New("remote actor inbox not found")
+// sameHost 判断两个 URL 是否属于同一主机
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Host != "" && strings.EqualFold(ua.Host, ub.Host)
+}
+
+// InvalidateRemoteActor 使远端 Actor 文档缓存失效
+func (core *FediverseCore) InvalidateRemoteActor(actorURL string) {
+	actorURL, _, _ = strings.Cut(actorURL, "#")
+	core.cache.Delete(remoteActorCacheKeyPrefix + ":" + actorURL)
+}
+
+// FetchActivityDocument 以 ActivityPub 格式拉取远端文档
+func FetchActivityDocument(documentURL string) ([]byte, error) {
+	if err := httpUtil.ValidateRemoteURL(documentURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, documentURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", `application/activity+json, application/ld+json; profile="https://www.w3.org/ns/activitystreams"`)
+	req.Header.Set("User-Agent", "Ech0-Fediverse")
+
+	resp, err := fetchClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", documentURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusGone {
+		return nil, ErrRemoteActorGone
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("fetch %s: unexpected status code %d", documentURL, resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxRemoteDocumentSize))
 }
@@ -1,19 +1,84 @@
 package fediverse
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
+	"context"
+	"time"
 
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+	model "github.com/lin-snow/ech0/internal/model/fediverse"
 	settingModel "github.com/lin-snow/ech0/internal/model/setting"
-	httpUtil "github.com/lin-snow/ech0/internal/util/http"
 	jsonUtil "github.com/lin-snow/ech0/internal/util/json"
 )
 
-// PushEchoToFediverse 将 Echo 推送到联邦网络
+// payloadBuilder 根据 Actor 与实例地址构建待推送的 Activity
+type payloadBuilder func(actor *model.Actor, serverURL string) ([]byte, error)
+
+// PushEchoToFediverse 将 Echo 以 Create 活动推送到联邦网络
 func (core *FediverseCore) PushEchoToFediverse(userId uint, echo echoModel.Echo) error {
+	if echo.Private {
+		return nil
+	}
+
+	return core.pushToFollowers(userId, func(actor *model.Actor, serverURL string) ([]byte, error) {
+		return core.BuildCreateActivityPayload(&echo, actor, serverURL)
+	})
+}
+
+// PushEchoUpdateToFediverse 将 Echo 的编辑以 Update 活动推送到联邦网络
+//
+// wasPrivate 为编辑前的可见性：公开转为私密时推送 Delete 撤回，私密转为公开时按新推文推送 Create。
+func (core *FediverseCore) PushEchoUpdateToFediverse(userId uint, echo echoModel.Echo, wasPrivate bool) error {
+	switch {
+	case echo.Private && wasPrivate:
+		return nil
+	case echo.Private:
+		return core.pushEchoRetract(userId, echo)
+	case wasPrivate:
+		return core.PushEchoToFediverse(userId, echo)
+	}
+
+	updated := time.Now().UTC()
+	return core.pushToFollowers(userId, func(actor *model.Actor, serverURL string) ([]byte, error) {
+		return core.BuildUpdateActivityPayload(&echo, actor, serverURL, updated)
+	})
+}
+
+// PushEchoDeleteToFediverse 将 Echo 的删除以 Delete 活动推送到联邦网络
+func (core *FediverseCore) PushEchoDeleteToFediverse(userId uint, echo echoModel.Echo) error {
+	if echo.Private {
+		return nil
+	}
+
+	deleted := time.Now().UTC()
+	return core.pushToFollowers(userId, func(actor *model.Actor, serverURL string) ([]byte, error) {
+		return BuildDeleteActivityPayload(echo.ID, actor, serverURL, deleted)
+	})
+}
+
+// pushEchoRetract 向粉丝推送 Delete 活动，撤回已转为私密的 Echo
+func (core *FediverseCore) pushEchoRetract(userId uint, echo echoModel.Echo) error {
+	deleted := time.Now().UTC()
+	return core.pushToFollowers(userId, func(actor *model.Actor, serverURL string) ([]byte, error) {
+		return BuildDeleteActivityPayload(echo.ID, actor, serverURL, deleted)
+	})
+}
+
+// RecordTombstone 记录已删除的公开 Echo，供 Object 接口返回 410
+func (core *FediverseCore) RecordTombstone(ctx context.Context, echo *echoModel.Echo) error {
+	if echo == nil || echo.ID == 0 || echo.Private {
+		return nil
+	}
+
+	return core.repo.SaveTombstone(ctx, &model.Tombstone{
+		EchoID:     echo.ID,
+		FormerType: "Note",
+		DeletedAt:  time.Now().UTC(),
+	})
+}
+
+// pushToFollowers 将构建好的 Activity 加入投递队列，推送给用户的全部粉丝
+func (core *FediverseCore) pushToFollowers(userId uint, build payloadBuilder) error {
 	// 检查是否开启了联邦网络功能
 	var fediverseSetting settingModel.FediverseSetting
 	if fediverseSettingJSON, err := core.keyvalueRepo.GetKeyValue(commonModel.FediverseSettingKey); err == nil {
@@ -28,10 +93,6 @@ func (core *FediverseCore) PushEchoToFediverse(userId uint, echo echoModel.Echo)
 		return nil
 	}
 
-	if echo.Private {
-		return nil
-	}
-
 	// 获取用户
 	user, err := core.userRepository.GetUserByID(int(userId))
 	if err != nil {
@@ -58,51 +119,13 @@ func (core *FediverseCore) PushEchoToFediverse(userId uint, echo echoModel.Echo)
 		return err
 	}
 
-	activity := core.ConvertEchoToActivity(&echo, &actor, serverURL)
-	object := core.ConvertEchoToObject(&echo, &actor, serverURL)
-
-	activityMap := map[string]any{}
-	activityBytes, err := json.Marshal(activity)
-	if err != nil {
-		return err
-	}
-	if err := json.Unmarshal(activityBytes, &activityMap); err != nil {
-		return err
-	}
-
-	objectMap := map[string]any{}
-	objectBytes, err := json.Marshal(object)
-	if err != nil {
-		return err
-	}
-	if err := json.Unmarshal(objectBytes, &objectMap); err != nil {
-		return err
-	}
-
-	activityMap["object"] = objectMap
-
-	payloadBytes, err := json.Marshal(activityMap)
+	payloadBytes, err := build(&actor, serverURL)
 	if err != nil {
 		return err
 	}
 
-	var errs []error
-	// 推送到每个粉丝的Inbox
-	for _, follower := range followers {
-		inboxURL, err := core.FetchRemoteActorInbox(follower.ActorID)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("fetch inbox for %s: %w", follower.ActorID, err))
-			continue
-		}
-
-		if err := httpUtil.PostActivity(payloadBytes, inboxURL, actor.ID); err != nil {
-			errs = append(errs, fmt.Errorf("post activity to %s: %w", inboxURL, err))
-		}
-	}
-
-	if len(errs) > 0 {
-		return errors.Join(errs...)
-	}
+	// 按收件箱加入投递队列，投递在后台并发进行
+	core.enqueueFollowerDeliveries(actor.ID, payloadBytes, followers)
 
 	return nil
 }
@@ -0,0 +1,126 @@
+package fediverse
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	model "github.com/lin-snow/ech0/internal/model/fediverse"
+	httpUtil "github.com/lin-snow/ech0/internal/util/http"
+)
+
+// maxSignatureClockSkew 允许的 Date 请求头时间偏差
+const maxSignatureClockSkew = 12 * time.Hour
+
+// VerifyInboxRequest 校验投递到 Inbox 的请求签名，返回签名者的 Actor URL
+//
+// 校验内容包括：Signature 头必须覆盖 (request-target)、date/(created) 与 digest，
+// Digest 与请求体一致，签名时间在允许范围内，签名可以被 keyId 对应 Actor 的公钥验证，
+// 且签名者必须与 Activity 的 actor 属于同一个 Actor。
+func (core *FediverseCore) VerifyInboxRequest(
+	req *http.Request,
+	body []byte,
+	activityActor string,
+) (string, error) {
+	sig, err := httpUtil.ParseSignatureHeader(req.Header.Get("Signature"))
+	if err != nil {
+		return "", err
+	}
+
+	// 必须签名的请求头
+	for _, required := range []string{"(request-target)", "digest"} {
+		if !slices.Contains(sig.Headers, required) {
+			return "", errors.New("signature does not cover " + required)
+		}
+	}
+	// 必须签名时间信息，防止截获的请求被无限重放
+	signsDate := slices.Contains(sig.Headers, "date")
+	signsCreated := slices.Contains(sig.Headers, "(created)")
+	if !signsDate && !signsCreated {
+		return "", errors.New("signature does not cover date or (created)")
+	}
+
+	if err := httpUtil.VerifyDigest(req, body); err != nil {
+		return "", err
+	}
+	if signsDate {
+		if err := httpUtil.VerifyDate(req, maxSignatureClockSkew); err != nil {
+			return "", err
+		}
+	}
+	if signsCreated {
+		if err := httpUtil.VerifyCreated(sig, maxSignatureClockSkew); err != nil {
+			return "", err
+		}
+	}
+
+	// 获取公钥并校验，失败时重新拉取一次以兼容密钥轮换
+	actor, err := core.FetchRemoteActor(sig.KeyID, false)
+	if err != nil {
+		return "", err
+	}
+	if err := core.verifyWithActorKey(req, sig, actor); err != nil {
+		// 强制刷新有冷却时间，避免伪造签名的请求反复触发远端拉取
+		if !core.allowActorRefresh(sig.KeyID) {
+			return "", err
+		}
+		actor, err = core.FetchRemoteActor(sig.KeyID, true)
+		if err != nil {
+			return "", err
+		}
+		if err := core.verifyWithActorKey(req, sig, actor); err != nil {
+			return "", err
+		}
+	}
+
+	// 签名者必须是 Activity 的 actor
+	if activityActor != "" && !SameActor(actor.ID, activityActor) {
+		return "", errors.New(commonModel.SIGNATURE_ACTOR_MISMATCH)
+	}
+
+	return actor.ID, nil
+}
+
+// verifyWithActorKey 使用 Actor 的公钥校验签名，公钥必须与 keyId 一致且归属于该 Actor
+func (core *FediverseCore) verifyWithActorKey(
+	req *http.Request,
+	sig *httpUtil.HTTPSignature,
+	actor *model.RemoteActor,
+) error {
+	if actor.PublicKey.ID != "" && actor.PublicKey.ID != sig.KeyID {
+		return errors.New("signature keyId does not match actor public key")
+	}
+	if !SameActor(actor.PublicKey.Owner, actor.ID) {
+		return errors.New("actor public key owner does not match actor id")
+	}
+
+	pub, err := httpUtil.ParsePublicKeyPem(actor.PublicKey.PublicKeyPem)
+	if err != nil {
+		return err
+	}
+
+	return httpUtil.VerifySignature(req, sig, pub)
+}
+
+// SameActor 判断两个 Actor URL 是否指向同一个 Actor
+func SameActor(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(ua.Host, ub.Host) &&
+		strings.TrimRight(ua.Path, "/") == strings.TrimRight(ub.Path, "/")
+}
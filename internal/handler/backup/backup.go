@@ -1,23 +1,33 @@
 package handler
 
 import (
+	"errors"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lin-snow/ech0/internal/backup"
 	res "github.com/lin-snow/ech0/internal/handler/response"
+	authModel "github.com/lin-snow/ech0/internal/model/auth"
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	service "github.com/lin-snow/ech0/internal/service/backup"
+	settingService "github.com/lin-snow/ech0/internal/service/setting"
 	jwtUtil "github.com/lin-snow/ech0/internal/util/jwt"
 )
 
 type BackupHandler struct {
-	backupService service.BackupServiceInterface
+	backupService  service.BackupServiceInterface
+	settingService settingService.SettingServiceInterface
 }
 
 // NewBackupHandler BackupHandler 的构造函数
-func NewBackupHandler(backupService service.BackupServiceInterface) *BackupHandler {
+func NewBackupHandler(
+	backupService service.BackupServiceInterface,
+	settingService settingService.SettingServiceInterface,
+) *BackupHandler {
 	return &BackupHandler{
-		backupService: backupService,
+		backupService:  backupService,
+		settingService: settingService,
 	}
 }
 
@@ -77,6 +87,25 @@ func (backupHandler *BackupHandler) ExportBackup() gin.HandlerFunc {
 			}
 		}
 
+		// 确认令牌未被撤销，访问令牌需要具备 backup 权限
+		scopes, scoped, err := backupHandler.settingService.AuthenticateToken(
+			claims,
+			token,
+			ctx.ClientIP(),
+		)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.TOKEN_REVOKED,
+				Err: err,
+			}
+		}
+		if scoped && !slices.Contains(scopes, authModel.ScopeBackup) {
+			return res.Response{
+				Msg: commonModel.TOKEN_SCOPE_DENIED,
+				Err: errors.New(commonModel.TOKEN_SCOPE_DENIED),
+			}
+		}
+
 		// 从 Claims中提取 UserID
 		userId := uint(claims.Userid)
 		if err := backupHandler.backupService.ExportBackup(ctx, userId); err != nil {
@@ -92,23 +121,23 @@ func (backupHandler *BackupHandler) ExportBackup() gin.HandlerFunc {
 	})
 }
 
-// ImportBackup 恢复数据备份
+// PreviewRestore 预检数据备份
 //
-//	@Summary		恢复数据备份
-//	@Description	用户上传备份文件，成功后恢复数据
+//	@Summary		预检数据备份
+//	@Description	上传备份文件并在副本上执行迁移，返回备份与当前数据的对比，不改动当前数据；确认后使用返回的 token 提交恢复
 //	@Tags			系统备份
 //	@Accept			multipart/form-data
 //	@Produce		json
-//	@Param			file	formData	file			true	"备份文件"
-//	@Success		200		{object}	res.Response	"导入备份成功"
-//	@Failure		200		{object}	res.Response	"导入备份失败"
-//	@Router			/backup/import [post]
-func (backupHandler *BackupHandler) ImportBackup() gin.HandlerFunc {
+//	@Param			file		formData	file										true	"备份文件"
+//	@Param			passphrase	formData	string										false	"加密备份的口令"
+//	@Param			identity	formData	string										false	"加密备份的 age 私钥（AGE-SECRET-KEY-1...）"
+//	@Success		200			{object}	res.Response{data=backup.RestorePreview}	"备份预检完成"
+//	@Failure		200			{object}	res.Response								"备份预检失败"
+//	@Router			/backup/import/preview [post]
+func (backupHandler *BackupHandler) PreviewRestore() gin.HandlerFunc {
 	return res.Execute(func(ctx *gin.Context) res.Response {
-		// 提取userid
 		userId := ctx.MustGet("userid").(uint)
 
-		// 提取上传的 File数据
 		file, err := ctx.FormFile("file")
 		if err != nil {
 			return res.Response{
@@ -117,7 +146,41 @@ func (backupHandler *BackupHandler) ImportBackup() gin.HandlerFunc {
 			}
 		}
 
-		if err := backupHandler.backupService.ImportBackup(ctx, userId, file); err != nil {
+		decryption := backup.Decryption{
+			Passphrase: ctx.PostForm("passphrase"),
+			Identity:   ctx.PostForm("identity"),
+		}
+
+		preview, err := backupHandler.backupService.PreviewRestore(ctx, userId, file, decryption)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: preview,
+			Msg:  commonModel.PREVIEW_RESTORE_SUCCESS,
+		}
+	})
+}
+
+// CommitRestore 确认恢复数据备份
+//
+//	@Summary		确认恢复数据备份
+//	@Description	使用预检返回的 token 执行恢复，覆盖当前数据
+//	@Tags			系统备份
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	path		string			true	"预检返回的 token"
+//	@Success		200		{object}	res.Response	"导入备份成功"
+//	@Failure		200		{object}	res.Response	"导入备份失败"
+//	@Router			/backup/import/{token}/commit [post]
+func (backupHandler *BackupHandler) CommitRestore() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userId := ctx.MustGet("userid").(uint)
+		if err := backupHandler.backupService.CommitRestore(userId, ctx.Param("token")); err != nil {
 			return res.Response{
 				Msg: "",
 				Err: err,
@@ -130,6 +193,115 @@ func (backupHandler *BackupHandler) ImportBackup() gin.HandlerFunc {
 	})
 }
 
+// DiscardRestore 取消恢复数据备份
+//
+//	@Summary		取消恢复数据备份
+//	@Description	放弃预检通过但未提交的恢复，删除暂存数据
+//	@Tags			系统备份
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	path		string			true	"预检返回的 token"
+//	@Success		200		{object}	res.Response	"已取消恢复"
+//	@Failure		200		{object}	res.Response	"取消恢复失败"
+//	@Router			/backup/import/{token} [delete]
+func (backupHandler *BackupHandler) DiscardRestore() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userId := ctx.MustGet("userid").(uint)
+		if err := backupHandler.backupService.DiscardRestore(userId, ctx.Param("token")); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.DISCARD_RESTORE_SUCCESS,
+		}
+	})
+}
+
+// ListBackups 获取备份列表
+//
+//	@Summary		获取备份列表
+//	@Description	获取服务器上保存的所有备份文件，按时间从新到旧排序
+//	@Tags			系统备份
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	res.Response{data=[]backup.ArchiveInfo}	"获取备份列表成功"
+//	@Failure		200	{object}	res.Response							"获取备份列表失败"
+//	@Router			/backup/archives [get]
+func (backupHandler *BackupHandler) ListBackups() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userId := ctx.MustGet("userid").(uint)
+		archives, err := backupHandler.backupService.ListBackups(userId)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: archives,
+			Msg:  commonModel.LIST_BACKUPS_SUCCESS,
+		}
+	})
+}
+
+// DownloadBackup 下载指定备份
+//
+//	@Summary		下载指定备份
+//	@Description	下载服务器上保存的指定备份文件
+//	@Tags			系统备份
+//	@Accept			json
+//	@Produce		application/octet-stream
+//	@Param			name	path		string			true	"备份文件名"
+//	@Success		200		{object}	res.Response	"返回文件下载"
+//	@Failure		200		{object}	res.Response	"下载备份失败"
+//	@Router			/backup/archives/{name} [get]
+func (backupHandler *BackupHandler) DownloadBackup() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userId := ctx.MustGet("userid").(uint)
+		if err := backupHandler.backupService.DownloadBackup(ctx, userId, ctx.Param("name")); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.EXPORT_BACKUP_SUCCESS,
+		}
+	})
+}
+
+// DeleteBackup 删除指定备份
+//
+//	@Summary		删除指定备份
+//	@Description	删除服务器上保存的指定备份文件
+//	@Tags			系统备份
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string			true	"备份文件名"
+//	@Success		200		{object}	res.Response	"删除备份成功"
+//	@Failure		200		{object}	res.Response	"删除备份失败"
+//	@Router			/backup/archives/{name} [delete]
+func (backupHandler *BackupHandler) DeleteBackup() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userId := ctx.MustGet("userid").(uint)
+		if err := backupHandler.backupService.DeleteBackup(userId, ctx.Param("name")); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.DELETE_BACKUP_SUCCESS,
+		}
+	})
+}
+
 // SyncLegacy 手动同步数据到原版表 (images)
 func (backupHandler *BackupHandler) SyncLegacy() gin.HandlerFunc {
 	return res.Execute(func(ctx *gin.Context) res.Response {
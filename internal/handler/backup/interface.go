@@ -11,8 +11,23 @@ type BackupHandlerInterface interface {
 	// ExportBackup 导出备份
 	ExportBackup() gin.HandlerFunc
 
-	// ImportBackup 恢复备份
-	ImportBackup() gin.HandlerFunc
+	// PreviewRestore 预检备份
+	PreviewRestore() gin.HandlerFunc
+
+	// CommitRestore 确认恢复
+	CommitRestore() gin.HandlerFunc
+
+	// DiscardRestore 取消恢复
+	DiscardRestore() gin.HandlerFunc
+
+	// ListBackups 获取备份列表
+	ListBackups() gin.HandlerFunc
+
+	// DownloadBackup 下载指定备份
+	DownloadBackup() gin.HandlerFunc
+
+	// DeleteBackup 删除指定备份
+	DeleteBackup() gin.HandlerFunc
 
 	// Legacy 兼容性同步与清理 (原版)
 	SyncLegacy() gin.HandlerFunc
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	res "github.com/lin-snow/ech0/internal/handler/response"
+	model "github.com/lin-snow/ech0/internal/model/comment"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	service "github.com/lin-snow/ech0/internal/service/comment"
+)
+
+type CommentHandler struct {
+	commentService service.CommentServiceInterface
+}
+
+// NewCommentHandler CommentHandler 的构造函数
+func NewCommentHandler(commentService service.CommentServiceInterface) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+// GetEchoComments 获取 Echo 的评论
+//
+//	@Summary		获取 Echo 的评论
+//	@Description	获取指定 Echo 下已通过审核的评论（含联邦网络回复），按楼层组织，无需登录
+//	@Tags			评论
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int									true	"Echo ID"
+//	@Success		200	{object}	res.Response{data=[]model.Comment}	"获取成功"
+//	@Failure		200	{object}	res.Response						"获取失败"
+//	@Router			/echo/{id}/comments [get]
+func (commentHandler *CommentHandler) GetEchoComments() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		userid := ctx.MustGet("userid").(uint)
+		comments, err := commentHandler.commentService.GetEchoComments(userid, uint(id))
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: comments,
+			Msg:  commonModel.GET_COMMENTS_SUCCESS,
+		}
+	})
+}
+
+// CreateComment 发表评论
+//
+//	@Summary		发表评论
+//	@Description	对指定 Echo 发表评论或回复已有评论，访客需填写昵称，邮箱与主页可选；未开启免审核时访客评论需管理员审核后展示
+//	@Tags			评论
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"Echo ID"
+//	@Param			comment	body		model.CreateCommentDto			true	"评论内容"
+//	@Success		200		{object}	res.Response{data=model.Comment}	"评论成功"
+//	@Failure		200		{object}	res.Response					"评论失败"
+//	@Router			/echo/{id}/comments [post]
+func (commentHandler *CommentHandler) CreateComment() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		var dto model.CreateCommentDto
+		if err := ctx.ShouldBindJSON(&dto); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		userAgent := ctx.Request.UserAgent()
+		client := model.ClientInfo{
+			IP:        ctx.ClientIP(),
+			UserAgent: userAgent[:min(len(userAgent), 255)],
+		}
+
+		userid := ctx.MustGet("userid").(uint)
+		comment, err := commentHandler.commentService.CreateComment(userid, uint(id), &dto, client)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		msg := commonModel.CREATE_COMMENT_SUCCESS
+		if comment.Status == model.CommentStatusPending {
+			msg = commonModel.CREATE_COMMENT_PENDING
+		}
+
+		return res.Response{
+			Data: comment,
+			Msg:  msg,
+		}
+	})
+}
+
+// GetComments 获取评论管理列表
+//
+//	@Summary		获取评论管理列表
+//	@Description	分页查看全部评论（含待审核与垃圾评论），可按审核状态、Echo 与来源过滤，仅管理员可用
+//	@Tags			评论
+//	@Accept			json
+//	@Produce		json
+//	@Param			status		query		string															false	"审核状态 pending/approved/spam"
+//	@Param			echo_id		query		int																false	"Echo ID"
+//	@Param			source		query		string															false	"来源 local/fediverse"
+//	@Param			page		query		int																false	"页码"
+//	@Param			pageSize	query		int																false	"每页大小"
+//	@Success		200			{object}	res.Response{data=commonModel.PageQueryResult[[]model.Comment]}	"获取成功"
+//	@Failure		200			{object}	res.Response													"获取失败"
+//	@Router			/comments [get]
+func (commentHandler *CommentHandler) GetComments() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		var dto model.CommentQueryDto
+		if err := ctx.ShouldBindQuery(&dto); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_QUERY_PARAMS,
+				Err: err,
+			}
+		}
+
+		userid := ctx.MustGet("userid").(uint)
+		result, err := commentHandler.commentService.GetComments(userid, dto)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: result,
+			Msg:  commonModel.GET_COMMENTS_SUCCESS,
+		}
+	})
+}
+
+// UpdateCommentStatus 审核评论
+//
+//	@Summary		审核评论
+//	@Description	将评论标记为待审核、已通过或垃圾评论，仅管理员可用
+//	@Tags			评论
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"评论 ID"
+//	@Param			status	body		model.UpdateCommentStatusDto	true	"审核状态"
+//	@Success		200		{object}	res.Response					"更新成功"
+//	@Failure		200		{object}	res.Response					"更新失败"
+//	@Router			/comments/{id}/status [put]
+func (commentHandler *CommentHandler) UpdateCommentStatus() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		var dto model.UpdateCommentStatusDto
+		if err := ctx.ShouldBindJSON(&dto); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		userid := ctx.MustGet("userid").(uint)
+		if err := commentHandler.commentService.UpdateCommentStatus(userid, uint(id), dto.Status); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.UPDATE_COMMENT_STATUS_SUCCESS,
+		}
+	})
+}
+
+// DeleteComment 删除评论
+//
+//	@Summary		删除评论
+//	@Description	删除评论及其全部子回复，仅管理员可用
+//	@Tags			评论
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int				true	"评论 ID"
+//	@Success		200	{object}	res.Response	"删除成功"
+//	@Failure		200	{object}	res.Response	"删除失败"
+//	@Router			/comments/{id} [delete]
+func (commentHandler *CommentHandler) DeleteComment() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		userid := ctx.MustGet("userid").(uint)
+		if err := commentHandler.commentService.DeleteComment(userid, uint(id)); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.DELETE_COMMENT_SUCCESS,
+		}
+	})
+}
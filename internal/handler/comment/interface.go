@@ -0,0 +1,20 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+type CommentHandlerInterface interface {
+	// GetEchoComments 获取 Echo 的评论
+	GetEchoComments() gin.HandlerFunc
+
+	// CreateComment 发表评论
+	CreateComment() gin.HandlerFunc
+
+	// GetComments 获取评论管理列表
+	GetComments() gin.HandlerFunc
+
+	// UpdateCommentStatus 审核评论
+	UpdateCommentStatus() gin.HandlerFunc
+
+	// DeleteComment 删除评论
+	DeleteComment() gin.HandlerFunc
+}
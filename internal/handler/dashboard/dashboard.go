@@ -7,6 +7,7 @@ import (
 	res "github.com/lin-snow/ech0/internal/handler/response"
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	service "github.com/lin-snow/ech0/internal/service/dashboard"
+	settingService "github.com/lin-snow/ech0/internal/service/setting"
 	jwtUtil "github.com/lin-snow/ech0/internal/util/jwt"
 	logUtil "github.com/lin-snow/ech0/internal/util/log"
 	"go.uber.org/zap"
@@ -14,11 +15,16 @@ import (
 
 type DashboardHandler struct {
 	dashboardService service.DashboardServiceInterface
+	settingService   settingService.SettingServiceInterface
 }
 
-func NewDashboardHandler(dashboardService service.DashboardServiceInterface) *DashboardHandler {
+func NewDashboardHandler(
+	dashboardService service.DashboardServiceInterface,
+	settingService settingService.SettingServiceInterface,
+) *DashboardHandler {
 	return &DashboardHandler{
 		dashboardService: dashboardService,
+		settingService:   settingService,
 	}
 }
 
@@ -67,7 +73,18 @@ func (dashboardHandler *DashboardHandler) WSSubsribeMetrics() gin.HandlerFunc {
 		token = strings.Trim(token, `"`) // 去掉可能的双引号
 
 		// 使用 JWT Util进行处理
-		if _, err := jwtUtil.ParseToken(token); err != nil {
+		claims, err := jwtUtil.ParseToken(token)
+		if err != nil {
+			return
+		}
+
+		// 访问令牌不允许订阅系统指标
+		_, scoped, err := dashboardHandler.settingService.AuthenticateToken(
+			claims,
+			token,
+			ctx.ClientIP(),
+		)
+		if err != nil || scoped {
 			return
 		}
 
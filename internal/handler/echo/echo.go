@@ -27,12 +27,12 @@ func NewEchoHandler(echoService service.EchoServiceInterface) *EchoHandler {
 // PostEcho 创建新的Echo
 //
 //	@Summary		创建新的Echo
-//	@Description	用户创建一条新的Echo动态
+//	@Description	用户创建一条新的Echo动态；status 为 draft 时保存为草稿，为 scheduled 时在 publish_at 定时发布，发布前不推送任何事件
 //	@Tags			Echo
 //	@Accept			json
 //	@Produce		json
 //	@Param			echo	body		model.Echo		true	"Echo内容"
-//	@Success		200		{object}	res.Response	"创建成功"
+//	@Success		200		{object}	res.Response{data=model.Echo}	"创建成功"
 //	@Failure		200		{object}	res.Response	"创建失败"
 //	@Router			/echo [post]
 func (echoHandler *EchoHandler) PostEcho() gin.HandlerFunc {
@@ -53,8 +53,18 @@ func (echoHandler *EchoHandler) PostEcho() gin.HandlerFunc {
 			}
 		}
 
+		// 返回创建的 Echo，草稿可据此 ID 继续自动保存
+		msg := commonModel.POST_ECHO_SUCCESS
+		switch newEcho.Status {
+		case model.EchoStatusDraft:
+			msg = commonModel.SAVE_DRAFT_SUCCESS
+		case model.EchoStatusScheduled:
+			msg = commonModel.SCHEDULE_ECHO_SUCCESS
+		}
+
 		return res.Response{
-			Msg: commonModel.POST_ECHO_SUCCESS,
+			Data: newEcho,
+			Msg:  msg,
 		}
 	})
 }
@@ -127,7 +137,7 @@ func (echoHandler *EchoHandler) GetEchosByPage() gin.HandlerFunc {
 // DeleteEcho 删除Echo
 //
 //	@Summary		删除Echo
-//	@Description	根据ID删除指定的Echo动态
+//	@Description	根据ID将指定的Echo动态移入回收站，超过保留期限后永久删除
 //	@Tags			Echo
 //	@Accept			json
 //	@Produce		json
@@ -195,7 +205,7 @@ func (echoHandler *EchoHandler) GetTodayEchos() gin.HandlerFunc {
 // UpdateEcho 更新Echo
 //
 //	@Summary		更新Echo
-//	@Description	更新指定的Echo动态内容
+//	@Description	更新指定的Echo动态内容，也用于自动保存草稿；草稿或定时 Echo 的 status 改为 published 时立即发布，已发布的 Echo 不能改回草稿或定时发布
 //	@Tags			Echo
 //	@Accept			json
 //	@Produce		json
@@ -221,8 +231,16 @@ func (echoHandler *EchoHandler) UpdateEcho() gin.HandlerFunc {
 			}
 		}
 
+		msg := commonModel.UPDATE_ECHO_SUCCESS
+		switch updateEcho.Status {
+		case model.EchoStatusDraft:
+			msg = commonModel.SAVE_DRAFT_SUCCESS
+		case model.EchoStatusScheduled:
+			msg = commonModel.SCHEDULE_ECHO_SUCCESS
+		}
+
 		return res.Response{
-			Msg: commonModel.UPDATE_ECHO_SUCCESS,
+			Msg: msg,
 		}
 	})
 }
@@ -230,13 +248,13 @@ func (echoHandler *EchoHandler) UpdateEcho() gin.HandlerFunc {
 // LikeEcho 点赞Echo
 //
 //	@Summary		点赞Echo
-//	@Description	根据ID为指定的Echo动态点赞
+//	@Description	根据ID为指定的Echo动态点赞，登录用户按用户ID、访客按签名Cookie与指纹去重，同一IP有频率限制
 //	@Tags			Echo
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		int				true	"Echo ID"
-//	@Success		200	{object}	res.Response	"点赞成功"
-//	@Failure		200	{object}	res.Response	"点赞失败"
+//	@Param			id	path		int										true	"Echo ID"
+//	@Success		200	{object}	res.Response{data=model.EchoLikeResult}	"点赞成功"
+//	@Failure		200	{object}	res.Response							"点赞失败"
 //	@Router			/echo/like/{id} [put]
 func (echoHandler *EchoHandler) LikeEcho() gin.HandlerFunc {
 	return res.Execute(func(ctx *gin.Context) res.Response {
@@ -249,15 +267,22 @@ func (echoHandler *EchoHandler) LikeEcho() gin.HandlerFunc {
 			}
 		}
 
-		if err := echoHandler.echoService.LikeEcho(uint(id)); err != nil {
+		identity, visitorToken := likeIdentity(ctx)
+		result, err := echoHandler.echoService.LikeEcho(uint(id), identity)
+		if err != nil {
 			return res.Response{
 				Msg: "",
 				Err: err,
 			}
 		}
+		// 只在点赞成功后下发访客 Cookie，被拒绝的请求拿不到新的身份
+		if visitorToken != "" {
+			setVisitorCookie(ctx, visitorToken)
+		}
 
 		return res.Response{
-			Msg: commonModel.LIKE_ECHO_SUCCESS,
+			Data: result,
+			Msg:  commonModel.LIKE_ECHO_SUCCESS,
 		}
 	})
 }
@@ -301,6 +326,34 @@ func (echoHandler *EchoHandler) GetEchoById() gin.HandlerFunc {
 	})
 }
 
+// GetUnpublishedEchos 获取草稿与定时发布的 Echo 列表
+//
+//	@Summary		获取草稿与定时发布的Echo
+//	@Description	获取全部草稿与定时发布的Echo，定时发布的按发布时间升序排在前面，仅管理员可用
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	res.Response{data=[]model.Echo}	"获取成功"
+//	@Failure		200	{object}	res.Response					"获取失败"
+//	@Router			/echo/drafts [get]
+func (echoHandler *EchoHandler) GetUnpublishedEchos() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userId := ctx.MustGet("userid").(uint)
+		echos, err := echoHandler.echoService.GetUnpublishedEchos(userId)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: echos,
+			Msg:  commonModel.GET_DRAFT_ECHOS_SUCCESS,
+		}
+	})
+}
+
 // GetAllTags 获取所有标签
 //
 //	@Summary		获取所有标签
@@ -490,3 +543,48 @@ func (echoHandler *EchoHandler) GetEchosByDate() gin.HandlerFunc {
 		}
 	})
 }
+
+// SearchEchos 全文搜索 Echo
+//
+// @Summary 全文搜索 Echo
+// @Description 基于 SQLite FTS5 的全文搜索，支持短语 "..."、前缀 abc* 与 AND/OR/NOT，结果按相关度排序并返回 <mark> 高亮片段；少于 3 个字符的词退化为模糊匹配
+// @Tags Echo
+// @Accept json
+// @Produce json
+// @Param q query string false "搜索语句"
+// @Param tag query string false "标签名"
+// @Param start_date query string false "起始日期 (YYYY-MM-DD)"
+// @Param end_date query string false "结束日期 (YYYY-MM-DD)"
+// @Param has_media query bool false "是否包含媒体"
+// @Param extension_type query string false "扩展类型"
+// @Param page query int false "页码"
+// @Param pageSize query int false "每页数量"
+// @Success 200 {object} res.Response{data=commonModel.PageQueryResult[[]model.EchoSearchResult]} "搜索成功"
+// @Failure 200 {object} res.Response "搜索失败"
+// @Router /echo/search [get]
+func (echoHandler *EchoHandler) SearchEchos() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		var searchDto model.EchoSearchDto
+		if err := ctx.ShouldBindQuery(&searchDto); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_QUERY_PARAMS,
+				Err: err,
+			}
+		}
+
+		userid := ctx.MustGet("userid").(uint)
+
+		result, err := echoHandler.echoService.SearchEchos(userid, searchDto)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: result,
+			Msg:  commonModel.SEARCH_ECHOS_SUCCESS,
+		}
+	})
+}
@@ -21,9 +21,42 @@ type EchoHandlerInterface interface {
 	// LikeEcho 点赞 Echo
 	LikeEcho() gin.HandlerFunc
 
+	// UnlikeEcho 取消点赞Echo
+	UnlikeEcho() gin.HandlerFunc
+
+	// GetEchoLikes 获取点赞记录
+	GetEchoLikes() gin.HandlerFunc
+
+	// CleanupEchoLikes 批量清理点赞记录
+	CleanupEchoLikes() gin.HandlerFunc
+
 	// GetEchoById 获取指定 ID 的 Echo
 	GetEchoById() gin.HandlerFunc
 
+	// GetUnpublishedEchos 获取草稿与定时发布的 Echo 列表
+	GetUnpublishedEchos() gin.HandlerFunc
+
+	// GetTrashedEchos 获取回收站中的 Echo 列表
+	GetTrashedEchos() gin.HandlerFunc
+
+	// RestoreTrashedEcho 将 Echo 从回收站恢复
+	RestoreTrashedEcho() gin.HandlerFunc
+
+	// PurgeTrashedEcho 永久删除回收站中的 Echo
+	PurgeTrashedEcho() gin.HandlerFunc
+
+	// EmptyTrash 清空回收站
+	EmptyTrash() gin.HandlerFunc
+
+	// GetEchoRevisions 获取 Echo 的历史版本列表
+	GetEchoRevisions() gin.HandlerFunc
+
+	// DiffEchoRevisions 对比 Echo 的两个版本
+	DiffEchoRevisions() gin.HandlerFunc
+
+	// RestoreEchoRevision 将 Echo 恢复到指定的历史版本
+	RestoreEchoRevision() gin.HandlerFunc
+
 	// GetAllTags 获取所有标签
 	GetAllTags() gin.HandlerFunc
 
@@ -35,4 +68,7 @@ type EchoHandlerInterface interface {
 
 	// GetEchosByDate 获取指定日期的 Echo 列表
 	GetEchosByDate() gin.HandlerFunc
+
+	// SearchEchos 全文搜索 Echo
+	SearchEchos() gin.HandlerFunc
 }
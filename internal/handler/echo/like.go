@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	res "github.com/lin-snow/ech0/internal/handler/response"
+	authModel "github.com/lin-snow/ech0/internal/model/auth"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	model "github.com/lin-snow/ech0/internal/model/echo"
+	visitorUtil "github.com/lin-snow/ech0/internal/util/visitor"
+)
+
+const (
+	// visitorCookieMaxAge 访客标识 Cookie 的有效期（秒）
+	visitorCookieMaxAge = 365 * 24 * 60 * 60
+	// visitorFingerprintWindow 访客 Cookie 签发后仍按指纹匹配点赞的时长
+	visitorFingerprintWindow = 7 * 24 * time.Hour
+)
+
+// UnlikeEcho 取消点赞Echo
+//
+//	@Summary		取消点赞Echo
+//	@Description	取消当前用户或访客对指定Echo的点赞
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int										true	"Echo ID"
+//	@Success		200	{object}	res.Response{data=model.EchoLikeResult}	"取消点赞成功"
+//	@Failure		200	{object}	res.Response							"取消点赞失败"
+//	@Router			/echo/like/{id} [delete]
+func (echoHandler *EchoHandler) UnlikeEcho() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		// 取消点赞不下发新的访客 Cookie
+		identity, _ := likeIdentity(ctx)
+		result, err := echoHandler.echoService.UnlikeEcho(uint(id), identity)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: result,
+			Msg:  commonModel.UNLIKE_ECHO_SUCCESS,
+		}
+	})
+}
+
+// GetEchoLikes 获取点赞记录
+//
+//	@Summary		获取点赞记录
+//	@Description	分页查看点赞记录（Echo、身份类型、IP、User-Agent 与时间），可按 Echo、IP 与身份类型过滤，仅管理员可用
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Param			echo_id		query		int																false	"Echo ID"
+//	@Param			ip			query		string															false	"IP"
+//	@Param			kind		query		string															false	"身份类型 user/visitor/fingerprint/legacy"
+//	@Param			page		query		int																false	"页码"
+//	@Param			pageSize	query		int																false	"每页大小"
+//	@Success		200			{object}	res.Response{data=commonModel.PageQueryResult[[]model.EchoLike]}	"获取成功"
+//	@Failure		200			{object}	res.Response													"获取失败"
+//	@Router			/echo/likes [get]
+func (echoHandler *EchoHandler) GetEchoLikes() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		var dto model.EchoLikeQueryDto
+		if err := ctx.ShouldBindQuery(&dto); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_QUERY_PARAMS,
+				Err: err,
+			}
+		}
+
+		userId := ctx.MustGet("userid").(uint)
+		result, err := echoHandler.echoService.GetEchoLikes(userId, dto)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: result,
+			Msg:  commonModel.GET_ECHO_LIKES_SUCCESS,
+		}
+	})
+}
+
+// CleanupEchoLikes 批量清理点赞记录
+//
+//	@Summary		批量清理点赞记录
+//	@Description	按 Echo、IP、身份类型与时间范围批量删除刷赞记录并重新计算点赞数，至少需要指定一项条件，仅管理员可用
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Param			cleanup	body		model.EchoLikeCleanupDto	true	"清理条件"
+//	@Success		200		{object}	res.Response{data=int}		"清理成功，返回删除的记录数"
+//	@Failure		200		{object}	res.Response				"清理失败"
+//	@Router			/echo/likes/cleanup [post]
+func (echoHandler *EchoHandler) CleanupEchoLikes() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		var dto model.EchoLikeCleanupDto
+		if err := ctx.ShouldBindJSON(&dto); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		userId := ctx.MustGet("userid").(uint)
+		deleted, err := echoHandler.echoService.CleanupEchoLikes(userId, dto)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: deleted,
+			Msg:  commonModel.CLEANUP_ECHO_LIKES_SUCCESS,
+		}
+	})
+}
+
+// likeIdentity 根据登录状态、访客 Cookie 与指纹确定点赞身份
+// 访客没有有效的 Cookie 时生成新的访客标识并返回对应的 Cookie 值，由调用方在点赞成功后再下发
+func likeIdentity(ctx *gin.Context) (model.LikeIdentity, string) {
+	ip := ctx.ClientIP()
+	userAgent := ctx.Request.UserAgent()
+	identity := model.LikeIdentity{
+		IP:          ip,
+		UserAgent:   userAgent[:min(len(userAgent), 255)],
+		Fingerprint: visitorUtil.Fingerprint(ip, userAgent, ctx.GetHeader("Accept-Language")),
+	}
+
+	if userid := ctx.MustGet("userid").(uint); userid != authModel.NO_USER_LOGINED {
+		identity.Kind = model.LikeKindUser
+		identity.UserID = userid
+		identity.Identity = fmt.Sprintf("%s:%d", model.LikeKindUser, userid)
+		return identity, ""
+	}
+
+	if cookie, err := ctx.Cookie(visitorUtil.CookieName); err == nil {
+		if visitorID, issuedAt, ok := visitorUtil.ParseVisitorToken(cookie); ok {
+			identity.Kind = model.LikeKindVisitor
+			identity.Identity = model.LikeKindVisitor + ":" + visitorID
+			// 新签发的 Cookie 同时按指纹匹配，防止反复换新 Cookie 重复点赞
+			identity.MatchFingerprint = !issuedAt.IsZero() && time.Since(issuedAt) < visitorFingerprintWindow
+			return identity, ""
+		}
+	}
+
+	// 点赞记录归属新的访客标识，下发 Cookie 后仍能识别为同一访客
+	visitorID, token := visitorUtil.NewVisitorToken()
+	identity.Kind = model.LikeKindFingerprint
+	identity.Identity = model.LikeKindVisitor + ":" + visitorID
+	identity.MatchFingerprint = true
+	return identity, token
+}
+
+// setVisitorCookie 下发访客标识 Cookie
+func setVisitorCookie(ctx *gin.Context, token string) {
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(visitorUtil.CookieName, token, visitorCookieMaxAge, "/", "", ctx.Request.TLS != nil, true)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	res "github.com/lin-snow/ech0/internal/handler/response"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+)
+
+// GetEchoRevisions 获取 Echo 的历史版本列表
+//
+//	@Summary		获取Echo的历史版本
+//	@Description	获取指定Echo每次更新前保存的历史版本（内容、标签、媒体、布局与扩展），按时间倒序排列，仅管理员可用
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int										true	"Echo ID"
+//	@Success		200	{object}	res.Response{data=[]model.EchoRevision}	"获取成功"
+//	@Failure		200	{object}	res.Response							"获取失败"
+//	@Router			/echo/{id}/revisions [get]
+func (echoHandler *EchoHandler) GetEchoRevisions() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		userId := ctx.MustGet("userid").(uint)
+		revisions, err := echoHandler.echoService.GetEchoRevisions(userId, uint(id))
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: revisions,
+			Msg:  commonModel.GET_ECHO_REVISIONS_SUCCESS,
+		}
+	})
+}
+
+// DiffEchoRevisions 对比 Echo 的两个版本
+//
+//	@Summary		对比Echo的两个版本
+//	@Description	以 unified diff 格式返回两个历史版本之间的差异，不指定 to 时与当前版本对比，仅管理员可用
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int										true	"Echo ID"
+//	@Param			from	query		int										true	"起始版本 ID"
+//	@Param			to		query		int										false	"目标版本 ID，默认当前版本"
+//	@Success		200		{object}	res.Response{data=model.EchoRevisionDiff}	"对比成功"
+//	@Failure		200		{object}	res.Response							"对比失败"
+//	@Router			/echo/{id}/revisions/diff [get]
+func (echoHandler *EchoHandler) DiffEchoRevisions() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		var query struct {
+			From uint `form:"from" binding:"required"`
+			To   uint `form:"to"`
+		}
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_QUERY_PARAMS,
+				Err: err,
+			}
+		}
+
+		userId := ctx.MustGet("userid").(uint)
+		diff, err := echoHandler.echoService.DiffEchoRevisions(userId, uint(id), query.From, query.To)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: diff,
+			Msg:  commonModel.DIFF_ECHO_REVISION_SUCCESS,
+		}
+	})
+}
+
+// RestoreEchoRevision 将 Echo 恢复到指定的历史版本
+//
+//	@Summary		恢复Echo的历史版本
+//	@Description	将Echo的内容、标签、媒体、布局与扩展恢复到指定版本，当前内容会保存为新的历史版本，已发布的Echo会推送 echo.updated 事件，仅管理员可用
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int								true	"Echo ID"
+//	@Param			revisionId	path		int								true	"历史版本 ID"
+//	@Success		200			{object}	res.Response{data=model.Echo}	"恢复成功"
+//	@Failure		200			{object}	res.Response					"恢复失败"
+//	@Router			/echo/{id}/revisions/{revisionId}/restore [post]
+func (echoHandler *EchoHandler) RestoreEchoRevision() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+		revisionId, err := strconv.ParseUint(ctx.Param("revisionId"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		userId := ctx.MustGet("userid").(uint)
+		echo, err := echoHandler.echoService.RestoreEchoRevision(userId, uint(id), uint(revisionId))
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: echo,
+			Msg:  commonModel.RESTORE_ECHO_REVISION_SUCCESS,
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	res "github.com/lin-snow/ech0/internal/handler/response"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+)
+
+// GetTrashedEchos 获取回收站中的 Echo 列表
+//
+//	@Summary		获取回收站
+//	@Description	获取回收站中的全部Echo，按删除时间倒序排列，仅管理员可用
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	res.Response{data=[]model.Echo}	"获取成功"
+//	@Failure		200	{object}	res.Response					"获取失败"
+//	@Router			/echo/trash [get]
+func (echoHandler *EchoHandler) GetTrashedEchos() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userId := ctx.MustGet("userid").(uint)
+		echos, err := echoHandler.echoService.GetTrashedEchos(userId)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: echos,
+			Msg:  commonModel.GET_TRASH_ECHOS_SUCCESS,
+		}
+	})
+}
+
+// RestoreTrashedEcho 将 Echo 从回收站恢复
+//
+//	@Summary		从回收站恢复Echo
+//	@Description	将回收站中的Echo恢复，已发布的Echo会重新推送 echo.created 事件，仅管理员可用
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int								true	"Echo ID"
+//	@Success		200	{object}	res.Response{data=model.Echo}	"恢复成功"
+//	@Failure		200	{object}	res.Response					"恢复失败"
+//	@Router			/echo/trash/{id}/restore [post]
+func (echoHandler *EchoHandler) RestoreTrashedEcho() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		userId := ctx.MustGet("userid").(uint)
+		echo, err := echoHandler.echoService.RestoreTrashedEcho(userId, uint(id))
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: echo,
+			Msg:  commonModel.RESTORE_TRASH_ECHO_SUCCESS,
+		}
+	})
+}
+
+// PurgeTrashedEcho 永久删除回收站中的 Echo
+//
+//	@Summary		永久删除Echo
+//	@Description	永久删除回收站中的Echo及其历史版本，不再被引用的媒体文件交由临时文件清理任务删除，仅管理员可用
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int				true	"Echo ID"
+//	@Success		200	{object}	res.Response	"删除成功"
+//	@Failure		200	{object}	res.Response	"删除失败"
+//	@Router			/echo/trash/{id} [delete]
+func (echoHandler *EchoHandler) PurgeTrashedEcho() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		userId := ctx.MustGet("userid").(uint)
+		if err := echoHandler.echoService.PurgeTrashedEcho(userId, uint(id)); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.PURGE_TRASH_ECHO_SUCCESS,
+		}
+	})
+}
+
+// EmptyTrash 清空回收站
+//
+//	@Summary		清空回收站
+//	@Description	永久删除回收站中的全部Echo，仅管理员可用
+//	@Tags			Echo
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	res.Response	"清空成功"
+//	@Failure		200	{object}	res.Response	"清空失败"
+//	@Router			/echo/trash [delete]
+func (echoHandler *EchoHandler) EmptyTrash() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userId := ctx.MustGet("userid").(uint)
+		if err := echoHandler.echoService.EmptyTrash(userId); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.EMPTY_TRASH_SUCCESS,
+		}
+	})
+}
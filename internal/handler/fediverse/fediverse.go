@@ -1,16 +1,24 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	res "github.com/lin-snow/ech0/internal/handler/response"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	model "github.com/lin-snow/ech0/internal/model/fediverse"
 	service "github.com/lin-snow/ech0/internal/service/fediverse"
 )
 
+// maxInboxBodySize Inbox 请求体最大长度
+const maxInboxBodySize = 1 << 20
+
 type FediverseHandler struct {
 	service service.FediverseServiceInterface
 }
@@ -104,8 +112,20 @@ func (h *FediverseHandler) PostInbox(ctx *gin.Context) {
 	// 从 URL 参数中获取用户名
 	username := ctx.Param("username")
 
+	// 读取原始请求体，签名校验需要逐字节比对 Digest
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxInboxBodySize))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, model.ActivityPubError{
+			Context: "https://www.w3.org/ns/activitystreams",
+			Type:    "Error",
+			Error:   "Invalid request body",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
 	var activity model.Activity
-	if err := ctx.ShouldBindJSON(&activity); err != nil {
+	if err := json.Unmarshal(body, &activity); err != nil {
 		ctx.JSON(http.StatusBadRequest, model.ActivityPubError{
 			Context: "https://www.w3.org/ns/activitystreams",
 			Type:    "Error",
@@ -115,6 +135,17 @@ func (h *FediverseHandler) PostInbox(ctx *gin.Context) {
 		return
 	}
 
+	// 校验 HTTP 签名，未签名或签名无效的请求一律拒绝
+	if err := h.service.VerifyInboxRequest(ctx.Request, body, &activity); err != nil {
+		ctx.JSON(http.StatusUnauthorized, model.ActivityPubError{
+			Context: "https://www.w3.org/ns/activitystreams",
+			Type:    "Error",
+			Error:   err.Error(),
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
 	if err := h.service.HandleInbox(username, &activity); err != nil {
 		ctx.JSON(http.StatusInternalServerError, model.ActivityPubError{
 			Context: "https://www.w3.org/ns/activitystreams",
@@ -342,6 +373,25 @@ func (h *FediverseHandler) GetObject(ctx *gin.Context) {
 
 	// 调用服务层获取对象信息
 	object, err := h.service.GetObjectByID(uint(uintID))
+	if err != nil && err.Error() == commonModel.ECHO_NOT_FOUND {
+		// 已删除的对象返回 Tombstone
+		tombstone, tombErr := h.service.GetTombstoneByID(uint(uintID))
+		if tombErr != nil {
+			err = tombErr
+		} else if tombstone != nil {
+			ctx.Header("Content-Type", "application/activity+json")
+			ctx.JSON(http.StatusGone, tombstone)
+			return
+		} else {
+			ctx.JSON(http.StatusNotFound, model.ActivityPubError{
+				Context: "https://www.w3.org/ns/activitystreams",
+				Type:    "Error",
+				Error:   err.Error(),
+				Status:  http.StatusNotFound,
+			})
+			return
+		}
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, model.ActivityPubError{
 			Context: "https://www.w3.org/ns/activitystreams",
@@ -360,135 +410,291 @@ func (h *FediverseHandler) GetObject(ctx *gin.Context) {
 }
 
 // GetFollowStatus 获取关注状态
-// func (h *FediverseHandler) GetFollowStatus(ctx *gin.Context) {
-// 	userID := ctx.MustGet("userid").(uint)
-// 	targetActor := strings.TrimSpace(ctx.Query("actor"))
-// 	if targetActor == "" {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]any](commonModel.FEDIVERSE_INVALID_INPUT))
-// 		return
-// 	}
-
-// 	followStatus, err := h.service.GetFollowStatus(userID, targetActor)
-// 	if err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]any](err.Error()))
-// 		return
-// 	}
-
-// 	ctx.JSON(http.StatusOK, commonModel.OK(followStatus, commonModel.FEDIVERSE_GET_FOLLOW_STATUS_SUCCESS))
-// }
+//
+//	@Summary		获取关注状态
+//	@Description	查询当前用户对远端 Actor 的关注状态（none / pending / accepted / rejected）
+//	@Tags			联邦网络
+//	@Accept			json
+//	@Produce		json
+//	@Param			actor	query		string							true	"Actor URL 或 user@domain"
+//	@Success		200		{object}	res.Response{data=string}	"获取成功，code=1"
+//	@Failure		200		{object}	res.Response				"获取失败，code=0，msg错误描述"
+//	@Security		ApiKeyAuth
+//	@Router			/follow/status [get]
+func (h *FediverseHandler) GetFollowStatus() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		targetActor := strings.TrimSpace(ctx.Query("actor"))
+		if targetActor == "" {
+			return res.Response{
+				Msg: commonModel.FEDIVERSE_INVALID_INPUT,
+				Err: errors.New(commonModel.FEDIVERSE_INVALID_INPUT),
+			}
+		}
+
+		userID := ctx.MustGet("userid").(uint)
+		followStatus, err := h.service.GetFollowStatus(userID, targetActor)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: followStatus,
+			Msg:  commonModel.FEDIVERSE_GET_FOLLOW_STATUS_SUCCESS,
+		}
+	})
+}
+
+// GetFollowingList 列出当前用户关注的全部 Actor
+//
+//	@Summary		获取关注列表
+//	@Description	列出当前用户已发起关注的远端 Actor 及其关注状态
+//	@Tags			联邦网络
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	res.Response{data=[]model.Follow}	"获取成功，code=1"
+//	@Failure		200	{object}	res.Response						"获取失败，code=0，msg错误描述"
+//	@Security		ApiKeyAuth
+//	@Router			/following [get]
+func (h *FediverseHandler) GetFollowingList() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userID := ctx.MustGet("userid").(uint)
+		following, err := h.service.ListFollowing(userID)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: following,
+			Msg:  commonModel.FEDIVERSE_GET_FOLLOWING_SUCCESS,
+		}
+	})
+}
 
 // GetTimeline 获取关注的 Actor 的推文
-// func (h *FediverseHandler) GetTimeline(ctx *gin.Context) {
-// 	userID := ctx.MustGet("userid").(uint)
-
-// 	page := 1
-// 	if pageParam := strings.TrimSpace(ctx.Query("page")); pageParam != "" {
-// 		if parsed, err := strconv.Atoi(pageParam); err == nil && parsed > 0 {
-// 			page = parsed
-// 		}
-// 	}
-
-// 	pageSize := 20
-// 	if pageSizeParam := strings.TrimSpace(ctx.Query("pageSize")); pageSizeParam != "" {
-// 		if parsed, err := strconv.Atoi(pageSizeParam); err == nil && parsed > 0 {
-// 			pageSize = parsed
-// 		}
-// 	}
-
-// 	timeline, err := h.service.GetTimeline(userID, page, pageSize)
-// 	if err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[commonModel.PageQueryResult[[]model.TimelineItem]](err.Error()))
-// 		return
-// 	}
-
-// 	ctx.JSON(http.StatusOK, commonModel.OK(timeline, commonModel.FEDIVERSE_GET_TIMELINE_SUCCESS))
-// }
+//
+//	@Summary		获取联邦时间线
+//	@Description	分页获取当前用户关注的远端 Actor 推送的推文
+//	@Tags			联邦网络
+//	@Accept			json
+//	@Produce		json
+//	@Param			page		query		int																	false	"页码，默认 1"
+//	@Param			pageSize	query		int																	false	"每页数量，默认 20"
+//	@Success		200			{object}	res.Response{data=commonModel.PageQueryResult[[]model.TimelineItem]}	"获取成功，code=1"
+//	@Failure		200			{object}	res.Response														"获取失败，code=0，msg错误描述"
+//	@Security		ApiKeyAuth
+//	@Router			/timeline [get]
+func (h *FediverseHandler) GetTimeline() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+		pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "20"))
+
+		userID := ctx.MustGet("userid").(uint)
+		timeline, err := h.service.GetTimeline(userID, page, pageSize)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: timeline,
+			Msg:  commonModel.FEDIVERSE_GET_TIMELINE_SUCCESS,
+		}
+	})
+}
 
 // SearchActorByActorID 根据 Actor URL 搜索远端 Actor
-// func (h *FediverseHandler) SearchActorByActorID(ctx *gin.Context) {
-// 	actorID := strings.TrimSpace(ctx.Query("actor"))
-// 	if actorID == "" {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]any](commonModel.FEDIVERSE_INVALID_INPUT))
-// 		return
-// 	}
-
-// 	actor, err := h.service.SearchActorByActorID(actorID)
-// 	if err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]any](err.Error()))
-// 		return
-// 	}
-
-// 	ctx.JSON(http.StatusOK, commonModel.OK(actor, commonModel.FEDIVERSE_SEARCH_ACTOR_SUCCESS))
-// }
+//
+//	@Summary		搜索远端 Actor
+//	@Description	根据 Actor URL 或 user@domain 拉取远端 Actor 资料
+//	@Tags			联邦网络
+//	@Accept			json
+//	@Produce		json
+//	@Param			actor	query		string								true	"Actor URL 或 user@domain"
+//	@Success		200		{object}	res.Response{data=map[string]any}	"搜索成功，code=1"
+//	@Failure		200		{object}	res.Response						"搜索失败，code=0，msg错误描述"
+//	@Security		ApiKeyAuth
+//	@Router			/search/actor [get]
+func (h *FediverseHandler) SearchActorByActorID() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		actorID := strings.TrimSpace(ctx.Query("actor"))
+		if actorID == "" {
+			return res.Response{
+				Msg: commonModel.FEDIVERSE_INVALID_INPUT,
+				Err: errors.New(commonModel.FEDIVERSE_INVALID_INPUT),
+			}
+		}
+
+		actor, err := h.service.SearchActorByActorID(actorID)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: actor,
+			Msg:  commonModel.FEDIVERSE_SEARCH_ACTOR_SUCCESS,
+		}
+	})
+}
 
 // PostFollow 发起关注请求
-// func (h *FediverseHandler) PostFollow(ctx *gin.Context) {
-// 	userID := ctx.MustGet("userid").(uint)
-// 	var req model.FollowActionRequest
-// 	if err := ctx.ShouldBindJSON(&req); err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]string](commonModel.INVALID_REQUEST_BODY))
-// 		return
-// 	}
-
-// 	result, err := h.service.FollowActor(userID, req)
-// 	if err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]string](err.Error()))
-// 		return
-// 	}
-
-// 	ctx.JSON(http.StatusOK, commonModel.OK(result, commonModel.FEDIVERSE_FOLLOW_SUCCESS))
-// }
+//
+//	@Summary		发起关注请求
+//	@Description	向远端 Actor 发送 Follow 活动，仅管理员可用
+//	@Tags			联邦网络
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.FollowActionRequest	true	"请求参数"
+//	@Success		200		{object}	res.Response{data=map[string]string}	"发送成功，code=1"
+//	@Failure		200		{object}	res.Response							"发送失败，code=0，msg错误描述"
+//	@Security		ApiKeyAuth
+//	@Router			/follow [post]
+func (h *FediverseHandler) PostFollow() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		var req model.FollowActionRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		userID := ctx.MustGet("userid").(uint)
+		result, err := h.service.FollowActor(userID, req)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: result,
+			Msg:  commonModel.FEDIVERSE_FOLLOW_SUCCESS,
+		}
+	})
+}
 
 // PostUnfollow 发起取消关注请求
-// func (h *FediverseHandler) PostUnfollow(ctx *gin.Context) {
-// 	userID := ctx.MustGet("userid").(uint)
-// 	var req model.FollowActionRequest
-// 	if err := ctx.ShouldBindJSON(&req); err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]string](commonModel.INVALID_REQUEST_BODY))
-// 		return
-// 	}
-
-// 	result, err := h.service.UnfollowActor(userID, req)
-// 	if err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]string](err.Error()))
-// 		return
-// 	}
-
-// 	ctx.JSON(http.StatusOK, commonModel.OK(result, commonModel.FEDIVERSE_UNFOLLOW_SUCCESS))
-// }
+//
+//	@Summary		发起取消关注请求
+//	@Description	向远端 Actor 发送 Undo Follow 活动并删除本地关注记录，仅管理员可用
+//	@Tags			联邦网络
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.FollowActionRequest	true	"请求参数"
+//	@Success		200		{object}	res.Response{data=map[string]string}	"发送成功，code=1"
+//	@Failure		200		{object}	res.Response							"发送失败，code=0，msg错误描述"
+//	@Security		ApiKeyAuth
+//	@Router			/unfollow [post]
+func (h *FediverseHandler) PostUnfollow() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		var req model.FollowActionRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		userID := ctx.MustGet("userid").(uint)
+		result, err := h.service.UnfollowActor(userID, req)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: result,
+			Msg:  commonModel.FEDIVERSE_UNFOLLOW_SUCCESS,
+		}
+	})
+}
 
 // PostLike 发起点赞请求
-// func (h *FediverseHandler) PostLike(ctx *gin.Context) {
-// 	userID := ctx.MustGet("userid").(uint)
-// 	var req model.LikeActionRequest
-// 	if err := ctx.ShouldBindJSON(&req); err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]string](commonModel.INVALID_REQUEST_BODY))
-// 		return
-// 	}
-
-// 	result, err := h.service.LikeObject(userID, req)
-// 	if err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]string](err.Error()))
-// 		return
-// 	}
-
-// 	ctx.JSON(http.StatusOK, commonModel.OK(result, commonModel.FEDIVERSE_LIKE_SUCCESS))
-// }
+//
+//	@Summary		发起点赞请求
+//	@Description	向远端推文发送 Like 活动，仅管理员可用
+//	@Tags			联邦网络
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.LikeActionRequest	true	"请求参数"
+//	@Success		200		{object}	res.Response{data=map[string]string}	"发送成功，code=1"
+//	@Failure		200		{object}	res.Response							"发送失败，code=0，msg错误描述"
+//	@Security		ApiKeyAuth
+//	@Router			/like [post]
+func (h *FediverseHandler) PostLike() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		var req model.LikeActionRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		userID := ctx.MustGet("userid").(uint)
+		result, err := h.service.LikeObject(userID, req)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: result,
+			Msg:  commonModel.FEDIVERSE_LIKE_SUCCESS,
+		}
+	})
+}
 
 // PostUndoLike 发起取消点赞请求
-// func (h *FediverseHandler) PostUndoLike(ctx *gin.Context) {
-// 	userID := ctx.MustGet("userid").(uint)
-// 	var req model.LikeActionRequest
-// 	if err := ctx.ShouldBindJSON(&req); err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]string](commonModel.INVALID_REQUEST_BODY))
-// 		return
-// 	}
-
-// 	result, err := h.service.UndoLikeObject(userID, req)
-// 	if err != nil {
-// 		ctx.JSON(http.StatusOK, commonModel.Fail[map[string]string](err.Error()))
-// 		return
-// 	}
-
-// 	ctx.JSON(http.StatusOK, commonModel.OK(result, commonModel.FEDIVERSE_UNDO_LIKE_SUCCESS))
-// }
+//
+//	@Summary		发起取消点赞请求
+//	@Description	向远端推文发送 Undo Like 活动，仅管理员可用
+//	@Tags			联邦网络
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.LikeActionRequest	true	"请求参数"
+//	@Success		200		{object}	res.Response{data=map[string]string}	"发送成功，code=1"
+//	@Failure		200		{object}	res.Response							"发送失败，code=0，msg错误描述"
+//	@Security		ApiKeyAuth
+//	@Router			/undo-like [post]
+func (h *FediverseHandler) PostUndoLike() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		var req model.LikeActionRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		userID := ctx.MustGet("userid").(uint)
+		result, err := h.service.UndoLikeObject(userID, req)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: result,
+			Msg:  commonModel.FEDIVERSE_UNDO_LIKE_SUCCESS,
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	service "github.com/lin-snow/ech0/internal/service/media"
+)
+
+type MediaHandler struct {
+	mediaService service.MediaServiceInterface
+}
+
+// NewMediaHandler MediaHandler 的构造函数
+func NewMediaHandler(mediaService service.MediaServiceInterface) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+	}
+}
+
+// HandleMediaRequest 按需处理媒体
+//
+//	@Summary		按需处理媒体
+//	@Description	与存储无关的媒体处理代理：对象存储中的图片从存储拉取原图后按参数处理，视频（本地或对象存储）截取封面后按参数处理，结果与本地图片共用磁盘缓存；本地图片跳转到 /api/images，对象存储中的图片无处理参数时跳转到原始地址。视频封面需要安装 ffmpeg
+//	@Tags			媒体
+//	@Produce		image/jpeg,image/png,image/webp
+//	@Param			source	path	string	true	"媒体来源：local 或 s3"
+//	@Param			key		path	string	true	"本地媒体路径（如 videos/xxx.mp4）或对象存储的 Key"
+//	@Param			w		query	int		false	"目标宽度，需在尺寸预设中"
+//	@Param			h		query	int		false	"目标高度，需在尺寸预设中"
+//	@Param			q		query	int		false	"质量 (1-100, 默认75)"
+//	@Param			fmt		query	string	false	"输出格式 (jpg/png/webp)，视频封面默认 jpg"
+//	@Param			mode	query	string	false	"缩放模式 (lfit/mfit/fill, 默认 lfit)"
+//	@Success		200		"处理后的图片"
+//	@Success		302		"跳转到原始地址"
+//	@Success		304		"未修改"
+//	@Failure		404		"媒体不存在、来源不支持或未安装 ffmpeg"
+//	@Failure		502		"处理失败"
+//	@Router			/media/{source}/{key} [get]
+func (mediaHandler *MediaHandler) HandleMediaRequest(ctx *gin.Context) {
+	processed, err := mediaHandler.mediaService.ProcessMedia(
+		ctx.Request.Context(),
+		ctx.Param("source"),
+		strings.TrimPrefix(ctx.Param("key"), "/"),
+		ctx.Request.URL.Query(),
+	)
+	if err != nil {
+		switch err.Error() {
+		case commonModel.MEDIA_NOT_FOUND, commonModel.MEDIA_SOURCE_NOT_SUPPORTED, commonModel.FFMPEG_NOT_INSTALLED:
+			ctx.String(http.StatusNotFound, err.Error())
+		default:
+			ctx.String(http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+
+	if processed.RedirectURL != "" {
+		ctx.Redirect(http.StatusFound, processed.RedirectURL)
+		return
+	}
+
+	// ETag 协商缓存
+	ctx.Header("ETag", processed.ETag)
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if ctx.GetHeader("If-None-Match") == processed.ETag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	if processed.FilePath != "" {
+		ctx.File(processed.FilePath)
+		return
+	}
+	ctx.Data(http.StatusOK, processed.ContentType, processed.Data)
+}
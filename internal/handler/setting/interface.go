@@ -42,6 +42,15 @@ type SettingHandlerInterface interface {
 	// CreateWebhook 创建 Webhook
 	CreateWebhook() gin.HandlerFunc
 
+	// ListWebhookDeliveries 获取 Webhook 投递记录
+	ListWebhookDeliveries() gin.HandlerFunc
+
+	// RedeliverWebhook 重新投递 Webhook 事件
+	RedeliverWebhook() gin.HandlerFunc
+
+	// PingWebhook 发送 Webhook 测试事件
+	PingWebhook() gin.HandlerFunc
+
 	// ListAccessTokens 列出访问令牌
 	ListAccessTokens() gin.HandlerFunc
 
@@ -63,6 +72,18 @@ type SettingHandlerInterface interface {
 	// UpdateBackupScheduleSetting 更新备份计划
 	UpdateBackupScheduleSetting() gin.HandlerFunc
 
+	// GetBackupDestination 获取异地备份设置
+	GetBackupDestination() gin.HandlerFunc
+
+	// UpdateBackupDestination 更新异地备份设置
+	UpdateBackupDestination() gin.HandlerFunc
+
+	// GetBackupEncryption 获取备份加密设置
+	GetBackupEncryption() gin.HandlerFunc
+
+	// UpdateBackupEncryption 更新备份加密设置
+	UpdateBackupEncryption() gin.HandlerFunc
+
 	// GetAgentSettings 获取 Agent 设置
 	GetAgentSettings() gin.HandlerFunc
 
@@ -71,4 +92,10 @@ type SettingHandlerInterface interface {
 
 	// GetAgentInfo 获取 Agent 信息
 	GetAgentInfo() gin.HandlerFunc
+
+	// GetTrashSettings 获取回收站设置
+	GetTrashSettings() gin.HandlerFunc
+
+	// UpdateTrashSettings 更新回收站设置
+	UpdateTrashSettings() gin.HandlerFunc
 }
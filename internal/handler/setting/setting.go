@@ -468,6 +468,140 @@ func (settingHandler *SettingHandler) CreateWebhook() gin.HandlerFunc {
 	})
 }
 
+// ListWebhookDeliveries 获取 Webhook 投递记录
+//
+//	@Summary		获取 Webhook 投递记录
+//	@Description	分页获取指定 Webhook 的投递历史（最新在前）
+//	@Tags			系统设置
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int																	true	"Webhook ID"
+//	@Param			page		query		int																	false	"页码"
+//	@Param			pageSize	query		int																	false	"每页数量"
+//	@Success		200			{object}	res.Response{data=commonModel.PageQueryResult[[]model.WebhookDelivery]}	"获取投递记录成功"
+//	@Failure		200			{object}	res.Response														"获取投递记录失败"
+//	@Router			/webhook/{id}/deliveries [get]
+func (settingHandler *SettingHandler) ListWebhookDeliveries() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		// 获取当前用户 ID
+		userid := ctx.MustGet("userid").(uint)
+
+		// 从路径参数中获取 Webhook ID
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		var pageQuery commonModel.PageQueryDto
+		if err := ctx.ShouldBindQuery(&pageQuery); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_QUERY_PARAMS,
+				Err: err,
+			}
+		}
+
+		result, err := settingHandler.settingService.ListWebhookDeliveries(userid, uint(id), pageQuery)
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: result,
+			Msg:  commonModel.GET_WEBHOOK_DELIVERIES_SUCCESS,
+		}
+	})
+}
+
+// RedeliverWebhook 重新投递 Webhook 事件
+//
+//	@Summary		重新投递 Webhook 事件
+//	@Description	使用投递记录中保存的原始事件，按当前配置重新签名后加入投递队列，返回等待中的投递记录
+//	@Tags			系统设置
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int				true	"Webhook ID"
+//	@Param			deliveryId	path		int				true	"投递记录 ID"
+//	@Success		200			{object}	res.Response{data=model.WebhookDelivery}	"已加入投递队列"
+//	@Failure		200			{object}	res.Response							"重新投递失败"
+//	@Router			/webhook/{id}/deliveries/{deliveryId}/redeliver [post]
+func (settingHandler *SettingHandler) RedeliverWebhook() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		// 获取当前用户 ID
+		userid := ctx.MustGet("userid").(uint)
+
+		// 从路径参数中获取 Webhook ID 与投递记录 ID
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+		deliveryID, err := strconv.ParseUint(ctx.Param("deliveryId"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		delivery, err := settingHandler.settingService.RedeliverWebhook(userid, uint(id), uint(deliveryID))
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: delivery,
+			Msg:  commonModel.REDELIVER_WEBHOOK_SUCCESS,
+		}
+	})
+}
+
+// PingWebhook 发送 Webhook 测试事件
+//
+//	@Summary		发送 Webhook 测试事件
+//	@Description	向指定 Webhook 发送一次 webhook.ping 事件（无论是否启用），返回本次投递结果
+//	@Tags			系统设置
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int													true	"Webhook ID"
+//	@Success		200	{object}	res.Response{data=model.WebhookDelivery}	"测试投递完成"
+//	@Failure		200	{object}	res.Response										"测试投递失败"
+//	@Router			/webhook/{id}/ping [post]
+func (settingHandler *SettingHandler) PingWebhook() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		// 获取当前用户 ID
+		userid := ctx.MustGet("userid").(uint)
+
+		// 从路径参数中获取 Webhook ID
+		id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_PARAMS,
+			}
+		}
+
+		delivery, err := settingHandler.settingService.PingWebhook(userid, uint(id))
+		if err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: delivery,
+			Msg:  commonModel.PING_WEBHOOK_SUCCESS,
+		}
+	})
+}
+
 // ListAccessTokens 列出访问令牌
 //
 //	@Summary		列出访问令牌
@@ -713,6 +847,140 @@ func (settingHandler *SettingHandler) UpdateBackupScheduleSetting() gin.HandlerF
 	})
 }
 
+// GetBackupDestination 获取异地备份设置
+//
+//	@Summary		获取异地备份设置
+//	@Description	获取备份完成后上传的异地存储设置（S3 兼容存储或本地挂载目录），密钥以占位符返回，更新时原样提交占位符表示不修改
+//	@Tags			系统设置
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	res.Response{data=model.BackupDestination}	"获取异地备份设置成功"
+//	@Failure		200	{object}	res.Response								"获取异地备份设置失败"
+//	@Router			/backup/destination [get]
+func (settingHandler *SettingHandler) GetBackupDestination() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		// 获取当前用户 ID
+		userid := ctx.MustGet("userid").(uint)
+
+		var backupDestination model.BackupDestination
+		if err := settingHandler.settingService.GetBackupDestination(userid, &backupDestination); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: backupDestination,
+			Msg:  commonModel.GET_BACKUP_DESTINATION_SUCCESS,
+		}
+	})
+}
+
+// UpdateBackupDestination 更新异地备份设置
+//
+//	@Summary		更新异地备份设置
+//	@Description	设置备份完成后上传的异地存储，type 为空表示不启用
+//	@Tags			系统设置
+//	@Accept			json
+//	@Produce		json
+//	@Param			backupDestination	body		model.BackupDestinationDto	true	"异地备份设置"
+//	@Success		200					{object}	res.Response				"更新异地备份设置成功"
+//	@Failure		200					{object}	res.Response				"更新异地备份设置失败"
+//	@Router			/backup/destination [put]
+func (settingHandler *SettingHandler) UpdateBackupDestination() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		// 获取当前用户 ID
+		userid := ctx.MustGet("userid").(uint)
+		// 解析请求体中的参数
+		var backupDestination model.BackupDestinationDto
+		if err := ctx.ShouldBindJSON(&backupDestination); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		if err := settingHandler.settingService.UpdateBackupDestination(userid, &backupDestination); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.UPDATE_BACKUP_DESTINATION_SUCCESS,
+		}
+	})
+}
+
+// GetBackupEncryption 获取备份加密设置
+//
+//	@Summary		获取备份加密设置
+//	@Description	获取备份归档的加密方式（口令或 age 公钥），口令以占位符返回，更新时原样提交占位符表示不修改
+//	@Tags			系统设置
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	res.Response{data=model.BackupEncryption}	"获取备份加密设置成功"
+//	@Failure		200	{object}	res.Response								"获取备份加密设置失败"
+//	@Router			/backup/encryption [get]
+func (settingHandler *SettingHandler) GetBackupEncryption() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		// 获取当前用户 ID
+		userid := ctx.MustGet("userid").(uint)
+
+		var backupEncryption model.BackupEncryption
+		if err := settingHandler.settingService.GetBackupEncryption(userid, &backupEncryption); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: backupEncryption,
+			Msg:  commonModel.GET_BACKUP_ENCRYPTION_SUCCESS,
+		}
+	})
+}
+
+// UpdateBackupEncryption 更新备份加密设置
+//
+//	@Summary		更新备份加密设置
+//	@Description	设置备份归档的加密方式，mode 为空表示不加密；x25519 模式只需提供公钥，私钥由管理员离线保管
+//	@Tags			系统设置
+//	@Accept			json
+//	@Produce		json
+//	@Param			backupEncryption	body		model.BackupEncryptionDto	true	"备份加密设置"
+//	@Success		200					{object}	res.Response				"更新备份加密设置成功"
+//	@Failure		200					{object}	res.Response				"更新备份加密设置失败"
+//	@Router			/backup/encryption [put]
+func (settingHandler *SettingHandler) UpdateBackupEncryption() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		// 获取当前用户 ID
+		userid := ctx.MustGet("userid").(uint)
+		// 解析请求体中的参数
+		var backupEncryption model.BackupEncryptionDto
+		if err := ctx.ShouldBindJSON(&backupEncryption); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		if err := settingHandler.settingService.UpdateBackupEncryption(userid, &backupEncryption); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.UPDATE_BACKUP_ENCRYPTION_SUCCESS,
+		}
+	})
+}
+
 // GetAgentInfo 获取 Agent 信息
 //
 //	@Summary		获取 Agent 信息
@@ -874,3 +1142,66 @@ func (settingHandler *SettingHandler) UpdateImageProcessSettings() gin.HandlerFu
 		}
 	})
 }
+
+// GetTrashSettings 获取回收站设置
+//
+//	@Summary		获取回收站设置
+//	@Description	获取回收站中 Echo 的保留天数
+//	@Tags			系统设置
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	res.Response{data=model.TrashSetting}	"获取回收站设置成功"
+//	@Failure		200	{object}	res.Response							"获取回收站设置失败"
+//	@Router			/trash/settings [get]
+func (settingHandler *SettingHandler) GetTrashSettings() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		var settings model.TrashSetting
+		if err := settingHandler.settingService.GetTrashSetting(&settings); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Data: settings,
+			Msg:  commonModel.GET_SETTINGS_SUCCESS,
+		}
+	})
+}
+
+// UpdateTrashSettings 更新回收站设置
+//
+//	@Summary		更新回收站设置
+//	@Description	更新回收站中 Echo 的保留天数，到期的 Echo 由定时任务永久删除
+//	@Tags			系统设置
+//	@Accept			json
+//	@Produce		json
+//	@Param			settings	body		model.TrashSettingDto	true	"新的回收站设置"
+//	@Success		200			{object}	res.Response			"更新回收站设置成功"
+//	@Failure		200			{object}	res.Response			"更新回收站设置失败"
+//	@Router			/trash/settings [put]
+func (settingHandler *SettingHandler) UpdateTrashSettings() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userid := ctx.MustGet("userid").(uint)
+
+		var newSettings model.TrashSettingDto
+		if err := ctx.ShouldBindJSON(&newSettings); err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		if err := settingHandler.settingService.UpdateTrashSetting(userid, &newSettings); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.UPDATE_SETTINGS_SUCCESS,
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	res "github.com/lin-snow/ech0/internal/handler/response"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	service "github.com/lin-snow/ech0/internal/service/transfer"
+)
+
+type TransferHandler struct {
+	transferService service.TransferServiceInterface
+}
+
+// NewTransferHandler TransferHandler 的构造函数
+func NewTransferHandler(transferService service.TransferServiceInterface) *TransferHandler {
+	return &TransferHandler{
+		transferService: transferService,
+	}
+}
+
+// ExportEchos 导出 Echo
+//
+//	@Summary		导出 Echo
+//	@Description	将全部 Echo 导出为可移植的压缩包（含标签、媒体、扩展与时间），与数据库快照无关，格式见 docs/export-format.md
+//	@Tags			数据迁移
+//	@Accept			json
+//	@Produce		application/zip
+//	@Param			format	query		string			false	"导出风格：ndjson（默认）或 markdown"
+//	@Success		200		{object}	res.Response	"导出成功，返回文件下载"
+//	@Failure		200		{object}	res.Response	"导出失败"
+//	@Router			/transfer/export [get]
+func (transferHandler *TransferHandler) ExportEchos() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userId := ctx.MustGet("userid").(uint)
+		if err := transferHandler.transferService.ExportEchos(ctx, userId, ctx.Query("format")); err != nil {
+			return res.Response{
+				Msg: "",
+				Err: err,
+			}
+		}
+
+		return res.Response{
+			Msg: commonModel.EXPORT_ECHOS_SUCCESS,
+		}
+	})
+}
+
+// ImportEchos 导入 Echo
+//
+//	@Summary		导入 Echo
+//	@Description	上传 Ech0 导出包（zip 或单独的 .ndjson 文件），或 Memos 数据库/JSON、Mastodon 归档、Twitter/X 归档，按内容指纹去重，并按用户名重新映射用户；导入日志会发送到收件箱
+//	@Tags			数据迁移
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			file	formData	file			true	"导出包或归档"
+//	@Param			source	formData	string			false	"数据来源：ech0、memos、mastodon 或 twitter，为空时自动识别"
+//	@Success		200		{object}	res.Response	"导入成功，返回导入结果"
+//	@Failure		200		{object}	res.Response	"导入失败"
+//	@Router			/transfer/import [post]
+func (transferHandler *TransferHandler) ImportEchos() gin.HandlerFunc {
+	return res.Execute(func(ctx *gin.Context) res.Response {
+		userId := ctx.MustGet("userid").(uint)
+
+		file, err := ctx.FormFile("file")
+		if err != nil {
+			return res.Response{
+				Msg: commonModel.INVALID_REQUEST_BODY,
+				Err: err,
+			}
+		}
+
+		result, err := transferHandler.transferService.ImportEchos(
+			ctx,
+			userId,
+			ctx.PostForm("source"),
+			file,
+		)
+		if err != nil {
+			return res.Response{
+				Data: result,
+				Msg:  "",
+				Err:  err,
+			}
+		}
+
+		return res.Response{
+			Data: result,
+			Msg:  commonModel.IMPORT_ECHOS_SUCCESS,
+		}
+	})
+}
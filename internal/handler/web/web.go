@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lin-snow/ech0/internal/cache"
 	authModel "github.com/lin-snow/ech0/internal/model/auth"
 	settingModel "github.com/lin-snow/ech0/internal/model/setting"
 	echoService "github.com/lin-snow/ech0/internal/service/echo"
@@ -26,16 +27,19 @@ import (
 type WebHandler struct {
 	settingService settingService.SettingServiceInterface
 	echoService    echoService.EchoServiceInterface
+	imageCache     *cache.DiskCache
 }
 
 // NewWebHandler WebHandler 的构造函数
 func NewWebHandler(
 	settingService settingService.SettingServiceInterface,
 	echoService echoService.EchoServiceInterface,
+	imageCache *cache.DiskCache,
 ) *WebHandler {
 	return &WebHandler{
 		settingService: settingService,
 		echoService:    echoService,
+		imageCache:     imageCache,
 	}
 }
 
@@ -469,6 +473,8 @@ func (webHandler *WebHandler) HandleDynamicIcon(ctx *gin.Context) {
 //   - q:    质量 (1-100, 默认75, 仅 JPEG 有效)
 //   - fmt:  输出格式 (jpg/png, 空=保持原格式)
 //   - mode: 缩放模式 (lfit=等比缩小/mfit=等比放大/fill=裁剪填充, 默认 lfit)
+//
+// w/h 需要是图片处理设置中允许的尺寸预设，否则直接返回原文件
 func (webHandler *WebHandler) HandleImageRequest(ctx *gin.Context) {
 	filePath := ctx.Param("filepath") // 如 "/1_17xxx_abc.jpg"
 	if filePath == "" || filePath == "/" {
@@ -493,14 +499,7 @@ func (webHandler *WebHandler) HandleImageRequest(ctx *gin.Context) {
 	}
 
 	// 检查是否有图片处理参数
-	wStr := ctx.Query("w")
-	hStr := ctx.Query("h")
-	qStr := ctx.Query("q")
-	fmtStr := ctx.Query("fmt")
-	modeStr := ctx.Query("mode")
-
-	hasProcessParams := wStr != "" || hStr != "" || qStr != "" || fmtStr != ""
-
+	opts, hasProcessParams := imgUtil.ParseProcessOptions(ctx.Request.URL.Query())
 	if !hasProcessParams {
 		// 无处理参数 → 直接返回原文件（等同原 r.Static 行为）
 		ctx.File(fullPath)
@@ -509,30 +508,16 @@ func (webHandler *WebHandler) HandleImageRequest(ctx *gin.Context) {
 
 	// ===== 有处理参数，走图片处理引擎 =====
 
-	// 解析参数
-	w, _ := strconv.Atoi(wStr)
-	h, _ := strconv.Atoi(hStr)
-	q, _ := strconv.Atoi(qStr)
-
-	// 安全限制
-	if w < 0 || w > 4096 {
-		w = 0
-	}
-	if h < 0 || h > 4096 {
-		h = 0
-	}
-	if q <= 0 || q > 100 {
-		q = 75
-	}
-	if modeStr == "" {
-		modeStr = "lfit"
+	// 尺寸不在预设中 → 返回原文件，不做处理也不写缓存
+	var processSetting settingModel.ImageProcessSetting
+	if err := webHandler.settingService.GetImageProcessSetting(&processSetting); err != nil ||
+		!processSetting.AllowsSize(opts.Width, opts.Height) {
+		ctx.File(fullPath)
+		return
 	}
 
 	// 生成指纹 (包含源文件信息 + 所有处理参数)
-	id := fmt.Sprintf("imgproc-%s-%d-%d-%d-%d-%d-%s-%s",
-		fullPath, fileStat.ModTime().Unix(), fileStat.Size(),
-		w, h, q, fmtStr, modeStr)
-	fingerprint := fmt.Sprintf("%x", md5.Sum([]byte(id)))
+	fingerprint := opts.Fingerprint(fmt.Sprintf("%s-%d-%d", fullPath, fileStat.ModTime().Unix(), fileStat.Size()))
 	etag := fmt.Sprintf(`W/"%s"`, fingerprint)
 
 	// ETag 协商缓存
@@ -544,21 +529,10 @@ func (webHandler *WebHandler) HandleImageRequest(ctx *gin.Context) {
 		return
 	}
 
-	// 确定输出格式的扩展名
-	outExt := fmtStr
-	if outExt == "" {
-		outExt = strings.TrimPrefix(filepath.Ext(fullPath), ".")
-	}
-	if outExt == "jpeg" {
-		outExt = "jpg"
-	}
-
-	// 磁盘缓存
-	cacheDir := filepath.Join("data", "cache", "images")
-	_ = os.MkdirAll(cacheDir, 0755)
-	cachePath := filepath.Join(cacheDir, fmt.Sprintf("%s.%s", fingerprint, outExt))
-
-	if _, err := os.Stat(cachePath); err == nil {
+	// 磁盘缓存（有容量上限，由定时任务与写入时淘汰最久未访问的文件）
+	srcFormat := strings.TrimPrefix(filepath.Ext(fullPath), ".")
+	cacheName := fmt.Sprintf("%s.%s", fingerprint, opts.OutputExt(srcFormat))
+	if cachePath, ok := webHandler.imageCache.Get(cacheName); ok {
 		// 缓存命中
 		ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
 		ctx.File(cachePath)
@@ -573,16 +547,6 @@ func (webHandler *WebHandler) HandleImageRequest(ctx *gin.Context) {
 	}
 	defer f.Close()
 
-	srcFormat := strings.TrimPrefix(filepath.Ext(fullPath), ".")
-
-	opts := imgUtil.ImageProcessOptions{
-		Width:   w,
-		Height:  h,
-		Quality: q,
-		Format:  fmtStr,
-		Mode:    modeStr,
-	}
-
 	data, contentType, err := imgUtil.ProcessImage(f, srcFormat, opts)
 	if err != nil {
 		// 处理失败，回退返回原文件
@@ -591,10 +555,7 @@ func (webHandler *WebHandler) HandleImageRequest(ctx *gin.Context) {
 	}
 
 	// 原子化写入缓存
-	tmpPath := cachePath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err == nil {
-		_ = os.Rename(tmpPath, cachePath)
-	}
+	_ = webHandler.imageCache.Put(cacheName, data)
 
 	ctx.Header("Content-Type", contentType)
 	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -11,8 +12,13 @@ import (
 	jwtUtil "github.com/lin-snow/ech0/internal/util/jwt"
 )
 
+// AccessTokenValidator 校验令牌是否仍然有效，并返回访问令牌的权限范围
+type AccessTokenValidator interface {
+	AuthenticateToken(claims *authModel.MyClaims, rawToken, ip string) ([]string, bool, error)
+}
+
 // JWTAuthMiddleware JWT 拦截器中间件
-func JWTAuthMiddleware() gin.HandlerFunc {
+func JWTAuthMiddleware(validator AccessTokenValidator) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// 获取 Authorization 头部信息
 		auth := ctx.Request.Header.Get("Authorization")
@@ -48,6 +54,25 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 				return
 			}
 
+			// 点赞与取消点赞（访客按 Cookie 与指纹识别）
+			if strings.HasPrefix(ctx.Request.URL.Path, "/api/echo/like/") &&
+				(ctx.Request.Method == http.MethodPut || ctx.Request.Method == http.MethodDelete) {
+				// 设置 userid 为 NO_USER_LOGINED
+				ctx.Set("userid", authModel.NO_USER_LOGINED)
+				ctx.Next()
+				return
+			}
+
+			// 发表评论（访客按昵称与邮箱评论）
+			if strings.HasPrefix(ctx.Request.URL.Path, "/api/echo/") &&
+				strings.HasSuffix(ctx.Request.URL.Path, "/comments") &&
+				ctx.Request.Method == http.MethodPost {
+				// 设置 userid 为 NO_USER_LOGINED
+				ctx.Set("userid", authModel.NO_USER_LOGINED)
+				ctx.Next()
+				return
+			}
+
 			// 获取 S3 存储设置
 			if strings.HasPrefix(ctx.Request.URL.Path, "/api/s3/settings") &&
 				ctx.Request.Method == http.MethodGet {
@@ -106,6 +131,36 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 确认令牌未被撤销，并记录访问令牌的使用情况
+		scopes, scoped, err := validator.AuthenticateToken(mc, parts[1], ctx.ClientIP())
+		if err != nil {
+			ctx.JSON(
+				http.StatusUnauthorized,
+				commonModel.Fail[any](errUtil.HandleError(&commonModel.ServerError{
+					Msg: commonModel.TOKEN_REVOKED,
+					Err: err,
+				})),
+			)
+			ctx.Abort()
+			return
+		}
+
+		// 访问令牌只能调用权限范围内的接口
+		if scoped {
+			scope, ok := requiredScope(ctx.Request.Method, ctx.Request.URL.Path)
+			if !ok || !slices.Contains(scopes, scope) {
+				ctx.JSON(
+					http.StatusForbidden,
+					commonModel.Fail[any](errUtil.HandleError(&commonModel.ServerError{
+						Msg: commonModel.TOKEN_SCOPE_DENIED,
+						Err: nil,
+					})),
+				)
+				ctx.Abort()
+				return
+			}
+		}
+
 		// 如果 token 解析成功，则将用户 ID 存入上下文
 		ctx.Set("userid", mc.Userid)
 		ctx.Next()
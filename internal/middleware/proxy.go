@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lin-snow/ech0/internal/config"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	"go.uber.org/zap"
+)
+
+// ProxyHeaderWarning 未配置受信任代理却收到 X-Forwarded-For 时记录一次警告
+// 这通常意味着部署在反向代理之后，所有访客会被识别为代理 IP 并共享限流额度
+func ProxyHeaderWarning() gin.HandlerFunc {
+	if len(config.Config.Server.TrustedProxies) > 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	var once sync.Once
+	return func(c *gin.Context) {
+		if forwarded := c.GetHeader("X-Forwarded-For"); forwarded != "" {
+			once.Do(func() {
+				logUtil.GetLogger().Warn(
+					"Received X-Forwarded-For but no trusted proxy is configured; "+
+						"all visitors will share the proxy IP for rate limiting. "+
+						"Set TRUSTED_PROXIES or server.trustedproxies to the proxy address",
+					zap.String("remote_addr", c.RemoteIP()),
+					zap.String("x_forwarded_for", forwarded),
+				)
+			})
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	errUtil "github.com/lin-snow/ech0/internal/util/err"
+)
+
+// RateLimit 按客户端 IP 限制请求频率，每个时间窗口内超过 limit 次的请求返回 429
+// 同一个限流器可挂载到多个路由上，共享计数
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := &ipRateLimiter{
+		limit:   limit,
+		window:  window,
+		windows: make(map[string]*rateWindow),
+	}
+
+	return func(c *gin.Context) {
+		if retryAfter, ok := limiter.allow(c.ClientIP(), time.Now()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(
+				http.StatusTooManyRequests,
+				commonModel.Fail[any](errUtil.HandleError(&commonModel.ServerError{
+					Msg: commonModel.TOO_MANY_REQUESTS,
+					Err: nil,
+				})),
+			)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateWindow 单个 IP 在当前时间窗口内的请求计数
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// ipRateLimiter 基于固定时间窗口的 IP 限流器
+type ipRateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+// allow 判断请求是否放行，被拒绝时返回距窗口结束的时间
+func (l *ipRateLimiter) allow(ip string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 定期清理过期的窗口，避免内存随 IP 数量增长
+	if now.Sub(l.lastSweep) >= l.window {
+		for key, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.windows[ip]
+	if !ok || now.Sub(w.start) >= l.window {
+		l.windows[ip] = &rateWindow{start: now, count: 1}
+		return 0, true
+	}
+	if w.count >= l.limit {
+		return l.window - now.Sub(w.start), false
+	}
+
+	w.count++
+	return 0, true
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	authModel "github.com/lin-snow/ech0/internal/model/auth"
+)
+
+// scopeRule 描述访问令牌访问某类接口所需的权限范围
+type scopeRule struct {
+	method string // 为空表示匹配所有请求方法
+	prefix string // 请求路径前缀
+	scope  string // 所需权限范围
+}
+
+// scopeRules 访问令牌的权限范围表，按顺序匹配，未命中的接口不允许访问令牌调用
+var scopeRules = []scopeRule{
+	// 回收站：查看、恢复与永久删除均视为写入，需排在通用的 Echo 规则之前
+	{prefix: "/api/echo/trash", scope: authModel.ScopeEchoWrite},
+
+	// 点赞管理：涉及访客标识，按系统管理处理
+	{prefix: "/api/echo/likes", scope: authModel.ScopeSettingsAdmin},
+
+	// Echo 读取
+	{method: http.MethodPost, prefix: "/api/echo/page", scope: authModel.ScopeEchoRead},
+	{method: http.MethodGet, prefix: "/api/echo", scope: authModel.ScopeEchoRead},
+
+	// Echo 写入
+	{method: http.MethodPost, prefix: "/api/echo", scope: authModel.ScopeEchoWrite},
+	{method: http.MethodPut, prefix: "/api/echo", scope: authModel.ScopeEchoWrite},
+	{method: http.MethodDelete, prefix: "/api/echo/", scope: authModel.ScopeEchoWrite},
+	{method: http.MethodDelete, prefix: "/api/tag/", scope: authModel.ScopeEchoWrite},
+
+	// 媒体上传
+	{prefix: "/api/images/", scope: authModel.ScopeMediaUpload},
+	{prefix: "/api/audios/", scope: authModel.ScopeMediaUpload},
+	{method: http.MethodPut, prefix: "/api/s3/presign", scope: authModel.ScopeMediaUpload},
+
+	// 备份
+	{prefix: "/api/backup", scope: authModel.ScopeBackup},
+
+	// 评论审核
+	{prefix: "/api/comments", scope: authModel.ScopeSettingsAdmin},
+
+	// 系统设置
+	{prefix: "/api/settings", scope: authModel.ScopeSettingsAdmin},
+	{prefix: "/api/comment/settings", scope: authModel.ScopeSettingsAdmin},
+	{prefix: "/api/s3/settings", scope: authModel.ScopeSettingsAdmin},
+	{prefix: "/api/oauth2/settings", scope: authModel.ScopeSettingsAdmin},
+	{prefix: "/api/webhook", scope: authModel.ScopeSettingsAdmin},
+	{prefix: "/api/fediverse/settings", scope: authModel.ScopeSettingsAdmin},
+	{prefix: "/api/agent/settings", scope: authModel.ScopeSettingsAdmin},
+	{prefix: "/api/image-process/settings", scope: authModel.ScopeSettingsAdmin},
+}
+
+// requiredScope 返回访问该接口所需的权限范围，未登记的接口返回 false
+func requiredScope(method, path string) (string, bool) {
+	for _, rule := range scopeRules {
+		if rule.method != "" && rule.method != method {
+			continue
+		}
+		if path == strings.TrimSuffix(rule.prefix, "/") || strings.HasPrefix(path, rule.prefix) {
+			return rule.scope, true
+		}
+	}
+	return "", false
+}
@@ -10,11 +10,36 @@ import (
 
 // MyClaims 是自定义的 JWT 声明结构体
 type MyClaims struct {
-	Userid   uint   `json:"user_id"`
-	Username string `json:"username"`
+	Userid   uint     `json:"user_id"`
+	Username string   `json:"username"`
+	Type     string   `json:"type,omitempty"`   // 令牌类型，访问令牌为 access，登录会话为 session，旧版令牌为空
+	Scopes   []string `json:"scopes,omitempty"` // 访问令牌的权限范围
 	jwt.RegisteredClaims
 }
 
+const (
+	TokenTypeAccess  = "access"  // 访问令牌（Personal Access Token）的令牌类型
+	TokenTypeSession = "session" // 登录会话的令牌类型
+)
+
+// 访问令牌权限范围
+const (
+	ScopeEchoRead      = "echo:read"      // 读取 Echo
+	ScopeEchoWrite     = "echo:write"     // 发布、编辑、删除 Echo
+	ScopeMediaUpload   = "media:upload"   // 上传与删除媒体文件
+	ScopeSettingsAdmin = "settings:admin" // 管理系统设置
+	ScopeBackup        = "backup"         // 备份与恢复
+)
+
+// AccessTokenScopes 所有可授予访问令牌的权限范围
+var AccessTokenScopes = []string{
+	ScopeEchoRead,
+	ScopeEchoWrite,
+	ScopeMediaUpload,
+	ScopeSettingsAdmin,
+	ScopeBackup,
+}
+
 const (
 	// MAX_USER_COUNT 定义最大用户数量
 	MAX_USER_COUNT = 5
@@ -0,0 +1,86 @@
+package model
+
+import "time"
+
+// Comment 定义 Echo 的原生评论实体，通过 ParentID 组织成楼中楼
+type Comment struct {
+	ID           uint      `gorm:"primaryKey"                     json:"id"`
+	EchoID       uint      `gorm:"not null;index"                 json:"echo_id"`
+	ParentID     uint      `gorm:"default:0;index"                json:"parent_id"`              // 回复的评论 ID，0 表示直接评论 Echo
+	UserID       uint      `gorm:"default:0;index"                json:"user_id"`                // 登录用户 ID，访客与远端用户为 0
+	AuthorName   string    `gorm:"type:varchar(100);not null"     json:"author_name"`            // 评论者昵称
+	AuthorEmail  string    `gorm:"type:varchar(255)"              json:"author_email,omitempty"` // 评论者邮箱，不对外展示
+	AuthorURL    string    `gorm:"type:varchar(255)"              json:"author_url"`             // 评论者主页
+	AuthorAvatar string    `gorm:"type:varchar(500)"              json:"author_avatar"`          // 评论者头像（远端用户）
+	Content      string    `gorm:"type:text;not null"             json:"content"`                // 评论内容
+	Status       string    `gorm:"type:varchar(20);index"         json:"status"`                 // 审核状态: pending/approved/spam
+	Source       string    `gorm:"type:varchar(20);default:local" json:"source"`                 // 评论来源: local/fediverse
+	RemoteID     string    `gorm:"type:varchar(500);index"        json:"remote_id,omitempty"`    // 远端回复的 Object ID
+	ActorURL     string    `gorm:"type:varchar(500);index"        json:"actor_url,omitempty"`    // 远端回复的 Actor
+	IP           string    `gorm:"type:varchar(64)"               json:"ip,omitempty"`           // 评论时的 IP
+	UserAgent    string    `gorm:"type:varchar(255)"              json:"user_agent,omitempty"`   // 评论时的 User-Agent
+	CreatedAt    time.Time `gorm:"index"                          json:"created_at"`
+	UpdatedAt    time.Time `                                      json:"updated_at"`
+
+	Replies []Comment `gorm:"-" json:"replies,omitempty"` // 子回复，仅在按楼层展示时填充
+}
+
+// Public 返回去除隐私字段后的评论，用于公开展示
+func (comment Comment) Public() Comment {
+	comment.AuthorEmail = ""
+	comment.IP = ""
+	comment.UserAgent = ""
+	return comment
+}
+
+// CreateCommentDto 发表评论的请求体
+//
+// swagger:model CreateCommentDto
+type CreateCommentDto struct {
+	ParentID    uint   `json:"parent_id"`    // 回复的评论 ID，0 表示直接评论 Echo
+	AuthorName  string `json:"author_name"`  // 访客昵称，登录用户可不填
+	AuthorEmail string `json:"author_email"` // 访客邮箱
+	AuthorURL   string `json:"author_url"`   // 访客主页
+	Content     string `json:"content"`      // 评论内容
+}
+
+// CommentQueryDto 评论管理的查询参数
+//
+// swagger:model CommentQueryDto
+type CommentQueryDto struct {
+	Status   string `form:"status"   json:"status"`   // 按审核状态过滤
+	EchoID   uint   `form:"echo_id"  json:"echo_id"`  // 按 Echo 过滤
+	Source   string `form:"source"   json:"source"`   // 按来源过滤
+	Page     int    `form:"page"     json:"page"`     // 页码，从1开始
+	PageSize int    `form:"pageSize" json:"pageSize"` // 每页大小
+}
+
+// UpdateCommentStatusDto 审核评论的请求体
+//
+// swagger:model UpdateCommentStatusDto
+type UpdateCommentStatusDto struct {
+	Status string `json:"status"` // 新的审核状态: pending/approved/spam
+}
+
+// ClientInfo 发表评论的客户端信息
+type ClientInfo struct {
+	IP        string // 请求 IP
+	UserAgent string // 请求 User-Agent
+}
+
+const (
+	CommentStatusPending  = "pending"  // 评论状态--待审核
+	CommentStatusApproved = "approved" // 评论状态--已通过
+	CommentStatusSpam     = "spam"     // 评论状态--垃圾评论
+
+	CommentSourceLocal     = "local"     // 评论来源--本站发表
+	CommentSourceFediverse = "fediverse" // 评论来源--联邦网络回复
+
+	MaxCommentLength     = 2000 // 评论内容的最大字符数
+	MaxCommentNameLength = 50   // 访客昵称的最大字符数
+)
+
+// IsValidCommentStatus 判断审核状态是否有效
+func IsValidCommentStatus(status string) bool {
+	return status == CommentStatusPending || status == CommentStatusApproved || status == CommentStatusSpam
+}
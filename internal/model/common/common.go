@@ -36,14 +36,15 @@ type Heatmap struct {
 }
 
 type (
-	UploadFileType  string
-	FileStorageType string
-	CommentProvider string
-	S3Provider      string
-	OAuth2Provider  string
-	AgentProvider   string
-	InboxType       string
-	InboxSource     string
+	UploadFileType       string
+	FileStorageType      string
+	CommentProvider      string
+	S3Provider           string
+	OAuth2Provider       string
+	AgentProvider        string
+	InboxType            string
+	InboxSource          string
+	BackupEncryptionMode string
 )
 
 const (
@@ -71,6 +72,8 @@ const (
 	WALINE CommentProvider = "waline"
 	// GISCUS 评论服务
 	GISCUS CommentProvider = "giscus"
+	// ECH0 内置评论服务
+	ECH0 CommentProvider = "ech0"
 )
 
 const (
@@ -103,11 +106,20 @@ const (
 	// Inbox 类型
 	EchoInboxType         InboxType = "echo"
 	NotificationInboxType InboxType = "notification"
+	CommentInboxType      InboxType = "comment"
 
 	// Inbox 来源
-	SystemSource InboxSource = "system"
-	AgentSource  InboxSource = "agent"
-	UserSource   InboxSource = "user"
+	SystemSource    InboxSource = "system"
+	AgentSource     InboxSource = "agent"
+	UserSource      InboxSource = "user"
+	FediverseSource InboxSource = "fediverse"
+)
+
+const (
+	// BACKUP_ENCRYPT_PASSPHRASE 使用口令加密备份
+	BACKUP_ENCRYPT_PASSPHRASE BackupEncryptionMode = "passphrase"
+	// BACKUP_ENCRYPT_X25519 使用 age 公钥加密备份
+	BACKUP_ENCRYPT_X25519 BackupEncryptionMode = "x25519"
 )
 
 // key value表
@@ -132,10 +144,16 @@ const (
 	FediverseSettingKey = "fediverse_setting"
 	// BackupScheduleKey 是备份计划设置的键
 	BackupScheduleKey = "backup_schedule"
+	// BackupDestinationKey 是异地备份设置的键
+	BackupDestinationKey = "backup_destination"
+	// BackupEncryptionKey 是备份加密设置的键
+	BackupEncryptionKey = "backup_encryption"
 	// AgentSettingKey 是 Agent 设置的键
 	AgentSettingKey = "agent_setting"
 	// ImageProcessSettingKey 是图片处理设置的键
 	ImageProcessSettingKey = "image_process_setting"
+	// TrashSettingKey 是回收站设置的键
+	TrashSettingKey = "trash_setting"
 	// ReleaseVersionKey 是发布版本号的键
 	ReleaseVersionKey = "release_version"
 	// VapidPublicKeyKey PWA Web Push 公钥
@@ -12,6 +12,7 @@ const (
 	INVALID_REQUEST_BODY   = "无效的请求体"
 	INVALID_PARAMS_BODY    = "无效参数"
 	INVALID_QUERY_PARAMS   = "无效的查询参数"
+	INVALID_SEARCH_QUERY   = "无效的搜索语句"
 	INVALID_REQUEST_METHOD = "无效的请求方法"
 )
 
@@ -25,14 +26,27 @@ const (
 	TOKEN_NOT_FOUND                   = "未找到令牌,请点击右上角登录"
 	TOKEN_NOT_VALID                   = "令牌无效，请重新登录"
 	TOKEN_PARSE_ERROR                 = "令牌解析失败，请尝试重新登陆"
+	TOKEN_REVOKED                     = "令牌已被撤销或已过期"
+	TOKEN_SCOPE_DENIED                = "令牌权限范围不足"
+	INVALID_TOKEN_SCOPE               = "无效的令牌权限范围"
 	USER_REGISTER_NOT_ALLOW           = "当前系统禁止注册新用户"
 )
 
 // Echo 错误相关常量
 const (
-	NO_PERMISSION_DENIED  = "没有权限,请联系系统管理员"
-	ECHO_CAN_NOT_BE_EMPTY = "ECHO 内容不能为空"
-	ECHO_NOT_FOUND        = "找不到Echo"
+	NO_PERMISSION_DENIED    = "没有权限,请联系系统管理员"
+	ECHO_CAN_NOT_BE_EMPTY   = "ECHO 内容不能为空"
+	ECHO_NOT_FOUND          = "找不到Echo"
+	ECHO_STATUS_INVALID     = "无效的发布状态"
+	ECHO_PUBLISH_AT_INVALID = "定时发布时间必须晚于当前时间"
+	ECHO_ALREADY_PUBLISHED  = "已发布的Echo不能改为草稿或定时发布"
+	ECHO_REVISION_NOT_FOUND = "找不到该历史版本"
+	ECHO_NOT_IN_TRASH       = "回收站中找不到该Echo"
+	TRASH_RETENTION_INVALID = "回收站保留天数必须大于0"
+	ECHO_ALREADY_LIKED      = "已经点过赞了"
+	ECHO_NOT_LIKED          = "还没有点赞"
+	ECHO_LIKE_CLEANUP_EMPTY = "请至少指定一项清理条件"
+	TOO_MANY_REQUESTS       = "请求过于频繁，请稍后再试"
 )
 
 // Common 错误相关常量
@@ -51,6 +65,10 @@ const (
 	S3_NOT_ENABLED             = "S3存储未启用"
 	S3_NOT_CONFIGURED          = "S3存储未配置"
 	S3_CONFIG_ERROR            = "S3存储配置错误"
+	MEDIA_NOT_FOUND            = "媒体未找到"
+	MEDIA_SOURCE_NOT_SUPPORTED = "不支持的媒体来源"
+	MEDIA_PROCESS_FAILED       = "媒体处理失败"
+	FFMPEG_NOT_INSTALLED       = "未安装 ffmpeg，无法生成视频封面"
 )
 
 // Inbox 错误相关常量
@@ -58,6 +76,19 @@ const (
 	INBOX_NOT_FOUND = "收件箱消息不存在"
 )
 
+// Comment 错误相关常量
+const (
+	COMMENT_DISABLED         = "评论功能未开启"
+	COMMENT_NOT_FOUND        = "评论不存在"
+	COMMENT_PARENT_INVALID   = "无法回复该评论"
+	COMMENT_CONTENT_EMPTY    = "评论内容不能为空"
+	COMMENT_CONTENT_TOO_LONG = "评论内容过长"
+	COMMENT_NAME_INVALID     = "请填写昵称，且不超过50个字符"
+	COMMENT_EMAIL_INVALID    = "邮箱格式不正确"
+	COMMENT_URL_INVALID      = "主页地址需以 http:// 或 https:// 开头"
+	COMMENT_STATUS_INVALID   = "无效的评论审核状态"
+)
+
 // User 错误相关常量
 const (
 	USERNAME_ALREADY_EXISTS        = "用户名已存在"
@@ -92,22 +123,40 @@ const (
 const (
 	NO_SUCH_COMMENT_PROVIDER            = "无效的评论服务提供者"
 	WEBHOOK_NAME_OR_URL_CANNOT_BE_EMPTY = "未填写 Webhook 名称或 URL"
+	INVALID_WEBHOOK_EVENT_TYPE          = "无效的 Webhook 订阅事件类型"
+	WEBHOOK_DELIVERY_NOT_FOUND          = "Webhook 投递记录不存在"
 	INVALID_CRON_EXPRESSION             = "无效的 Cron 表达式"
 )
 
 // Backup 错误相关常量
 const (
-	SNAPSHOT_UPLOAD_FAILED  = "快照上传失败"
-	SNAPSHOT_RESTORE_FAILED = "快照恢复失败"
-	DATABASE_CLOSE_FAILED   = "数据库关闭失败"
+	SNAPSHOT_UPLOAD_FAILED     = "快照上传失败"
+	SNAPSHOT_RESTORE_FAILED    = "快照恢复失败"
+	DATABASE_CLOSE_FAILED      = "数据库关闭失败"
+	BACKUP_NOT_FOUND           = "备份文件不存在"
+	INVALID_BACKUP_NAME        = "无效的备份文件名"
+	INVALID_BACKUP_RETAIN      = "无效的备份保留策略"
+	INVALID_BACKUP_DEST        = "无效的异地备份设置"
+	BACKUP_UPLOAD_FAILED       = "异地备份上传失败"
+	INVALID_BACKUP_ENCRYPT     = "无效的备份加密设置"
+	BACKUP_KEY_REQUIRED        = "备份已加密，请提供口令或私钥"
+	BACKUP_KEY_MISMATCH        = "口令或私钥与备份不匹配"
+	RESTORE_PREVIEW_NOT_FOUND  = "恢复预检不存在或已过期，请重新上传备份"
+	INVALID_EXPORT_FILE        = "无效的导出文件"
+	UNSUPPORTED_EXPORT_VERSION = "不支持的导出格式版本"
+	INVALID_EXPORT_FLAVOR      = "不支持的导出风格"
+	UNSUPPORTED_IMPORT_SOURCE  = "无法识别的导入来源"
+	INVALID_IMPORT_FILE        = "无效的导入文件"
 )
 
 // Fediverse 错误相关常量
 const (
-	GET_ACTOR_ERROR         = "获取 Actor 信息失败"
-	ACTIVEPUB_NOT_ENABLED   = "ActivityPub 未启用"
-	FEDIVERSE_INVALID_INPUT = "无效的联邦参数"
-	FOLLOW_RELATION_MISSING = "未找到关注关系"
+	GET_ACTOR_ERROR          = "获取 Actor 信息失败"
+	ACTIVEPUB_NOT_ENABLED    = "ActivityPub 未启用"
+	FEDIVERSE_INVALID_INPUT  = "无效的联邦参数"
+	FOLLOW_RELATION_MISSING  = "未找到关注关系"
+	INVALID_HTTP_SIGNATURE   = "无效的 HTTP 签名"
+	SIGNATURE_ACTOR_MISMATCH = "签名者与 Activity 的 Actor 不一致"
 )
 
 // Agent 错误相关常量
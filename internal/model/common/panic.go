@@ -11,5 +11,6 @@ const (
 	INIT_HANDLERS_PANIC        = "初始化 Handlers 失败"
 	INIT_TASKER_PANIC          = "初始化 Tasker 失败"
 	INIT_EVENT_REGISTRAR_PANIC = "初始化 EventRegistrar 失败"
+	INIT_TRUSTED_PROXIES_PANIC = "设置受信任代理失败"
 	GIN_RUN_FAILED             = "启动 GIN 服务器失败"
 )
@@ -13,17 +13,31 @@ const (
 
 // Echo 成功相关常量
 const (
-	POST_ECHO_SUCCESS           = "发布Echo成功！"
-	GET_ECHOS_BY_PAGE_SUCCESS   = "获取Echos成功！"
-	DELETE_ECHO_SUCCESS         = "删除Echo成功"
-	GET_TODAY_ECHOS_SUCCESS     = "获取当日Echos成功"
-	UPDATE_ECHO_SUCCESS         = "更新Echo成功"
-	LIKE_ECHO_SUCCESS           = "点赞Echo成功"
-	GET_ECHO_BY_ID_SUCCESS      = "获取Echo成功"
-	GET_ALL_TAGS_SUCCESS        = "获取所有标签成功"
-	DELETE_TAG_SUCCESS          = "删除标签成功"
-	GET_ECHOS_BY_TAG_ID_SUCCESS = "获取标签下的Echos成功"
-	GET_ECHOS_BY_DATE_SUCCESS   = "获取日期下的Echos成功"
+	POST_ECHO_SUCCESS             = "发布Echo成功！"
+	GET_ECHOS_BY_PAGE_SUCCESS     = "获取Echos成功！"
+	DELETE_ECHO_SUCCESS           = "已将Echo移入回收站"
+	GET_TODAY_ECHOS_SUCCESS       = "获取当日Echos成功"
+	UPDATE_ECHO_SUCCESS           = "更新Echo成功"
+	LIKE_ECHO_SUCCESS             = "点赞Echo成功"
+	GET_ECHO_BY_ID_SUCCESS        = "获取Echo成功"
+	GET_ALL_TAGS_SUCCESS          = "获取所有标签成功"
+	DELETE_TAG_SUCCESS            = "删除标签成功"
+	GET_ECHOS_BY_TAG_ID_SUCCESS   = "获取标签下的Echos成功"
+	GET_ECHOS_BY_DATE_SUCCESS     = "获取日期下的Echos成功"
+	SEARCH_ECHOS_SUCCESS          = "搜索Echos成功"
+	SAVE_DRAFT_SUCCESS            = "保存草稿成功"
+	SCHEDULE_ECHO_SUCCESS         = "已设置定时发布"
+	GET_DRAFT_ECHOS_SUCCESS       = "获取草稿与定时Echos成功"
+	GET_ECHO_REVISIONS_SUCCESS    = "获取历史版本成功"
+	DIFF_ECHO_REVISION_SUCCESS    = "对比历史版本成功"
+	RESTORE_ECHO_REVISION_SUCCESS = "恢复历史版本成功"
+	GET_TRASH_ECHOS_SUCCESS       = "获取回收站成功"
+	RESTORE_TRASH_ECHO_SUCCESS    = "已从回收站恢复Echo"
+	PURGE_TRASH_ECHO_SUCCESS      = "已永久删除Echo"
+	EMPTY_TRASH_SUCCESS           = "已清空回收站"
+	UNLIKE_ECHO_SUCCESS           = "取消点赞成功"
+	GET_ECHO_LIKES_SUCCESS        = "获取点赞记录成功"
+	CLEANUP_ECHO_LIKES_SUCCESS    = "清理点赞记录成功"
 )
 
 // Common 成功相关常量
@@ -40,6 +54,15 @@ const (
 	GET_WEBSITE_TITLE_SUCCESS  = "获取网站标题成功"
 )
 
+// Comment 成功相关常量
+const (
+	GET_COMMENTS_SUCCESS          = "获取评论成功"
+	CREATE_COMMENT_SUCCESS        = "评论成功"
+	CREATE_COMMENT_PENDING        = "评论已提交，等待审核"
+	UPDATE_COMMENT_STATUS_SUCCESS = "更新评论状态成功"
+	DELETE_COMMENT_SUCCESS        = "删除评论成功"
+)
+
 // Inbox 成功相关常量
 const (
 	GET_INBOX_LIST_SUCCESS   = "获取收件箱成功"
@@ -64,12 +87,19 @@ const (
 	DELETE_WEBHOOK_SUCCESS            = "删除 Webhook 成功"
 	UPDATE_WEBHOOK_SUCCESS            = "更新 Webhook 成功"
 	CREATE_WEBHOOK_SUCCESS            = "创建 Webhook 成功"
+	GET_WEBHOOK_DELIVERIES_SUCCESS    = "获取 Webhook 投递记录成功"
+	REDELIVER_WEBHOOK_SUCCESS         = "Webhook 已加入投递队列"
+	PING_WEBHOOK_SUCCESS              = "Webhook 测试投递完成"
 	LIST_ACCESS_TOKENS_SUCCESS        = "列出访问令牌成功"
 	CREATE_ACCESS_TOKEN_SUCCESS       = "创建访问令牌成功"
 	DELETE_ACCESS_TOKEN_SUCCESS       = "删除访问令牌成功"
 	GET_FEDIVERSE_SETTINGS_SUCCESS    = "获取联邦网络设置成功"
 	UPDATE_FEDIVERSE_SETTINGS_SUCCESS = "更新联邦网络设置成功"
 	SCHEDULE_BACKUP_SUCCESS           = "设置备份计划成功"
+	GET_BACKUP_DESTINATION_SUCCESS    = "获取异地备份设置成功"
+	UPDATE_BACKUP_DESTINATION_SUCCESS = "更新异地备份设置成功"
+	GET_BACKUP_ENCRYPTION_SUCCESS     = "获取备份加密设置成功"
+	UPDATE_BACKUP_ENCRYPTION_SUCCESS  = "更新备份加密设置成功"
 )
 
 // To do 成功相关常量
@@ -102,9 +132,15 @@ const (
 
 // Backup 成功相关常量
 const (
-	BACKUP_SUCCESS        = "备份成功"
-	EXPORT_BACKUP_SUCCESS = "导出备份成功"
-	IMPORT_BACKUP_SUCCESS = "导入备份成功"
+	BACKUP_SUCCESS          = "备份成功"
+	EXPORT_BACKUP_SUCCESS   = "导出备份成功"
+	IMPORT_BACKUP_SUCCESS   = "导入备份成功"
+	PREVIEW_RESTORE_SUCCESS = "备份预检完成，请确认后恢复"
+	DISCARD_RESTORE_SUCCESS = "已取消恢复"
+	LIST_BACKUPS_SUCCESS    = "获取备份列表成功"
+	DELETE_BACKUP_SUCCESS   = "删除备份成功"
+	EXPORT_ECHOS_SUCCESS    = "导出 Echo 成功"
+	IMPORT_ECHOS_SUCCESS    = "导入 Echo 成功"
 )
 
 // Fediverse 成功相关常量
@@ -116,6 +152,7 @@ const (
 	FEDIVERSE_UNDO_LIKE_SUCCESS         = "取消点赞请求已发送"
 	FEDIVERSE_GET_FOLLOW_STATUS_SUCCESS = "获取关注状态成功"
 	FEDIVERSE_GET_TIMELINE_SUCCESS      = "获取时间线成功"
+	FEDIVERSE_GET_FOLLOWING_SUCCESS     = "获取关注列表成功"
 )
 
 // Agent 成功相关常量
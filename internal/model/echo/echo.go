@@ -3,24 +3,37 @@ package model
 import (
 	"encoding/json"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Echo 定义Echo实体
 type Echo struct {
-	ID            uint      `gorm:"primaryKey"                                       json:"id"`
-	Content       string    `gorm:"type:text;not null"                               json:"content"`
-	Username      string    `gorm:"type:varchar(100)"                                json:"username,omitempty"`
-	Media         []Media   `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE" json:"media,omitempty"`
-	Images        []Media   `gorm:"-"                                                json:"images,omitempty"` // 兼容旧版客户端，不存数据库
-	Layout        string    `gorm:"type:varchar(50);default:'waterfall'"             json:"layout,omitempty"`
-	Private       bool      `gorm:"default:false"                                    json:"private"`
-	UserID        uint      `gorm:"not null;index"                                   json:"user_id"`
-	Extension     string    `gorm:"type:text"                                        json:"extension,omitempty"`
-	ExtensionType string    `gorm:"type:varchar(100)"                                json:"extension_type,omitempty"`
-	Tags          []Tag     `gorm:"many2many:echo_tags;"                             json:"tags,omitempty"`
-	FavCount      int       `gorm:"default:0"                                        json:"fav_count"`
-	CreatedAt     time.Time `                                                        json:"created_at"`
-	User          User      `gorm:"foreignKey:UserID"                                json:"user,omitempty"` // 关联用户信息
+	ID            uint           `gorm:"primaryKey"                                       json:"id"`
+	Content       string         `gorm:"type:text;not null"                               json:"content"`
+	Username      string         `gorm:"type:varchar(100)"                                json:"username,omitempty"`
+	Media         []Media        `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE" json:"media,omitempty"`
+	Images        []Media        `gorm:"-"                                                json:"images,omitempty"` // 兼容旧版客户端，不存数据库
+	Layout        string         `gorm:"type:varchar(50);default:'waterfall'"             json:"layout,omitempty"`
+	Private       bool           `gorm:"default:false"                                    json:"private"`
+	UserID        uint           `gorm:"not null;index"                                   json:"user_id"`
+	Extension     string         `gorm:"type:text"                                        json:"extension,omitempty"`
+	ExtensionType string         `gorm:"type:varchar(100)"                                json:"extension_type,omitempty"`
+	Tags          []Tag          `gorm:"many2many:echo_tags;"                             json:"tags,omitempty"`
+	FavCount      int            `gorm:"default:0"                                        json:"fav_count"`
+	FediLikes     int            `gorm:"default:0"                                        json:"fedi_likes"`           // 联邦宇宙点赞数（Like）
+	FediBoosts    int            `gorm:"default:0"                                        json:"fedi_boosts"`          // 联邦宇宙转发数（Announce）
+	Status        string         `gorm:"type:varchar(20);default:'published';index"       json:"status"`               // 发布状态: published/draft/scheduled
+	PublishAt     *time.Time     `gorm:"index"                                            json:"publish_at,omitempty"` // 定时发布时间（仅定时发布有效）
+	CreatedAt     time.Time      `                                                        json:"created_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index"                                            json:"deleted_at"`     // 移入回收站的时间，为空表示未删除
+	User          User           `gorm:"foreignKey:UserID"                                json:"user,omitempty"` // 关联用户信息
+}
+
+// IsPublished 是否已发布，草稿与定时发布的 Echo 不对外展示，也不推送事件
+// 旧数据与未指定状态的 Echo 视为已发布
+func (e Echo) IsPublished() bool {
+	return e.Status == "" || e.Status == EchoStatusPublished
 }
 
 // User 用户信息（用于Echo关联查询）
@@ -47,16 +60,19 @@ type User struct {
 
 // Media 定义Media实体（原Image实体）
 type Media struct {
-	ID          uint   `gorm:"primaryKey"       json:"id"`
-	MessageID   uint   `gorm:"index;not null"   json:"message_id"`           // 关联的Echo ID(注意⚠️: 该字段名为MessageID, 但实际关联的是Echo表,因为为了兼容旧版Echo用户)
-	MediaURL    string `gorm:"type:text"        json:"media_url"`            // 媒体URL（原image_url）
-	MediaType   string `gorm:"type:varchar(20)" json:"media_type"`           // 媒体类型: image/video
-	MediaSource string `gorm:"type:varchar(20)" json:"media_source"`         // 媒体来源: local/url/s3（原image_source）
-	ObjectKey   string `gorm:"type:text"        json:"object_key,omitempty"` // 对象存储的Key (如果是本地存储则为空)
-	Width       int    `gorm:"default:0"        json:"width,omitempty"`      // 媒体宽度
-	Height      int    `gorm:"default:0"        json:"height,omitempty"`     // 媒体高度
-	LiveVideoID *uint  `gorm:"index"            json:"live_video_id,omitempty"` // 实况照片关联的视频Media ID（仅图片类型有效）
-	LivePairID  string `gorm:"-"                json:"live_pair_id,omitempty"`  // 实况照片配对ID（仅用于请求，不持久化）
+	ID            uint           `gorm:"primaryKey"                json:"id"`
+	MessageID     uint           `gorm:"index;not null"            json:"message_id"`               // 关联的Echo ID(注意⚠️: 该字段名为MessageID, 但实际关联的是Echo表,因为为了兼容旧版Echo用户)
+	MediaURL      string         `gorm:"type:text"                 json:"media_url"`                // 媒体URL（原image_url）
+	MediaType     string         `gorm:"type:varchar(20)"          json:"media_type"`               // 媒体类型: image/video
+	MediaSource   string         `gorm:"type:varchar(20)"          json:"media_source"`             // 媒体来源: local/url/s3（原image_source）
+	ObjectKey     string         `gorm:"type:text"                 json:"object_key,omitempty"`     // 对象存储的Key (如果是本地存储则为空)
+	Width         int            `gorm:"default:0"                 json:"width,omitempty"`          // 媒体宽度
+	Height        int            `gorm:"default:0"                 json:"height,omitempty"`         // 媒体高度
+	LiveVideoID   *uint          `gorm:"index"                     json:"live_video_id,omitempty"`  // 实况照片关联的视频Media ID（仅图片类型有效）
+	LivePairID    string         `gorm:"-"                         json:"live_pair_id,omitempty"`   // 实况照片配对ID（仅用于请求，不持久化）
+	Blurhash      string         `gorm:"type:varchar(64)"          json:"blurhash,omitempty"`       // 图片的 BlurHash 占位图
+	DominantColor string         `gorm:"type:varchar(7)"           json:"dominant_color,omitempty"` // 图片主色调，如 #a1b2c3
+	Variants      []MediaVariant `gorm:"serializer:json;type:text" json:"variants,omitempty"`       // 上传后异步生成的响应式尺寸，按宽度升序
 }
 
 // Image 旧版兼容结构体，用于 JSON 序列化时提供 images 字段（仅包含图片，不含视频）
@@ -160,4 +176,7 @@ const (
 	LayoutHorizontal = "horizontal" // 横向布局
 	LayoutCarousel   = "carousel"   // 单图轮播布局
 
+	EchoStatusPublished = "published" // 发布状态--已发布
+	EchoStatusDraft     = "draft"     // 发布状态--草稿
+	EchoStatusScheduled = "scheduled" // 发布状态--定时发布
 )
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// EchoLike 点赞记录，每个身份对同一条 Echo 只能点赞一次
+type EchoLike struct {
+	ID          uint      `gorm:"primaryKey"                                           json:"id"`
+	EchoID      uint      `gorm:"not null;uniqueIndex:idx_echo_like_identity"          json:"echo_id"`
+	Identity    string    `gorm:"type:varchar(100);uniqueIndex:idx_echo_like_identity" json:"identity"`    // 点赞身份，如 user:1、visitor:xxx、fingerprint:xxx
+	Kind        string    `gorm:"type:varchar(20);index"                               json:"kind"`        // 身份类型: user/visitor/fingerprint/legacy
+	UserID      uint      `gorm:"default:0;index"                                      json:"user_id"`     // 登录用户 ID，访客为 0
+	Fingerprint string    `gorm:"type:varchar(64);index"                               json:"fingerprint"` // 访客指纹（IP 与浏览器特征的摘要）
+	IP          string    `gorm:"type:varchar(64);index"                               json:"ip"`          // 点赞时的 IP
+	UserAgent   string    `gorm:"type:varchar(255)"                                    json:"user_agent"`  // 点赞时的 User-Agent
+	CreatedAt   time.Time `gorm:"index"                                                json:"created_at"`
+}
+
+// LikeIdentity 点赞者身份，由请求的登录状态、访客 Cookie 与指纹确定
+type LikeIdentity struct {
+	Kind             string // 身份类型
+	Identity         string // 唯一身份标识
+	UserID           uint   // 登录用户 ID，访客为 0
+	Fingerprint      string // 访客指纹
+	MatchFingerprint bool   // 是否同时按指纹匹配，未携带 Cookie 或 Cookie 签发不久的访客为 true
+	IP               string // 请求 IP
+	UserAgent        string // 请求 User-Agent
+}
+
+// EchoLikeResult 点赞或取消点赞后的状态
+type EchoLikeResult struct {
+	Liked    bool `json:"liked"`     // 当前身份是否已点赞
+	FavCount int  `json:"fav_count"` // Echo 的点赞数
+}
+
+// EchoLikeQueryDto 点赞记录查询参数
+//
+// swagger:model EchoLikeQueryDto
+type EchoLikeQueryDto struct {
+	EchoID   uint   `form:"echo_id"  json:"echo_id"`  // 按 Echo 过滤
+	IP       string `form:"ip"       json:"ip"`       // 按 IP 过滤
+	Kind     string `form:"kind"     json:"kind"`     // 按身份类型过滤
+	Page     int    `form:"page"     json:"page"`     // 页码，从1开始
+	PageSize int    `form:"pageSize" json:"pageSize"` // 每页大小
+}
+
+// EchoLikeCleanupDto 批量清理点赞记录的条件，至少需要指定一项
+//
+// swagger:model EchoLikeCleanupDto
+type EchoLikeCleanupDto struct {
+	EchoID uint       `json:"echo_id"` // 只清理该 Echo 的点赞
+	IP     string     `json:"ip"`      // 只清理该 IP 的点赞
+	Kind   string     `json:"kind"`    // 只清理该身份类型的点赞
+	Since  *time.Time `json:"since"`   // 只清理该时间之后的点赞
+	Until  *time.Time `json:"until"`   // 只清理该时间之前的点赞
+}
+
+// IsEmpty 是否未指定任何清理条件
+func (dto EchoLikeCleanupDto) IsEmpty() bool {
+	return dto.EchoID == 0 && dto.IP == "" && dto.Kind == "" && dto.Since == nil && dto.Until == nil
+}
+
+const (
+	LikeKindUser        = "user"        // 点赞身份--登录用户
+	LikeKindVisitor     = "visitor"     // 点赞身份--带签名 Cookie 的访客
+	LikeKindFingerprint = "fingerprint" // 点赞身份--未携带 Cookie 的访客，按指纹识别
+	LikeKindLegacy      = "legacy"      // 点赞身份--旧版计数迁移而来，无法追溯来源
+)
@@ -0,0 +1,19 @@
+package model
+
+// ProcessedMedia 按需处理后的媒体（缩放后的图片或视频封面）
+// 命中缓存时返回缓存文件路径，刚生成时直接返回处理结果
+type ProcessedMedia struct {
+	FilePath    string // 缓存文件路径
+	Data        []byte // 刚生成的处理结果
+	ContentType string // 处理结果的 Content-Type，仅 Data 不为空时有效
+	ETag        string // 由源文件与处理参数生成的 ETag
+	RedirectURL string // 无需处理时跳转的原始地址，不为空时其余字段为空
+}
+
+// MediaVariant 图片的一个响应式尺寸，可直接拼成 srcset（如 "/api/images/variants/xxx_640.webp 640w"）
+type MediaVariant struct {
+	URL    string `json:"url"`    // 访问地址，与 MediaURL 的格式一致
+	Width  int    `json:"width"`  // 宽度
+	Height int    `json:"height"` // 高度
+	Format string `json:"format"` // 编码格式，如 webp
+}
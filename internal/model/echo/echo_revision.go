@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// MaxEchoRevisions 每条 Echo 保留的历史版本上限，超出后删除最旧的版本
+const MaxEchoRevisions = 50
+
+// EchoRevision Echo 的历史版本，每次更新前保存被覆盖的内容
+type EchoRevision struct {
+	ID            uint      `gorm:"primaryKey"                json:"id"`
+	EchoID        uint      `gorm:"index;not null"            json:"echo_id"`
+	Content       string    `gorm:"type:text"                 json:"content"`
+	Tags          []string  `gorm:"serializer:json;type:text" json:"tags"`                     // 标签名称列表
+	Media         []Media   `gorm:"serializer:json;type:text" json:"media"`                    // 媒体列表快照
+	Layout        string    `gorm:"type:varchar(50)"          json:"layout,omitempty"`         // 图片布局
+	Extension     string    `gorm:"type:text"                 json:"extension,omitempty"`      // 扩展附加内容
+	ExtensionType string    `gorm:"type:varchar(100)"         json:"extension_type,omitempty"` // 扩展类型
+	CreatedAt     time.Time `                                 json:"created_at"`               // 被覆盖的时间
+}
+
+// EchoRevisionDiff 两个版本之间的差异
+type EchoRevisionDiff struct {
+	From uint   `json:"from"` // 起始版本 ID
+	To   uint   `json:"to"`   // 目标版本 ID，0 表示当前版本
+	Diff string `json:"diff"` // unified diff 格式的差异，内容相同时为空
+}
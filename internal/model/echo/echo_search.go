@@ -0,0 +1,21 @@
+package model
+
+// EchoSearchDto Echo 全文搜索的查询参数
+//
+// swagger:model EchoSearchDto
+type EchoSearchDto struct {
+	Query         string `form:"q"              json:"q"`              // 搜索语句，支持短语 "..."、前缀 abc* 与 AND/OR/NOT
+	Tag           string `form:"tag"            json:"tag"`            // 按标签名过滤
+	StartDate     string `form:"start_date"     json:"start_date"`     // 起始日期 (YYYY-MM-DD)
+	EndDate       string `form:"end_date"       json:"end_date"`       // 结束日期 (YYYY-MM-DD)
+	HasMedia      *bool  `form:"has_media"      json:"has_media"`      // 是否包含媒体，为空表示不过滤
+	ExtensionType string `form:"extension_type" json:"extension_type"` // 扩展类型 (MUSIC/VIDEO/GITHUBPROJ/WEBSITE)
+	Page          int    `form:"page"           json:"page"`           // 页码，从1开始
+	PageSize      int    `form:"pageSize"       json:"pageSize"`       // 每页大小
+}
+
+// EchoSearchResult Echo 搜索结果
+type EchoSearchResult struct {
+	Echo    Echo   `json:"echo"`    // 命中的 Echo
+	Snippet string `json:"snippet"` // 带 <mark> 高亮的内容片段
+}
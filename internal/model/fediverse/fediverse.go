@@ -21,8 +21,14 @@ const (
 	ActivityTypeAccept   string = "Accept"
 	ActivityTypeAnnounce string = "Announce"
 	ActivityTypeUndo     string = "Undo"
+	ActivityTypeDelete   string = "Delete"
+	ActivityTypeUpdate   string = "Update"
+	ActivityTypeReject   string = "Reject"
 )
 
+// ObjectTypeTombstone 已删除对象的占位类型
+const ObjectTypeTombstone = "Tombstone"
+
 const (
 	DefaultCollectionPageSize = 20
 	MaxCollectionPageSize     = 80
@@ -124,6 +130,53 @@ type Actor struct {
 	PublicKey         PublicKey     `json:"publicKey"`         // 公钥信息
 }
 
+// RemoteActor 远端 Actor 文档（仅保留联邦交互需要的字段）
+type RemoteActor struct {
+	ID                string          `json:"id"`                // Actor URL
+	Type              string          `json:"type"`              // Person, Service, Application...
+	PreferredUsername string          `json:"preferredUsername"` // 用户名
+	Name              string          `json:"name"`              // 显示名称
+	Summary           string          `json:"summary"`           // 简介
+	Icon              json.RawMessage `json:"icon,omitempty"`    // 头像，可能是对象、数组或字符串
+	Inbox             string          `json:"inbox"`             // 个人收件箱
+	Endpoints         struct {
+		SharedInbox string `json:"sharedInbox"` // 共享收件箱
+	} `json:"endpoints"`
+	PublicKey PublicKey `json:"publicKey"` // 公钥信息
+}
+
+// AvatarURL 解析头像 URL
+func (actor *RemoteActor) AvatarURL() string {
+	if len(actor.Icon) == 0 {
+		return ""
+	}
+
+	var url string
+	if err := json.Unmarshal(actor.Icon, &url); err == nil {
+		return url
+	}
+
+	var icon Preview
+	if err := json.Unmarshal(actor.Icon, &icon); err == nil {
+		return icon.URL
+	}
+
+	var icons []Preview
+	if err := json.Unmarshal(actor.Icon, &icons); err == nil && len(icons) > 0 {
+		return icons[0].URL
+	}
+
+	return ""
+}
+
+// Delivery 待投递到远端收件箱的 Activity
+type Delivery struct {
+	ActorID   string          `json:"actor_id"`            // 发送者 Actor URL，用于签名
+	Inbox     string          `json:"inbox,omitempty"`     // 目标收件箱，优先使用共享收件箱
+	Recipient string          `json:"recipient,omitempty"` // 目标 Actor URL，收件箱未解析时投递前再解析
+	Activity  json.RawMessage `json:"activity"`            // Activity JSON
+}
+
 // Follow 表：存储关注请求及状态
 type Follow struct {
 	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -144,6 +197,33 @@ type Follower struct {
 	CreatedAt time.Time `gorm:"autoCreateTime"           json:"created_at"`
 }
 
+// Interaction 表：存储远端 Actor 对本地 Echo 的互动（Like、Announce）
+type Interaction struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	EchoID     uint      `gorm:"not null;index"           json:"echo_id"`     // 被互动的本地 Echo ID
+	ActorID    string    `gorm:"size:512;not null;index"  json:"actor_id"`    // 发起互动的 Actor URL
+	Type       string    `gorm:"size:20;not null"         json:"type"`        // Like, Announce
+	ActivityID string    `gorm:"size:512;index"           json:"activity_id"` // 互动 Activity ID，便于撤销
+	CreatedAt  time.Time `gorm:"autoCreateTime"           json:"created_at"`
+}
+
+// Tombstone 表：记录已删除的公开 Echo，再次访问其 Object 时返回 410
+type Tombstone struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	EchoID     uint      `gorm:"not null;uniqueIndex"     json:"echo_id"`     // 已删除的 Echo ID
+	FormerType string    `gorm:"size:64;not null"         json:"former_type"` // 删除前的 Object 类型，例如 Note
+	DeletedAt  time.Time `gorm:"not null"                 json:"deleted_at"`  // 删除时间
+}
+
+// TombstoneObject ActivityPub Tombstone 对象
+type TombstoneObject struct {
+	Context    any    `json:"@context,omitempty"`
+	ID         string `json:"id"`         // 原 Object URL
+	Type       string `json:"type"`       // 固定为 "Tombstone"
+	FormerType string `json:"formerType"` // 删除前的 Object 类型
+	Deleted    string `json:"deleted"`    // 删除时间，RFC3339 格式
+}
+
 // InboxStatus 收件箱中存储的远端推文记录，供后续时间线展示使用
 type InboxStatus struct {
 	ID                     uint      `gorm:"primaryKey;autoIncrement"                             json:"id"`
@@ -50,11 +50,23 @@ type SystemMetric struct {
 	GoRoutineCount int           // 当前 Goroutine 数量
 }
 
+// ImageCacheMetric 图片处理磁盘缓存指标
+type ImageCacheMetric struct {
+	Hits      uint64  // 命中次数
+	Misses    uint64  // 未命中次数
+	HitRate   float64 // 命中率百分比
+	Evictions uint64  // 淘汰的文件数
+	Entries   int     // 缓存的文件数
+	Size      uint64  // 已用大小
+	MaxSize   uint64  // 容量上限
+}
+
 // Metrics 综合监控指标
 type Metrics struct {
-	CPU     CpuMetric     // CPU 监控指标
-	Memory  MemoryMetric  // 内存监控指标
-	Disk    DiskMetric    // 磁盘监控指标
-	Network NetworkMetric // 网络监控指标
-	System  SystemMetric  // 系统监控指标
+	CPU        CpuMetric        // CPU 监控指标
+	Memory     MemoryMetric     // 内存监控指标
+	Disk       DiskMetric       // 磁盘监控指标
+	Network    NetworkMetric    // 网络监控指标
+	System     SystemMetric     // 系统监控指标
+	ImageCache ImageCacheMetric // 图片处理磁盘缓存指标
 }
@@ -9,6 +9,8 @@ const (
 	DeadLetterTypeWebhook = "webhook"
 	// DeadLetterTypePushEchoFediverse 联邦宇宙相关的 push 类型的死信任务
 	DeadLetterTypePushEchoFediverse = "push_echo_fediverse"
+	// DeadLetterTypeFediverseDelivery 联邦宇宙单个收件箱投递失败的死信任务
+	DeadLetterTypeFediverseDelivery = "fediverse_delivery"
 )
 
 const (
@@ -1,6 +1,12 @@
 package model
 
-import "time"
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
 const (
 	EIGHT_HOUR_EXPIRY string = "8_hours"
@@ -29,6 +35,7 @@ type CommentSetting struct {
 	EnableComment bool   `json:"enable_comment"` // 是否启用评论
 	Provider      string `json:"provider"`       // 评论提供者
 	CommentAPI    string `json:"comment_api"`    // 评论 API 地址
+	AutoApprove   bool   `json:"auto_approve"`   // 内置评论是否免审核直接展示
 }
 
 // S3Setting 定义 S3 存储设置实体
@@ -66,12 +73,16 @@ type OAuth2Setting struct {
 
 // AccessTokenSetting 定义访问令牌设置实体
 type AccessTokenSetting struct {
-	ID        int        `json:"id"`         // 访问令牌 ID
-	UserID    uint       `json:"user_id"`    // 创建该访问令牌的用户 ID
-	Token     string     `json:"token"`      // 访问令牌
-	Name      string     `json:"name"`       // 访问令牌名称
-	Expiry    *time.Time `json:"expiry"`     // 指针类型，NULL 表示永不过期
-	CreatedAt time.Time  `json:"created_at"` // 访问令牌创建时间，RFC3339 时间字符串
+	ID         int        `json:"id"`                                            // 访问令牌 ID
+	UserID     uint       `json:"user_id"`                                       // 创建该访问令牌的用户 ID
+	Token      string     `json:"token"`                                         // 访问令牌
+	JTI        string     `json:"-"            gorm:"size:64;index"`             // 令牌唯一标识，对应 JWT 的 jti
+	Name       string     `json:"name"`                                          // 访问令牌名称
+	Scopes     []string   `json:"scopes"       gorm:"serializer:json;type:text"` // 权限范围，为空表示旧版全权限令牌
+	Expiry     *time.Time `json:"expiry"`                                        // 指针类型，NULL 表示永不过期
+	LastUsedAt *time.Time `json:"last_used_at"`                                  // 最近一次使用时间
+	LastUsedIP string     `json:"last_used_ip"`                                  // 最近一次使用的来源 IP
+	CreatedAt  time.Time  `json:"created_at"`                                    // 访问令牌创建时间，RFC3339 时间字符串
 }
 
 // FediverseSetting 定义联邦网络设置实体
@@ -91,8 +102,55 @@ type AgentSetting struct {
 }
 
 type BackupSchedule struct {
-	Enable         bool   `json:"enable"`          // 是否启用备份计划
-	CronExpression string `json:"cron_expression"` // 备份计划的 Cron 表达式
+	Enable         bool            `json:"enable"`          // 是否启用备份计划
+	CronExpression string          `json:"cron_expression"` // 备份计划的 Cron 表达式
+	Retention      BackupRetention `json:"retention"`       // 备份保留策略
+}
+
+// BackupRetention 定义备份保留策略，各项规则保留的备份取并集，最新的一份备份始终保留
+// 全部为 0 时（如旧版本数据）使用默认策略
+type BackupRetention struct {
+	KeepLast    int `json:"keep_last"`    // 保留最近的 N 份备份
+	KeepDaily   int `json:"keep_daily"`   // 保留最近 N 天中每天最新的一份
+	KeepWeekly  int `json:"keep_weekly"`  // 保留最近 N 周中每周最新的一份
+	KeepMonthly int `json:"keep_monthly"` // 保留最近 N 个月中每月最新的一份
+}
+
+// BackupDestination 定义异地备份设置，备份完成后上传到 S3 兼容存储或本地挂载目录
+type BackupDestination struct {
+	Type       string          `json:"type"`        // 异地存储类型: "" 不启用 / "s3" / "local"
+	LocalPath  string          `json:"local_path"`  // 本地挂载目录（如 NAS、外接硬盘），必须为绝对路径
+	Provider   string          `json:"provider"`    // S3 服务提供商，例如 "aws", "r2", "minio", "other"
+	Endpoint   string          `json:"endpoint"`    // S3 端点
+	AccessKey  string          `json:"access_key"`  // 访问密钥 ID
+	SecretKey  string          `json:"secret_key"`  // 秘密访问密钥
+	BucketName string          `json:"bucket_name"` // 存储桶名称
+	Region     string          `json:"region"`      // 区域
+	UseSSL     bool            `json:"use_ssl"`     // 是否使用 SSL
+	PathPrefix string          `json:"path_prefix"` // 备份存放的路径前缀，例如 "ech0-backups/"
+	Retention  BackupRetention `json:"retention"`   // 异地备份保留策略
+}
+
+// BackupEncryption 定义备份加密设置，加密后的备份为 age 格式（.zip.age），可用 age 命令行工具解密
+type BackupEncryption struct {
+	Mode       string `json:"mode"`       // 加密方式: "" 不加密 / "passphrase" 口令 / "x25519" age 公钥
+	Passphrase string `json:"passphrase"` // 加密口令，Mode 为 passphrase 时使用
+	Recipient  string `json:"recipient"`  // age 公钥（age1...），Mode 为 x25519 时使用，私钥由管理员离线保管
+}
+
+// DefaultBackupRetention 默认的备份保留策略
+func DefaultBackupRetention() BackupRetention {
+	return BackupRetention{
+		KeepLast:    3,
+		KeepDaily:   7,
+		KeepWeekly:  4,
+		KeepMonthly: 6,
+	}
+}
+
+// IsZero 判断是否未设置任何保留规则
+func (r BackupRetention) IsZero() bool {
+	return r == BackupRetention{}
 }
 
 // ImageProcessSetting 定义图片处理设置实体
@@ -101,7 +159,60 @@ type ImageProcessSetting struct {
 	LocalProcess    string `json:"local_process"`     // 本地图片处理方式: "" / "local" / "tencent_eo" / "custom"
 	LocalThumbParam string `json:"local_thumb_param"` // 本地缩略图拼参: 例如 "?w=800&q=80"
 	LocalFullParam  string `json:"local_full_param"`  // 本地大图拼参: 例如 "?q=100"
-	S3Process       string `json:"s3_process"`        // S3图片处理方式: "" / "aliyun" / "tencent" / "custom"
+	S3Process       string `json:"s3_process"`        // S3图片处理方式: "" / "aliyun" / "tencent" / "custom" / "proxy"（由 Ech0 拉取原图处理，拼参同本地图片）
 	S3ThumbParam    string `json:"s3_thumb_param"`    // S3缩略图拼参: 例如 "?x-oss-process=..."
 	S3FullParam     string `json:"s3_full_param"`     // S3大图拼参: 例如 "?x-oss-process=..."
+
+	CacheMaxBytes int64             `json:"cache_max_bytes"` // 本地图片处理结果的磁盘缓存容量上限，单位字节，0 表示默认 512 MB
+	SizePresets   []ImageSizePreset `json:"size_presets"`    // 允许的本地图片处理尺寸，为空时使用默认预设；缩略图与大图拼参中的尺寸始终允许
+}
+
+// DefaultTrashRetentionDays 回收站默认保留天数
+const DefaultTrashRetentionDays = 30
+
+// TrashSetting 定义回收站设置实体
+type TrashSetting struct {
+	RetentionDays int `json:"retention_days"` // 回收站中的 Echo 保留天数，到期后永久删除并释放媒体文件
+}
+
+// ImageSizePreset 定义本地图片处理允许的尺寸，0 表示该方向不限制
+type ImageSizePreset struct {
+	Width  int `json:"width"`  // 目标宽度
+	Height int `json:"height"` // 目标高度
+}
+
+// DefaultImageSizePresets 默认的本地图片处理尺寸预设
+func DefaultImageSizePresets() []ImageSizePreset {
+	return []ImageSizePreset{
+		{Width: 200},
+		{Width: 400},
+		{Width: 800},
+		{Width: 1200},
+		{Width: 1600},
+		{Width: 2400},
+	}
+}
+
+// AllowsSize 判断本地图片处理是否允许该尺寸，不缩放（宽高均为 0）始终允许
+// 限制尺寸可以避免通过遍历参数生成大量缓存文件
+func (s ImageProcessSetting) AllowsSize(width, height int) bool {
+	if width == 0 && height == 0 {
+		return true
+	}
+
+	presets := s.SizePresets
+	if len(presets) == 0 {
+		presets = DefaultImageSizePresets()
+	}
+	for _, param := range []string{s.LocalThumbParam, s.LocalFullParam} {
+		values, err := url.ParseQuery(strings.TrimPrefix(param, "?"))
+		if err != nil {
+			continue
+		}
+		w, _ := strconv.Atoi(values.Get("w"))
+		h, _ := strconv.Atoi(values.Get("h"))
+		presets = append(presets, ImageSizePreset{Width: w, Height: h})
+	}
+
+	return slices.Contains(presets, ImageSizePreset{Width: width, Height: height})
 }
@@ -1,5 +1,7 @@
 package model
 
+import webhookModel "github.com/lin-snow/ech0/internal/model/webhook"
+
 // SystemSettingDto 定义系统设置数据传输对象
 type SystemSettingDto struct {
 	SiteTitle       string `json:"site_title"`       // 站点标题
@@ -20,6 +22,7 @@ type CommentSettingDto struct {
 	EnableComment bool   `json:"enable_comment"` // 是否启用评论
 	Provider      string `json:"provider"`       // 评论提供者
 	CommentAPI    string `json:"comment_api"`    // 评论 API 地址
+	AutoApprove   bool   `json:"auto_approve"`   // 内置评论是否免审核直接展示
 }
 
 type S3SettingDto struct {
@@ -58,15 +61,18 @@ type OAuth2Status struct {
 }
 
 type WebhookDto struct {
-	Name     string `json:"name"`                                 // Webhook 名称
-	URL      string `json:"url"`                                  // Webhook URL
-	Secret   string `json:"secret,omitempty"`                     // 签名密钥，用于请求验证（HMAC等）
-	IsActive bool   `json:"is_active"        gorm:"default:true"` // 启用/禁用状态
+	Name       string                     `json:"name"`                                 // Webhook 名称
+	URL        string                     `json:"url"`                                  // Webhook URL
+	Secret     string                     `json:"secret,omitempty"`                     // 签名密钥，用于请求验证（HMAC等）
+	IsActive   bool                       `json:"is_active"        gorm:"default:true"` // 启用/禁用状态
+	EventTypes []string                   `json:"event_types"`                          // 订阅的事件类型，为空表示订阅全部事件
+	Filter     webhookModel.WebhookFilter `json:"filter"`                               // Echo 负载过滤条件
 }
 
 type AccessTokenSettingDto struct {
-	Name   string `json:"name"`   // 访问令牌名称
-	Expiry string `json:"expiry"` // 访问令牌过期策略（8_hours/1_month/never）
+	Name   string   `json:"name"`   // 访问令牌名称
+	Expiry string   `json:"expiry"` // 访问令牌过期策略（8_hours/1_month/never）
+	Scopes []string `json:"scopes"` // 权限范围（echo:read/echo:write/media:upload/settings:admin/backup）
 }
 
 type FediverseSettingDto struct {
@@ -75,8 +81,29 @@ type FediverseSettingDto struct {
 }
 
 type BackupScheduleDto struct {
-	Enable         bool   `json:"enable"`          // 是否启用备份计划
-	CronExpression string `json:"cron_expression"` // 备份计划的 Cron 表达式
+	Enable         bool             `json:"enable"`          // 是否启用备份计划
+	CronExpression string           `json:"cron_expression"` // 备份计划的 Cron 表达式
+	Retention      *BackupRetention `json:"retention"`       // 备份保留策略，为空时沿用当前设置
+}
+
+type BackupDestinationDto struct {
+	Type       string          `json:"type"`        // 异地存储类型: "" 不启用 / "s3" / "local"
+	LocalPath  string          `json:"local_path"`  // 本地挂载目录，必须为绝对路径
+	Provider   string          `json:"provider"`    // S3 服务提供商
+	Endpoint   string          `json:"endpoint"`    // S3 端点
+	AccessKey  string          `json:"access_key"`  // 访问密钥 ID
+	SecretKey  string          `json:"secret_key"`  // 秘密访问密钥
+	BucketName string          `json:"bucket_name"` // 存储桶名称
+	Region     string          `json:"region"`      // 区域
+	UseSSL     bool            `json:"use_ssl"`     // 是否使用 SSL
+	PathPrefix string          `json:"path_prefix"` // 备份存放的路径前缀
+	Retention  BackupRetention `json:"retention"`   // 异地备份保留策略，全部为 0 时使用默认策略
+}
+
+type BackupEncryptionDto struct {
+	Mode       string `json:"mode"`       // 加密方式: "" 不加密 / "passphrase" / "x25519"
+	Passphrase string `json:"passphrase"` // 加密口令
+	Recipient  string `json:"recipient"`  // age 公钥（age1...）
 }
 
 type AgentSettingDto struct {
@@ -96,4 +123,12 @@ type ImageProcessSettingDto struct {
 	S3Process       string `json:"s3_process"`
 	S3ThumbParam    string `json:"s3_thumb_param"`
 	S3FullParam     string `json:"s3_full_param"`
+
+	CacheMaxBytes int64             `json:"cache_max_bytes"`
+	SizePresets   []ImageSizePreset `json:"size_presets"`
+}
+
+// TrashSettingDto 回收站设置 DTO
+type TrashSettingDto struct {
+	RetentionDays int `json:"retention_days"`
 }
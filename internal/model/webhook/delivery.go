@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+const (
+	// DeliveryStatusPending 等待投递
+	DeliveryStatusPending = "pending"
+	// DeliveryStatusSuccess 投递成功
+	DeliveryStatusSuccess = "success"
+	// DeliveryStatusFailed 投递失败
+	DeliveryStatusFailed = "failed"
+
+	// MaxDeliveryResponseBodySize 投递记录中保存的响应体最大长度（字节）
+	MaxDeliveryResponseBodySize = 2048
+
+	// DeliveryRetention 投递记录保留时长
+	DeliveryRetention = 30 * 24 * time.Hour
+)
+
+// WebhookDelivery 定义 Webhook 的单次投递记录
+type WebhookDelivery struct {
+	ID           uint      `gorm:"primaryKey"              json:"id"`                      // 记录 ID
+	WebhookID    uint      `gorm:"index;not null"          json:"webhook_id"`              // 所属 Webhook ID
+	EventID      string    `gorm:"type:varchar(64);index"  json:"event_id"`                // 事件 ID
+	EventType    string    `gorm:"type:varchar(100)"       json:"event_type"`              // 事件类型
+	Attempt      int       `gorm:"default:1"               json:"attempt"`                 // 第几次尝试
+	Status       string    `gorm:"type:varchar(20)"        json:"status"`                  // 投递状态（pending / success / failed）
+	StatusCode   int       `gorm:"default:0"               json:"status_code"`             // HTTP 状态码，未收到响应时为 0
+	LatencyMs    int64     `gorm:"default:0"               json:"latency_ms"`              // 请求耗时（毫秒）
+	ResponseBody string    `gorm:"type:text"               json:"response_body,omitempty"` // 截断后的响应体
+	Error        string    `gorm:"type:text"               json:"error,omitempty"`         // 错误信息
+	Payload      []byte    `                               json:"-"`                       // 事件原文（JSON），用于重新投递
+	CreatedAt    time.Time `gorm:"index"                   json:"created_at"`              // 投递时间
+}
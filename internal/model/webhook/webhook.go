@@ -1,16 +1,51 @@
 package model
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Webhook 定义 Webhook 设置实体
 type Webhook struct {
-	ID          uint      `gorm:"primaryKey"   json:"id"`           // Webhook ID
-	Name        string    `                    json:"name"`         // Webhook 名称
-	URL         string    `                    json:"url"`          // Webhook URL
-	Secret      string    `                    json:"secret"`       // 签名密钥，用于请求验证（HMAC等）
-	IsActive    bool      `gorm:"default:true" json:"is_active"`    // 启用/禁用状态
-	LastStatus  string    `                    json:"last_status"`  // 最近调用状态（如 success, failed）
-	LastTrigger time.Time `                    json:"last_trigger"` // 最近触发时间
-	CreatedAt   time.Time `                    json:"created_at"`   // 创建时间
-	UpdatedAt   time.Time `                    json:"updated_at"`   // 更新时间
+	ID          uint          `gorm:"primaryKey"                json:"id"`           // Webhook ID
+	Name        string        `                                 json:"name"`         // Webhook 名称
+	URL         string        `                                 json:"url"`          // Webhook URL
+	Secret      string        `                                 json:"secret"`       // 签名密钥，用于请求验证（HMAC等）
+	IsActive    bool          `gorm:"default:true"              json:"is_active"`    // 启用/禁用状态
+	EventTypes  []string      `gorm:"serializer:json;type:text" json:"event_types"`  // 订阅的事件类型，为空表示订阅全部事件
+	Filter      WebhookFilter `gorm:"serializer:json;type:text" json:"filter"`       // Echo 负载过滤条件
+	LastStatus  string        `                                 json:"last_status"`  // 最近调用状态（如 success, failed）
+	LastTrigger time.Time     `                                 json:"last_trigger"` // 最近触发时间
+	CreatedAt   time.Time     `                                 json:"created_at"`   // 创建时间
+	UpdatedAt   time.Time     `                                 json:"updated_at"`   // 更新时间
+}
+
+// WebhookFilter 定义 Webhook 的 Echo 负载过滤条件（仅对携带 Echo 的事件生效）
+type WebhookFilter struct {
+	PublicOnly bool     `json:"public_only"`    // 仅推送公开的 Echo
+	Tags       []string `json:"tags,omitempty"` // 仅推送包含任一指定标签的 Echo，为空表示不限制
+}
+
+// ShouldHandle 判断 webhook 是否订阅了指定的事件类型
+func (wh *Webhook) ShouldHandle(eventType string) bool {
+	if len(wh.EventTypes) == 0 {
+		return true
+	}
+	return slices.Contains(wh.EventTypes, eventType)
+}
+
+// MatchEcho 判断 Echo 的可见性与标签是否满足过滤条件
+func (f *WebhookFilter) MatchEcho(private bool, tags []string) bool {
+	if f.PublicOnly && private {
+		return false
+	}
+	if len(f.Tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if slices.Contains(f.Tags, tag) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	model "github.com/lin-snow/ech0/internal/model/comment"
+	"github.com/lin-snow/ech0/internal/transaction"
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db func() *gorm.DB
+}
+
+func NewCommentRepository(dbProvider func() *gorm.DB) CommentRepositoryInterface {
+	return &CommentRepository{
+		db: dbProvider,
+	}
+}
+
+// getDB 从上下文中获取事务
+func (commentRepository *CommentRepository) getDB(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(transaction.TxKey).(*gorm.DB); ok {
+		return tx
+	}
+	return commentRepository.db()
+}
+
+// CreateComment 创建评论
+func (commentRepository *CommentRepository) CreateComment(ctx context.Context, comment *model.Comment) error {
+	return commentRepository.getDB(ctx).Create(comment).Error
+}
+
+// GetCommentByID 根据ID获取评论，不存在时返回 nil
+func (commentRepository *CommentRepository) GetCommentByID(ctx context.Context, id uint) (*model.Comment, error) {
+	var comment model.Comment
+	if err := commentRepository.getDB(ctx).First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// GetCommentByRemoteID 根据远端 Object ID 获取评论，不存在时返回 nil
+func (commentRepository *CommentRepository) GetCommentByRemoteID(
+	ctx context.Context,
+	remoteID string,
+) (*model.Comment, error) {
+	var comment model.Comment
+	if err := commentRepository.getDB(ctx).
+		Where("remote_id = ?", remoteID).
+		First(&comment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// GetApprovedCommentsByEchoID 获取 Echo 下所有已通过审核的评论，按时间正序
+func (commentRepository *CommentRepository) GetApprovedCommentsByEchoID(echoID uint) ([]model.Comment, error) {
+	var comments []model.Comment
+	if err := commentRepository.db().
+		Where("echo_id = ? AND status = ?", echoID, model.CommentStatusApproved).
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// GetComments 按条件分页获取评论
+func (commentRepository *CommentRepository) GetComments(dto model.CommentQueryDto) ([]model.Comment, int64, error) {
+	query := commentRepository.db().Model(&model.Comment{})
+	if dto.Status != "" {
+		query = query.Where("status = ?", dto.Status)
+	}
+	if dto.EchoID != 0 {
+		query = query.Where("echo_id = ?", dto.EchoID)
+	}
+	if dto.Source != "" {
+		query = query.Where("source = ?", dto.Source)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var comments []model.Comment
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Limit(dto.PageSize).
+		Offset((dto.Page - 1) * dto.PageSize).
+		Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// UpdateCommentStatus 更新评论的审核状态
+func (commentRepository *CommentRepository) UpdateCommentStatus(ctx context.Context, id uint, status string) error {
+	return commentRepository.getDB(ctx).
+		Model(&model.Comment{}).
+		Where("id = ?", id).
+		Update("status", status).Error
+}
+
+// UpdateRemoteComment 更新远端回复的内容与审核状态
+func (commentRepository *CommentRepository) UpdateRemoteComment(ctx context.Context, comment *model.Comment) error {
+	return commentRepository.getDB(ctx).
+		Model(&model.Comment{}).
+		Where("id = ?", comment.ID).
+		Updates(map[string]any{
+			"author_name":   comment.AuthorName,
+			"author_url":    comment.AuthorURL,
+			"author_avatar": comment.AuthorAvatar,
+			"content":       comment.Content,
+			"status":        comment.Status,
+		}).Error
+}
+
+// DeleteCommentTree 删除评论及其全部子回复，返回删除数量
+func (commentRepository *CommentRepository) DeleteCommentTree(ctx context.Context, id uint) (int64, error) {
+	db := commentRepository.getDB(ctx)
+
+	// 逐层收集子回复的 ID
+	ids := []uint{id}
+	parents := []uint{id}
+	for len(parents) > 0 {
+		var children []uint
+		if err := db.Model(&model.Comment{}).
+			Where("parent_id IN ?", parents).
+			Pluck("id", &children).Error; err != nil {
+			return 0, err
+		}
+		ids = append(ids, children...)
+		parents = children
+	}
+
+	result := db.Where("id IN ?", ids).Delete(&model.Comment{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteCommentsByActor 删除远端 Actor 的全部回复
+func (commentRepository *CommentRepository) DeleteCommentsByActor(ctx context.Context, actorURL string) error {
+	return commentRepository.getDB(ctx).
+		Where("source = ? AND actor_url = ?", model.CommentSourceFediverse, actorURL).
+		Delete(&model.Comment{}).Error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	model "github.com/lin-snow/ech0/internal/model/comment"
+)
+
+type CommentRepositoryInterface interface {
+	// CreateComment 创建评论
+	CreateComment(ctx context.Context, comment *model.Comment) error
+
+	// GetCommentByID 根据ID获取评论，不存在时返回 nil
+	GetCommentByID(ctx context.Context, id uint) (*model.Comment, error)
+
+	// GetCommentByRemoteID 根据远端 Object ID 获取评论，不存在时返回 nil
+	GetCommentByRemoteID(ctx context.Context, remoteID string) (*model.Comment, error)
+
+	// GetApprovedCommentsByEchoID 获取 Echo 下所有已通过审核的评论，按时间正序
+	GetApprovedCommentsByEchoID(echoID uint) ([]model.Comment, error)
+
+	// GetComments 按条件分页获取评论
+	GetComments(dto model.CommentQueryDto) ([]model.Comment, int64, error)
+
+	// UpdateCommentStatus 更新评论的审核状态
+	UpdateCommentStatus(ctx context.Context, id uint, status string) error
+
+	// UpdateRemoteComment 更新远端回复的内容与审核状态
+	UpdateRemoteComment(ctx context.Context, comment *model.Comment) error
+
+	// DeleteCommentTree 删除评论及其全部子回复，返回删除数量
+	DeleteCommentTree(ctx context.Context, id uint) (int64, error)
+
+	// DeleteCommentsByActor 删除远端 Actor 的全部回复
+	DeleteCommentsByActor(ctx context.Context, actorURL string) error
+}
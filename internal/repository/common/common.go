@@ -64,13 +64,14 @@ func (commonRepository *CommonRepository) GetAllUsers() ([]userModel.User, error
 func (commonRepository *CommonRepository) GetAllEchos(showPrivate bool) ([]echoModel.Echo, error) {
 	var echos []echoModel.Echo
 
-	// 是否将私密内容也查询出来
+	// 只查询已发布的Echo，是否将私密内容也查询出来
+	query := commonRepository.db().Where("echos.status = ?", echoModel.EchoStatusPublished)
 	if showPrivate {
-		if err := commonRepository.db().Preload("Media").Preload("Tags").Joins("User").Order("created_at DESC").Find(&echos).Error; err != nil {
+		if err := query.Preload("Media").Preload("Tags").Joins("User").Order("created_at DESC").Find(&echos).Error; err != nil {
 			return nil, err
 		}
 	} else {
-		if err := commonRepository.db().Preload("Media").Preload("Tags").Joins("User").Where("private = ?", false).Find(&echos).Error; err != nil {
+		if err := query.Preload("Media").Preload("Tags").Joins("User").Where("private = ?", false).Find(&echos).Error; err != nil {
 			return nil, err
 		}
 	}
@@ -86,6 +87,7 @@ func (commonRepository *CommonRepository) GetHeatMap(
 
 	err := commonRepository.db().
 		Table("echos").
+		Where("status = ? AND deleted_at IS NULL", echoModel.EchoStatusPublished).
 		Where("created_at >= ? AND created_at < ?", startUTC, endUTC).
 		Order("created_at ASC").
 		Pluck("created_at", &results).Error
@@ -78,11 +78,11 @@ func (echoRepository *EchoRepository) GetEchosByPage(
 
 	query := echoRepository.db().Model(&model.Echo{})
 
-	// 如果 search 不为空，添加模糊查询条件
-	if search != "" {
-		searchPattern := "%" + search + "%" // 模糊匹配模式
-		query = query.Where("content LIKE ?", searchPattern)
-	}
+	// 只展示已发布的Echo，草稿与定时发布的Echo不出现在列表中
+	query = query.Where("echos.status = ?", model.EchoStatusPublished)
+
+	// 如果 search 不为空，添加关键字查询条件（优先使用全文索引）
+	query = applyContentSearch(query, search)
 
 	// 如果不是管理员，过滤私密Echo
 	if !showPrivate {
@@ -138,23 +138,9 @@ func (echoRepository *EchoRepository) GetEchosById(id uint) (*model.Echo, error)
 	return &echo, nil
 }
 
-// DeleteEchoById 删除 Echo
+// DeleteEchoById 将 Echo 移入回收站（软删除），媒体与历史版本保留到永久删除时再清理
 func (echoRepository *EchoRepository) DeleteEchoById(ctx context.Context, id uint) error {
-	var echo model.Echo
-
-	// 先获取要删除的 media IDs，用于同步删除 images 表
-	var mediaIDs []uint
-	echoRepository.getDB(ctx).Model(&model.Media{}).Where("message_id = ?", id).Pluck("id", &mediaIDs)
-
-	// 删除外键 media
-	echoRepository.getDB(ctx).Where("message_id = ?", id).Delete(&model.Media{})
-
-	// 同步删除 images 表中的对应记录（防止启动时复活）
-	if len(mediaIDs) > 0 && echoRepository.db().Migrator().HasTable("images") {
-		echoRepository.getDB(ctx).Exec("DELETE FROM images WHERE id IN ?", mediaIDs)
-	}
-
-	result := echoRepository.getDB(ctx).Delete(&echo, id)
+	result := echoRepository.getDB(ctx).Delete(&model.Echo{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -196,6 +182,8 @@ func (echoRepository *EchoRepository) GetTodayEchos(showPrivate bool, timezone s
 	endOfDayUTC := endOfDayUser.UTC()
 
 	query := echoRepository.db().Model(&model.Echo{})
+	// 只展示已发布的Echo
+	query = query.Where("echos.status = ?", model.EchoStatusPublished)
 	// 如果不是管理员，过滤私密Echo
 	if !showPrivate {
 		query = query.Where("private = ?", false)
@@ -469,32 +457,25 @@ func (echoRepository *EchoRepository) UpdateEcho(ctx context.Context, echo *mode
 	return nil
 }
 
-// LikeEcho 点赞 Echo
-func (echoRepository *EchoRepository) LikeEcho(ctx context.Context, id uint) error {
-	// 检查是否存在（可选，防止无效点赞）
-	var exists bool
-	if err := echoRepository.getDB(ctx).
-		Model(&model.Echo{}).
-		Select("count(*) > 0").
-		Where("id = ?", id).
-		Find(&exists).Error; err != nil {
-		return err
-	}
-	if !exists {
-		return errors.New(commonModel.ECHO_NOT_FOUND)
-	}
-
-	// 原子自增点赞数
+// UpdateFediverseCounts 更新 Echo 的联邦宇宙互动计数
+func (echoRepository *EchoRepository) UpdateFediverseCounts(
+	ctx context.Context,
+	id uint,
+	likes, boosts int64,
+) error {
 	if err := echoRepository.getDB(ctx).
 		Model(&model.Echo{}).
 		Where("id = ?", id).
-		UpdateColumn("fav_count", gorm.Expr("fav_count + ?", 1)).Error; err != nil {
+		UpdateColumns(map[string]any{
+			"fedi_likes":  likes,
+			"fedi_boosts": boosts,
+		}).Error; err != nil {
 		return err
 	}
 
 	// 清除相关缓存
 	ClearEchoPageCache(echoRepository.cache)
-	echoRepository.cache.Delete(GetEchoByIDCacheKey(id))      // 删除具体 Echo 的缓存
+	echoRepository.cache.Delete(GetEchoByIDCacheKey(id))
 	ClearTodayEchosCache(echoRepository.cache)
 
 	return nil
@@ -593,15 +574,14 @@ func (echoRepository *EchoRepository) GetEchosByTagId(
 
 	applyFilters := func(db *gorm.DB) *gorm.DB {
 		db = db.Joins("JOIN echo_tags ON echo_tags.echo_id = echos.id").
-			Where("echo_tags.tag_id = ?", tagId)
+			Where("echo_tags.tag_id = ?", tagId).
+			Where("echos.status = ?", model.EchoStatusPublished)
 
 		if !showPrivate {
 			db = db.Where("echos.private = ?", false)
 		}
 
-		if search != "" {
-			db = db.Where("echos.content LIKE ?", "%"+search+"%")
-		}
+		db = applyContentSearch(db, search)
 
 		return db
 	}
@@ -656,15 +636,14 @@ func (echoRepository *EchoRepository) GetEchosByDate(
 
 	applyFilters := func(db *gorm.DB) *gorm.DB {
 		// 使用 localtime 转换确保时区一致性
-		db = db.Where("DATE(echos.created_at, 'localtime') >= ? AND DATE(echos.created_at, 'localtime') <= ?", startDate, endDate)
+		db = db.Where("DATE(echos.created_at, 'localtime') >= ? AND DATE(echos.created_at, 'localtime') <= ?", startDate, endDate).
+			Where("echos.status = ?", model.EchoStatusPublished)
 
 		if !showPrivate {
 			db = db.Where("echos.private = ?", false)
 		}
 
-		if search != "" {
-			db = db.Where("echos.content LIKE ?", "%"+search+"%")
-		}
+		db = applyContentSearch(db, search)
 
 		return db
 	}
@@ -692,6 +671,149 @@ func (echoRepository *EchoRepository) GetEchosByDate(
 	return echos, total, nil
 }
 
+// GetAllEchos 获取全部 Echo（含媒体与标签），按创建时间升序排列
+func (echoRepository *EchoRepository) GetAllEchos() ([]model.Echo, error) {
+	var echos []model.Echo
+	if err := echoRepository.db().
+		Preload("Media").
+		Preload("Tags").
+		Order("created_at ASC, id ASC").
+		Find(&echos).Error; err != nil {
+		return nil, err
+	}
+
+	return echos, nil
+}
+
+// GetUnpublishedEchos 获取全部草稿与定时发布的 Echo，定时发布的按发布时间升序排在前面
+func (echoRepository *EchoRepository) GetUnpublishedEchos() ([]model.Echo, error) {
+	var echos []model.Echo
+	if err := echoRepository.db().
+		Where("echos.status IN ?", []string{model.EchoStatusDraft, model.EchoStatusScheduled}).
+		Preload("Media").
+		Preload("Tags").
+		Joins("User").
+		Order("echos.status DESC, echos.publish_at ASC, echos.id DESC").
+		Find(&echos).Error; err != nil {
+		return nil, err
+	}
+
+	return echos, nil
+}
+
+// GetDueScheduledEchos 获取发布时间已到的定时 Echo
+func (echoRepository *EchoRepository) GetDueScheduledEchos(now time.Time) ([]model.Echo, error) {
+	var echos []model.Echo
+	if err := echoRepository.db().
+		Where("status = ? AND publish_at <= ?", model.EchoStatusScheduled, now).
+		Order("publish_at ASC, id ASC").
+		Find(&echos).Error; err != nil {
+		return nil, err
+	}
+
+	return echos, nil
+}
+
+// UpdateEchoStatus 更新草稿或定时 Echo 的发布状态与定时发布时间
+func (echoRepository *EchoRepository) UpdateEchoStatus(
+	ctx context.Context,
+	id uint,
+	status string,
+	publishAt *time.Time,
+) error {
+	if err := echoRepository.getDB(ctx).Model(&model.Echo{}).
+		Where("id = ? AND status <> ?", id, model.EchoStatusPublished).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"publish_at": publishAt,
+		}).Error; err != nil {
+		return err
+	}
+
+	echoRepository.cache.Delete(GetEchoByIDCacheKey(id))
+
+	return nil
+}
+
+// PublishEcho 将草稿或定时 Echo 标记为已发布，并以发布时间作为创建时间
+// 返回 false 表示 Echo 不存在或已经发布，用于避免重复发布
+func (echoRepository *EchoRepository) PublishEcho(
+	ctx context.Context,
+	id uint,
+	publishedAt time.Time,
+) (bool, error) {
+	result := echoRepository.getDB(ctx).Model(&model.Echo{}).
+		Where("id = ? AND status <> ?", id, model.EchoStatusPublished).
+		Updates(map[string]interface{}{
+			"status":     model.EchoStatusPublished,
+			"publish_at": nil,
+			"created_at": publishedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	// 清除相关缓存
+	echoRepository.cache.Delete(GetEchoByIDCacheKey(id))
+	ClearTodayEchosCache(echoRepository.cache)
+	ClearEchoPageCache(echoRepository.cache)
+
+	return true, nil
+}
+
+// CreateEchoRevision 保存 Echo 的历史版本，超出上限时删除最旧的版本
+func (echoRepository *EchoRepository) CreateEchoRevision(ctx context.Context, revision *model.EchoRevision) error {
+	if err := echoRepository.getDB(ctx).Create(revision).Error; err != nil {
+		return err
+	}
+
+	// 只保留最近的 MaxEchoRevisions 个版本
+	var staleIDs []uint
+	if err := echoRepository.getDB(ctx).Model(&model.EchoRevision{}).
+		Where("echo_id = ?", revision.EchoID).
+		Order("id DESC").
+		Offset(model.MaxEchoRevisions).
+		Pluck("id", &staleIDs).Error; err != nil {
+		return err
+	}
+	if len(staleIDs) > 0 {
+		return echoRepository.getDB(ctx).Delete(&model.EchoRevision{}, staleIDs).Error
+	}
+
+	return nil
+}
+
+// GetEchoRevisions 获取 Echo 的全部历史版本，按时间倒序排列
+func (echoRepository *EchoRepository) GetEchoRevisions(echoID uint) ([]model.EchoRevision, error) {
+	var revisions []model.EchoRevision
+	if err := echoRepository.db().
+		Where("echo_id = ?", echoID).
+		Order("id DESC").
+		Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetEchoRevisionByID 获取 Echo 的指定历史版本，不存在时返回 nil
+func (echoRepository *EchoRepository) GetEchoRevisionByID(echoID, revisionID uint) (*model.EchoRevision, error) {
+	var revision model.EchoRevision
+	result := echoRepository.db().
+		Where("echo_id = ?", echoID).
+		First(&revision, revisionID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &revision, nil
+}
+
 // UpdateMediaLiveVideoID 更新媒体的实况照片关联
 func (echoRepository *EchoRepository) UpdateMediaLiveVideoID(ctx context.Context, mediaID uint, liveVideoID uint) error {
 	return echoRepository.getDB(ctx).Model(&model.Media{}).
@@ -712,6 +834,56 @@ func (echoRepository *EchoRepository) GetMediaByID(id uint) (*model.Media, error
 	return &media, nil
 }
 
+// GetMediaByObjectKey 根据对象存储的 Key 获取媒体
+func (echoRepository *EchoRepository) GetMediaByObjectKey(objectKey string) (*model.Media, error) {
+	var media model.Media
+	result := echoRepository.db().
+		Where("media_source = ? AND object_key = ?", model.MediaSourceS3, objectKey).
+		First(&media)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &media, nil
+}
+
+// UpdateMediaVariants 为引用该本地图片的全部媒体回填响应式尺寸与占位信息
+func (echoRepository *EchoRepository) UpdateMediaVariants(
+	ctx context.Context,
+	mediaURL, blurhash, dominantColor string,
+	variants []model.MediaVariant,
+) error {
+	var echoIDs []uint
+	query := echoRepository.getDB(ctx).Model(&model.Media{}).
+		Where("media_source = ? AND media_type = ? AND media_url = ?", model.MediaSourceLocal, model.MediaTypeImage, mediaURL).
+		Session(&gorm.Session{})
+	if err := query.Distinct().Pluck("message_id", &echoIDs).Error; err != nil {
+		return err
+	}
+	if len(echoIDs) == 0 {
+		return nil
+	}
+
+	if err := query.Updates(&model.Media{
+		Blurhash:      blurhash,
+		DominantColor: dominantColor,
+		Variants:      variants,
+	}).Error; err != nil {
+		return err
+	}
+
+	// 清除缓存
+	for _, id := range echoIDs {
+		echoRepository.cache.Delete(GetEchoByIDCacheKey(id))
+	}
+	ClearTodayEchosCache(echoRepository.cache)
+	ClearEchoPageCache(echoRepository.cache)
+
+	return nil
+}
+
 // IsLivePhotoVideo 检查视频是否是实况照片的一部分
 func (echoRepository *EchoRepository) IsLivePhotoVideo(videoID uint) (bool, error) {
 	var count int64
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	model "github.com/lin-snow/ech0/internal/model/echo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// matchLikeIdentity 匹配同一身份的点赞记录
+// 未携带 Cookie 或 Cookie 签发不久的访客额外按指纹匹配，避免通过丢弃或换新 Cookie 重复点赞；
+// 持有较早 Cookie 的访客只按 Cookie 匹配，避免同一网络下的访客互相影响
+func matchLikeIdentity(db *gorm.DB, identity model.LikeIdentity) *gorm.DB {
+	if !identity.MatchFingerprint || identity.Fingerprint == "" {
+		return db.Where("identity = ?", identity.Identity)
+	}
+	return db.Where("identity = ? OR (user_id = 0 AND fingerprint = ?)", identity.Identity, identity.Fingerprint)
+}
+
+// HasEchoLike 判断该身份是否已为 Echo 点赞
+func (echoRepository *EchoRepository) HasEchoLike(
+	ctx context.Context,
+	echoID uint,
+	identity model.LikeIdentity,
+) (bool, error) {
+	var count int64
+	query := echoRepository.getDB(ctx).Model(&model.EchoLike{}).Where("echo_id = ?", echoID)
+	if err := matchLikeIdentity(query, identity).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// CreateEchoLike 保存点赞记录，该身份已点赞时返回 false
+func (echoRepository *EchoRepository) CreateEchoLike(ctx context.Context, like *model.EchoLike) (bool, error) {
+	result := echoRepository.getDB(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(like)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// CreateLegacyEchoLikes 为 Echo 补齐无法追溯来源的点赞记录，使点赞数与记录数一致
+func (echoRepository *EchoRepository) CreateLegacyEchoLikes(ctx context.Context, echoID uint, count int) error {
+	if count <= 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	likes := make([]model.EchoLike, 0, count)
+	for i := 1; i <= count; i++ {
+		likes = append(likes, model.EchoLike{
+			EchoID:    echoID,
+			Identity:  fmt.Sprintf("%s:%d", model.LikeKindLegacy, i),
+			Kind:      model.LikeKindLegacy,
+			CreatedAt: now,
+		})
+	}
+
+	return echoRepository.getDB(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(likes, 500).Error
+}
+
+// DeleteEchoLike 删除该身份对 Echo 的点赞记录，未点赞时返回 false
+func (echoRepository *EchoRepository) DeleteEchoLike(
+	ctx context.Context,
+	echoID uint,
+	identity model.LikeIdentity,
+) (bool, error) {
+	query := echoRepository.getDB(ctx).Where("echo_id = ?", echoID)
+	result := matchLikeIdentity(query, identity).Delete(&model.EchoLike{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// RefreshEchoFavCount 按点赞记录重新计算 Echo 的点赞数
+func (echoRepository *EchoRepository) RefreshEchoFavCount(ctx context.Context, echoID uint) (int, error) {
+	db := echoRepository.getDB(ctx)
+
+	var count int64
+	if err := db.Model(&model.EchoLike{}).Where("echo_id = ?", echoID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	// 回收站中的 Echo 也需要更新
+	if err := db.Unscoped().
+		Model(&model.Echo{}).
+		Where("id = ?", echoID).
+		UpdateColumn("fav_count", count).Error; err != nil {
+		return 0, err
+	}
+
+	// 清除相关缓存
+	ClearEchoPageCache(echoRepository.cache)
+	echoRepository.cache.Delete(GetEchoByIDCacheKey(echoID)) // 删除具体 Echo 的缓存
+	ClearTodayEchosCache(echoRepository.cache)
+
+	return int(count), nil
+}
+
+// GetEchoLikes 分页获取点赞记录，按时间倒序排列
+func (echoRepository *EchoRepository) GetEchoLikes(dto model.EchoLikeQueryDto) ([]model.EchoLike, int64, error) {
+	query := echoRepository.db().Model(&model.EchoLike{})
+	if dto.EchoID != 0 {
+		query = query.Where("echo_id = ?", dto.EchoID)
+	}
+	if dto.IP != "" {
+		query = query.Where("ip = ?", dto.IP)
+	}
+	if dto.Kind != "" {
+		query = query.Where("kind = ?", dto.Kind)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var likes []model.EchoLike
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Limit(dto.PageSize).
+		Offset((dto.Page - 1) * dto.PageSize).
+		Find(&likes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return likes, total, nil
+}
+
+// DeleteEchoLikes 按条件批量删除点赞记录，返回删除数量与受影响的 Echo ID
+func (echoRepository *EchoRepository) DeleteEchoLikes(
+	ctx context.Context,
+	dto model.EchoLikeCleanupDto,
+) (int64, []uint, error) {
+	if dto.IsEmpty() {
+		return 0, nil, errors.New(commonModel.ECHO_LIKE_CLEANUP_EMPTY)
+	}
+
+	query := echoRepository.getDB(ctx).Model(&model.EchoLike{})
+	if dto.EchoID != 0 {
+		query = query.Where("echo_id = ?", dto.EchoID)
+	}
+	if dto.IP != "" {
+		query = query.Where("ip = ?", dto.IP)
+	}
+	if dto.Kind != "" {
+		query = query.Where("kind = ?", dto.Kind)
+	}
+	if dto.Since != nil {
+		query = query.Where("created_at >= ?", *dto.Since)
+	}
+	if dto.Until != nil {
+		query = query.Where("created_at <= ?", *dto.Until)
+	}
+
+	var echoIDs []uint
+	if err := query.Session(&gorm.Session{}).Distinct("echo_id").Pluck("echo_id", &echoIDs).Error; err != nil {
+		return 0, nil, err
+	}
+
+	result := query.Delete(&model.EchoLike{})
+	if result.Error != nil {
+		return 0, nil, result.Error
+	}
+
+	return result.RowsAffected, echoIDs, nil
+}
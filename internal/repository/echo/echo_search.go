@@ -0,0 +1,404 @@
+package repository
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lin-snow/ech0/internal/database"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	model "github.com/lin-snow/ech0/internal/model/echo"
+	"gorm.io/gorm"
+)
+
+const (
+	ftsMinTermLength  = 3  // trigram 分词器要求每个词至少 3 个字符
+	ftsSnippetTokens  = 32 // FTS5 片段长度（trigram 下约等于字符数）
+	likeSnippetRadius = 30 // LIKE 退化查询时片段两侧保留的字符数
+
+	snippetMarkOpen  = "<mark>"
+	snippetMarkClose = "</mark>"
+	snippetEllipsis  = "…"
+
+	// 片段先用私有区字符标记命中位置，转义 HTML 后再替换为 <mark>，避免内容中的标签被渲染
+	snippetSentinelOpen  = "\uE000"
+	snippetSentinelClose = "\uE001"
+)
+
+// echoTermMatchSQL 单个词在内容、用户名或标签名中出现
+const echoTermMatchSQL = `(echos.content LIKE ? ESCAPE '\' OR echos.username LIKE ? ESCAPE '\' OR EXISTS (
+	SELECT 1 FROM echo_tags et JOIN tags t ON t.id = et.tag_id
+	WHERE et.echo_id = echos.id AND t.name LIKE ? ESCAPE '\'))`
+
+// searchTerm 搜索语句中的单个词
+type searchTerm struct {
+	text   string
+	negate bool
+}
+
+// applyContentSearch 为列表查询添加关键字过滤，全文索引可用时使用 FTS5
+func applyContentSearch(db *gorm.DB, search string) *gorm.DB {
+	if search == "" {
+		return db
+	}
+
+	if database.IsEchoSearchAvailable() && utf8.RuneCountInString(search) >= ftsMinTermLength {
+		// 整体作为短语查询，与原先的子串匹配语义一致
+		phrase := `"` + strings.ReplaceAll(search, `"`, `""`) + `"`
+		return db.Where(
+			"echos.id IN (SELECT rowid FROM "+database.EchoSearchTable+" WHERE "+
+				database.EchoSearchTable+" MATCH ?)",
+			phrase,
+		)
+	}
+
+	return db.Where(`echos.content LIKE ? ESCAPE '\'`, "%"+escapeLike(search)+"%")
+}
+
+// escapeLike 转义 LIKE 中的通配符，配合 ESCAPE '\' 使用
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// SearchEchos 全文搜索 Echo，返回按相关度排序的结果与高亮片段
+func (echoRepository *EchoRepository) SearchEchos(
+	dto model.EchoSearchDto,
+	showPrivate bool,
+) ([]model.EchoSearchResult, int64, error) {
+	query := strings.TrimSpace(dto.Query)
+	groups := parseSearchQuery(query)
+	useFTS := query != "" && canUseFTS(groups)
+
+	applyFilters := func(db *gorm.DB) *gorm.DB {
+		db = db.Table("echos").
+			Where("echos.status = ?", model.EchoStatusPublished).
+			Where("echos.deleted_at IS NULL")
+
+		if !showPrivate {
+			db = db.Where("echos.private = ?", false)
+		}
+		if dto.Tag != "" {
+			db = db.Where(`EXISTS (SELECT 1 FROM echo_tags et JOIN tags t ON t.id = et.tag_id
+				WHERE et.echo_id = echos.id AND t.name = ?)`, dto.Tag)
+		}
+		if dto.StartDate != "" {
+			db = db.Where("DATE(echos.created_at, 'localtime') >= ?", dto.StartDate)
+		}
+		if dto.EndDate != "" {
+			db = db.Where("DATE(echos.created_at, 'localtime') <= ?", dto.EndDate)
+		}
+		if dto.HasMedia != nil {
+			mediaExists := "EXISTS (SELECT 1 FROM media m WHERE m.message_id = echos.id)"
+			if !*dto.HasMedia {
+				mediaExists = "NOT " + mediaExists
+			}
+			db = db.Where(mediaExists)
+		}
+		if dto.ExtensionType != "" {
+			db = db.Where("echos.extension_type = ?", dto.ExtensionType)
+		}
+
+		switch {
+		case useFTS:
+			db = db.Joins("JOIN "+database.EchoSearchTable+" ON "+
+				database.EchoSearchTable+".rowid = echos.id").
+				Where(database.EchoSearchTable+" MATCH ?", query)
+		case query != "":
+			db = applyTermSearch(db, groups)
+		}
+
+		return db
+	}
+
+	var total int64
+	if err := applyFilters(echoRepository.db()).Count(&total).Error; err != nil {
+		return nil, 0, searchError(err)
+	}
+
+	var hits []struct {
+		ID      uint
+		Snippet string
+	}
+	hitsQuery := applyFilters(echoRepository.db())
+	if useFTS {
+		hitsQuery = hitsQuery.
+			Select(
+				"echos.id AS id, snippet("+database.EchoSearchTable+", 0, ?, ?, ?, ?) AS snippet",
+				snippetSentinelOpen, snippetSentinelClose, snippetEllipsis, ftsSnippetTokens,
+			).
+			// 标签命中权重更高，用户名命中权重更低
+			Order("bm25(" + database.EchoSearchTable + ", 1.0, 2.0, 0.5), echos.created_at DESC")
+	} else {
+		hitsQuery = hitsQuery.Select("echos.id AS id").Order("echos.created_at DESC")
+	}
+	if err := hitsQuery.
+		Limit(dto.PageSize).
+		Offset((dto.Page - 1) * dto.PageSize).
+		Scan(&hits).Error; err != nil {
+		return nil, 0, searchError(err)
+	}
+
+	if len(hits) == 0 {
+		return []model.EchoSearchResult{}, total, nil
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+
+	var echos []model.Echo
+	if err := echoRepository.db().
+		Where("echos.id IN ?", ids).
+		Preload("Media").
+		Preload("Tags").
+		Joins("User").
+		Find(&echos).Error; err != nil {
+		return nil, 0, err
+	}
+
+	echoByID := make(map[uint]model.Echo, len(echos))
+	for _, echo := range echos {
+		echoByID[echo.ID] = echo
+	}
+
+	// 按命中顺序组装结果
+	results := make([]model.EchoSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		echo, ok := echoByID[hit.ID]
+		if !ok {
+			continue
+		}
+		snippet := renderSnippet(hit.Snippet)
+		if !useFTS {
+			snippet = highlightSnippet(echo.Content, groups)
+		}
+		results = append(results, model.EchoSearchResult{
+			Echo:    echo,
+			Snippet: snippet,
+		})
+	}
+
+	return results, total, nil
+}
+
+// searchError 将 FTS5 的语法错误转换为参数错误
+func searchError(err error) error {
+	if strings.Contains(err.Error(), "fts5") {
+		return errors.New(commonModel.INVALID_SEARCH_QUERY)
+	}
+	return err
+}
+
+// canUseFTS 判断搜索语句能否交给 FTS5 执行，trigram 分词无法匹配少于 3 个字符的词
+func canUseFTS(groups [][]searchTerm) bool {
+	if !database.IsEchoSearchAvailable() || len(groups) == 0 {
+		return false
+	}
+	for _, group := range groups {
+		for _, term := range group {
+			if utf8.RuneCountInString(term.text) < ftsMinTermLength {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// applyTermSearch 使用 LIKE 执行解析后的搜索语句，组间为 OR，组内为 AND
+func applyTermSearch(db *gorm.DB, groups [][]searchTerm) *gorm.DB {
+	var (
+		clauses []string
+		args    []any
+	)
+	for _, group := range groups {
+		conditions := make([]string, 0, len(group))
+		for _, term := range group {
+			condition := echoTermMatchSQL
+			if term.negate {
+				condition = "NOT " + condition
+			}
+			pattern := "%" + escapeLike(term.text) + "%"
+			conditions = append(conditions, condition)
+			args = append(args, pattern, pattern, pattern)
+		}
+		clauses = append(clauses, "("+strings.Join(conditions, " AND ")+")")
+	}
+	if len(clauses) == 0 {
+		return db
+	}
+	return db.Where(strings.Join(clauses, " OR "), args...)
+}
+
+// parseSearchQuery 将 FTS5 风格的搜索语句拆分为 OR 分组，每组内的词为 AND 关系
+func parseSearchQuery(query string) [][]searchTerm {
+	var (
+		groups  [][]searchTerm
+		current []searchTerm
+		negate  bool
+	)
+
+	for _, token := range tokenizeSearchQuery(query) {
+		if !token.quoted {
+			switch token.text {
+			case "AND":
+				continue
+			case "OR":
+				if len(current) > 0 {
+					groups = append(groups, current)
+				}
+				current = nil
+				continue
+			case "NOT":
+				negate = true
+				continue
+			}
+		}
+
+		text := token.text
+		if !token.quoted {
+			// 去掉列过滤、首词标记与前缀通配符
+			if column, rest, ok := strings.Cut(text, ":"); ok &&
+				(column == "content" || column == "tags" || column == "username") {
+				text = rest
+			}
+			text = strings.TrimPrefix(text, "^")
+			text = strings.TrimSuffix(text, "*")
+		}
+		if text == "" {
+			continue
+		}
+
+		current = append(current, searchTerm{text: text, negate: negate})
+		negate = false
+	}
+
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+type searchToken struct {
+	text   string
+	quoted bool
+}
+
+// tokenizeSearchQuery 按空白与括号切分搜索语句，双引号内的短语保持完整
+func tokenizeSearchQuery(query string) []searchToken {
+	var (
+		tokens  []searchToken
+		builder strings.Builder
+		quoted  bool
+	)
+
+	flush := func(isQuoted bool) {
+		if builder.Len() > 0 || isQuoted {
+			tokens = append(tokens, searchToken{text: builder.String(), quoted: isQuoted})
+		}
+		builder.Reset()
+	}
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quoted && r == '"':
+			// 短语中的 "" 表示转义的双引号
+			if i+1 < len(runes) && runes[i+1] == '"' {
+				builder.WriteRune('"')
+				i++
+				continue
+			}
+			flush(true)
+			quoted = false
+		case quoted:
+			builder.WriteRune(r)
+		case r == '"':
+			flush(false)
+			quoted = true
+		case unicode.IsSpace(r) || r == '(' || r == ')':
+			flush(false)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	flush(quoted)
+
+	return tokens
+}
+
+// highlightSnippet 截取首个命中词附近的内容并用 <mark> 高亮
+func highlightSnippet(content string, groups [][]searchTerm) string {
+	runes := []rune(content)
+	lower := []rune(strings.ToLower(content))
+	if len(lower) != len(runes) {
+		lower = runes
+	}
+
+	for _, group := range groups {
+		for _, term := range group {
+			if term.negate {
+				continue
+			}
+			needle := []rune(strings.ToLower(term.text))
+			index := indexRunes(lower, needle)
+			if index < 0 {
+				continue
+			}
+
+			start := max(0, index-likeSnippetRadius)
+			end := min(len(runes), index+len(needle)+likeSnippetRadius)
+
+			var builder strings.Builder
+			if start > 0 {
+				builder.WriteString(snippetEllipsis)
+			}
+			builder.WriteString(html.EscapeString(string(runes[start:index])))
+			builder.WriteString(snippetMarkOpen)
+			builder.WriteString(html.EscapeString(string(runes[index : index+len(needle)])))
+			builder.WriteString(snippetMarkClose)
+			builder.WriteString(html.EscapeString(string(runes[index+len(needle) : end])))
+			if end < len(runes) {
+				builder.WriteString(snippetEllipsis)
+			}
+			return builder.String()
+		}
+	}
+
+	// 仅命中标签或用户名时返回内容开头
+	if len(runes) > likeSnippetRadius*2 {
+		return html.EscapeString(string(runes[:likeSnippetRadius*2])) + snippetEllipsis
+	}
+	return html.EscapeString(content)
+}
+
+// renderSnippet 转义 FTS5 返回的片段，并将命中标记替换为 <mark>
+func renderSnippet(snippet string) string {
+	return strings.NewReplacer(
+		snippetSentinelOpen, snippetMarkOpen,
+		snippetSentinelClose, snippetMarkClose,
+	).Replace(html.EscapeString(snippet))
+}
+
+// indexRunes 返回 needle 在 haystack 中首次出现的位置
+func indexRunes(haystack, needle []rune) int {
+	if len(needle) == 0 || len(needle) > len(haystack) {
+		return -1
+	}
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		match := true
+		for j := range needle {
+			if haystack[i+j] != needle[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	commentModel "github.com/lin-snow/ech0/internal/model/comment"
+	model "github.com/lin-snow/ech0/internal/model/echo"
+	"gorm.io/gorm"
+)
+
+// GetTrashedEchos 获取回收站中的全部 Echo，按删除时间倒序排列
+func (echoRepository *EchoRepository) GetTrashedEchos() ([]model.Echo, error) {
+	var echos []model.Echo
+	if err := echoRepository.db().Unscoped().
+		Where("echos.deleted_at IS NOT NULL").
+		Preload("Media").
+		Preload("Tags").
+		Joins("User").
+		Order("echos.deleted_at DESC, echos.id DESC").
+		Find(&echos).Error; err != nil {
+		return nil, err
+	}
+
+	return echos, nil
+}
+
+// GetTrashedEchoById 获取回收站中的 Echo，不存在时返回 nil
+func (echoRepository *EchoRepository) GetTrashedEchoById(id uint) (*model.Echo, error) {
+	var echo model.Echo
+	if err := echoRepository.db().Unscoped().
+		Where("echos.deleted_at IS NOT NULL").
+		Preload("Media").
+		Preload("Tags").
+		Joins("User").
+		First(&echo, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &echo, nil
+}
+
+// GetExpiredTrashedEchos 获取在指定时间之前移入回收站的 Echo
+func (echoRepository *EchoRepository) GetExpiredTrashedEchos(before time.Time) ([]model.Echo, error) {
+	var echos []model.Echo
+	if err := echoRepository.db().Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", before).
+		Preload("Media").
+		Order("deleted_at ASC, id ASC").
+		Find(&echos).Error; err != nil {
+		return nil, err
+	}
+
+	return echos, nil
+}
+
+// RestoreEchoById 将 Echo 从回收站恢复
+func (echoRepository *EchoRepository) RestoreEchoById(ctx context.Context, id uint) error {
+	result := echoRepository.getDB(ctx).Unscoped().
+		Model(&model.Echo{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	// 清除相关缓存
+	echoRepository.cache.Delete(GetEchoByIDCacheKey(id))
+	ClearTodayEchosCache(echoRepository.cache)
+	ClearEchoPageCache(echoRepository.cache)
+
+	return nil
+}
+
+// PurgeEchoById 永久删除回收站中的 Echo 及其媒体记录、历史版本、点赞与评论
+func (echoRepository *EchoRepository) PurgeEchoById(ctx context.Context, id uint) error {
+	db := echoRepository.getDB(ctx)
+
+	// 先获取要删除的 media IDs，用于同步删除 images 表
+	var mediaIDs []uint
+	if err := db.Model(&model.Media{}).Where("message_id = ?", id).Pluck("id", &mediaIDs).Error; err != nil {
+		return err
+	}
+
+	// 删除外键 media
+	if err := db.Where("message_id = ?", id).Delete(&model.Media{}).Error; err != nil {
+		return err
+	}
+
+	// 同步删除 images 表中的对应记录（防止启动时复活）
+	if len(mediaIDs) > 0 && echoRepository.db().Migrator().HasTable("images") {
+		if err := db.Exec("DELETE FROM images WHERE id IN ?", mediaIDs).Error; err != nil {
+			return err
+		}
+	}
+
+	// 删除历史版本
+	if err := db.Where("echo_id = ?", id).Delete(&model.EchoRevision{}).Error; err != nil {
+		return err
+	}
+
+	// 删除点赞记录
+	if err := db.Where("echo_id = ?", id).Delete(&model.EchoLike{}).Error; err != nil {
+		return err
+	}
+
+	// 删除评论
+	if err := db.Where("echo_id = ?", id).Delete(&commentModel.Comment{}).Error; err != nil {
+		return err
+	}
+
+	// 删除标签关联
+	if err := db.Exec("DELETE FROM echo_tags WHERE echo_id = ?", id).Error; err != nil {
+		return err
+	}
+
+	result := db.Unscoped().Where("deleted_at IS NOT NULL").Delete(&model.Echo{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	echoRepository.cache.Delete(GetEchoByIDCacheKey(id))
+
+	return nil
+}
+
+// IsMediaInUse 判断媒体文件是否仍被其他 Echo 或其历史版本引用，S3 媒体按对象键比较（URL 会随 CDN 设置变化）
+func (echoRepository *EchoRepository) IsMediaInUse(ctx context.Context, media model.Media) (bool, error) {
+	column, value := "media_url", media.MediaURL
+	if media.MediaSource == model.MediaSourceS3 && media.ObjectKey != "" {
+		column, value = "object_key", media.ObjectKey
+	}
+
+	var count int64
+	if err := echoRepository.getDB(ctx).
+		Model(&model.Media{}).
+		Where(column+" = ?", value).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	// 历史版本以 JSON 保存媒体快照，按序列化后的字段匹配，恢复旧版本时仍需要这些文件
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	pattern := "%" + escapeLike(fmt.Sprintf(`"%s":%s`, column, encoded)) + "%"
+	if err := echoRepository.getDB(ctx).
+		Model(&model.EchoRevision{}).
+		Where(`media LIKE ? ESCAPE '\'`, pattern).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
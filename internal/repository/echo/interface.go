@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	model "github.com/lin-snow/ech0/internal/model/echo"
 )
@@ -16,7 +17,7 @@ type EchoRepositoryInterface interface {
 	// GetEchosById 根据 ID 获取 Echo
 	GetEchosById(id uint) (*model.Echo, error)
 
-	// DeleteEchoById 删除 Echo
+	// DeleteEchoById 将 Echo 移入回收站
 	DeleteEchoById(ctx context.Context, id uint) error
 
 	// GetTodayEchos 获取今天的 Echo 列表
@@ -25,8 +26,29 @@ type EchoRepositoryInterface interface {
 	// UpdateEcho 更新 Echo
 	UpdateEcho(ctx context.Context, echo *model.Echo) error
 
-	// LikeEcho 点赞 Echo
-	LikeEcho(ctx context.Context, id uint) error
+	// HasEchoLike 判断该身份是否已为 Echo 点赞
+	HasEchoLike(ctx context.Context, echoID uint, identity model.LikeIdentity) (bool, error)
+
+	// CreateEchoLike 保存点赞记录，该身份已点赞时返回 false
+	CreateEchoLike(ctx context.Context, like *model.EchoLike) (bool, error)
+
+	// CreateLegacyEchoLikes 为 Echo 补齐无法追溯来源的点赞记录，使点赞数与记录数一致
+	CreateLegacyEchoLikes(ctx context.Context, echoID uint, count int) error
+
+	// DeleteEchoLike 删除该身份对 Echo 的点赞记录，未点赞时返回 false
+	DeleteEchoLike(ctx context.Context, echoID uint, identity model.LikeIdentity) (bool, error)
+
+	// RefreshEchoFavCount 按点赞记录重新计算 Echo 的点赞数
+	RefreshEchoFavCount(ctx context.Context, echoID uint) (int, error)
+
+	// GetEchoLikes 分页获取点赞记录
+	GetEchoLikes(dto model.EchoLikeQueryDto) ([]model.EchoLike, int64, error)
+
+	// DeleteEchoLikes 按条件批量删除点赞记录，返回删除数量与受影响的 Echo ID
+	DeleteEchoLikes(ctx context.Context, dto model.EchoLikeCleanupDto) (int64, []uint, error)
+
+	// UpdateFediverseCounts 更新 Echo 的联邦宇宙互动计数
+	UpdateFediverseCounts(ctx context.Context, id uint, likes, boosts int64) error
 
 	// GetAllTags 获取所有标签
 	GetAllTags() ([]model.Tag, error)
@@ -61,12 +83,67 @@ type EchoRepositoryInterface interface {
 		showPrivate bool,
 	) ([]model.Echo, int64, error)
 
+	// SearchEchos 全文搜索 Echo，结果按相关度排序并附带高亮片段
+	SearchEchos(dto model.EchoSearchDto, showPrivate bool) ([]model.EchoSearchResult, int64, error)
+
+	// GetAllEchos 获取全部 Echo（含媒体与标签），按创建时间升序排列
+	GetAllEchos() ([]model.Echo, error)
+
+	// GetUnpublishedEchos 获取全部草稿与定时发布的 Echo
+	GetUnpublishedEchos() ([]model.Echo, error)
+
+	// GetDueScheduledEchos 获取发布时间已到的定时 Echo
+	GetDueScheduledEchos(now time.Time) ([]model.Echo, error)
+
+	// UpdateEchoStatus 更新草稿或定时 Echo 的发布状态与定时发布时间
+	UpdateEchoStatus(ctx context.Context, id uint, status string, publishAt *time.Time) error
+
+	// PublishEcho 将草稿或定时 Echo 标记为已发布，返回 false 表示不存在或已经发布
+	PublishEcho(ctx context.Context, id uint, publishedAt time.Time) (bool, error)
+
+	// GetTrashedEchos 获取回收站中的全部 Echo，按删除时间倒序排列
+	GetTrashedEchos() ([]model.Echo, error)
+
+	// GetTrashedEchoById 获取回收站中的 Echo，不存在时返回 nil
+	GetTrashedEchoById(id uint) (*model.Echo, error)
+
+	// GetExpiredTrashedEchos 获取在指定时间之前移入回收站的 Echo
+	GetExpiredTrashedEchos(before time.Time) ([]model.Echo, error)
+
+	// RestoreEchoById 将 Echo 从回收站恢复
+	RestoreEchoById(ctx context.Context, id uint) error
+
+	// PurgeEchoById 永久删除回收站中的 Echo 及其媒体记录、历史版本与点赞记录
+	PurgeEchoById(ctx context.Context, id uint) error
+
+	// IsMediaInUse 判断媒体文件是否仍被其他 Echo 或其历史版本引用
+	IsMediaInUse(ctx context.Context, media model.Media) (bool, error)
+
+	// CreateEchoRevision 保存 Echo 的历史版本，超出上限时删除最旧的版本
+	CreateEchoRevision(ctx context.Context, revision *model.EchoRevision) error
+
+	// GetEchoRevisions 获取 Echo 的全部历史版本，按时间倒序排列
+	GetEchoRevisions(echoID uint) ([]model.EchoRevision, error)
+
+	// GetEchoRevisionByID 获取 Echo 的指定历史版本，不存在时返回 nil
+	GetEchoRevisionByID(echoID, revisionID uint) (*model.EchoRevision, error)
+
 	// UpdateMediaLiveVideoID 更新媒体的实况照片关联
 	UpdateMediaLiveVideoID(ctx context.Context, mediaID uint, liveVideoID uint) error
 
 	// GetMediaByID 根据 ID 获取媒体
 	GetMediaByID(id uint) (*model.Media, error)
 
+	// GetMediaByObjectKey 根据对象存储的 Key 获取媒体，不存在时返回 nil
+	GetMediaByObjectKey(objectKey string) (*model.Media, error)
+
+	// UpdateMediaVariants 为引用该本地图片的全部媒体回填响应式尺寸与占位信息
+	UpdateMediaVariants(
+		ctx context.Context,
+		mediaURL, blurhash, dominantColor string,
+		variants []model.MediaVariant,
+	) error
+
 	// IsLivePhotoVideo 检查视频是否是实况照片的一部分
 	IsLivePhotoVideo(videoID uint) (bool, error)
 }
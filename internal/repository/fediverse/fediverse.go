@@ -59,6 +59,17 @@ func (r *FediverseRepository) FollowerExists(
 	return count > 0, nil
 }
 
+func (r *FediverseRepository) DeleteFollower(ctx context.Context, userID uint, actor string) error {
+	return r.getDB(ctx).
+		Where("user_id = ? AND actor_id = ?", userID, actor).
+		Delete(&model.Follower{}).
+		Error
+}
+
+func (r *FediverseRepository) DeleteFollowersByActor(ctx context.Context, actor string) error {
+	return r.getDB(ctx).Where("actor_id = ?", actor).Delete(&model.Follower{}).Error
+}
+
 func (r *FediverseRepository) SaveOrUpdateFollow(ctx context.Context, follow *model.Follow) error {
 	if follow == nil {
 		return errors.New("follow is nil")
@@ -227,3 +238,145 @@ func (r *FediverseRepository) UpdateFollowStatusByActivityID(
 
 	return nil
 }
+
+func (r *FediverseRepository) DeleteFollowsByObject(ctx context.Context, objectID string) error {
+	return r.getDB(ctx).Where("object_id = ?", objectID).Delete(&model.Follow{}).Error
+}
+
+func (r *FediverseRepository) UpdateInboxStatusActor(
+	ctx context.Context,
+	actorID, preferredUsername, displayName, avatar string,
+) error {
+	return r.getDB(ctx).
+		Model(&model.InboxStatus{}).
+		Where("actor_id = ?", actorID).
+		Updates(map[string]any{
+			"actor_preferred_username": preferredUsername,
+			"actor_display_name":       displayName,
+			"actor_avatar":             avatar,
+			"updated_at":               time.Now().UTC(),
+		}).Error
+}
+
+func (r *FediverseRepository) DeleteInboxStatusesByActor(ctx context.Context, actorID string) error {
+	return r.getDB(ctx).Where("actor_id = ?", actorID).Delete(&model.InboxStatus{}).Error
+}
+
+func (r *FediverseRepository) DeleteInboxStatusByObject(
+	ctx context.Context,
+	actorID, objectID string,
+) error {
+	return r.getDB(ctx).
+		Where("actor_id = ? AND object_id = ?", actorID, objectID).
+		Delete(&model.InboxStatus{}).
+		Error
+}
+
+func (r *FediverseRepository) SaveInteraction(
+	ctx context.Context,
+	interaction *model.Interaction,
+) error {
+	if interaction == nil {
+		return errors.New("interaction is nil")
+	}
+
+	return r.getDB(ctx).
+		Where("echo_id = ? AND actor_id = ? AND type = ?", interaction.EchoID, interaction.ActorID, interaction.Type).
+		Attrs(model.Interaction{ActivityID: interaction.ActivityID}).
+		FirstOrCreate(interaction).
+		Error
+}
+
+func (r *FediverseRepository) GetInteractionByActivityID(
+	ctx context.Context,
+	actorID, activityID string,
+) (*model.Interaction, error) {
+	var interaction model.Interaction
+	err := r.getDB(ctx).
+		Where("actor_id = ? AND activity_id = ?", actorID, activityID).
+		First(&interaction).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &interaction, nil
+}
+
+func (r *FediverseRepository) DeleteInteraction(
+	ctx context.Context,
+	echoID uint,
+	actorID, interactionType string,
+) error {
+	return r.getDB(ctx).
+		Where("echo_id = ? AND actor_id = ? AND type = ?", echoID, actorID, interactionType).
+		Delete(&model.Interaction{}).
+		Error
+}
+
+func (r *FediverseRepository) DeleteInteractionsByActor(
+	ctx context.Context,
+	actorID string,
+) ([]uint, error) {
+	db := r.getDB(ctx)
+
+	var echoIDs []uint
+	if err := db.Model(&model.Interaction{}).
+		Where("actor_id = ?", actorID).
+		Distinct().
+		Pluck("echo_id", &echoIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(echoIDs) == 0 {
+		return echoIDs, nil
+	}
+
+	if err := db.Where("actor_id = ?", actorID).Delete(&model.Interaction{}).Error; err != nil {
+		return nil, err
+	}
+	return echoIDs, nil
+}
+
+func (r *FediverseRepository) CountInteractions(
+	ctx context.Context,
+	echoID uint,
+	interactionType string,
+) (int64, error) {
+	var count int64
+	if err := r.getDB(ctx).
+		Model(&model.Interaction{}).
+		Where("echo_id = ? AND type = ?", echoID, interactionType).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *FediverseRepository) SaveTombstone(ctx context.Context, tombstone *model.Tombstone) error {
+	if tombstone == nil {
+		return errors.New("tombstone is nil")
+	}
+
+	return r.getDB(ctx).
+		Where("echo_id = ?", tombstone.EchoID).
+		Attrs(model.Tombstone{FormerType: tombstone.FormerType, DeletedAt: tombstone.DeletedAt}).
+		FirstOrCreate(tombstone).
+		Error
+}
+
+func (r *FediverseRepository) GetTombstoneByEchoID(
+	ctx context.Context,
+	echoID uint,
+) (*model.Tombstone, error) {
+	var tombstone model.Tombstone
+	err := r.getDB(ctx).Where("echo_id = ?", echoID).First(&tombstone).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tombstone, nil
+}
@@ -19,6 +19,12 @@ type FediverseRepositoryInterface interface {
 	// 检查粉丝记录是否存在
 	FollowerExists(ctx context.Context, userID uint, actor string) (bool, error)
 
+	// 删除指定用户的粉丝记录
+	DeleteFollower(ctx context.Context, userID uint, actor string) error
+
+	// 删除某个 Actor 在所有用户下的粉丝记录
+	DeleteFollowersByActor(ctx context.Context, actor string) error
+
 	// 保存或更新新的关注关系
 	SaveOrUpdateFollow(ctx context.Context, follow *model.Follow) error
 
@@ -48,4 +54,43 @@ type FediverseRepositoryInterface interface {
 		userID uint,
 		activityID, status string,
 	) error
+
+	// DeleteFollowsByObject 删除所有以该 Actor 为目标的关注关系
+	DeleteFollowsByObject(ctx context.Context, objectID string) error
+
+	// UpdateInboxStatusActor 刷新收件箱推文中缓存的发送者资料
+	UpdateInboxStatusActor(
+		ctx context.Context,
+		actorID, preferredUsername, displayName, avatar string,
+	) error
+
+	// DeleteInboxStatusesByActor 删除某个 Actor 的全部收件箱推文
+	DeleteInboxStatusesByActor(ctx context.Context, actorID string) error
+
+	// DeleteInboxStatusByObject 删除某个 Actor 发布的指定推文
+	DeleteInboxStatusByObject(ctx context.Context, actorID, objectID string) error
+
+	// SaveInteraction 保存互动记录，同一 Actor 对同一 Echo 的同类互动只保存一次
+	SaveInteraction(ctx context.Context, interaction *model.Interaction) error
+
+	// GetInteractionByActivityID 根据互动 Activity ID 获取互动记录
+	GetInteractionByActivityID(
+		ctx context.Context,
+		actorID, activityID string,
+	) (*model.Interaction, error)
+
+	// DeleteInteraction 删除互动记录
+	DeleteInteraction(ctx context.Context, echoID uint, actorID, interactionType string) error
+
+	// DeleteInteractionsByActor 删除某个 Actor 的全部互动记录，返回受影响的 Echo ID
+	DeleteInteractionsByActor(ctx context.Context, actorID string) ([]uint, error)
+
+	// CountInteractions 统计 Echo 的某类互动数量
+	CountInteractions(ctx context.Context, echoID uint, interactionType string) (int64, error)
+
+	// SaveTombstone 记录已删除的 Echo
+	SaveTombstone(ctx context.Context, tombstone *model.Tombstone) error
+
+	// GetTombstoneByEchoID 根据 Echo ID 获取删除记录
+	GetTombstoneByEchoID(ctx context.Context, echoID uint) (*model.Tombstone, error)
 }
@@ -14,7 +14,7 @@ type QueueRepositoryInterface interface {
 	// DeleteDeadLetter 删除死信任务
 	DeleteDeadLetter(ctx context.Context, id int64) error
 
-	// ListDeadLetters 列出所有可重试的死信任务
+	// ListDeadLetters 列出已到重试时间的死信任务
 	ListDeadLetters(limit int) ([]model.DeadLetter, error)
 
 	// UpdateDeadLetter 更新死信任务
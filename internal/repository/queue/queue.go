@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	model "github.com/lin-snow/ech0/internal/model/queue"
 	"github.com/lin-snow/ech0/internal/transaction"
@@ -39,7 +40,12 @@ func (queueRepository *QueueRepository) DeleteDeadLetter(ctx context.Context, id
 // ListDeadLetters 列出所有死信任务
 func (queueRepository *QueueRepository) ListDeadLetters(limit int) ([]model.DeadLetter, error) {
 	var deadLetters []model.DeadLetter
-	err := queueRepository.db().Limit(limit).Find(&deadLetters).Error
+	// 只取出已到重试时间的任务，按到期先后处理
+	err := queueRepository.db().
+		Where("next_retry <= ?", time.Now().UTC()).
+		Order("next_retry ASC").
+		Limit(limit).
+		Find(&deadLetters).Error
 	if err != nil {
 		return []model.DeadLetter{}, err
 	}
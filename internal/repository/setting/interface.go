@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	model "github.com/lin-snow/ech0/internal/model/setting"
 )
@@ -15,4 +16,16 @@ type SettingRepositoryInterface interface {
 
 	// DeleteAccessTokenByID 删除访问令牌
 	DeleteAccessTokenByID(ctx context.Context, id uint) error
+
+	// GetAccessTokenByID 根据 ID 获取访问令牌
+	GetAccessTokenByID(id uint) (*model.AccessTokenSetting, error)
+
+	// GetAccessTokenByJTI 根据 jti 获取访问令牌，不存在时返回 nil
+	GetAccessTokenByJTI(jti string) (*model.AccessTokenSetting, error)
+
+	// GetAccessTokenByToken 根据令牌原文获取访问令牌，不存在时返回 nil
+	GetAccessTokenByToken(token string) (*model.AccessTokenSetting, error)
+
+	// UpdateAccessTokenUsage 记录访问令牌最近一次使用的时间与 IP
+	UpdateAccessTokenUsage(ctx context.Context, id uint, usedAt time.Time, ip string) error
 }
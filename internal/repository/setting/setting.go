@@ -2,19 +2,27 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/lin-snow/ech0/internal/cache"
 	model "github.com/lin-snow/ech0/internal/model/setting"
 	"github.com/lin-snow/ech0/internal/transaction"
 	"gorm.io/gorm"
 )
 
 type SettingRepository struct {
-	db func() *gorm.DB
+	db    func() *gorm.DB
+	cache cache.ICache[string, any]
 }
 
-func NewSettingRepository(dbProvider func() *gorm.DB) SettingRepositoryInterface {
+func NewSettingRepository(
+	dbProvider func() *gorm.DB,
+	cache cache.ICache[string, any],
+) SettingRepositoryInterface {
 	return &SettingRepository{
-		db: dbProvider,
+		db:    dbProvider,
+		cache: cache,
 	}
 }
 
@@ -52,5 +60,119 @@ func (settingRepository *SettingRepository) DeleteAccessTokenByID(
 	id uint,
 ) error {
 	db := settingRepository.getDB(ctx)
-	return db.Delete(&model.AccessTokenSetting{}, id).Error
+
+	var token model.AccessTokenSetting
+	if err := db.First(&token, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := db.Delete(&model.AccessTokenSetting{}, id).Error; err != nil {
+		return err
+	}
+
+	// 清除缓存，令牌立即失效
+	settingRepository.clearAccessTokenCache(&token)
+	return nil
+}
+
+// GetAccessTokenByID 根据 ID 获取访问令牌
+func (settingRepository *SettingRepository) GetAccessTokenByID(
+	id uint,
+) (*model.AccessTokenSetting, error) {
+	var token model.AccessTokenSetting
+	if err := settingRepository.db().First(&token, id).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetAccessTokenByJTI 根据 jti 获取访问令牌，不存在时返回 nil
+func (settingRepository *SettingRepository) GetAccessTokenByJTI(
+	jti string,
+) (*model.AccessTokenSetting, error) {
+	cacheKey := GetAccessTokenJTICacheKey(jti)
+	if cached, err := settingRepository.cache.Get(cacheKey); err == nil {
+		if token, ok := cached.(*model.AccessTokenSetting); ok {
+			return token, nil
+		}
+	}
+
+	var token model.AccessTokenSetting
+	err := settingRepository.db().Where("jti = ?", jti).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	settingRepository.cache.SetWithTTL(cacheKey, &token, 1, accessTokenCacheTTL)
+	return &token, nil
+}
+
+// GetAccessTokenByToken 根据令牌原文获取访问令牌，不存在时返回 nil
+func (settingRepository *SettingRepository) GetAccessTokenByToken(
+	token string,
+) (*model.AccessTokenSetting, error) {
+	// 不存在的结果同样缓存，避免登录会话的每次请求都查询数据库
+	cacheKey := GetAccessTokenTokenCacheKey(token)
+	if cached, err := settingRepository.cache.Get(cacheKey); err == nil {
+		if accessToken, ok := cached.(*model.AccessTokenSetting); ok {
+			return accessToken, nil
+		}
+	}
+
+	var accessToken model.AccessTokenSetting
+	err := settingRepository.db().Where("token = ?", token).First(&accessToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		settingRepository.cache.SetWithTTL(
+			cacheKey,
+			(*model.AccessTokenSetting)(nil),
+			1,
+			accessTokenCacheTTL,
+		)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	settingRepository.cache.SetWithTTL(cacheKey, &accessToken, 1, accessTokenCacheTTL)
+	return &accessToken, nil
+}
+
+// UpdateAccessTokenUsage 记录访问令牌最近一次使用的时间与 IP
+func (settingRepository *SettingRepository) UpdateAccessTokenUsage(
+	ctx context.Context,
+	id uint,
+	usedAt time.Time,
+	ip string,
+) error {
+	db := settingRepository.getDB(ctx)
+
+	var token model.AccessTokenSetting
+	if err := db.First(&token, id).Error; err != nil {
+		return err
+	}
+
+	if err := db.Model(&token).Updates(map[string]any{
+		"last_used_at": usedAt,
+		"last_used_ip": ip,
+	}).Error; err != nil {
+		return err
+	}
+
+	settingRepository.clearAccessTokenCache(&token)
+	return nil
+}
+
+// clearAccessTokenCache 清除访问令牌的缓存
+func (settingRepository *SettingRepository) clearAccessTokenCache(token *model.AccessTokenSetting) {
+	if token.JTI != "" {
+		settingRepository.cache.Delete(GetAccessTokenJTICacheKey(token.JTI))
+	}
+	settingRepository.cache.Delete(GetAccessTokenTokenCacheKey(token.Token))
 }
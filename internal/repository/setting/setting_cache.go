@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	AccessTokenJTICacheKeyPrefix   = "access_token_jti"   // access_token_jti:jti
+	AccessTokenTokenCacheKeyPrefix = "access_token_token" // access_token_token:sha256(token)
+	accessTokenCacheTTL            = 5 * time.Minute
+)
+
+func GetAccessTokenJTICacheKey(jti string) string {
+	return AccessTokenJTICacheKeyPrefix + ":" + jti
+}
+
+func GetAccessTokenTokenCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return AccessTokenTokenCacheKeyPrefix + ":" + hex.EncodeToString(sum[:])
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	model "github.com/lin-snow/ech0/internal/model/webhook"
 )
@@ -13,9 +14,37 @@ type WebhookRepositoryInterface interface {
 	// GetAllWebhooks 获取所有webhooks
 	GetAllWebhooks() ([]model.Webhook, error)
 
+	// GetWebhookByID 根据ID获取webhook
+	GetWebhookByID(ctx context.Context, id uint) (model.Webhook, error)
+
 	// DeleteWebhookByID 根据ID删除webhook
 	DeleteWebhookByID(ctx context.Context, id uint) error
 
 	// ListActiveWebhooks 列出所有激活的 webhook
 	ListActiveWebhooks() ([]model.Webhook, error)
+
+	// UpdateWebhookStatus 更新 webhook 最近调用状态与时间
+	UpdateWebhookStatus(ctx context.Context, id uint, status string, trigger time.Time) error
+
+	// CreateDelivery 保存一条投递记录
+	CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+
+	// UpdateDelivery 更新一条投递记录
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+
+	// ListDeliveries 分页列出指定 webhook 的投递记录（最新在前）
+	ListDeliveries(
+		ctx context.Context,
+		webhookID uint,
+		offset, limit int,
+	) ([]model.WebhookDelivery, int64, error)
+
+	// GetDeliveryByID 根据ID获取投递记录
+	GetDeliveryByID(ctx context.Context, id uint) (model.WebhookDelivery, error)
+
+	// DeleteDeliveriesByWebhookID 删除指定 webhook 的全部投递记录
+	DeleteDeliveriesByWebhookID(ctx context.Context, webhookID uint) error
+
+	// DeleteDeliveriesBefore 删除指定时间之前的投递记录
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) error
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	model "github.com/lin-snow/ech0/internal/model/webhook"
 	"github.com/lin-snow/ech0/internal/transaction"
@@ -47,6 +48,19 @@ func (webhookRepository *WebhookRepository) GetAllWebhooks() ([]model.Webhook, e
 	return webhooks, nil
 }
 
+// GetWebhookByID 根据ID获取webhook
+func (webhookRepository *WebhookRepository) GetWebhookByID(
+	ctx context.Context,
+	id uint,
+) (model.Webhook, error) {
+	var webhook model.Webhook
+	if err := webhookRepository.getDB(ctx).First(&webhook, id).Error; err != nil {
+		return model.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
 // DeleteWebhookByID 根据ID删除webhook
 func (webhookRepository *WebhookRepository) DeleteWebhookByID(ctx context.Context, id uint) error {
 	if err := webhookRepository.getDB(ctx).Delete(&model.Webhook{}, id).Error; err != nil {
@@ -65,3 +79,102 @@ func (webhookRepository *WebhookRepository) ListActiveWebhooks() ([]model.Webhoo
 
 	return webhooks, nil
 }
+
+// UpdateWebhookStatus 更新 webhook 最近调用状态与时间
+func (webhookRepository *WebhookRepository) UpdateWebhookStatus(
+	ctx context.Context,
+	id uint,
+	status string,
+	trigger time.Time,
+) error {
+	return webhookRepository.getDB(ctx).
+		Model(&model.Webhook{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"last_status":  status,
+			"last_trigger": trigger,
+		}).Error
+}
+
+// CreateDelivery 保存一条投递记录
+func (webhookRepository *WebhookRepository) CreateDelivery(
+	ctx context.Context,
+	delivery *model.WebhookDelivery,
+) error {
+	return webhookRepository.getDB(ctx).Create(delivery).Error
+}
+
+// UpdateDelivery 更新一条投递记录
+func (webhookRepository *WebhookRepository) UpdateDelivery(
+	ctx context.Context,
+	delivery *model.WebhookDelivery,
+) error {
+	return webhookRepository.getDB(ctx).Save(delivery).Error
+}
+
+// ListDeliveries 分页列出指定 webhook 的投递记录（最新在前）
+func (webhookRepository *WebhookRepository) ListDeliveries(
+	ctx context.Context,
+	webhookID uint,
+	offset, limit int,
+) ([]model.WebhookDelivery, int64, error) {
+	var (
+		deliveries []model.WebhookDelivery
+		total      int64
+	)
+
+	query := webhookRepository.getDB(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("webhook_id = ?", webhookID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order("created_at DESC").Order("id DESC")
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// GetDeliveryByID 根据ID获取投递记录
+func (webhookRepository *WebhookRepository) GetDeliveryByID(
+	ctx context.Context,
+	id uint,
+) (model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := webhookRepository.getDB(ctx).First(&delivery, id).Error; err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+// DeleteDeliveriesByWebhookID 删除指定 webhook 的全部投递记录
+func (webhookRepository *WebhookRepository) DeleteDeliveriesByWebhookID(
+	ctx context.Context,
+	webhookID uint,
+) error {
+	return webhookRepository.getDB(ctx).
+		Where("webhook_id = ?", webhookID).
+		Delete(&model.WebhookDelivery{}).Error
+}
+
+// DeleteDeliveriesBefore 删除指定时间之前的投递记录
+func (webhookRepository *WebhookRepository) DeleteDeliveriesBefore(
+	ctx context.Context,
+	before time.Time,
+) error {
+	return webhookRepository.getDB(ctx).
+		Where("created_at < ?", before).
+		Delete(&model.WebhookDelivery{}).Error
+}
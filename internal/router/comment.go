@@ -0,0 +1,24 @@
+package router
+
+import (
+	"time"
+
+	"github.com/lin-snow/ech0/internal/di"
+	"github.com/lin-snow/ech0/internal/middleware"
+)
+
+// setupCommentRoutes 设置评论路由
+func setupCommentRoutes(appRouterGroup *AppRouterGroup, h *di.Handlers) {
+	// 查看与发表评论（访客可用），同一 IP 限制每分钟的评论次数
+	appRouterGroup.AuthRouterGroup.GET("/echo/:id/comments", h.CommentHandler.GetEchoComments())
+	appRouterGroup.AuthRouterGroup.POST(
+		"/echo/:id/comments",
+		middleware.RateLimit(5, time.Minute),
+		h.CommentHandler.CreateComment(),
+	)
+
+	// Auth
+	appRouterGroup.AuthRouterGroup.GET("/comments", h.CommentHandler.GetComments())
+	appRouterGroup.AuthRouterGroup.PUT("/comments/:id/status", h.CommentHandler.UpdateCommentStatus())
+	appRouterGroup.AuthRouterGroup.DELETE("/comments/:id", h.CommentHandler.DeleteComment())
+}
@@ -20,7 +20,12 @@ func setupCommonRoutes(appRouterGroup *AppRouterGroup, h *di.Handlers) {
 	appRouterGroup.AuthRouterGroup.POST("/audios/upload", h.CommonHandler.UploadAudio())
 	appRouterGroup.AuthRouterGroup.DELETE("/audios/delete", h.CommonHandler.DeleteAudio())
 	appRouterGroup.AuthRouterGroup.GET("/backup", h.BackupHandler.Backup())
-	appRouterGroup.AuthRouterGroup.POST("/backup/import", h.BackupHandler.ImportBackup())
+	appRouterGroup.AuthRouterGroup.POST("/backup/import/preview", h.BackupHandler.PreviewRestore())
+	appRouterGroup.AuthRouterGroup.POST("/backup/import/:token/commit", h.BackupHandler.CommitRestore())
+	appRouterGroup.AuthRouterGroup.DELETE("/backup/import/:token", h.BackupHandler.DiscardRestore())
+	appRouterGroup.AuthRouterGroup.GET("/backup/archives", h.BackupHandler.ListBackups())
+	appRouterGroup.AuthRouterGroup.GET("/backup/archives/:name", h.BackupHandler.DownloadBackup())
+	appRouterGroup.AuthRouterGroup.DELETE("/backup/archives/:name", h.BackupHandler.DeleteBackup())
 	appRouterGroup.AuthRouterGroup.PUT("/s3/presign", h.CommonHandler.GetS3PresignURL())
 
 	// 原版兼容维护
@@ -1,23 +1,47 @@
 package router
 
-import "github.com/lin-snow/ech0/internal/di"
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lin-snow/ech0/internal/di"
+	"github.com/lin-snow/ech0/internal/middleware"
+)
 
 // setupEchoRoutes 设置Echo路由
 func setupEchoRoutes(appRouterGroup *AppRouterGroup, h *di.Handlers) {
 	// Public
-	appRouterGroup.PublicRouterGroup.PUT("/echo/like/:id", h.EchoHandler.LikeEcho())
 	appRouterGroup.PublicRouterGroup.GET("/tags", h.EchoHandler.GetAllTags())
 
+	// 点赞（访客可用，登录后按用户去重），同一 IP 限制每分钟与每天的次数
+	likeLimiters := []gin.HandlerFunc{
+		middleware.RateLimit(20, time.Minute),
+		middleware.RateLimit(200, 24*time.Hour),
+	}
+	appRouterGroup.AuthRouterGroup.PUT("/echo/like/:id", append(likeLimiters, h.EchoHandler.LikeEcho())...)
+	appRouterGroup.AuthRouterGroup.DELETE("/echo/like/:id", append(likeLimiters, h.EchoHandler.UnlikeEcho())...)
+
 	// Auth
 	appRouterGroup.AuthRouterGroup.POST("/echo", h.EchoHandler.PostEcho())
 	appRouterGroup.AuthRouterGroup.GET("/echo/page", h.EchoHandler.GetEchosByPage())
 	appRouterGroup.AuthRouterGroup.POST("/echo/page", h.EchoHandler.GetEchosByPage())
 	appRouterGroup.AuthRouterGroup.DELETE("/echo/:id", h.EchoHandler.DeleteEcho())
 	appRouterGroup.AuthRouterGroup.GET("/echo/today", h.EchoHandler.GetTodayEchos())
+	appRouterGroup.AuthRouterGroup.GET("/echo/drafts", h.EchoHandler.GetUnpublishedEchos())
+	appRouterGroup.AuthRouterGroup.GET("/echo/likes", h.EchoHandler.GetEchoLikes())
+	appRouterGroup.AuthRouterGroup.POST("/echo/likes/cleanup", h.EchoHandler.CleanupEchoLikes())
+	appRouterGroup.AuthRouterGroup.GET("/echo/trash", h.EchoHandler.GetTrashedEchos())
+	appRouterGroup.AuthRouterGroup.DELETE("/echo/trash", h.EchoHandler.EmptyTrash())
+	appRouterGroup.AuthRouterGroup.POST("/echo/trash/:id/restore", h.EchoHandler.RestoreTrashedEcho())
+	appRouterGroup.AuthRouterGroup.DELETE("/echo/trash/:id", h.EchoHandler.PurgeTrashedEcho())
 	appRouterGroup.AuthRouterGroup.PUT("/echo", h.EchoHandler.UpdateEcho())
 	appRouterGroup.AuthRouterGroup.GET("/echo/:id", h.EchoHandler.GetEchoById())
+	appRouterGroup.AuthRouterGroup.GET("/echo/:id/revisions", h.EchoHandler.GetEchoRevisions())
+	appRouterGroup.AuthRouterGroup.GET("/echo/:id/revisions/diff", h.EchoHandler.DiffEchoRevisions())
+	appRouterGroup.AuthRouterGroup.POST("/echo/:id/revisions/:revisionId/restore", h.EchoHandler.RestoreEchoRevision())
 	appRouterGroup.AuthRouterGroup.GET("/echo/tag/:tagid", h.EchoHandler.GetEchosByTagId())
 	appRouterGroup.AuthRouterGroup.GET("/echo/date", h.EchoHandler.GetEchosByDate())
+	appRouterGroup.AuthRouterGroup.GET("/echo/search", h.EchoHandler.SearchEchos())
 	appRouterGroup.AuthRouterGroup.DELETE("/tag/:id", h.EchoHandler.DeleteTag())
 	// appRouterGroup.AuthRouterGroup.PUT("/tag", h.EchoHandler.UpdateTag())
 }
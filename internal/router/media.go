@@ -0,0 +1,9 @@
+package router
+
+import "github.com/lin-snow/ech0/internal/di"
+
+// setupMediaRoutes 设置媒体处理路由
+func setupMediaRoutes(appRouterGroup *AppRouterGroup, h *di.Handlers) {
+	// Public
+	appRouterGroup.PublicRouterGroup.GET("/media/:source/*key", h.MediaHandler.HandleMediaRequest)
+}
@@ -9,6 +9,8 @@ import (
 func setupMiddleware(r *gin.Engine) {
 	// Recovery middleware to recover from any panics and write a 500 if there was one.
 	r.Use(gin.Recovery())
+	// Warn when running behind an untrusted reverse proxy
+	r.Use(middleware.ProxyHeaderWarning())
 	// Cors middleware
 	r.Use(middleware.Cors())
 	// Global write guard middleware
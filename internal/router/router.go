@@ -39,7 +39,7 @@ func SetupRouter(r *gin.Engine, h *di.Handlers) {
 
 	// ===  路由组与各模块路由  ===
 	// Setup Router Groups
-	appRouterGroup := setupRouterGroup(r)
+	appRouterGroup := setupRouterGroup(r, h)
 
 	// Setup Resource Routes
 	setupResourceRoutes(appRouterGroup, h)
@@ -50,6 +50,9 @@ func SetupRouter(r *gin.Engine, h *di.Handlers) {
 	// Setup Echo Routes
 	setupEchoRoutes(appRouterGroup, h)
 
+	// Setup Comment Routes
+	setupCommentRoutes(appRouterGroup, h)
+
 	// Setup Common Routes
 	setupCommonRoutes(appRouterGroup, h)
 
@@ -76,14 +79,20 @@ func SetupRouter(r *gin.Engine, h *di.Handlers) {
 
 	// Setup PWA Routes
 	setupPwaRoutes(appRouterGroup, h)
+
+	// Setup Transfer Routes
+	setupTransferRoutes(appRouterGroup, h)
+
+	// Setup Media Routes
+	setupMediaRoutes(appRouterGroup, h)
 }
 
 // setupRouterGroup 初始化路由组
-func setupRouterGroup(r *gin.Engine) *AppRouterGroup {
+func setupRouterGroup(r *gin.Engine, h *di.Handlers) *AppRouterGroup {
 	resource := r.Group("/")
 	public := r.Group("/api")
 	auth := r.Group("/api")
-	auth.Use(middleware.NoCache(), middleware.JWTAuthMiddleware())
+	auth.Use(middleware.NoCache(), middleware.JWTAuthMiddleware(h.AccessTokenValidator))
 	ws := r.Group("/ws")
 	return &AppRouterGroup{
 		ResourceGroup:     resource,
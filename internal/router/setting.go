@@ -26,8 +26,17 @@ func setupSettingRoutes(appRouterGroup *AppRouterGroup, h *di.Handlers) {
 
 	appRouterGroup.AuthRouterGroup.GET("/webhook", h.SettingHandler.GetWebhook())
 	appRouterGroup.AuthRouterGroup.POST("/webhook", h.SettingHandler.CreateWebhook())
-	appRouterGroup.AuthRouterGroup.PUT("/webhook", h.SettingHandler.UpdateWebhook())
+	appRouterGroup.AuthRouterGroup.PUT("/webhook/:id", h.SettingHandler.UpdateWebhook())
 	appRouterGroup.AuthRouterGroup.DELETE("/webhook/:id", h.SettingHandler.DeleteWebhook())
+	appRouterGroup.AuthRouterGroup.POST("/webhook/:id/ping", h.SettingHandler.PingWebhook())
+	appRouterGroup.AuthRouterGroup.GET(
+		"/webhook/:id/deliveries",
+		h.SettingHandler.ListWebhookDeliveries(),
+	)
+	appRouterGroup.AuthRouterGroup.POST(
+		"/webhook/:id/deliveries/:deliveryId/redeliver",
+		h.SettingHandler.RedeliverWebhook(),
+	)
 
 	appRouterGroup.AuthRouterGroup.GET("/access-tokens", h.SettingHandler.ListAccessTokens())
 	appRouterGroup.AuthRouterGroup.POST("/access-tokens", h.SettingHandler.CreateAccessToken())
@@ -53,6 +62,22 @@ func setupSettingRoutes(appRouterGroup *AppRouterGroup, h *di.Handlers) {
 		"/backup/schedule",
 		h.SettingHandler.UpdateBackupScheduleSetting(),
 	)
+	appRouterGroup.AuthRouterGroup.GET(
+		"/backup/destination",
+		h.SettingHandler.GetBackupDestination(),
+	)
+	appRouterGroup.AuthRouterGroup.PUT(
+		"/backup/destination",
+		h.SettingHandler.UpdateBackupDestination(),
+	)
+	appRouterGroup.AuthRouterGroup.GET(
+		"/backup/encryption",
+		h.SettingHandler.GetBackupEncryption(),
+	)
+	appRouterGroup.AuthRouterGroup.PUT(
+		"/backup/encryption",
+		h.SettingHandler.UpdateBackupEncryption(),
+	)
 
 	appRouterGroup.AuthRouterGroup.GET("/agent/settings", h.SettingHandler.GetAgentSettings())
 	appRouterGroup.AuthRouterGroup.PUT("/agent/settings", h.SettingHandler.UpdateAgentSettings())
@@ -60,4 +85,8 @@ func setupSettingRoutes(appRouterGroup *AppRouterGroup, h *di.Handlers) {
 	// 图片处理设置（GET 公开，前端需要读取来决定拼接方式）
 	appRouterGroup.PublicRouterGroup.GET("/image-process/settings", h.SettingHandler.GetImageProcessSettings())
 	appRouterGroup.AuthRouterGroup.PUT("/image-process/settings", h.SettingHandler.UpdateImageProcessSettings())
+
+	// 回收站设置
+	appRouterGroup.AuthRouterGroup.GET("/trash/settings", h.SettingHandler.GetTrashSettings())
+	appRouterGroup.AuthRouterGroup.PUT("/trash/settings", h.SettingHandler.UpdateTrashSettings())
 }
@@ -0,0 +1,10 @@
+package router
+
+import "github.com/lin-snow/ech0/internal/di"
+
+// setupTransferRoutes 设置数据迁移路由
+func setupTransferRoutes(appRouterGroup *AppRouterGroup, h *di.Handlers) {
+	// Auth
+	appRouterGroup.AuthRouterGroup.GET("/transfer/export", h.TransferHandler.ExportEchos())
+	appRouterGroup.AuthRouterGroup.POST("/transfer/import", h.TransferHandler.ImportEchos())
+}
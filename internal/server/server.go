@@ -52,6 +52,14 @@ func (s *Server) Init() {
 	// Gin Engine
 	s.GinEngine = gin.New()
 
+	// 仅采信受信任代理转发的客户端 IP，避免伪造 X-Forwarded-For 绕过限流
+	if err := s.GinEngine.SetTrustedProxies(config.Config.Server.TrustedProxies); err != nil {
+		errUtil.HandlePanicError(&commonModel.ServerError{
+			Msg: commonModel.INIT_TRUSTED_PROXIES_PANIC,
+			Err: err,
+		})
+	}
+
 	// Database
 	database.InitDatabase()
 
@@ -13,7 +13,9 @@ import (
 	"github.com/lin-snow/ech0/internal/database"
 	"github.com/lin-snow/ech0/internal/event"
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	settingModel "github.com/lin-snow/ech0/internal/model/setting"
 	commonService "github.com/lin-snow/ech0/internal/service/common"
+	settingService "github.com/lin-snow/ech0/internal/service/setting"
 	logUtil "github.com/lin-snow/ech0/internal/util/log"
 	"go.uber.org/zap"
 )
@@ -34,17 +36,20 @@ func (ImageLegacy) TableName() string {
 }
 
 type BackupService struct {
-	commonService commonService.CommonServiceInterface
-	eventBus      event.IEventBus
+	commonService  commonService.CommonServiceInterface
+	settingService settingService.SettingServiceInterface
+	eventBus       event.IEventBus
 }
 
 func NewBackupService(
 	commonService commonService.CommonServiceInterface,
+	settingService settingService.SettingServiceInterface,
 	eventBusProvider func() event.IEventBus,
 ) BackupServiceInterface {
 	return &BackupService{
-		commonService: commonService,
-		eventBus:      eventBusProvider(),
+		commonService:  commonService,
+		settingService: settingService,
+		eventBus:       eventBusProvider(),
 	}
 }
 
@@ -59,28 +64,101 @@ func (backupService *BackupService) Backup(userid uint) error {
 		return errors.New(commonModel.NO_PERMISSION_DENIED)
 	}
 
-	// 执行备份
-	if _, _, err := backup.ExecuteBackup(); err != nil {
-		return err
+	return backupService.RunBackup(context.Background(), "System backup completed")
+}
+
+// RunBackup 执行完整的备份流程：本地备份、清理旧备份、上传异地存储，并发布备份事件
+func (backupService *BackupService) RunBackup(ctx context.Context, info string) error {
+	payload := event.EventPayload{
+		event.EventPayloadInfo:   info,
+		event.EventPayloadStatus: "success",
 	}
 
-	// 触发备份完成事件
-	if err := backupService.eventBus.Publish(
+	err := backupService.runBackup(ctx, payload)
+	if err != nil {
+		payload[event.EventPayloadStatus] = "failed"
+		payload[event.EventPayloadError] = err.Error()
+	}
+
+	// 无论成功与否都发布备份事件，便于 Webhook 告警
+	if publishErr := backupService.eventBus.Publish(
 		context.Background(),
-		event.NewEvent(
-			event.EventTypeSystemBackup,
-			event.EventPayload{
-				event.EventPayloadInfo: "System backup completed",
-			},
-		),
-	); err != nil {
+		event.NewEvent(event.EventTypeSystemBackup, payload),
+	); publishErr != nil {
 		logUtil.GetLogger().
-			Error("Failed to publish system backup completed event", zap.String("error", err.Error()))
+			Error("Failed to publish system backup event", zap.String("error", publishErr.Error()))
+	}
+
+	return err
+}
+
+// runBackup 执行备份并将结果写入事件负载
+func (backupService *BackupService) runBackup(ctx context.Context, payload event.EventPayload) error {
+	encryption, err := backupService.backupEncryption()
+	if err != nil {
+		return err
+	}
+
+	backupPath, backupFileName, err := backup.ExecuteBackup(encryption)
+	if err != nil {
+		// 备份失败时不清理旧备份，避免丢失最后一份可用备份
+		return err
+	}
+
+	payload[event.EventPayloadFile] = backupFileName
+	if fileInfo, err := os.Stat(backupPath); err == nil {
+		payload[event.EventPayloadSize] = fileInfo.Size()
+	}
+
+	// 按保留策略清理旧备份
+	backupService.applyRetention()
+
+	// 上传到异地存储
+	var destination settingModel.BackupDestination
+	if err := backupService.settingService.GetBackupDestinationSetting(&destination); err != nil {
+		return errors.New(commonModel.BACKUP_UPLOAD_FAILED + ": " + err.Error())
+	}
+	if destination.Type == "" {
+		return nil
+	}
+
+	target := map[string]any{"type": destination.Type}
+	payload[event.EventPayloadTarget] = target
+
+	store, err := backup.NewDestinationStorage(destination)
+	if err != nil {
+		return errors.New(commonModel.BACKUP_UPLOAD_FAILED + ": " + err.Error())
+	}
+
+	objectName, err := backup.UploadBackup(ctx, store, destination.PathPrefix, backupPath)
+	target["object"] = objectName
+	if err != nil {
+		return errors.New(commonModel.BACKUP_UPLOAD_FAILED + ": " + err.Error())
+	}
+
+	// 按异地保留策略清理异地旧备份，清理失败不影响本次备份结果
+	deleted, err := backup.ApplyRemoteRetention(ctx, store, destination.PathPrefix, destination.Retention)
+	if err != nil {
+		logUtil.GetLogger().
+			Error("Failed to apply remote backup retention", zap.String("error", err.Error()))
+	}
+	if len(deleted) > 0 {
+		logUtil.GetLogger().
+			Info("Pruned old remote backups", zap.Strings("backups", deleted))
 	}
 
 	return nil
 }
 
+// backupEncryption 读取备份加密设置
+func (backupService *BackupService) backupEncryption() (backup.Encryption, error) {
+	var setting settingModel.BackupEncryption
+	if err := backupService.settingService.GetBackupEncryptionSetting(&setting); err != nil {
+		return backup.Encryption{}, err
+	}
+	return backup.EncryptionFromSetting(setting), nil
+}
+
 // ExportBackup 导出备份
 func (backupService *BackupService) ExportBackup(ctx *gin.Context, userid uint) error {
 	// 鉴权
@@ -95,9 +173,12 @@ func (backupService *BackupService) ExportBackup(ctx *gin.Context, userid uint)
 
 	// 导出备份
 	// 1. 先备份
-	var backupFilePath string // 备份文件路径
+	encryption, err := backupService.backupEncryption()
+	if err != nil {
+		return err
+	}
 
-	backupFilePath, _, err = backup.ExecuteBackup()
+	backupFilePath, backupFileName, err := backup.ExecuteBackup(encryption)
 	if err != nil {
 		return err
 	}
@@ -110,9 +191,12 @@ func (backupService *BackupService) ExportBackup(ctx *gin.Context, userid uint)
 
 	// 设置响应头
 	filename := fmt.Sprintf("ech0-backup-%s.zip", time.Now().UTC().Format("2006-01-02-150405"))
+	if encryption.Enabled() {
+		filename += ".age"
+	}
 
 	// 设置响应头的顺序很重要
-	ctx.Writer.Header().Set("Content-Type", "application/zip")
+	ctx.Writer.Header().Set("Content-Type", backup.ContentType(backupFileName))
 	ctx.Writer.Header().
 		Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
 	ctx.Writer.Header().Set("Content-Length", fmt.Sprintf("%d", fileInfo.Size()))
@@ -125,6 +209,9 @@ func (backupService *BackupService) ExportBackup(ctx *gin.Context, userid uint)
 	// 使用 Gin 的内置方法，支持 Range 请求
 	ctx.File(backupFilePath)
 
+	// 按保留策略清理旧备份
+	backupService.applyRetention()
+
 	// 触发导出完成事件
 	if err := backupService.eventBus.Publish(
 		context.Background(),
@@ -143,12 +230,41 @@ func (backupService *BackupService) ExportBackup(ctx *gin.Context, userid uint)
 	return nil
 }
 
-// ImportBackup 恢复备份
-func (backupService *BackupService) ImportBackup(
+// PreviewRestore 上传并预检备份，不改动当前数据
+func (backupService *BackupService) PreviewRestore(
 	ctx *gin.Context,
 	userid uint,
 	file *multipart.FileHeader,
-) error {
+	decryption backup.Decryption,
+) (*backup.RestorePreview, error) {
+	user, err := backupService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsAdmin {
+		return nil, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	tempFilePath, err := saveUploadedBackup(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	// 预检完成后备份已解压到暂存目录，上传的文件不再需要
+	defer func() {
+		_ = os.Remove(tempFilePath)
+	}()
+
+	preview, err := backup.PrepareRestore(tempFilePath, decryption)
+	if err != nil {
+		return nil, errors.New(commonModel.SNAPSHOT_RESTORE_FAILED + ": " + err.Error())
+	}
+
+	return preview, nil
+}
+
+// CommitRestore 确认并执行预检通过的恢复
+func (backupService *BackupService) CommitRestore(userid uint, token string) error {
 	user, err := backupService.commonService.CommonGetUserByUserId(userid)
 	if err != nil {
 		return err
@@ -158,24 +274,46 @@ func (backupService *BackupService) ImportBackup(
 		return errors.New(commonModel.NO_PERMISSION_DENIED)
 	}
 
+	if err := backup.CommitRestore(token); err != nil {
+		return errors.New(commonModel.SNAPSHOT_RESTORE_FAILED + ": " + err.Error())
+	}
+
+	backupService.publishRestoreEvent()
+
+	return nil
+}
+
+// DiscardRestore 取消预检通过但未提交的恢复
+func (backupService *BackupService) DiscardRestore(userid uint, token string) error {
+	user, err := backupService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	return backup.DiscardRestore(token)
+}
+
+// saveUploadedBackup 保存上传的备份文件到临时位置 (./temp/snapshot_时间戳.zip)
+func saveUploadedBackup(ctx *gin.Context, file *multipart.FileHeader) (string, error) {
 	// 确保临时目录存在，避免容器环境中不存在 ./temp 导致上传失败
 	if err := os.MkdirAll("./temp", 0o755); err != nil {
-		return errors.New(commonModel.SNAPSHOT_UPLOAD_FAILED + ": " + err.Error())
+		return "", errors.New(commonModel.SNAPSHOT_UPLOAD_FAILED + ": " + err.Error())
 	}
 
-	// 保存上传的文件到临时位置, (./temp/snapshot_时间戳.zip)
-	timestamp := time.Now().UTC().Unix()
-	tempFilePath := fmt.Sprintf("./temp/snapshot_%d.zip", timestamp)
+	tempFilePath := fmt.Sprintf("./temp/snapshot_%d.zip", time.Now().UTC().UnixNano())
 	if err := ctx.SaveUploadedFile(file, tempFilePath); err != nil {
-		return errors.New(commonModel.SNAPSHOT_UPLOAD_FAILED + ": " + err.Error())
+		return "", errors.New(commonModel.SNAPSHOT_UPLOAD_FAILED + ": " + err.Error())
 	}
 
-	// 执行恢复
-	if err := backup.ExcuteRestoreOnline(tempFilePath, timestamp); err != nil {
-		return errors.New(commonModel.SNAPSHOT_RESTORE_FAILED + ": " + err.Error())
-	}
+	return tempFilePath, nil
+}
 
-	// 触发恢复完成事件
+// publishRestoreEvent 触发恢复完成事件
+func (backupService *BackupService) publishRestoreEvent() {
 	if err := backupService.eventBus.Publish(
 		context.Background(),
 		event.NewEvent(
@@ -188,8 +326,6 @@ func (backupService *BackupService) ImportBackup(
 		logUtil.GetLogger().
 			Error("Failed to publish system restore completed event", zap.String("error", err.Error()))
 	}
-
-	return nil
 }
 
 // SyncToLegacyTable 将 media 中的数据同步回 images 表，供回退到原版使用
@@ -240,3 +376,75 @@ func (backupService *BackupService) CleanLegacyTable(ctx context.Context, userid
 	}
 	return nil
 }
+
+// ListBackups 获取备份列表
+func (backupService *BackupService) ListBackups(userid uint) ([]backup.ArchiveInfo, error) {
+	user, err := backupService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin {
+		return nil, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	return backup.ListBackups()
+}
+
+// DownloadBackup 下载指定的备份文件
+func (backupService *BackupService) DownloadBackup(ctx *gin.Context, userid uint, name string) error {
+	user, err := backupService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	backupFilePath, err := backup.ResolveBackup(name)
+	if err != nil {
+		return err
+	}
+
+	ctx.Writer.Header().Set("Content-Type", backup.ContentType(name))
+	ctx.Writer.Header().
+		Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", name))
+	ctx.Writer.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+
+	// 使用 Gin 的内置方法，支持 Range 请求
+	ctx.File(backupFilePath)
+
+	return nil
+}
+
+// DeleteBackup 删除指定的备份文件
+func (backupService *BackupService) DeleteBackup(userid uint, name string) error {
+	user, err := backupService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	return backup.DeleteBackup(name)
+}
+
+// applyRetention 按备份计划中的保留策略清理旧备份，失败时仅记录日志
+func (backupService *BackupService) applyRetention() {
+	var schedule settingModel.BackupSchedule
+	if err := backupService.settingService.GetBackupScheduleSetting(&schedule); err != nil {
+		logUtil.GetLogger().
+			Error("Failed to get backup retention setting", zap.String("error", err.Error()))
+		return
+	}
+
+	deleted, err := backup.ApplyRetention(schedule.Retention)
+	if err != nil {
+		logUtil.GetLogger().
+			Error("Failed to apply backup retention", zap.String("error", err.Error()))
+	}
+	if len(deleted) > 0 {
+		logUtil.GetLogger().
+			Info("Pruned old backups", zap.Strings("backups", deleted))
+	}
+}
@@ -5,17 +5,41 @@ import (
 	"mime/multipart"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lin-snow/ech0/internal/backup"
 )
 
 type BackupServiceInterface interface {
 	// Backup 执行备份
 	Backup(userid uint) error
 
+	// RunBackup 执行完整的备份流程（本地备份、清理旧备份、上传异地存储并发布备份事件），不做鉴权
+	RunBackup(ctx context.Context, info string) error
+
 	// ExportBackup 导出备份
 	ExportBackup(ctx *gin.Context, userid uint) error
 
-	// 恢复备份
-	ImportBackup(ctx *gin.Context, userid uint, file *multipart.FileHeader) error
+	// PreviewRestore 上传并预检备份，返回与当前数据的对比，不改动当前数据
+	PreviewRestore(
+		ctx *gin.Context,
+		userid uint,
+		file *multipart.FileHeader,
+		decryption backup.Decryption,
+	) (*backup.RestorePreview, error)
+
+	// CommitRestore 确认并执行预检通过的恢复
+	CommitRestore(userid uint, token string) error
+
+	// DiscardRestore 取消预检通过但未提交的恢复
+	DiscardRestore(userid uint, token string) error
+
+	// ListBackups 获取备份列表
+	ListBackups(userid uint) ([]backup.ArchiveInfo, error)
+
+	// DownloadBackup 下载指定的备份文件
+	DownloadBackup(ctx *gin.Context, userid uint, name string) error
+
+	// DeleteBackup 删除指定的备份文件
+	DeleteBackup(userid uint, name string) error
 
 	// 数据库维护兼容性 (原版)
 	SyncToLegacyTable(ctx context.Context, userid uint) error
@@ -0,0 +1,441 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lin-snow/ech0/internal/config"
+	"github.com/lin-snow/ech0/internal/event"
+	authModel "github.com/lin-snow/ech0/internal/model/auth"
+	model "github.com/lin-snow/ech0/internal/model/comment"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	settingModel "github.com/lin-snow/ech0/internal/model/setting"
+	userModel "github.com/lin-snow/ech0/internal/model/user"
+	repository "github.com/lin-snow/ech0/internal/repository/comment"
+	echoRepository "github.com/lin-snow/ech0/internal/repository/echo"
+	keyvalueRepository "github.com/lin-snow/ech0/internal/repository/keyvalue"
+	commonService "github.com/lin-snow/ech0/internal/service/common"
+	"github.com/lin-snow/ech0/internal/transaction"
+	jsonUtil "github.com/lin-snow/ech0/internal/util/json"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+)
+
+type CommentService struct {
+	txManager          transaction.TransactionManager                 // 事务管理器
+	commentRepository  repository.CommentRepositoryInterface          // 评论数据层接口
+	echoRepository     echoRepository.EchoRepositoryInterface         // Echo数据层接口
+	keyvalueRepository keyvalueRepository.KeyValueRepositoryInterface // 键值对数据层接口
+	commonService      commonService.CommonServiceInterface           // 公共服务接口
+	eventBus           event.IEventBus                                // 事件总线
+}
+
+func NewCommentService(
+	tm transaction.TransactionManager,
+	commentRepository repository.CommentRepositoryInterface,
+	echoRepository echoRepository.EchoRepositoryInterface,
+	keyvalueRepository keyvalueRepository.KeyValueRepositoryInterface,
+	commonService commonService.CommonServiceInterface,
+	ebProvider func() event.IEventBus,
+) CommentServiceInterface {
+	return &CommentService{
+		txManager:          tm,
+		commentRepository:  commentRepository,
+		echoRepository:     echoRepository,
+		keyvalueRepository: keyvalueRepository,
+		commonService:      commonService,
+		eventBus:           ebProvider(),
+	}
+}
+
+// GetEchoComments 获取 Echo 下已通过审核的评论，按楼层组织
+func (commentService *CommentService) GetEchoComments(userid, echoID uint) ([]model.Comment, error) {
+	isAdmin, err := commentService.isAdmin(userid)
+	if err != nil {
+		return nil, err
+	}
+	if err := commentService.checkEchoVisible(echoID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	comments, err := commentService.commentRepository.GetApprovedCommentsByEchoID(echoID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCommentTree(comments), nil
+}
+
+// CreateComment 发表评论，登录用户与访客均可使用
+func (commentService *CommentService) CreateComment(
+	userid, echoID uint,
+	dto *model.CreateCommentDto,
+	client model.ClientInfo,
+) (model.Comment, error) {
+	setting := commentService.getCommentSetting()
+	if !setting.EnableComment || setting.Provider != string(commonModel.ECH0) {
+		return model.Comment{}, errors.New(commonModel.COMMENT_DISABLED)
+	}
+
+	comment := model.Comment{
+		EchoID:    echoID,
+		ParentID:  dto.ParentID,
+		Source:    model.CommentSourceLocal,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+
+	// 登录用户使用账号信息，管理员的评论直接通过审核
+	var user *userModel.User
+	if userid != authModel.NO_USER_LOGINED {
+		u, err := commentService.commonService.CommonGetUserByUserId(userid)
+		if err != nil {
+			return model.Comment{}, err
+		}
+		user = &u
+		comment.UserID = u.ID
+		comment.AuthorName = u.Username
+		comment.AuthorAvatar = u.Avatar
+	} else {
+		comment.AuthorName = strings.TrimSpace(dto.AuthorName)
+		comment.AuthorEmail = strings.TrimSpace(dto.AuthorEmail)
+		comment.AuthorURL = strings.TrimSpace(dto.AuthorURL)
+		if err := validateGuestAuthor(&comment); err != nil {
+			return model.Comment{}, err
+		}
+	}
+
+	comment.Content = strings.TrimSpace(dto.Content)
+	if comment.Content == "" {
+		return model.Comment{}, errors.New(commonModel.COMMENT_CONTENT_EMPTY)
+	}
+	if utf8.RuneCountInString(comment.Content) > model.MaxCommentLength {
+		return model.Comment{}, errors.New(commonModel.COMMENT_CONTENT_TOO_LONG)
+	}
+
+	isAdmin := user != nil && user.IsAdmin
+	if err := commentService.checkEchoVisible(echoID, isAdmin); err != nil {
+		return model.Comment{}, err
+	}
+
+	comment.Status = model.CommentStatusPending
+	if isAdmin || setting.AutoApprove {
+		comment.Status = model.CommentStatusApproved
+	}
+
+	if err := commentService.txManager.Run(func(ctx context.Context) error {
+		// 只能回复同一 Echo 下已通过审核的评论
+		if comment.ParentID != 0 {
+			parent, err := commentService.commentRepository.GetCommentByID(ctx, comment.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent == nil || parent.EchoID != echoID || parent.Status != model.CommentStatusApproved {
+				return errors.New(commonModel.COMMENT_PARENT_INVALID)
+			}
+		}
+
+		return commentService.commentRepository.CreateComment(ctx, &comment)
+	}); err != nil {
+		return model.Comment{}, err
+	}
+
+	commentService.publishCommentCreatedEvent(comment, user)
+
+	return comment.Public(), nil
+}
+
+// GetComments 按条件分页获取评论，仅管理员可用
+func (commentService *CommentService) GetComments(
+	userid uint,
+	dto model.CommentQueryDto,
+) (commonModel.PageQueryResult[[]model.Comment], error) {
+	if err := commentService.checkAdmin(userid); err != nil {
+		return commonModel.PageQueryResult[[]model.Comment]{}, err
+	}
+
+	if dto.Status != "" && !model.IsValidCommentStatus(dto.Status) {
+		return commonModel.PageQueryResult[[]model.Comment]{}, errors.New(commonModel.COMMENT_STATUS_INVALID)
+	}
+	if dto.Page < 1 {
+		dto.Page = 1
+	}
+	if dto.PageSize < 1 || dto.PageSize > 100 {
+		dto.PageSize = 20
+	}
+
+	comments, total, err := commentService.commentRepository.GetComments(dto)
+	if err != nil {
+		return commonModel.PageQueryResult[[]model.Comment]{}, err
+	}
+
+	return commonModel.PageQueryResult[[]model.Comment]{
+		Items: comments,
+		Total: total,
+	}, nil
+}
+
+// UpdateCommentStatus 审核评论，仅管理员可用
+func (commentService *CommentService) UpdateCommentStatus(userid, id uint, status string) error {
+	if err := commentService.checkAdmin(userid); err != nil {
+		return err
+	}
+	if !model.IsValidCommentStatus(status) {
+		return errors.New(commonModel.COMMENT_STATUS_INVALID)
+	}
+
+	return commentService.txManager.Run(func(ctx context.Context) error {
+		comment, err := commentService.commentRepository.GetCommentByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if comment == nil {
+			return errors.New(commonModel.COMMENT_NOT_FOUND)
+		}
+
+		return commentService.commentRepository.UpdateCommentStatus(ctx, id, status)
+	})
+}
+
+// DeleteComment 删除评论及其全部子回复，仅管理员可用
+func (commentService *CommentService) DeleteComment(userid, id uint) error {
+	if err := commentService.checkAdmin(userid); err != nil {
+		return err
+	}
+
+	return commentService.txManager.Run(func(ctx context.Context) error {
+		comment, err := commentService.commentRepository.GetCommentByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if comment == nil {
+			return errors.New(commonModel.COMMENT_NOT_FOUND)
+		}
+
+		_, err = commentService.commentRepository.DeleteCommentTree(ctx, id)
+		return err
+	})
+}
+
+// GetCommentByRemoteID 根据远端 Object ID 获取评论，不存在时返回 nil
+func (commentService *CommentService) GetCommentByRemoteID(remoteID string) (*model.Comment, error) {
+	return commentService.commentRepository.GetCommentByRemoteID(context.Background(), remoteID)
+}
+
+// SaveRemoteComment 保存联邦网络中对 Echo 的回复，已存在时更新内容，未启用内置评论时直接丢弃
+func (commentService *CommentService) SaveRemoteComment(comment *model.Comment) error {
+	if comment == nil || comment.RemoteID == "" {
+		return errors.New(commonModel.INVALID_PARAMS)
+	}
+
+	// 未启用内置评论时管理员看不到审核队列，回复不再保存以免无人处理地堆积
+	setting := commentService.getCommentSetting()
+	if !setting.EnableComment || setting.Provider != string(commonModel.ECH0) {
+		return nil
+	}
+
+	autoApprove := setting.AutoApprove
+	comment.Source = model.CommentSourceFediverse
+	comment.Status = model.CommentStatusPending
+	if autoApprove {
+		comment.Status = model.CommentStatusApproved
+	}
+	if utf8.RuneCountInString(comment.Content) > model.MaxCommentLength {
+		comment.Content = string([]rune(comment.Content)[:model.MaxCommentLength])
+	}
+
+	created := false
+	if err := commentService.txManager.Run(func(ctx context.Context) error {
+		existing, err := commentService.commentRepository.GetCommentByRemoteID(ctx, comment.RemoteID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			// 只允许原作者更新回复
+			if existing.ActorURL != comment.ActorURL {
+				return nil
+			}
+			// 内容变化且未开启免审核时需重新审核，避免通过审核后再被改成垃圾内容
+			comment.ID = existing.ID
+			comment.Status = existing.Status
+			if existing.Content != comment.Content && !autoApprove {
+				comment.Status = model.CommentStatusPending
+			}
+			return commentService.commentRepository.UpdateRemoteComment(ctx, comment)
+		}
+
+		// 回复的是远端回复时挂到对应楼层下，找不到则作为直接评论
+		if comment.ParentID != 0 {
+			parent, err := commentService.commentRepository.GetCommentByID(ctx, comment.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent == nil || parent.EchoID != comment.EchoID {
+				comment.ParentID = 0
+			}
+		}
+
+		created = true
+		return commentService.commentRepository.CreateComment(ctx, comment)
+	}); err != nil {
+		return err
+	}
+
+	if created {
+		commentService.publishCommentCreatedEvent(*comment, nil)
+	}
+
+	return nil
+}
+
+// DeleteRemoteComment 删除远端 Actor 撤回的回复
+func (commentService *CommentService) DeleteRemoteComment(actorURL, remoteID string) error {
+	return commentService.txManager.Run(func(ctx context.Context) error {
+		comment, err := commentService.commentRepository.GetCommentByRemoteID(ctx, remoteID)
+		if err != nil {
+			return err
+		}
+		if comment == nil || comment.ActorURL != actorURL {
+			return nil
+		}
+
+		_, err = commentService.commentRepository.DeleteCommentTree(ctx, comment.ID)
+		return err
+	})
+}
+
+// DeleteRemoteCommentsByActor 删除已注销的远端 Actor 的全部回复
+func (commentService *CommentService) DeleteRemoteCommentsByActor(actorURL string) error {
+	return commentService.txManager.Run(func(ctx context.Context) error {
+		return commentService.commentRepository.DeleteCommentsByActor(ctx, actorURL)
+	})
+}
+
+// publishCommentCreatedEvent 推送新评论事件(Inbox 通知, Webhook 等)，需在事务提交后调用
+func (commentService *CommentService) publishCommentCreatedEvent(comment model.Comment, user *userModel.User) {
+	payload := event.EventPayload{
+		event.EventPayloadComment: comment,
+	}
+	if user != nil {
+		payload[event.EventPayloadUser] = *user
+	}
+
+	if err := commentService.eventBus.Publish(
+		context.Background(),
+		event.NewEvent(event.EventTypeCommentCreated, payload),
+	); err != nil {
+		// 推送失败不影响评论
+		logUtil.GetLogger().Error(err.Error())
+	}
+}
+
+// getCommentSetting 读取评论设置，数据库中不存在时使用配置文件中的默认值
+func (commentService *CommentService) getCommentSetting() settingModel.CommentSetting {
+	setting := settingModel.CommentSetting{
+		EnableComment: config.Config.Comment.EnableComment,
+		Provider:      config.Config.Comment.Provider,
+		CommentAPI:    config.Config.Comment.CommentAPI,
+	}
+
+	value, err := commentService.keyvalueRepository.GetKeyValue(commonModel.CommentSettingKey)
+	if err != nil {
+		return setting
+	}
+	if str, ok := value.(string); ok {
+		_ = jsonUtil.JSONUnmarshal([]byte(str), &setting)
+	}
+
+	return setting
+}
+
+// checkEchoVisible 检查 Echo 是否存在且对当前用户可见
+func (commentService *CommentService) checkEchoVisible(echoID uint, isAdmin bool) error {
+	echo, err := commentService.echoRepository.GetEchosById(echoID)
+	if err != nil {
+		return err
+	}
+	if echo == nil || !echo.IsPublished() {
+		return errors.New(commonModel.ECHO_NOT_FOUND)
+	}
+	if echo.Private && !isAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	return nil
+}
+
+// isAdmin 判断当前用户是否为管理员，未登录时返回 false
+func (commentService *CommentService) isAdmin(userid uint) (bool, error) {
+	if userid == authModel.NO_USER_LOGINED {
+		return false, nil
+	}
+	user, err := commentService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return false, err
+	}
+
+	return user.IsAdmin, nil
+}
+
+// checkAdmin 检查当前用户是否为管理员
+func (commentService *CommentService) checkAdmin(userid uint) error {
+	isAdmin, err := commentService.isAdmin(userid)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	return nil
+}
+
+// validateGuestAuthor 校验访客填写的昵称、邮箱与主页
+func validateGuestAuthor(comment *model.Comment) error {
+	if comment.AuthorName == "" || utf8.RuneCountInString(comment.AuthorName) > model.MaxCommentNameLength {
+		return errors.New(commonModel.COMMENT_NAME_INVALID)
+	}
+
+	if comment.AuthorEmail != "" {
+		addr, err := mail.ParseAddress(comment.AuthorEmail)
+		if err != nil || addr.Address != comment.AuthorEmail || len(comment.AuthorEmail) > 255 {
+			return errors.New(commonModel.COMMENT_EMAIL_INVALID)
+		}
+	}
+
+	if comment.AuthorURL != "" {
+		u, err := url.Parse(comment.AuthorURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			len(comment.AuthorURL) > 255 {
+			return errors.New(commonModel.COMMENT_URL_INVALID)
+		}
+	}
+
+	return nil
+}
+
+// buildCommentTree 按 ParentID 将评论组织为楼中楼，父评论不可见的回复不展示
+func buildCommentTree(comments []model.Comment) []model.Comment {
+	children := make(map[uint][]model.Comment)
+	for _, comment := range comments {
+		children[comment.ParentID] = append(children[comment.ParentID], comment.Public())
+	}
+
+	var attach func(parentID uint) []model.Comment
+	attach = func(parentID uint) []model.Comment {
+		nodes := children[parentID]
+		for i := range nodes {
+			nodes[i].Replies = attach(nodes[i].ID)
+		}
+		return nodes
+	}
+
+	roots := attach(0)
+	if roots == nil {
+		return []model.Comment{}
+	}
+	return roots
+}
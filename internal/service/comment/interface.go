@@ -0,0 +1,42 @@
+package service
+
+import (
+	model "github.com/lin-snow/ech0/internal/model/comment"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+)
+
+type CommentServiceInterface interface {
+	// GetEchoComments 获取 Echo 下已通过审核的评论，按楼层组织
+	GetEchoComments(userid, echoID uint) ([]model.Comment, error)
+
+	// CreateComment 发表评论，登录用户与访客均可使用
+	CreateComment(
+		userid, echoID uint,
+		dto *model.CreateCommentDto,
+		client model.ClientInfo,
+	) (model.Comment, error)
+
+	// GetComments 按条件分页获取评论，仅管理员可用
+	GetComments(
+		userid uint,
+		dto model.CommentQueryDto,
+	) (commonModel.PageQueryResult[[]model.Comment], error)
+
+	// UpdateCommentStatus 审核评论，仅管理员可用
+	UpdateCommentStatus(userid, id uint, status string) error
+
+	// DeleteComment 删除评论及其全部子回复，仅管理员可用
+	DeleteComment(userid, id uint) error
+
+	// GetCommentByRemoteID 根据远端 Object ID 获取评论，不存在时返回 nil
+	GetCommentByRemoteID(remoteID string) (*model.Comment, error)
+
+	// SaveRemoteComment 保存联邦网络中对 Echo 的回复，已存在时更新内容，未启用内置评论时直接丢弃
+	SaveRemoteComment(comment *model.Comment) error
+
+	// DeleteRemoteComment 删除远端 Actor 撤回的回复
+	DeleteRemoteComment(actorURL, remoteID string) error
+
+	// DeleteRemoteCommentsByActor 删除已注销的远端 Actor 的全部回复
+	DeleteRemoteCommentsByActor(actorURL string) error
+}
@@ -181,6 +181,11 @@ func (commonService *CommonService) DeleteImage(userid uint, url, source, object
 			return fmt.Errorf("路径验证失败: %w", err)
 		}
 
+		// 删除图片的响应式尺寸
+		if prefix == "/images/" {
+			_ = storageUtil.DeleteImageVariants(mediaPath)
+		}
+
 		// 删除媒体文件
 		return storageUtil.DeleteFileFromLocal(mediaPath)
 	case echoModel.MediaSourceURL:
@@ -220,6 +225,11 @@ func (commonService *CommonService) DeleteImage(userid uint, url, source, object
 			return fmt.Errorf("路径验证失败: %w", err)
 		}
 
+		// 删除图片的响应式尺寸
+		if prefix == "/images/" {
+			_ = storageUtil.DeleteImageVariants(mediaPath)
+		}
+
 		// 删除媒体文件
 		return storageUtil.DeleteFileFromLocal(mediaPath)
 	}
@@ -255,6 +265,11 @@ func (commonService *CommonService) DirectDeleteImage(url, source, object_key st
 			return fmt.Errorf("路径验证失败: %w", err)
 		}
 
+		// 删除图片的响应式尺寸
+		if prefix == "/images/" {
+			_ = storageUtil.DeleteImageVariants(mediaPath)
+		}
+
 		// 删除媒体文件
 		return storageUtil.DeleteFileFromLocal(mediaPath)
 	case echoModel.MediaSourceURL:
@@ -292,6 +307,11 @@ func (commonService *CommonService) DirectDeleteImage(url, source, object_key st
 			return fmt.Errorf("路径验证失败: %w", err)
 		}
 
+		// 删除图片的响应式尺寸
+		if prefix == "/images/" {
+			_ = storageUtil.DeleteImageVariants(mediaPath)
+		}
+
 		// 删除媒体文件
 		return storageUtil.DeleteFileFromLocal(mediaPath)
 	}
@@ -847,6 +867,46 @@ func (CommonService *CommonService) GetS3ObjectURL(
 	return fmt.Sprintf("%s/%s", baseURL, objectKey), nil
 }
 
+// ReleaseMediaFiles 将不再被引用的媒体文件登记为临时文件，交由临时文件清理任务删除
+// 释放的文件最后访问时间记为 0，下一次清理时即被删除；直链媒体无需处理
+func (commonService *CommonService) ReleaseMediaFiles(ctx context.Context, media []echoModel.Media) error {
+	now := time.Now().UTC().Unix()
+	for _, m := range media {
+		tempFile := commonModel.TempFile{
+			FileName:       m.MediaURL,
+			FileType:       m.MediaType,
+			Deleted:        false,
+			CreatedAt:      now,
+			LastAccessedAt: 0,
+		}
+
+		switch m.MediaSource {
+		case echoModel.MediaSourceLocal:
+			if m.MediaURL == "" {
+				continue
+			}
+			tempFile.Storage = string(commonModel.LOCAL_FILE)
+		case echoModel.MediaSourceS3:
+			if m.ObjectKey == "" {
+				continue
+			}
+			tempFile.Storage = string(commonModel.S3_FILE)
+			tempFile.ObjectKey = m.ObjectKey
+			if _, s3setting, err := commonService.GetS3Client(); err == nil {
+				tempFile.Bucket = s3setting.BucketName
+			}
+		default:
+			continue
+		}
+
+		if err := commonService.commonRepository.SaveTempFile(ctx, tempFile); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // CleanupTempFiles 清理过期的临时文件
 func (commonService *CommonService) CleanupTempFiles() error {
 	// 获取所有未删除的临时文件
@@ -864,8 +924,13 @@ func (commonService *CommonService) CleanupTempFiles() error {
 			// 删除文件
 			switch file.Storage {
 			case string(commonModel.LOCAL_FILE):
-				// TODO: 删除本地文件
-
+				// 本地文件以访问 URL 作为文件名，删除时一并清理图片的响应式尺寸
+				if err := commonService.DirectDeleteImage(file.FileName, echoModel.MediaSourceLocal, ""); err != nil {
+					// 记录日志，继续处理下一个文件
+					logUtil.GetLogger().Warn("Failed to delete local temp file",
+						zap.String("file", file.FileName),
+						zap.String("error", err.Error()))
+				}
 			case string(commonModel.S3_FILE):
 				// 获取 S3 客户端
 				cli, _, err := commonService.GetS3Client()
@@ -880,7 +945,9 @@ func (commonService *CommonService) CleanupTempFiles() error {
 				// 删除 S3 上的文件
 				if err := cli.DeleteObject(context.Background(), file.ObjectKey); err != nil {
 					// 记录日志，继续处理下一个文件
-					fmt.Printf("删除S3临时文件失败: %s, 错误: %v\n", file.ObjectKey, err)
+					logUtil.GetLogger().Warn("Failed to delete S3 temp file",
+						zap.String("object_key", file.ObjectKey),
+						zap.String("error", err.Error()))
 				}
 			default:
 				// 未知存储类型，忽略
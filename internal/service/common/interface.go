@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"mime/multipart"
 
 	"github.com/gin-gonic/gin"
@@ -64,6 +65,9 @@ type CommonServiceInterface interface {
 	// GetS3ObjectURL 获取 S3 对象的访问 URL
 	GetS3ObjectURL(s3setting settingModel.S3Setting, objectKey string) (string, error)
 
+	// ReleaseMediaFiles 将不再被引用的媒体文件交由临时文件清理任务删除
+	ReleaseMediaFiles(ctx context.Context, media []echoModel.Media) error
+
 	// CleanupTempFiles 清理过期的临时文件
 	CleanupTempFiles() error
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/lin-snow/ech0/internal/cache"
 	model "github.com/lin-snow/ech0/internal/model/metric"
 	"github.com/lin-snow/ech0/internal/monitor"
 	commonService "github.com/lin-snow/ech0/internal/service/common"
@@ -15,20 +16,40 @@ import (
 type DashboardService struct {
 	monitor       *monitor.Monitor
 	commonService commonService.CommonServiceInterface
+	imageCache    *cache.DiskCache
 }
 
 func NewDashboardService(
 	monitor *monitor.Monitor,
 	commonService commonService.CommonServiceInterface,
+	imageCache *cache.DiskCache,
 ) DashboardServiceInterface {
 	return &DashboardService{
 		monitor:       monitor,
 		commonService: commonService,
+		imageCache:    imageCache,
 	}
 }
 
 func (dashboardService *DashboardService) GetMetrics() (model.Metrics, error) {
-	return dashboardService.monitor.GetMetrics(), nil
+	return dashboardService.collectMetrics(), nil
+}
+
+// collectMetrics 合并系统指标与图片缓存统计，缓存统计实时读取
+func (dashboardService *DashboardService) collectMetrics() model.Metrics {
+	metrics := dashboardService.monitor.GetMetrics()
+
+	stats := dashboardService.imageCache.Stats()
+	metrics.ImageCache = model.ImageCacheMetric{
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		HitRate:   stats.HitRate() * 100,
+		Evictions: stats.Evictions,
+		Entries:   stats.Entries,
+		Size:      uint64(stats.Size),
+		MaxSize:   uint64(stats.MaxSize),
+	}
+	return metrics
 }
 
 func (s *DashboardService) WSSubsribeMetrics(w http.ResponseWriter, r *http.Request) error {
@@ -50,7 +71,7 @@ func (s *DashboardService) WSSubsribeMetrics(w http.ResponseWriter, r *http.Requ
 		defer func() { _ = conn.Close() }()
 
 		for {
-			rawMetrics := s.monitor.GetMetrics()
+			rawMetrics := s.collectMetrics()
 			formatted := fmtUtil.FormatMetrics(&rawMetrics)
 
 			resp := struct {
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/lin-snow/ech0/internal/event"
 	authModel "github.com/lin-snow/ech0/internal/model/auth"
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	model "github.com/lin-snow/ech0/internal/model/echo"
+	userModel "github.com/lin-snow/ech0/internal/model/user"
 	commonRepository "github.com/lin-snow/ech0/internal/repository/common"
 	repository "github.com/lin-snow/ech0/internal/repository/echo"
 	keyvalueRepository "github.com/lin-snow/ech0/internal/repository/keyvalue"
@@ -64,6 +66,11 @@ func (echoService *EchoService) PostEcho(userid uint, newEcho *model.Echo) error
 		return errors.New(commonModel.NO_PERMISSION_DENIED)
 	}
 
+	// 检查发布状态
+	if err := normalizeEchoStatus(newEcho); err != nil {
+		return err
+	}
+
 	// 检查图片布局
 	layout := strings.TrimSpace(newEcho.Layout)
 	if layout == "" || (layout != model.LayoutWaterfall &&
@@ -159,26 +166,58 @@ func (echoService *EchoService) PostEcho(userid uint, newEcho *model.Echo) error
 		return err
 	}
 
+	// 草稿与定时发布的Echo在发布时才推送
+	if !newEcho.IsPublished() {
+		return nil
+	}
+
 	// 事务提交成功后再推送，确保已拿到持久化 ID
-	savedEcho, fetchErr := echoService.echoRepository.GetEchosById(newEcho.ID)
-	if fetchErr != nil {
-		return fetchErr
-	}
-	if savedEcho != nil {
-		// 推送事件(Webhook, Fediverse, Agent等)
-		if pubErr := echoService.eventBus.Publish(
-			context.Background(),
-			event.NewEvent(
-				event.EventTypeEchoCreated,
-				event.EventPayload{
-					event.EventPayloadEcho: *savedEcho,
-					event.EventPayloadUser: user,
-				},
-			),
-		); pubErr != nil {
-			// 推送失败不影响发布
-			logUtil.GetLogger().Error(pubErr.Error())
+	return echoService.publishEchoCreatedEvent(newEcho.ID, user)
+}
+
+// publishEchoCreatedEvent 推送 Echo 发布事件(Webhook, Fediverse, Agent等)，需在事务提交后调用
+func (echoService *EchoService) publishEchoCreatedEvent(id uint, user userModel.User) error {
+	savedEcho, err := echoService.echoRepository.GetEchosById(id)
+	if err != nil {
+		return err
+	}
+	if savedEcho == nil {
+		return nil
+	}
+
+	if pubErr := echoService.eventBus.Publish(
+		context.Background(),
+		event.NewEvent(
+			event.EventTypeEchoCreated,
+			event.EventPayload{
+				event.EventPayloadEcho: *savedEcho,
+				event.EventPayloadUser: user,
+			},
+		),
+	); pubErr != nil {
+		// 推送失败不影响发布
+		logUtil.GetLogger().Error(pubErr.Error())
+	}
+
+	return nil
+}
+
+// normalizeEchoStatus 校验并补全发布状态，定时发布需指定晚于当前的发布时间
+func normalizeEchoStatus(echo *model.Echo) error {
+	switch echo.Status {
+	case "", model.EchoStatusPublished:
+		echo.Status = model.EchoStatusPublished
+		echo.PublishAt = nil
+	case model.EchoStatusDraft:
+		echo.PublishAt = nil
+	case model.EchoStatusScheduled:
+		if echo.PublishAt == nil || !echo.PublishAt.After(time.Now()) {
+			return errors.New(commonModel.ECHO_PUBLISH_AT_INVALID)
 		}
+		publishAt := echo.PublishAt.UTC()
+		echo.PublishAt = &publishAt
+	default:
+		return errors.New(commonModel.ECHO_STATUS_INVALID)
 	}
 
 	return nil
@@ -243,8 +282,8 @@ func (echoService *EchoService) DeleteEchoById(userid, id uint) error {
 		return errors.New(commonModel.NO_PERMISSION_DENIED)
 	}
 
+	var deletedEcho model.Echo
 	if err := echoService.txManager.Run(func(ctx context.Context) error {
-		// 检查该Echo是否存在图片
 		echo, err := echoService.echoRepository.GetEchosById(id)
 		if err != nil {
 			return err
@@ -252,29 +291,26 @@ func (echoService *EchoService) DeleteEchoById(userid, id uint) error {
 		if echo == nil {
 			return errors.New(commonModel.ECHO_NOT_FOUND)
 		}
+		deletedEcho = *echo
 
-		// 删除Echo中的媒体
-		if len(echo.Media) > 0 {
-			for _, m := range echo.Media {
-				if err := echoService.commonService.DirectDeleteImage(m.MediaURL, m.MediaSource, m.ObjectKey); err != nil {
-					return err
-				}
-			}
-		}
-
-		// 删除Echo
+		// 移入回收站，媒体文件保留到永久删除时再释放
 		return echoService.echoRepository.DeleteEchoById(ctx, id)
 	}); err != nil {
 		return err
 	}
 
+	// 草稿与定时发布的Echo从未对外发布，无需推送删除
+	if !deletedEcho.IsPublished() {
+		return nil
+	}
+
 	// 删除成功后推送事件
 	if pubErr := echoService.eventBus.Publish(
 		context.Background(),
 		event.NewEvent(
 			event.EventTypeEchoDeleted,
 			event.EventPayload{
-				event.EventPayloadEcho: model.Echo{ID: id},
+				event.EventPayloadEcho: deletedEcho,
 				event.EventPayloadUser: user,
 			},
 		),
@@ -317,6 +353,11 @@ func (echoService *EchoService) GetTodayEchos(userid uint, timezone string) ([]m
 
 // UpdateEcho 更新指定ID的Echo
 func (echoService *EchoService) UpdateEcho(userid uint, echo *model.Echo) error {
+	return echoService.updateEcho(userid, echo, false)
+}
+
+// updateEcho 更新 Echo，keepStatus 为 true 时沿用现有的发布状态与定时发布时间，不做状态校验
+func (echoService *EchoService) updateEcho(userid uint, echo *model.Echo, keepStatus bool) error {
 	user, err := echoService.commonService.CommonGetUserByUserId(userid)
 	if err != nil {
 		return err
@@ -325,6 +366,27 @@ func (echoService *EchoService) UpdateEcho(userid uint, echo *model.Echo) error
 		return errors.New(commonModel.NO_PERMISSION_DENIED)
 	}
 
+	existing, err := echoService.echoRepository.GetEchosById(echo.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return errors.New(commonModel.ECHO_NOT_FOUND)
+	}
+
+	// 检查发布状态，已发布的Echo不能撤回为草稿或定时发布
+	if keepStatus {
+		// 沿用现有状态时跳过定时发布时间校验，发布时间已过但尚未被调度发布的 Echo 也可以更新
+		echo.Status = existing.Status
+		echo.PublishAt = existing.PublishAt
+	} else if err := normalizeEchoStatus(echo); err != nil {
+		return err
+	}
+	if existing.IsPublished() && !echo.IsPublished() {
+		return errors.New(commonModel.ECHO_ALREADY_PUBLISHED)
+	}
+	publishNow := !existing.IsPublished() && echo.IsPublished()
+
 	// 检查图片布局
 	layout := strings.TrimSpace(echo.Layout)
 	if layout == "" || (layout != model.LayoutWaterfall &&
@@ -395,11 +457,30 @@ func (echoService *EchoService) UpdateEcho(userid uint, echo *model.Echo) error
 			}
 		}
 
+		// 保存被覆盖的版本，内容没有变化时（如重复的自动保存）不保存
+		previous := newEchoRevision(existing)
+		if revisionText(previous) != revisionText(newEchoRevision(echo)) {
+			if err := echoService.echoRepository.CreateEchoRevision(ctx, &previous); err != nil {
+				return err
+			}
+		}
+
 		// 更新Echo（保留现有媒体，只新增/删除变化的媒体）
 		if err := echoService.echoRepository.UpdateEcho(ctx, echo); err != nil {
 			return err
 		}
 
+		// 更新草稿或定时发布的状态，立即发布时以当前时间作为发布时间
+		if publishNow {
+			if _, err := echoService.echoRepository.PublishEcho(ctx, echo.ID, time.Now().UTC()); err != nil {
+				return err
+			}
+		} else if !existing.IsPublished() {
+			if err := echoService.echoRepository.UpdateEchoStatus(ctx, echo.ID, echo.Status, echo.PublishAt); err != nil {
+				return err
+			}
+		}
+
 		// 处理新媒体中的实况照片关联（根据 live_pair_id 建立关联）
 		if len(newMediaIndexes) > 0 {
 			// 提取新媒体
@@ -435,14 +516,23 @@ func (echoService *EchoService) UpdateEcho(userid uint, echo *model.Echo) error
 		return err
 	}
 
+	// 草稿或定时发布的Echo转为发布时推送发布事件，仍未发布时不推送
+	if publishNow {
+		return echoService.publishEchoCreatedEvent(echo.ID, user)
+	}
+	if !echo.IsPublished() {
+		return nil
+	}
+
 	// 更新成功后推送事件
 	if pubErr := echoService.eventBus.Publish(
 		context.Background(),
 		event.NewEvent(
 			event.EventTypeEchoUpdated,
 			event.EventPayload{
-				event.EventPayloadEcho: *echo,
-				event.EventPayloadUser: user,
+				event.EventPayloadEcho:       *echo,
+				event.EventPayloadUser:       user,
+				event.EventPayloadWasPrivate: existing.Private,
 			},
 		),
 	); pubErr != nil {
@@ -453,13 +543,6 @@ func (echoService *EchoService) UpdateEcho(userid uint, echo *model.Echo) error
 	return nil
 }
 
-// LikeEcho 点赞指定ID的Echo
-func (echoService *EchoService) LikeEcho(id uint) error {
-	return echoService.txManager.Run(func(ctx context.Context) error {
-		return echoService.echoRepository.LikeEcho(ctx, id)
-	})
-}
-
 // GetEchoById 获取指定 ID 的 Echo
 func (echoService *EchoService) GetEchoById(userId, id uint) (*model.Echo, error) {
 	var echo *model.Echo
@@ -474,6 +557,21 @@ func (echoService *EchoService) GetEchoById(userId, id uint) (*model.Echo, error
 		return nil, errors.New(commonModel.ECHO_NOT_FOUND)
 	}
 
+	// 草稿与定时发布的Echo仅管理员可见
+	if !echo.IsPublished() {
+		if userId == authModel.NO_USER_LOGINED {
+			return nil, errors.New(commonModel.ECHO_NOT_FOUND)
+		}
+		user, err := echoService.commonService.CommonGetUserByUserId(userId)
+		if err != nil {
+			return nil, err
+		}
+		if !user.IsAdmin {
+			return nil, errors.New(commonModel.ECHO_NOT_FOUND)
+		}
+		return echo, nil
+	}
+
 	// 如果没有登录用户，则不允许获取私密Echo
 	if userId == authModel.NO_USER_LOGINED {
 		// 如果Echo是私密的，则不允许获取
@@ -504,6 +602,61 @@ func (echoService *EchoService) GetEchoById(userId, id uint) (*model.Echo, error
 	return echo, nil
 }
 
+// GetUnpublishedEchos 获取草稿与定时发布的 Echo 列表
+func (echoService *EchoService) GetUnpublishedEchos(userid uint) ([]model.Echo, error) {
+	user, err := echoService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin {
+		return nil, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	return echoService.echoRepository.GetUnpublishedEchos()
+}
+
+// PublishScheduledEchos 发布已到发布时间的定时 Echo，并推送发布事件
+func (echoService *EchoService) PublishScheduledEchos() error {
+	dueEchos, err := echoService.echoRepository.GetDueScheduledEchos(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	for _, echo := range dueEchos {
+		// 以计划的发布时间作为创建时间
+		publishedAt := time.Now().UTC()
+		if echo.PublishAt != nil {
+			publishedAt = echo.PublishAt.UTC()
+		}
+
+		var published bool
+		if err := echoService.txManager.Run(func(ctx context.Context) error {
+			var err error
+			published, err = echoService.echoRepository.PublishEcho(ctx, echo.ID, publishedAt)
+			return err
+		}); err != nil {
+			logUtil.GetLogger().Error("Failed to publish scheduled echo", zap.Uint("echoID", echo.ID), zap.Error(err))
+			continue
+		}
+		// 已被其他请求发布
+		if !published {
+			continue
+		}
+
+		user, err := echoService.commonService.CommonGetUserByUserId(echo.UserID)
+		if err != nil {
+			logUtil.GetLogger().Error("Failed to get user of scheduled echo", zap.Uint("echoID", echo.ID), zap.Error(err))
+			continue
+		}
+		if err := echoService.publishEchoCreatedEvent(echo.ID, user); err != nil {
+			logUtil.GetLogger().Error("Failed to push scheduled echo", zap.Uint("echoID", echo.ID), zap.Error(err))
+		}
+		logUtil.GetLogger().Info("Published scheduled echo", zap.Uint("echoID", echo.ID))
+	}
+
+	return nil
+}
+
 // GetAllTags 获取所有标签
 func (echoService *EchoService) GetAllTags() ([]model.Tag, error) {
 	tags, err := echoService.echoRepository.GetAllTags()
@@ -667,3 +820,50 @@ func (echoService *EchoService) GetEchosByDate(
 		Total: total,
 	}, nil
 }
+
+// SearchEchos 全文搜索 Echo，支持标签、日期范围、媒体与扩展类型过滤
+func (echoService *EchoService) SearchEchos(
+	userId uint,
+	searchDto model.EchoSearchDto,
+) (commonModel.PageQueryResult[[]model.EchoSearchResult], error) {
+	if searchDto.Page < 1 {
+		searchDto.Page = 1
+	}
+	if searchDto.PageSize < 1 || searchDto.PageSize > 100 {
+		searchDto.PageSize = 10
+	}
+	searchDto.Query = strings.TrimSpace(searchDto.Query)
+	searchDto.Tag = strings.TrimSpace(searchDto.Tag)
+
+	// 校验日期格式，避免无效日期导致静默的空结果
+	for _, date := range []string{searchDto.StartDate, searchDto.EndDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return commonModel.PageQueryResult[[]model.EchoSearchResult]{}, errors.New(
+				commonModel.INVALID_QUERY_PARAMS,
+			)
+		}
+	}
+
+	// 管理员登陆则支持查看隐私数据，否则不允许
+	showPrivate := false
+	if userId != authModel.NO_USER_LOGINED {
+		user, err := echoService.commonService.CommonGetUserByUserId(userId)
+		if err != nil {
+			return commonModel.PageQueryResult[[]model.EchoSearchResult]{}, err
+		}
+		showPrivate = user.IsAdmin
+	}
+
+	results, total, err := echoService.echoRepository.SearchEchos(searchDto, showPrivate)
+	if err != nil {
+		return commonModel.PageQueryResult[[]model.EchoSearchResult]{}, err
+	}
+
+	return commonModel.PageQueryResult[[]model.EchoSearchResult]{
+		Items: results,
+		Total: total,
+	}, nil
+}
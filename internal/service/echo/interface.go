@@ -1,6 +1,9 @@
 package service
 
 import (
+	"context"
+	"time"
+
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	model "github.com/lin-snow/ech0/internal/model/echo"
 )
@@ -15,7 +18,7 @@ type EchoServiceInterface interface {
 		pageQueryDto commonModel.PageQueryDto,
 	) (commonModel.PageQueryResult[[]model.Echo], error)
 
-	// DeleteEchoById 删除指定ID的Echo
+	// DeleteEchoById 将指定ID的Echo移入回收站
 	DeleteEchoById(userid, id uint) error
 
 	// GetTodayEchos 获取今天的Echo列表
@@ -24,12 +27,54 @@ type EchoServiceInterface interface {
 	// UpdateEcho 更新指定ID的Echo
 	UpdateEcho(userid uint, echo *model.Echo) error
 
-	// LikeEcho 点赞指定ID的Echo
-	LikeEcho(id uint) error
+	// LikeEcho 以指定身份为Echo点赞
+	LikeEcho(id uint, identity model.LikeIdentity) (model.EchoLikeResult, error)
+
+	// UnlikeEcho 取消指定身份对Echo的点赞
+	UnlikeEcho(id uint, identity model.LikeIdentity) (model.EchoLikeResult, error)
+
+	// GetEchoLikes 分页获取点赞记录
+	GetEchoLikes(userid uint, dto model.EchoLikeQueryDto) (commonModel.PageQueryResult[[]model.EchoLike], error)
+
+	// CleanupEchoLikes 按条件批量清理点赞记录
+	CleanupEchoLikes(userid uint, dto model.EchoLikeCleanupDto) (int64, error)
 
 	// GetEchoById 获取指定 ID 的 Echo
 	GetEchoById(userId, id uint) (*model.Echo, error)
 
+	// GetTrashedEchos 获取回收站中的 Echo 列表
+	GetTrashedEchos(userid uint) ([]model.Echo, error)
+
+	// RestoreTrashedEcho 将 Echo 从回收站恢复
+	RestoreTrashedEcho(userid, id uint) (*model.Echo, error)
+
+	// PurgeTrashedEcho 永久删除回收站中的 Echo
+	PurgeTrashedEcho(userid, id uint) error
+
+	// EmptyTrash 清空回收站
+	EmptyTrash(userid uint) error
+
+	// PurgeExpiredTrash 永久删除在回收站中超过保留期限的 Echo
+	PurgeExpiredTrash(retention time.Duration) error
+
+	// GetUnpublishedEchos 获取草稿与定时发布的 Echo 列表
+	GetUnpublishedEchos(userid uint) ([]model.Echo, error)
+
+	// PublishScheduledEchos 发布已到发布时间的定时 Echo，并推送发布事件
+	PublishScheduledEchos() error
+
+	// GetEchoRevisions 获取 Echo 的历史版本列表
+	GetEchoRevisions(userid, echoID uint) ([]model.EchoRevision, error)
+
+	// DiffEchoRevisions 比较 Echo 的两个版本，to 为 0 时与当前版本比较
+	DiffEchoRevisions(userid, echoID, from, to uint) (model.EchoRevisionDiff, error)
+
+	// RestoreEchoRevision 将 Echo 恢复到指定的历史版本
+	RestoreEchoRevision(userid, echoID, revisionID uint) (*model.Echo, error)
+
+	// ProcessEchoTags 将 Echo 的标签替换为已持久化的标签并更新使用计数，需在事务中调用
+	ProcessEchoTags(ctx context.Context, echo *model.Echo) error
+
 	// GetAllTags 获取所有标签
 	GetAllTags() ([]model.Tag, error)
 
@@ -47,4 +92,10 @@ type EchoServiceInterface interface {
 		startDate, endDate string,
 		pageQueryDto commonModel.PageQueryDto,
 	) (commonModel.PageQueryResult[[]model.Echo], error)
+
+	// SearchEchos 全文搜索 Echo
+	SearchEchos(
+		userId uint,
+		searchDto model.EchoSearchDto,
+	) (commonModel.PageQueryResult[[]model.EchoSearchResult], error)
 }
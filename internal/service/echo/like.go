@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	authModel "github.com/lin-snow/ech0/internal/model/auth"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	model "github.com/lin-snow/ech0/internal/model/echo"
+)
+
+// LikeEcho 以指定身份为Echo点赞，点赞数由点赞记录重新计算
+func (echoService *EchoService) LikeEcho(id uint, identity model.LikeIdentity) (model.EchoLikeResult, error) {
+	if err := echoService.checkLikeable(id, identity); err != nil {
+		return model.EchoLikeResult{}, err
+	}
+
+	var result model.EchoLikeResult
+	if err := echoService.txManager.Run(func(ctx context.Context) error {
+		liked, err := echoService.echoRepository.HasEchoLike(ctx, id, identity)
+		if err != nil {
+			return err
+		}
+		if liked {
+			return errors.New(commonModel.ECHO_ALREADY_LIKED)
+		}
+
+		created, err := echoService.echoRepository.CreateEchoLike(ctx, &model.EchoLike{
+			EchoID:      id,
+			Identity:    identity.Identity,
+			Kind:        identity.Kind,
+			UserID:      identity.UserID,
+			Fingerprint: identity.Fingerprint,
+			IP:          identity.IP,
+			UserAgent:   identity.UserAgent,
+		})
+		if err != nil {
+			return err
+		}
+		if !created {
+			return errors.New(commonModel.ECHO_ALREADY_LIKED)
+		}
+
+		result.Liked = true
+		result.FavCount, err = echoService.echoRepository.RefreshEchoFavCount(ctx, id)
+		return err
+	}); err != nil {
+		return model.EchoLikeResult{}, err
+	}
+
+	return result, nil
+}
+
+// UnlikeEcho 取消指定身份对Echo的点赞
+func (echoService *EchoService) UnlikeEcho(id uint, identity model.LikeIdentity) (model.EchoLikeResult, error) {
+	if err := echoService.checkLikeable(id, identity); err != nil {
+		return model.EchoLikeResult{}, err
+	}
+
+	var result model.EchoLikeResult
+	if err := echoService.txManager.Run(func(ctx context.Context) error {
+		deleted, err := echoService.echoRepository.DeleteEchoLike(ctx, id, identity)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return errors.New(commonModel.ECHO_NOT_LIKED)
+		}
+
+		result.FavCount, err = echoService.echoRepository.RefreshEchoFavCount(ctx, id)
+		return err
+	}); err != nil {
+		return model.EchoLikeResult{}, err
+	}
+
+	return result, nil
+}
+
+// GetEchoLikes 分页获取点赞记录，仅管理员可用
+func (echoService *EchoService) GetEchoLikes(
+	userid uint,
+	dto model.EchoLikeQueryDto,
+) (commonModel.PageQueryResult[[]model.EchoLike], error) {
+	user, err := echoService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return commonModel.PageQueryResult[[]model.EchoLike]{}, err
+	}
+	if !user.IsAdmin {
+		return commonModel.PageQueryResult[[]model.EchoLike]{}, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	if dto.Page < 1 {
+		dto.Page = 1
+	}
+	if dto.PageSize < 1 || dto.PageSize > 100 {
+		dto.PageSize = 20
+	}
+
+	likes, total, err := echoService.echoRepository.GetEchoLikes(dto)
+	if err != nil {
+		return commonModel.PageQueryResult[[]model.EchoLike]{}, err
+	}
+
+	return commonModel.PageQueryResult[[]model.EchoLike]{
+		Items: likes,
+		Total: total,
+	}, nil
+}
+
+// CleanupEchoLikes 按条件批量清理点赞记录，并重新计算受影响 Echo 的点赞数，仅管理员可用
+func (echoService *EchoService) CleanupEchoLikes(userid uint, dto model.EchoLikeCleanupDto) (int64, error) {
+	user, err := echoService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return 0, err
+	}
+	if !user.IsAdmin {
+		return 0, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	if dto.IsEmpty() {
+		return 0, errors.New(commonModel.ECHO_LIKE_CLEANUP_EMPTY)
+	}
+
+	var deleted int64
+	if err := echoService.txManager.Run(func(ctx context.Context) error {
+		var echoIDs []uint
+		var err error
+		deleted, echoIDs, err = echoService.echoRepository.DeleteEchoLikes(ctx, dto)
+		if err != nil {
+			return err
+		}
+
+		for _, echoID := range echoIDs {
+			if _, err := echoService.echoRepository.RefreshEchoFavCount(ctx, echoID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// checkLikeable 检查Echo是否存在且已发布，草稿与回收站中的Echo不能点赞
+// 私密Echo仅管理员可点赞，其他身份按不存在处理，避免泄露私密Echo的ID
+func (echoService *EchoService) checkLikeable(id uint, identity model.LikeIdentity) error {
+	echo, err := echoService.echoRepository.GetEchosById(id)
+	if err != nil {
+		return err
+	}
+	if echo == nil || !echo.IsPublished() {
+		return errors.New(commonModel.ECHO_NOT_FOUND)
+	}
+	if !echo.Private {
+		return nil
+	}
+
+	if identity.UserID == authModel.NO_USER_LOGINED {
+		return errors.New(commonModel.ECHO_NOT_FOUND)
+	}
+	user, err := echoService.commonService.CommonGetUserByUserId(identity.UserID)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.ECHO_NOT_FOUND)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	model "github.com/lin-snow/ech0/internal/model/echo"
+	diffUtil "github.com/lin-snow/ech0/internal/util/diff"
+)
+
+// revisionDiffContext 版本差异中每个变更块前后保留的上下文行数
+const revisionDiffContext = 3
+
+// GetEchoRevisions 获取 Echo 的历史版本列表
+func (echoService *EchoService) GetEchoRevisions(userid, echoID uint) ([]model.EchoRevision, error) {
+	if _, err := echoService.getEchoForRevision(userid, echoID); err != nil {
+		return nil, err
+	}
+
+	return echoService.echoRepository.GetEchoRevisions(echoID)
+}
+
+// DiffEchoRevisions 比较 Echo 的两个版本，to 为 0 时与当前版本比较
+func (echoService *EchoService) DiffEchoRevisions(
+	userid, echoID, from, to uint,
+) (model.EchoRevisionDiff, error) {
+	echo, err := echoService.getEchoForRevision(userid, echoID)
+	if err != nil {
+		return model.EchoRevisionDiff{}, err
+	}
+
+	fromRevision, err := echoService.getEchoRevision(echoID, from)
+	if err != nil {
+		return model.EchoRevisionDiff{}, err
+	}
+
+	toRevision, toName := newEchoRevision(echo), "current"
+	if to != 0 {
+		revision, err := echoService.getEchoRevision(echoID, to)
+		if err != nil {
+			return model.EchoRevisionDiff{}, err
+		}
+		toRevision, toName = *revision, fmt.Sprintf("revision %d", to)
+	}
+
+	return model.EchoRevisionDiff{
+		From: from,
+		To:   to,
+		Diff: diffUtil.UnifiedDiff(
+			fmt.Sprintf("revision %d", from),
+			toName,
+			revisionText(*fromRevision),
+			revisionText(toRevision),
+			revisionDiffContext,
+		),
+	}, nil
+}
+
+// RestoreEchoRevision 将 Echo 恢复到指定的历史版本
+// 恢复按普通更新处理：当前版本会被保存为新的历史版本，已发布的 Echo 会推送 echo.updated 事件；
+// 恢复只回退内容，发布状态与定时发布时间保持不变
+func (echoService *EchoService) RestoreEchoRevision(userid, echoID, revisionID uint) (*model.Echo, error) {
+	current, err := echoService.getEchoForRevision(userid, echoID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := echoService.getEchoRevision(echoID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	restored := *current
+	restored.Content = revision.Content
+	restored.Layout = revision.Layout
+	restored.Extension = revision.Extension
+	restored.ExtensionType = revision.ExtensionType
+
+	restored.Tags = make([]model.Tag, 0, len(revision.Tags))
+	for _, name := range revision.Tags {
+		restored.Tags = append(restored.Tags, model.Tag{Name: name})
+	}
+
+	// 媒体按 URL 与现有媒体匹配，已删除的媒体重新创建
+	// 实况照片关联沿用现有媒体的关联，历史版本中记录的视频 ID 可能已被删除，不再使用
+	liveVideoIDs := make(map[string]*uint, len(current.Media))
+	for _, media := range current.Media {
+		liveVideoIDs[media.MediaURL] = media.LiveVideoID
+	}
+	restored.Media = make([]model.Media, len(revision.Media))
+	for i, media := range revision.Media {
+		media.ID = 0
+		media.LiveVideoID = liveVideoIDs[media.MediaURL]
+		media.MessageID = echoID
+		restored.Media[i] = media
+	}
+
+	if err := echoService.updateEcho(userid, &restored, true); err != nil {
+		return nil, err
+	}
+
+	return echoService.echoRepository.GetEchosById(echoID)
+}
+
+// getEchoForRevision 校验管理员权限并获取 Echo
+func (echoService *EchoService) getEchoForRevision(userid, echoID uint) (*model.Echo, error) {
+	user, err := echoService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin {
+		return nil, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	echo, err := echoService.echoRepository.GetEchosById(echoID)
+	if err != nil {
+		return nil, err
+	}
+	if echo == nil {
+		return nil, errors.New(commonModel.ECHO_NOT_FOUND)
+	}
+
+	return echo, nil
+}
+
+// getEchoRevision 获取 Echo 的指定历史版本
+func (echoService *EchoService) getEchoRevision(echoID, revisionID uint) (*model.EchoRevision, error) {
+	revision, err := echoService.echoRepository.GetEchoRevisionByID(echoID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, errors.New(commonModel.ECHO_REVISION_NOT_FOUND)
+	}
+
+	return revision, nil
+}
+
+// newEchoRevision 由 Echo 的当前内容生成历史版本
+func newEchoRevision(echo *model.Echo) model.EchoRevision {
+	tags := make([]string, 0, len(echo.Tags))
+	for _, tag := range echo.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return model.EchoRevision{
+		EchoID:        echo.ID,
+		Content:       echo.Content,
+		Tags:          tags,
+		Media:         append([]model.Media(nil), echo.Media...),
+		Layout:        echo.Layout,
+		Extension:     echo.Extension,
+		ExtensionType: echo.ExtensionType,
+	}
+}
+
+// revisionText 将版本渲染为便于逐行比较的文本
+func revisionText(revision model.EchoRevision) string {
+	var builder strings.Builder
+
+	builder.WriteString("[content]\n")
+	builder.WriteString(strings.TrimSpace(revision.Content))
+	builder.WriteString("\n\n[tags]\n")
+	for _, tag := range revision.Tags {
+		builder.WriteString("#" + tag + "\n")
+	}
+	builder.WriteString("\n[media]\n")
+	for _, media := range revision.Media {
+		if media.MediaURL != "" {
+			builder.WriteString(media.MediaType + " " + media.MediaURL + "\n")
+		}
+	}
+	builder.WriteString("\n[layout]\n")
+	builder.WriteString(revision.Layout)
+	builder.WriteString("\n\n[extension]\n")
+	if revision.ExtensionType != "" {
+		builder.WriteString(revision.ExtensionType + " " + revision.Extension + "\n")
+	}
+
+	return builder.String()
+}
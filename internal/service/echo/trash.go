@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	model "github.com/lin-snow/ech0/internal/model/echo"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	"go.uber.org/zap"
+)
+
+// GetTrashedEchos 获取回收站中的 Echo 列表
+func (echoService *EchoService) GetTrashedEchos(userid uint) ([]model.Echo, error) {
+	user, err := echoService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin {
+		return nil, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	return echoService.echoRepository.GetTrashedEchos()
+}
+
+// RestoreTrashedEcho 将 Echo 从回收站恢复
+// 已发布的 Echo 在移入回收站时推送过 echo.deleted，恢复后重新推送 echo.created
+func (echoService *EchoService) RestoreTrashedEcho(userid, id uint) (*model.Echo, error) {
+	user, err := echoService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin {
+		return nil, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	trashed, err := echoService.getTrashedEcho(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := echoService.txManager.Run(func(ctx context.Context) error {
+		return echoService.echoRepository.RestoreEchoById(ctx, id)
+	}); err != nil {
+		return nil, err
+	}
+
+	if trashed.IsPublished() {
+		if err := echoService.publishEchoCreatedEvent(id, user); err != nil {
+			logUtil.GetLogger().Error("Failed to push restored echo", zap.Uint("echoID", id), zap.Error(err))
+		}
+	}
+
+	return echoService.echoRepository.GetEchosById(id)
+}
+
+// PurgeTrashedEcho 永久删除回收站中的 Echo
+func (echoService *EchoService) PurgeTrashedEcho(userid, id uint) error {
+	user, err := echoService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	trashed, err := echoService.getTrashedEcho(id)
+	if err != nil {
+		return err
+	}
+
+	return echoService.purgeEcho(trashed)
+}
+
+// EmptyTrash 清空回收站
+func (echoService *EchoService) EmptyTrash(userid uint) error {
+	user, err := echoService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	trashed, err := echoService.echoRepository.GetTrashedEchos()
+	if err != nil {
+		return err
+	}
+
+	for i := range trashed {
+		if err := echoService.purgeEcho(&trashed[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PurgeExpiredTrash 永久删除在回收站中超过保留期限的 Echo
+func (echoService *EchoService) PurgeExpiredTrash(retention time.Duration) error {
+	expired, err := echoService.echoRepository.GetExpiredTrashedEchos(time.Now().UTC().Add(-retention))
+	if err != nil {
+		return err
+	}
+
+	for i := range expired {
+		if err := echoService.purgeEcho(&expired[i]); err != nil {
+			logUtil.GetLogger().Error("Failed to purge trashed echo", zap.Uint("echoID", expired[i].ID), zap.Error(err))
+			continue
+		}
+		logUtil.GetLogger().Info("Purged expired trashed echo", zap.Uint("echoID", expired[i].ID))
+	}
+
+	return nil
+}
+
+// getTrashedEcho 获取回收站中的 Echo
+func (echoService *EchoService) getTrashedEcho(id uint) (*model.Echo, error) {
+	trashed, err := echoService.echoRepository.GetTrashedEchoById(id)
+	if err != nil {
+		return nil, err
+	}
+	if trashed == nil {
+		return nil, errors.New(commonModel.ECHO_NOT_IN_TRASH)
+	}
+
+	return trashed, nil
+}
+
+// purgeEcho 永久删除 Echo，并将不再被其他 Echo 引用的媒体文件交由临时文件清理任务删除
+// 删除记录与登记待清理文件在同一事务中完成，失败时两者都不会生效
+func (echoService *EchoService) purgeEcho(echo *model.Echo) error {
+	return echoService.txManager.Run(func(ctx context.Context) error {
+		if err := echoService.echoRepository.PurgeEchoById(ctx, echo.ID); err != nil {
+			return err
+		}
+
+		released := make([]model.Media, 0, len(echo.Media))
+		for _, media := range echo.Media {
+			inUse, err := echoService.echoRepository.IsMediaInUse(ctx, media)
+			if err != nil {
+				return err
+			}
+			if !inUse {
+				released = append(released, media)
+			}
+		}
+
+		return echoService.commonService.ReleaseMediaFiles(ctx, released)
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lin-snow/ech0/internal/fediverse"
+	model "github.com/lin-snow/ech0/internal/model/fediverse"
+)
+
+//=======================================
+//	处理 Delete
+//=======================================
+
+// handleDeleteActivity 处理删除活动：删除 Actor 时清理其全部数据，删除推文时移除收件箱记录与对应评论
+func (fediverseService *FediverseService) handleDeleteActivity(activity *model.Activity) error {
+	if activity.ActorURL == "" {
+		return errors.New("delete activity missing actor")
+	}
+
+	objectID := extractObjectID(activity.Object)
+	if objectID == "" {
+		return errors.New("delete activity missing object")
+	}
+
+	if fediverse.SameActor(objectID, activity.ActorURL) {
+		return fediverseService.purgeRemoteActor(activity.ActorURL)
+	}
+
+	if err := fediverseService.txManager.Run(func(ctx context.Context) error {
+		return fediverseService.fediverseRepository.DeleteInboxStatusByObject(
+			ctx,
+			activity.ActorURL,
+			objectID,
+		)
+	}); err != nil {
+		return err
+	}
+
+	return fediverseService.commentService.DeleteRemoteComment(activity.ActorURL, objectID)
+}
+
+// purgeRemoteActor 清理已删除的远端 Actor 的粉丝、关注、收件箱、互动记录与评论
+func (fediverseService *FediverseService) purgeRemoteActor(actorURL string) error {
+	if err := fediverseService.txManager.Run(func(ctx context.Context) error {
+		repo := fediverseService.fediverseRepository
+		if err := repo.DeleteFollowersByActor(ctx, actorURL); err != nil {
+			return err
+		}
+		if err := repo.DeleteFollowsByObject(ctx, actorURL); err != nil {
+			return err
+		}
+		if err := repo.DeleteInboxStatusesByActor(ctx, actorURL); err != nil {
+			return err
+		}
+
+		echoIDs, err := repo.DeleteInteractionsByActor(ctx, actorURL)
+		if err != nil {
+			return err
+		}
+		for _, echoID := range echoIDs {
+			if err := fediverseService.refreshEchoInteractionCounts(ctx, echoID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	fediverseService.core.InvalidateRemoteActor(actorURL)
+	return fediverseService.commentService.DeleteRemoteCommentsByActor(actorURL)
+}
@@ -5,6 +5,7 @@ import (
 	echoRepository "github.com/lin-snow/ech0/internal/repository/echo"
 	repository "github.com/lin-snow/ech0/internal/repository/fediverse"
 	userRepository "github.com/lin-snow/ech0/internal/repository/user"
+	commentService "github.com/lin-snow/ech0/internal/service/comment"
 	"github.com/lin-snow/ech0/internal/transaction"
 )
 
@@ -14,6 +15,7 @@ type FediverseService struct {
 	fediverseRepository repository.FediverseRepositoryInterface
 	userRepository      userRepository.UserRepositoryInterface
 	echoRepository      echoRepository.EchoRepositoryInterface
+	commentService      commentService.CommentServiceInterface
 }
 
 func NewFediverseService(
@@ -22,6 +24,7 @@ func NewFediverseService(
 	fediverseRepository repository.FediverseRepositoryInterface,
 	userRepository userRepository.UserRepositoryInterface,
 	echoRepository echoRepository.EchoRepositoryInterface,
+	commentService commentService.CommentServiceInterface,
 ) FediverseServiceInterface {
 	return &FediverseService{
 		core:                core,
@@ -29,5 +32,6 @@ func NewFediverseService(
 		fediverseRepository: fediverseRepository,
 		userRepository:      userRepository,
 		echoRepository:      echoRepository,
+		commentService:      commentService,
 	}
 }
@@ -2,14 +2,48 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 
+	"github.com/lin-snow/ech0/internal/fediverse"
 	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	model "github.com/lin-snow/ech0/internal/model/fediverse"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
-// ProcessInbox 处理接收到的 ActivityPub 消息
+// VerifyInboxRequest 校验投递到 Inbox 的请求签名
+func (fediverseService *FediverseService) VerifyInboxRequest(
+	req *http.Request,
+	body []byte,
+	activity *model.Activity,
+) error {
+	_, err := fediverseService.core.VerifyInboxRequest(req, body, activity.ActorURL)
+	if err == nil {
+		return nil
+	}
+
+	// 已删除的 Actor 无法再提供公钥，其自删除活动以源站返回 410 为准
+	if errors.Is(err, fediverse.ErrRemoteActorGone) && isSelfDelete(activity) {
+		if _, fetchErr := fediverseService.core.FetchRemoteActor(
+			activity.ActorURL,
+			true,
+		); errors.Is(fetchErr, fediverse.ErrRemoteActorGone) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: %w", commonModel.INVALID_HTTP_SIGNATURE, err)
+}
+
+// isSelfDelete 判断是否为 Actor 删除自身的活动
+func isSelfDelete(activity *model.Activity) bool {
+	return activity.Type == model.ActivityTypeDelete &&
+		activity.ActorURL != "" &&
+		fediverse.SameActor(extractObjectID(activity.Object), activity.ActorURL)
+}
+
+// HandleInbox 处理接收到的 ActivityPub 消息
 func (fediverseService *FediverseService) HandleInbox(
 	username string,
 	activity *model.Activity,
@@ -27,16 +61,40 @@ func (fediverseService *FediverseService) HandleInbox(
 		if err := fediverseService.handleFollowActivity(&user, activity); err != nil {
 			return err
 		}
+	// 处理撤销请求（取消关注、取消点赞、取消转发）
+	case model.ActivityTypeUndo:
+		if err := fediverseService.handleUndoActivity(&user, activity); err != nil {
+			return err
+		}
+	// 处理删除请求（Actor 注销或推文删除）
+	case model.ActivityTypeDelete:
+		if err := fediverseService.handleDeleteActivity(activity); err != nil {
+			return err
+		}
+	// 处理更新请求（Actor 资料更新）
+	case model.ActivityTypeUpdate:
+		if err := fediverseService.handleUpdateActivity(activity); err != nil {
+			return err
+		}
+	// 处理点赞与转发
+	case model.ActivityTypeLike, model.ActivityTypeAnnounce:
+		if err := fediverseService.handleInteractionActivity(&user, activity); err != nil {
+			return err
+		}
 	// 处理接收到的推文推送
-	// case model.ActivityTypeCreate:
-	// 	if err := fediverseService.handleCreateActivity(&user, activity); err != nil {
-	// 		return err
-	// 	}
-	// 处理接收到的接受关注请求
-	// case model.ActivityTypeAccept:
-	// 	if err := fediverseService.handleAcceptActivity(&user, activity); err != nil {
-	// 		return err
-	// 	}
+	case model.ActivityTypeCreate:
+		if err := fediverseService.handleCreateActivity(&user, activity); err != nil {
+			return err
+		}
+	// 处理远端对关注请求的接受与拒绝
+	case model.ActivityTypeAccept:
+		if err := fediverseService.handleAcceptActivity(&user, activity, model.FollowStatusAccepted); err != nil {
+			return err
+		}
+	case model.ActivityTypeReject:
+		if err := fediverseService.handleAcceptActivity(&user, activity, model.FollowStatusRejected); err != nil {
+			return err
+		}
 
 	default:
 		return errors.New(
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	model "github.com/lin-snow/ech0/internal/model/fediverse"
+	userModel "github.com/lin-snow/ech0/internal/model/user"
+	httpUtil "github.com/lin-snow/ech0/internal/util/http"
+)
+
+//=======================================
+//	处理 Like / Announce
+//=======================================
+
+// handleInteractionActivity 处理远端对本地 Echo 的 Like 与 Announce
+func (fediverseService *FediverseService) handleInteractionActivity(
+	user *userModel.User,
+	activity *model.Activity,
+) error {
+	if activity.ActorURL == "" {
+		return errors.New("interaction activity missing actor")
+	}
+
+	echoID, ok, err := fediverseService.resolveUserEchoID(user, extractObjectID(activity.Object))
+	if err != nil {
+		return err
+	}
+	// 不是本地 Echo 的互动（例如转发他人的推文）直接忽略
+	if !ok {
+		return nil
+	}
+
+	return fediverseService.txManager.Run(func(ctx context.Context) error {
+		if err := fediverseService.fediverseRepository.SaveInteraction(ctx, &model.Interaction{
+			EchoID:     echoID,
+			ActorID:    activity.ActorURL,
+			Type:       activity.Type,
+			ActivityID: activity.ActivityID,
+		}); err != nil {
+			return err
+		}
+		return fediverseService.refreshEchoInteractionCounts(ctx, echoID)
+	})
+}
+
+// resolveUserEchoID 解析对象 URL 对应的本地 Echo，仅返回属于该用户的公开 Echo
+func (fediverseService *FediverseService) resolveUserEchoID(
+	user *userModel.User,
+	objectURL string,
+) (uint, bool, error) {
+	if objectURL == "" {
+		return 0, false, nil
+	}
+
+	_, setting, err := fediverseService.core.BuildActor(user)
+	if err != nil {
+		return 0, false, err
+	}
+
+	echoID, ok := resolveLocalEchoID(objectURL, httpUtil.TrimURL(setting.ServerURL))
+	if !ok {
+		return 0, false, nil
+	}
+
+	echo, err := fediverseService.echoRepository.GetEchosById(echoID)
+	if err != nil || echo == nil {
+		return 0, false, nil
+	}
+	if echo.Private || !echo.IsPublished() || echo.UserID != user.ID {
+		return 0, false, nil
+	}
+
+	return echoID, true, nil
+}
+
+// refreshEchoInteractionCounts 根据互动记录重新计算 Echo 的互动计数
+func (fediverseService *FediverseService) refreshEchoInteractionCounts(
+	ctx context.Context,
+	echoID uint,
+) error {
+	likes, err := fediverseService.fediverseRepository.CountInteractions(
+		ctx,
+		echoID,
+		model.ActivityTypeLike,
+	)
+	if err != nil {
+		return err
+	}
+	boosts, err := fediverseService.fediverseRepository.CountInteractions(
+		ctx,
+		echoID,
+		model.ActivityTypeAnnounce,
+	)
+	if err != nil {
+		return err
+	}
+
+	return fediverseService.echoRepository.UpdateFediverseCounts(ctx, echoID, likes, boosts)
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"net/http"
 
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	model "github.com/lin-snow/ech0/internal/model/fediverse"
 )
 
@@ -13,6 +15,9 @@ type FediverseServiceInterface interface {
 	// GetActorByUsername 通过用户名获取 Actor 信息
 	GetActorByUsername(username string) (model.Actor, error)
 
+	// VerifyInboxRequest 校验投递到 Inbox 的请求签名
+	VerifyInboxRequest(req *http.Request, body []byte, activity *model.Activity) error
+
 	// HandleInbox 处理接收到的 ActivityPub 消息
 	HandleInbox(username string, activity *model.Activity) error
 
@@ -41,27 +46,30 @@ type FediverseServiceInterface interface {
 	// GetObjectByID 通过 ID 获取内容对象
 	GetObjectByID(id uint) (model.Object, error)
 
+	// GetTombstoneByID 获取已删除内容对象的 Tombstone，未删除时返回 nil
+	GetTombstoneByID(id uint) (*model.TombstoneObject, error)
+
 	// GetTimeline 获取关注人的时间线
-	// GetTimeline(userID uint, page, pageSize int) (commonModel.PageQueryResult[[]model.TimelineItem], error)
+	GetTimeline(userID uint, page, pageSize int) (commonModel.PageQueryResult[[]model.TimelineItem], error)
 
-	// PushEchoToFediverse 将 Echo 推送到联邦网络
-	// PushEchoToFediverse(userId uint, echo echoModel.Echo) error
+	// SearchActorByActorID 根据 Actor URL 搜索远端 Actor
+	SearchActorByActorID(actorID string) (map[string]any, error)
 
-	// // SearchActorByActorID 根据 Actor URL 搜索远端 Actor
-	// SearchActorByActorID(actorID string) (map[string]any, error)
+	// GetFollowStatus 获取关注状态
+	GetFollowStatus(userID uint, targetActor string) (string, error)
 
-	// // GetFollowStatus 获取关注状态
-	// GetFollowStatus(userID uint, targetActor string) (string, error)
+	// ListFollowing 列出当前用户关注的全部 Actor
+	ListFollowing(userID uint) ([]model.Follow, error)
 
-	// // FollowActor 发送关注请求
-	// FollowActor(userID uint, req model.FollowActionRequest) (map[string]string, error)
+	// FollowActor 发送关注请求
+	FollowActor(userID uint, req model.FollowActionRequest) (map[string]string, error)
 
-	// // UnfollowActor 发送取消关注请求
-	// UnfollowActor(userID uint, req model.FollowActionRequest) (map[string]string, error)
+	// UnfollowActor 发送取消关注请求
+	UnfollowActor(userID uint, req model.FollowActionRequest) (map[string]string, error)
 
-	// // LikeObject 发送点赞请求
-	// LikeObject(userID uint, req model.LikeActionRequest) (map[string]string, error)
+	// LikeObject 发送点赞请求
+	LikeObject(userID uint, req model.LikeActionRequest) (map[string]string, error)
 
-	// // UndoLikeObject 发送取消点赞请求
-	// UndoLikeObject(userID uint, req model.LikeActionRequest) (map[string]string, error)
+	// UndoLikeObject 发送取消点赞请求
+	UndoLikeObject(userID uint, req model.LikeActionRequest) (map[string]string, error)
 }
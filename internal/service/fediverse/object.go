@@ -1,7 +1,13 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/lin-snow/ech0/internal/fediverse"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	model "github.com/lin-snow/ech0/internal/model/fediverse"
 )
 
@@ -9,9 +15,12 @@ import (
 func (fediverseService *FediverseService) GetObjectByID(id uint) (model.Object, error) {
 	// 获取 Echo
 	echo, err := fediverseService.echoRepository.GetEchosById(id)
-	if err != nil || echo.Private {
+	if err != nil {
 		return model.Object{}, err
 	}
+	if echo == nil || echo.Private || !echo.IsPublished() {
+		return model.Object{}, errors.New(commonModel.ECHO_NOT_FOUND)
+	}
 
 	// 获取 Actor 和 setting
 	user, err := fediverseService.userRepository.GetUserByUsername(echo.Username)
@@ -30,3 +39,27 @@ func (fediverseService *FediverseService) GetObjectByID(id uint) (model.Object,
 	// 转 Object
 	return fediverseService.core.ConvertEchoToObject(echo, &actor, serverURL), nil
 }
+
+// GetTombstoneByID 获取已删除内容对象的 Tombstone
+func (fediverseService *FediverseService) GetTombstoneByID(id uint) (*model.TombstoneObject, error) {
+	tombstone, err := fediverseService.fediverseRepository.GetTombstoneByEchoID(
+		context.Background(),
+		id,
+	)
+	if err != nil || tombstone == nil {
+		return nil, err
+	}
+
+	serverURL, err := fediverseService.core.GetServerURL()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TombstoneObject{
+		Context:    "https://www.w3.org/ns/activitystreams",
+		ID:         fmt.Sprintf("%s/objects/%d", serverURL, tombstone.EchoID),
+		Type:       model.ObjectTypeTombstone,
+		FormerType: tombstone.FormerType,
+		Deleted:    tombstone.DeletedAt.UTC().Format(time.RFC3339),
+	}, nil
+}
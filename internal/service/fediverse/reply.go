@@ -0,0 +1,138 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/lin-snow/ech0/internal/fediverse"
+	commentModel "github.com/lin-snow/ech0/internal/model/comment"
+	model "github.com/lin-snow/ech0/internal/model/fediverse"
+	userModel "github.com/lin-snow/ech0/internal/model/user"
+	mdUtil "github.com/lin-snow/ech0/internal/util/md"
+)
+
+//=======================================
+//	处理对本地 Echo 的回复
+//=======================================
+
+// activityStreamsPublic 是 ActivityPub 中表示公开的收件人
+const activityStreamsPublic = "https://www.w3.org/ns/activitystreams#Public"
+
+// saveReplyAsComment 将回复本地 Echo（或其下远端回复）的推文保存为评论，返回是否已处理
+func (fediverseService *FediverseService) saveReplyAsComment(
+	user *userModel.User,
+	activity *model.Activity,
+	objectID string,
+	objectMap map[string]any,
+) (bool, error) {
+	inReplyTo := extractObjectID(objectMap["inReplyTo"])
+	if inReplyTo == "" {
+		return false, nil
+	}
+
+	// 私信或仅关注者可见的回复不作为公开评论，按普通推文处理
+	audience := extractAudience(objectMap["to"], activity.To)
+	audience = append(audience, extractAudience(objectMap["cc"], activity.Cc)...)
+	if !isPublicAudience(audience) {
+		return false, nil
+	}
+
+	comment := &commentModel.Comment{
+		RemoteID:  objectID,
+		ActorURL:  strings.TrimSpace(activity.ActorURL),
+		CreatedAt: resolvePublishedAt(activity, objectMap),
+	}
+
+	echoID, ok, err := fediverseService.resolveUserEchoID(user, inReplyTo)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		comment.EchoID = echoID
+	} else {
+		// 回复的是已保存的远端回复时挂到同一楼层下
+		parent, err := fediverseService.commentService.GetCommentByRemoteID(inReplyTo)
+		if err != nil {
+			return false, err
+		}
+		if parent == nil {
+			return false, nil
+		}
+		comment.EchoID = parent.EchoID
+		comment.ParentID = parent.ID
+	}
+
+	fediverseService.fillRemoteComment(comment, objectMap)
+	if comment.Content == "" {
+		return true, nil
+	}
+
+	return true, fediverseService.commentService.SaveRemoteComment(comment)
+}
+
+// updateReplyComment 远端编辑回复后同步已保存的评论内容
+func (fediverseService *FediverseService) updateReplyComment(
+	remoteActor string,
+	objectMap map[string]any,
+) error {
+	objectID := getStringFromMap(objectMap, "id")
+	attributedTo := extractAttributedTo(objectMap["attributedTo"])
+	if objectID == "" || attributedTo == "" || !fediverse.SameActor(attributedTo, remoteActor) {
+		return nil
+	}
+
+	existing, err := fediverseService.commentService.GetCommentByRemoteID(objectID)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	// 编辑后不再公开的回复直接移除
+	audience := extractAudience(objectMap["to"], nil)
+	audience = append(audience, extractAudience(objectMap["cc"], nil)...)
+	if !isPublicAudience(audience) {
+		return fediverseService.commentService.DeleteRemoteComment(remoteActor, objectID)
+	}
+
+	comment := &commentModel.Comment{
+		EchoID:   existing.EchoID,
+		ParentID: existing.ParentID,
+		RemoteID: objectID,
+		ActorURL: remoteActor,
+	}
+	fediverseService.fillRemoteComment(comment, objectMap)
+	if comment.Content == "" {
+		return nil
+	}
+
+	return fediverseService.commentService.SaveRemoteComment(comment)
+}
+
+// fillRemoteComment 填充远端回复的作者资料与纯文本内容
+func (fediverseService *FediverseService) fillRemoteComment(
+	comment *commentModel.Comment,
+	objectMap map[string]any,
+) {
+	comment.AuthorName = derivePreferredUsername(comment.ActorURL)
+	comment.AuthorURL = comment.ActorURL
+	if actor, err := fediverseService.core.FetchRemoteActor(comment.ActorURL, false); err == nil {
+		if actor.Name != "" {
+			comment.AuthorName = actor.Name
+		} else if actor.PreferredUsername != "" {
+			comment.AuthorName = actor.PreferredUsername
+		}
+		comment.AuthorAvatar = actor.AvatarURL()
+	}
+	if len([]rune(comment.AuthorName)) > 100 {
+		comment.AuthorName = string([]rune(comment.AuthorName)[:100])
+	}
+
+	content := normalizeActivityContent(getStringFromMap(objectMap, "content"), objectMap)
+	comment.Content = strings.TrimSpace(mdUtil.HTMLToText(content))
+}
+
+// isPublicAudience 判断收件人中是否包含公开地址
+func isPublicAudience(audience []string) bool {
+	return slices.ContainsFunc(audience, func(recipient string) bool {
+		return recipient == activityStreamsPublic || recipient == "as:Public" || recipient == "Public"
+	})
+}
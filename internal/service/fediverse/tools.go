@@ -1 +1,41 @@
 package service
+
+import (
+	"strconv"
+	"strings"
+)
+
+// extractObjectID 从 Activity 的 object 字段中提取对象 ID，object 可能是字符串或对象
+func extractObjectID(object any) string {
+	switch value := object.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case map[string]any:
+		return getStringFromMap(value, "id")
+	}
+	return ""
+}
+
+// getStringFromMap 读取 map 中的字符串字段
+func getStringFromMap(payload map[string]any, key string) string {
+	if value, ok := payload[key].(string); ok {
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
+
+// resolveLocalEchoID 解析指向本地 Echo 的 Object 或 Activity URL，返回 Echo ID
+func resolveLocalEchoID(objectURL, serverURL string) (uint, bool) {
+	for _, prefix := range []string{serverURL + "/objects/", serverURL + "/activities/"} {
+		rest, ok := strings.CutPrefix(objectURL, prefix)
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseUint(rest, 10, 64)
+		if err != nil || id == 0 {
+			return 0, false
+		}
+		return uint(id), true
+	}
+	return 0, false
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lin-snow/ech0/internal/fediverse"
+	model "github.com/lin-snow/ech0/internal/model/fediverse"
+	userModel "github.com/lin-snow/ech0/internal/model/user"
+)
+
+//=======================================
+//	处理 Undo
+//=======================================
+
+// handleUndoActivity 处理撤销活动，支持撤销 Follow、Like 与 Announce
+func (fediverseService *FediverseService) handleUndoActivity(
+	user *userModel.User,
+	activity *model.Activity,
+) error {
+	if activity.ActorURL == "" {
+		return errors.New("undo activity missing actor")
+	}
+
+	inner, ok := activity.Object.(map[string]any)
+	if !ok {
+		// object 仅为 Activity ID 时，只能按已记录的互动撤销
+		return fediverseService.undoInteractionByActivityID(
+			activity.ActorURL,
+			extractObjectID(activity.Object),
+		)
+	}
+
+	// 只允许撤销自己发起的活动
+	if innerActor := getStringFromMap(inner, "actor"); innerActor != "" &&
+		!fediverse.SameActor(innerActor, activity.ActorURL) {
+		return errors.New("undo actor does not match original activity actor")
+	}
+
+	switch getStringFromMap(inner, "type") {
+	case model.ActivityTypeFollow:
+		return fediverseService.txManager.Run(func(ctx context.Context) error {
+			return fediverseService.fediverseRepository.DeleteFollower(
+				ctx,
+				user.ID,
+				activity.ActorURL,
+			)
+		})
+	case model.ActivityTypeLike, model.ActivityTypeAnnounce:
+		echoID, ok, err := fediverseService.resolveUserEchoID(user, extractObjectID(inner["object"]))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fediverseService.undoInteractionByActivityID(
+				activity.ActorURL,
+				getStringFromMap(inner, "id"),
+			)
+		}
+		return fediverseService.txManager.Run(func(ctx context.Context) error {
+			if err := fediverseService.fediverseRepository.DeleteInteraction(
+				ctx,
+				echoID,
+				activity.ActorURL,
+				getStringFromMap(inner, "type"),
+			); err != nil {
+				return err
+			}
+			return fediverseService.refreshEchoInteractionCounts(ctx, echoID)
+		})
+	}
+
+	// 其余类型的撤销暂不处理
+	return nil
+}
+
+// undoInteractionByActivityID 根据原互动 Activity ID 撤销互动
+func (fediverseService *FediverseService) undoInteractionByActivityID(
+	actorID, activityID string,
+) error {
+	if activityID == "" {
+		return nil
+	}
+
+	return fediverseService.txManager.Run(func(ctx context.Context) error {
+		interaction, err := fediverseService.fediverseRepository.GetInteractionByActivityID(
+			ctx,
+			actorID,
+			activityID,
+		)
+		if err != nil || interaction == nil {
+			return err
+		}
+
+		if err := fediverseService.fediverseRepository.DeleteInteraction(
+			ctx,
+			interaction.EchoID,
+			actorID,
+			interaction.Type,
+		); err != nil {
+			return err
+		}
+		return fediverseService.refreshEchoInteractionCounts(ctx, interaction.EchoID)
+	})
+}
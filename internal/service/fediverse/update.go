@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lin-snow/ech0/internal/fediverse"
+	model "github.com/lin-snow/ech0/internal/model/fediverse"
+)
+
+//=======================================
+//	处理 Update
+//=======================================
+
+// handleUpdateActivity 处理更新活动，Actor 资料更新时刷新本地缓存，回复被编辑时同步评论
+func (fediverseService *FediverseService) handleUpdateActivity(activity *model.Activity) error {
+	if activity.ActorURL == "" {
+		return errors.New("update activity missing actor")
+	}
+
+	objectID := extractObjectID(activity.Object)
+	if objectID == "" {
+		return nil
+	}
+
+	// 推文的更新只同步已保存为评论的回复
+	if !fediverse.SameActor(objectID, activity.ActorURL) {
+		objectMap, ok := activity.Object.(map[string]any)
+		if !ok {
+			return nil
+		}
+		return fediverseService.updateReplyComment(activity.ActorURL, objectMap)
+	}
+
+	// 不信任 Activity 中携带的资料，从源站重新拉取
+	fediverseService.core.InvalidateRemoteActor(activity.ActorURL)
+	actor, err := fediverseService.core.FetchRemoteActor(activity.ActorURL, true)
+	if err != nil {
+		return err
+	}
+
+	return fediverseService.txManager.Run(func(ctx context.Context) error {
+		return fediverseService.fediverseRepository.UpdateInboxStatusActor(
+			ctx,
+			activity.ActorURL,
+			actor.PreferredUsername,
+			actor.Name,
+			actor.AvatarURL(),
+		)
+	})
+}
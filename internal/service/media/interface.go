@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"net/url"
+
+	model "github.com/lin-snow/ech0/internal/model/echo"
+)
+
+type MediaServiceInterface interface {
+	// ProcessMedia 按需处理媒体：图片按 w/h/q/fmt/mode 参数处理，视频截取封面后按同样的参数处理
+	// source 为 local 或 s3，key 为本地媒体路径（如 videos/xxx.mp4）或对象存储的 Key
+	ProcessMedia(ctx context.Context, source, key string, query url.Values) (*model.ProcessedMedia, error)
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lin-snow/ech0/internal/cache"
+	"github.com/lin-snow/ech0/internal/config"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	model "github.com/lin-snow/ech0/internal/model/echo"
+	settingModel "github.com/lin-snow/ech0/internal/model/setting"
+	echoRepository "github.com/lin-snow/ech0/internal/repository/echo"
+	commonService "github.com/lin-snow/ech0/internal/service/common"
+	settingService "github.com/lin-snow/ech0/internal/service/setting"
+	imgUtil "github.com/lin-snow/ech0/internal/util/img"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	storageUtil "github.com/lin-snow/ech0/internal/util/storage"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// posterFormat 是未指定输出格式时视频封面的格式
+const posterFormat = "jpg"
+
+type MediaService struct {
+	commonService  commonService.CommonServiceInterface
+	settingService settingService.SettingServiceInterface
+	echoRepository echoRepository.EchoRepositoryInterface
+	imageCache     *cache.DiskCache
+	group          singleflight.Group // 合并同一处理结果的并发请求，避免重复下载与截帧
+}
+
+func NewMediaService(
+	commonService commonService.CommonServiceInterface,
+	settingService settingService.SettingServiceInterface,
+	echoRepository echoRepository.EchoRepositoryInterface,
+	imageCache *cache.DiskCache,
+) MediaServiceInterface {
+	return &MediaService{
+		commonService:  commonService,
+		settingService: settingService,
+		echoRepository: echoRepository,
+		imageCache:     imageCache,
+	}
+}
+
+// ProcessMedia 按需处理媒体，处理结果写入与本地图片处理共用的磁盘缓存
+func (mediaService *MediaService) ProcessMedia(
+	ctx context.Context,
+	source, key string,
+	query url.Values,
+) (*model.ProcessedMedia, error) {
+	key = strings.TrimPrefix(key, "/")
+	if key == "" {
+		return nil, errors.New(commonModel.MEDIA_NOT_FOUND)
+	}
+	// 处理结果会写入缓存并由并发请求共享，不随单个请求取消
+	ctx = context.WithoutCancel(ctx)
+
+	switch source {
+	case model.MediaSourceLocal:
+		return mediaService.processLocal(ctx, key, query)
+	case model.MediaSourceS3:
+		return mediaService.processS3(ctx, key, query)
+	default:
+		return nil, errors.New(commonModel.MEDIA_SOURCE_NOT_SUPPORTED)
+	}
+}
+
+// processLocal 处理本地媒体：图片交给 /api/images 处理，视频截取封面
+func (mediaService *MediaService) processLocal(
+	ctx context.Context,
+	key string,
+	query url.Values,
+) (*model.ProcessedMedia, error) {
+	// 防止路径遍历
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+
+	if strings.HasPrefix(key, "images/") {
+		redirectURL := "/api/" + key
+		if encoded := query.Encode(); encoded != "" {
+			redirectURL += "?" + encoded
+		}
+		return &model.ProcessedMedia{RedirectURL: redirectURL}, nil
+	}
+
+	name, ok := strings.CutPrefix(key, "videos/")
+	if !ok {
+		return nil, errors.New(commonModel.MEDIA_NOT_FOUND)
+	}
+	filePath := filepath.Join(config.Config.Upload.VideoPath, filepath.FromSlash(name))
+	fileStat, err := os.Stat(filePath)
+	if err != nil || fileStat.IsDir() {
+		return nil, errors.New(commonModel.MEDIA_NOT_FOUND)
+	}
+
+	opts := mediaService.posterOptions(query)
+	fingerprint := opts.Fingerprint(
+		fmt.Sprintf("poster-%s-%d-%d", filePath, fileStat.ModTime().Unix(), fileStat.Size()),
+	)
+	return mediaService.cached(fingerprint, opts.Format, func() ([]byte, string, error) {
+		return extractPoster(ctx, filePath, opts)
+	})
+}
+
+// processS3 处理对象存储中的媒体，只允许处理 Echo 引用的对象，避免读取存储桶中的其他文件（如备份）
+// 对象 Key 上传后不会变化，因此直接以 Key 作为源文件指纹
+func (mediaService *MediaService) processS3(
+	ctx context.Context,
+	key string,
+	query url.Values,
+) (*model.ProcessedMedia, error) {
+	media, err := mediaService.echoRepository.GetMediaByObjectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if media == nil {
+		return nil, errors.New(commonModel.MEDIA_NOT_FOUND)
+	}
+
+	client, s3Setting, err := mediaService.commonService.GetS3Client()
+	if err != nil {
+		return nil, err
+	}
+
+	if media.MediaType == model.MediaTypeVideo {
+		opts := mediaService.posterOptions(query)
+		fingerprint := opts.Fingerprint("poster-s3-" + key)
+		return mediaService.cached(fingerprint, opts.Format, func() ([]byte, string, error) {
+			videoPath, err := downloadTemp(ctx, client, key)
+			if err != nil {
+				return nil, "", err
+			}
+			defer os.Remove(videoPath)
+			return extractPoster(ctx, videoPath, opts)
+		})
+	}
+
+	// 无处理参数或尺寸不在预设中 → 跳转到原图
+	opts, hasProcessParams := imgUtil.ParseProcessOptions(query)
+	if !hasProcessParams || !mediaService.allowsSize(opts) {
+		return mediaService.originalS3(s3Setting, key)
+	}
+
+	fingerprint := opts.Fingerprint("s3-" + key)
+	srcFormat := strings.TrimPrefix(path.Ext(key), ".")
+	processed, err := mediaService.cached(fingerprint, opts.OutputExt(srcFormat), func() ([]byte, string, error) {
+		reader, err := client.Download(ctx, key)
+		if err != nil {
+			return nil, "", err
+		}
+		defer reader.Close()
+
+		return imgUtil.ProcessImage(reader, srcFormat, opts)
+	})
+	if err != nil {
+		// 处理失败，回退到原图
+		logUtil.GetLogger().Warn("Failed to process S3 image",
+			zap.String("key", key), zap.String("error", err.Error()))
+		return mediaService.originalS3(s3Setting, key)
+	}
+	return processed, nil
+}
+
+// originalS3 返回跳转到对象原始地址的结果
+func (mediaService *MediaService) originalS3(
+	s3Setting settingModel.S3Setting,
+	key string,
+) (*model.ProcessedMedia, error) {
+	objectURL, err := mediaService.commonService.GetS3ObjectURL(s3Setting, key)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ProcessedMedia{RedirectURL: objectURL}, nil
+}
+
+// cached 读取磁盘缓存，未命中时生成并写入缓存，同一缓存文件的并发请求只生成一次
+func (mediaService *MediaService) cached(
+	fingerprint, ext string,
+	produce func() ([]byte, string, error),
+) (*model.ProcessedMedia, error) {
+	etag := fmt.Sprintf(`W/"%s"`, fingerprint)
+	cacheName := fmt.Sprintf("%s.%s", fingerprint, ext)
+	if cachePath, ok := mediaService.imageCache.Get(cacheName); ok {
+		return &model.ProcessedMedia{FilePath: cachePath, ETag: etag}, nil
+	}
+
+	result, err, _ := mediaService.group.Do(cacheName, func() (any, error) {
+		data, contentType, err := produce()
+		if err != nil {
+			return nil, err
+		}
+		_ = mediaService.imageCache.Put(cacheName, data)
+		return &model.ProcessedMedia{Data: data, ContentType: contentType, ETag: etag}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*model.ProcessedMedia), nil
+}
+
+// posterOptions 解析视频封面的处理参数，默认输出原尺寸的 JPEG，尺寸不在预设中时输出原尺寸
+func (mediaService *MediaService) posterOptions(query url.Values) imgUtil.ImageProcessOptions {
+	opts, hasProcessParams := imgUtil.ParseProcessOptions(query)
+	if !hasProcessParams {
+		opts = imgUtil.ImageProcessOptions{Quality: 75, Mode: "lfit"}
+	}
+	if opts.Format == "" {
+		opts.Format = posterFormat
+	}
+	if !mediaService.allowsSize(opts) {
+		opts.Width, opts.Height = 0, 0
+	}
+	return opts
+}
+
+// allowsSize 判断处理尺寸是否在图片处理设置的预设中
+func (mediaService *MediaService) allowsSize(opts imgUtil.ImageProcessOptions) bool {
+	var processSetting settingModel.ImageProcessSetting
+	if err := mediaService.settingService.GetImageProcessSetting(&processSetting); err != nil {
+		return false
+	}
+	return processSetting.AllowsSize(opts.Width, opts.Height)
+}
+
+// extractPoster 截取视频封面并按参数处理
+func extractPoster(
+	ctx context.Context,
+	videoPath string,
+	opts imgUtil.ImageProcessOptions,
+) ([]byte, string, error) {
+	poster, err := imgUtil.ExtractPoster(ctx, videoPath)
+	if err != nil {
+		if errors.Is(err, imgUtil.ErrFFmpegNotFound) {
+			return nil, "", errors.New(commonModel.FFMPEG_NOT_INSTALLED)
+		}
+		logUtil.GetLogger().Warn("Failed to extract video poster",
+			zap.String("video", videoPath), zap.String("error", err.Error()))
+		return nil, "", errors.New(commonModel.MEDIA_PROCESS_FAILED)
+	}
+
+	data, contentType, err := imgUtil.ProcessImage(bytes.NewReader(poster), "png", opts)
+	if err != nil {
+		return nil, "", errors.New(commonModel.MEDIA_PROCESS_FAILED)
+	}
+	return data, contentType, nil
+}
+
+// downloadTemp 将对象下载到临时文件，大小不超过视频上传上限，返回临时文件路径
+func downloadTemp(ctx context.Context, client storageUtil.ObjectStorage, key string) (string, error) {
+	reader, err := client.Download(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	file, err := os.CreateTemp("", "ech0-media-*"+path.Ext(key))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var src io.Reader = reader
+	limit := int64(config.Config.Upload.VideoMaxSize)
+	if limit > 0 {
+		src = io.LimitReader(reader, limit+1)
+	}
+	written, err := io.Copy(file, src)
+	if err == nil && limit > 0 && written > limit {
+		err = errors.New(commonModel.FILE_SIZE_EXCEED_LIMIT)
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
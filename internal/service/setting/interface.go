@@ -1,6 +1,8 @@
 package service
 
 import (
+	authModel "github.com/lin-snow/ech0/internal/model/auth"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
 	model "github.com/lin-snow/ech0/internal/model/setting"
 	webhookModel "github.com/lin-snow/ech0/internal/model/webhook"
 )
@@ -45,6 +47,21 @@ type SettingServiceInterface interface {
 	// CreateWebhook 创建 Webhook
 	CreateWebhook(userid uint, newWebhook *model.WebhookDto) error
 
+	// ListWebhookDeliveries 分页获取 Webhook 投递记录
+	ListWebhookDeliveries(
+		userid, id uint,
+		pageQueryDto commonModel.PageQueryDto,
+	) (commonModel.PageQueryResult[[]webhookModel.WebhookDelivery], error)
+
+	// RedeliverWebhook 将指定的 Webhook 事件重新加入投递队列，返回新的投递记录
+	RedeliverWebhook(userid, id, deliveryID uint) (webhookModel.WebhookDelivery, error)
+
+	// PingWebhook 向 Webhook 发送测试事件
+	PingWebhook(userid, id uint) (webhookModel.WebhookDelivery, error)
+
+	// CleanupWebhookDeliveries 清理过期的 Webhook 投递记录
+	CleanupWebhookDeliveries() error
+
 	// ListAccessTokens 列出访问令牌
 	ListAccessTokens(userid uint) ([]model.AccessTokenSetting, error)
 
@@ -54,6 +71,9 @@ type SettingServiceInterface interface {
 	// DeleteAccessToken 删除访问令牌
 	DeleteAccessToken(userid, id uint) error
 
+	// AuthenticateToken 校验令牌是否仍然有效并返回访问令牌的权限范围
+	AuthenticateToken(claims *authModel.MyClaims, rawToken, ip string) ([]string, bool, error)
+
 	// GetFediverseSetting 获取联邦网络设置
 	GetFediverseSetting(userid uint, setting *model.FediverseSetting) error
 
@@ -66,6 +86,24 @@ type SettingServiceInterface interface {
 	// UpdateBackupScheduleSetting 更新备份计划
 	UpdateBackupScheduleSetting(userid uint, newSetting *model.BackupScheduleDto) error
 
+	// GetBackupDestinationSetting 获取异地备份设置（内部使用，不做鉴权）
+	GetBackupDestinationSetting(setting *model.BackupDestination) error
+
+	// GetBackupDestination 获取异地备份设置
+	GetBackupDestination(userid uint, setting *model.BackupDestination) error
+
+	// UpdateBackupDestination 更新异地备份设置
+	UpdateBackupDestination(userid uint, newSetting *model.BackupDestinationDto) error
+
+	// GetBackupEncryptionSetting 获取备份加密设置（内部使用，不做鉴权）
+	GetBackupEncryptionSetting(setting *model.BackupEncryption) error
+
+	// GetBackupEncryption 获取备份加密设置
+	GetBackupEncryption(userid uint, setting *model.BackupEncryption) error
+
+	// UpdateBackupEncryption 更新备份加密设置
+	UpdateBackupEncryption(userid uint, newSetting *model.BackupEncryptionDto) error
+
 	// GetAgentInfo 获取 Agent 信息
 	GetAgentInfo(setting *model.AgentSetting) error
 
@@ -80,4 +118,10 @@ type SettingServiceInterface interface {
 
 	// UpdateImageProcessSetting 更新图片处理设置
 	UpdateImageProcessSetting(userid uint, newSetting *model.ImageProcessSettingDto) error
+
+	// GetTrashSetting 获取回收站设置
+	GetTrashSetting(setting *model.TrashSetting) error
+
+	// UpdateTrashSetting 更新回收站设置
+	UpdateTrashSetting(userid uint, newSetting *model.TrashSettingDto) error
 }
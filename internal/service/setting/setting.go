@@ -2,10 +2,14 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"filippo.io/age"
 	"github.com/lin-snow/ech0/internal/config"
 	"github.com/lin-snow/ech0/internal/event"
 	authModel "github.com/lin-snow/ech0/internal/model/auth"
@@ -17,6 +21,7 @@ import (
 	webhookRepository "github.com/lin-snow/ech0/internal/repository/webhook"
 	commonService "github.com/lin-snow/ech0/internal/service/common"
 	"github.com/lin-snow/ech0/internal/transaction"
+	cryptoUtil "github.com/lin-snow/ech0/internal/util/crypto"
 	fmtUtil "github.com/lin-snow/ech0/internal/util/format"
 	httpUtil "github.com/lin-snow/ech0/internal/util/http"
 	jsonUtil "github.com/lin-snow/ech0/internal/util/json"
@@ -25,12 +30,20 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// accessTokenUsageInterval 访问令牌使用记录的最小写入间隔
+	accessTokenUsageInterval = time.Minute
+	// maskedSecret 返回给前端的敏感字段占位符，更新时收到占位符表示保持原值
+	maskedSecret = "******"
+)
+
 type SettingService struct {
 	txManager          transaction.TransactionManager
 	commonService      commonService.CommonServiceInterface
 	keyvalueRepository keyvalueRepository.KeyValueRepositoryInterface
 	settingRepository  settingRepository.SettingRepositoryInterface
 	webhookRepository  webhookRepository.WebhookRepositoryInterface
+	webhookDispatcher  *event.WebhookDispatcher
 	eventBus           event.IEventBus
 }
 
@@ -40,6 +53,7 @@ func NewSettingService(
 	keyvalueRepository keyvalueRepository.KeyValueRepositoryInterface,
 	settingRepository settingRepository.SettingRepositoryInterface,
 	webhookRepository webhookRepository.WebhookRepositoryInterface,
+	webhookDispatcher *event.WebhookDispatcher,
 	ebProvider func() event.IEventBus,
 ) SettingServiceInterface {
 	return &SettingService{
@@ -47,6 +61,7 @@ func NewSettingService(
 		commonService:      commonService,
 		keyvalueRepository: keyvalueRepository,
 		webhookRepository:  webhookRepository,
+		webhookDispatcher:  webhookDispatcher,
 		settingRepository:  settingRepository,
 		eventBus:           ebProvider(),
 	}
@@ -204,7 +219,8 @@ func (settingService *SettingService) UpdateCommentSetting(
 		if newSetting.Provider != string(commonModel.TWIKOO) &&
 			newSetting.Provider != string(commonModel.ARTALK) &&
 			newSetting.Provider != string(commonModel.WALINE) &&
-			newSetting.Provider != string(commonModel.GISCUS) {
+			newSetting.Provider != string(commonModel.GISCUS) &&
+			newSetting.Provider != string(commonModel.ECH0) {
 			return errors.New(commonModel.NO_SUCH_COMMENT_PROVIDER)
 		}
 
@@ -212,6 +228,7 @@ func (settingService *SettingService) UpdateCommentSetting(
 			EnableComment: newSetting.EnableComment,
 			Provider:      newSetting.Provider,
 			CommentAPI:    httpUtil.TrimURL(newSetting.CommentAPI),
+			AutoApprove:   newSetting.AutoApprove,
 		}
 
 		// 序列化为 JSON
@@ -506,6 +523,9 @@ func (settingService *SettingService) DeleteWebhook(userid, id uint) error {
 	}
 
 	return settingService.txManager.Run(func(ctx context.Context) error {
+		if err := settingService.webhookRepository.DeleteDeliveriesByWebhookID(ctx, id); err != nil {
+			return err
+		}
 		return settingService.webhookRepository.DeleteWebhookByID(ctx, id)
 	})
 }
@@ -532,13 +552,20 @@ func (settingService *SettingService) UpdateWebhook(
 		return errors.New(commonModel.WEBHOOK_NAME_OR_URL_CANNOT_BE_EMPTY)
 	}
 
+	// 校验订阅的事件类型
+	if err := validateWebhookEventTypes(newWebhook.EventTypes); err != nil {
+		return err
+	}
+
 	// 保存到数据库
 	webhook := &webhookModel.Webhook{
-		ID:       id,
-		Name:     newWebhook.Name,
-		URL:      newWebhook.URL,
-		Secret:   newWebhook.Secret,
-		IsActive: newWebhook.IsActive,
+		ID:         id,
+		Name:       newWebhook.Name,
+		URL:        newWebhook.URL,
+		Secret:     newWebhook.Secret,
+		IsActive:   newWebhook.IsActive,
+		EventTypes: newWebhook.EventTypes,
+		Filter:     newWebhook.Filter,
 	}
 
 	return settingService.txManager.Run(func(ctx context.Context) error {
@@ -572,12 +599,19 @@ func (settingService *SettingService) CreateWebhook(
 		return errors.New(commonModel.WEBHOOK_NAME_OR_URL_CANNOT_BE_EMPTY)
 	}
 
+	// 校验订阅的事件类型
+	if err := validateWebhookEventTypes(newWebhook.EventTypes); err != nil {
+		return err
+	}
+
 	// 保存到数据库
 	webhook := &webhookModel.Webhook{
-		Name:     newWebhook.Name,
-		URL:      newWebhook.URL,
-		Secret:   newWebhook.Secret,
-		IsActive: newWebhook.IsActive,
+		Name:       newWebhook.Name,
+		URL:        newWebhook.URL,
+		Secret:     newWebhook.Secret,
+		IsActive:   newWebhook.IsActive,
+		EventTypes: newWebhook.EventTypes,
+		Filter:     newWebhook.Filter,
 	}
 
 	return settingService.txManager.Run(func(ctx context.Context) error {
@@ -585,6 +619,127 @@ func (settingService *SettingService) CreateWebhook(
 	})
 }
 
+// ListWebhookDeliveries 分页获取 Webhook 投递记录
+func (settingService *SettingService) ListWebhookDeliveries(
+	userid, id uint,
+	pageQueryDto commonModel.PageQueryDto,
+) (commonModel.PageQueryResult[[]webhookModel.WebhookDelivery], error) {
+	// 鉴权
+	user, err := settingService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return commonModel.PageQueryResult[[]webhookModel.WebhookDelivery]{}, err
+	}
+	if !user.IsAdmin {
+		return commonModel.PageQueryResult[[]webhookModel.WebhookDelivery]{}, errors.New(
+			commonModel.NO_PERMISSION_DENIED,
+		)
+	}
+
+	if pageQueryDto.Page < 1 {
+		pageQueryDto.Page = 1
+	}
+	if pageQueryDto.PageSize < 1 || pageQueryDto.PageSize > 100 {
+		pageQueryDto.PageSize = 10
+	}
+	offset := (pageQueryDto.Page - 1) * pageQueryDto.PageSize
+
+	deliveries, total, err := settingService.webhookRepository.ListDeliveries(
+		context.Background(),
+		id,
+		offset,
+		pageQueryDto.PageSize,
+	)
+	if err != nil {
+		return commonModel.PageQueryResult[[]webhookModel.WebhookDelivery]{}, err
+	}
+
+	return commonModel.PageQueryResult[[]webhookModel.WebhookDelivery]{
+		Items: deliveries,
+		Total: total,
+	}, nil
+}
+
+// RedeliverWebhook 将指定的 Webhook 事件重新加入投递队列，返回新的投递记录
+func (settingService *SettingService) RedeliverWebhook(
+	userid, id, deliveryID uint,
+) (webhookModel.WebhookDelivery, error) {
+	// 鉴权
+	user, err := settingService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return webhookModel.WebhookDelivery{}, err
+	}
+	if !user.IsAdmin {
+		return webhookModel.WebhookDelivery{}, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	ctx := context.Background()
+	delivery, err := settingService.webhookRepository.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return webhookModel.WebhookDelivery{}, err
+	}
+	if delivery.WebhookID != id {
+		return webhookModel.WebhookDelivery{}, errors.New(commonModel.WEBHOOK_DELIVERY_NOT_FOUND)
+	}
+
+	webhook, err := settingService.webhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		return webhookModel.WebhookDelivery{}, err
+	}
+
+	// 还原原始事件，沿用原事件 ID 便于接收方去重
+	var e event.Event
+	if err := json.Unmarshal(delivery.Payload, &e); err != nil {
+		return webhookModel.WebhookDelivery{}, err
+	}
+
+	// 异步投递，避免请求阻塞在重试上
+	redelivery, err := settingService.webhookDispatcher.Redeliver(ctx, &webhook, &e)
+	if err != nil {
+		return webhookModel.WebhookDelivery{}, err
+	}
+	return *redelivery, nil
+}
+
+// PingWebhook 向 Webhook 发送测试事件（无论是否启用）
+func (settingService *SettingService) PingWebhook(
+	userid, id uint,
+) (webhookModel.WebhookDelivery, error) {
+	// 鉴权
+	user, err := settingService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return webhookModel.WebhookDelivery{}, err
+	}
+	if !user.IsAdmin {
+		return webhookModel.WebhookDelivery{}, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	ctx := context.Background()
+	webhook, err := settingService.webhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		return webhookModel.WebhookDelivery{}, err
+	}
+
+	return *settingService.webhookDispatcher.Ping(ctx, &webhook), nil
+}
+
+// CleanupWebhookDeliveries 清理过期的 Webhook 投递记录
+func (settingService *SettingService) CleanupWebhookDeliveries() error {
+	before := time.Now().UTC().Add(-webhookModel.DeliveryRetention)
+	return settingService.txManager.Run(func(ctx context.Context) error {
+		return settingService.webhookRepository.DeleteDeliveriesBefore(ctx, before)
+	})
+}
+
+// validateWebhookEventTypes 校验 Webhook 订阅的事件类型是否合法
+func validateWebhookEventTypes(eventTypes []string) error {
+	for _, et := range eventTypes {
+		if !event.IsWebhookEventType(et) {
+			return errors.New(commonModel.INVALID_WEBHOOK_EVENT_TYPE)
+		}
+	}
+	return nil
+}
+
 // ListAccessTokens 列出访问令牌
 func (settingService *SettingService) ListAccessTokens(
 	userid uint,
@@ -651,8 +806,20 @@ func (settingService *SettingService) CreateAccessToken(
 		expiryDuration = 8 * time.Hour
 	}
 
-	// 生成jwt令牌
-	claims := jwtUtil.CreateClaimsWithExpiry(user, int64(expiryDuration))
+	// 校验权限范围
+	scopes, err := normalizeAccessTokenScopes(newToken.Scopes)
+	if err != nil {
+		return "", err
+	}
+
+	// 生成jwt令牌，jti 用于在数据库中定位令牌以支持撤销
+	jti := cryptoUtil.GenerateRandomString(32)
+	claims := jwtUtil.CreateAccessTokenClaims(
+		user,
+		int64(expiryDuration/time.Second),
+		jti,
+		scopes,
+	)
 	tokenString, err := jwtUtil.GenerateToken(claims)
 	if err != nil {
 		return "", err
@@ -671,7 +838,9 @@ func (settingService *SettingService) CreateAccessToken(
 	accessToken := &model.AccessTokenSetting{
 		UserID:    user.ID,
 		Token:     tokenString,
+		JTI:       jti,
 		Name:      name,
+		Scopes:    scopes,
 		Expiry:    expiryPtr,
 		CreatedAt: time.Now().UTC(),
 	}
@@ -696,11 +865,122 @@ func (settingService *SettingService) DeleteAccessToken(userid, id uint) error {
 		return errors.New(commonModel.NO_PERMISSION_DENIED)
 	}
 
+	token, err := settingService.settingRepository.GetAccessTokenByID(id)
+	if err != nil {
+		return err
+	}
+	if token.UserID != user.ID {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
 	return settingService.txManager.Run(func(ctx context.Context) error {
 		return settingService.settingRepository.DeleteAccessTokenByID(ctx, id)
 	})
 }
 
+// AuthenticateToken 校验请求携带的令牌是否仍然有效，返回访问令牌的权限范围
+// scoped 为 false 表示登录会话或旧版全权限令牌，不受权限范围限制
+func (settingService *SettingService) AuthenticateToken(
+	claims *authModel.MyClaims,
+	rawToken, ip string,
+) ([]string, bool, error) {
+	var (
+		token *model.AccessTokenSetting
+		err   error
+	)
+
+	switch claims.Type {
+	case authModel.TokenTypeSession:
+		// 登录会话由签名与过期时间保证有效性，无需查库
+		return nil, false, nil
+	case authModel.TokenTypeAccess:
+		token, err = settingService.settingRepository.GetAccessTokenByJTI(claims.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		if token == nil {
+			return nil, false, errors.New(commonModel.TOKEN_REVOKED)
+		}
+	default:
+		// 旧版令牌没有类型标识，只能通过令牌原文判断是否仍存在
+		token, err = settingService.settingRepository.GetAccessTokenByToken(rawToken)
+		if err != nil {
+			return nil, false, err
+		}
+		if token == nil {
+			// 库中不存在时仅放行有效期不超过登录会话时长的旧版会话，已删除的旧版访问令牌一律拒绝
+			if !isLegacySessionClaims(claims) {
+				return nil, false, errors.New(commonModel.TOKEN_REVOKED)
+			}
+			return nil, false, nil
+		}
+	}
+
+	now := time.Now().UTC()
+	if token.UserID != claims.Userid || (token.Expiry != nil && token.Expiry.Before(now)) {
+		return nil, false, errors.New(commonModel.TOKEN_REVOKED)
+	}
+
+	settingService.recordAccessTokenUsage(token, now, ip)
+
+	if claims.Type != authModel.TokenTypeAccess {
+		return nil, false, nil
+	}
+	return token.Scopes, true, nil
+}
+
+// isLegacySessionClaims 判断无类型标识的令牌是否为旧版登录会话
+func isLegacySessionClaims(claims *authModel.MyClaims) bool {
+	if claims.ExpiresAt == nil || claims.IssuedAt == nil {
+		return false
+	}
+	lifetime := claims.ExpiresAt.Sub(claims.IssuedAt.Time)
+	return lifetime <= time.Duration(config.Config.Auth.Jwt.Expires)*time.Second
+}
+
+// recordAccessTokenUsage 记录访问令牌的使用情况，同一来源每分钟最多写入一次
+func (settingService *SettingService) recordAccessTokenUsage(
+	token *model.AccessTokenSetting,
+	now time.Time,
+	ip string,
+) {
+	if token.LastUsedAt != nil && token.LastUsedIP == ip &&
+		now.Sub(*token.LastUsedAt) < accessTokenUsageInterval {
+		return
+	}
+
+	if err := settingService.txManager.Run(func(ctx context.Context) error {
+		return settingService.settingRepository.UpdateAccessTokenUsage(
+			ctx,
+			uint(token.ID),
+			now,
+			ip,
+		)
+	}); err != nil {
+		logUtil.GetLogger().Warn("Failed to record access token usage",
+			zap.Int("tokenID", token.ID),
+			zap.String("error", err.Error()))
+	}
+}
+
+// normalizeAccessTokenScopes 校验并去重访问令牌的权限范围
+func normalizeAccessTokenScopes(scopes []string) ([]string, error) {
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !slices.Contains(authModel.AccessTokenScopes, scope) {
+			return nil, errors.New(commonModel.INVALID_TOKEN_SCOPE)
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, errors.New(commonModel.INVALID_TOKEN_SCOPE)
+	}
+	return normalized, nil
+}
+
 // GetFediverseSetting 获取联邦网络设置
 func (settingService *SettingService) GetFediverseSetting(
 	userid uint,
@@ -796,6 +1076,7 @@ func (settingService *SettingService) GetBackupScheduleSetting(
 			setting.Enable = false
 			// 默认每周日凌晨2点备份
 			setting.CronExpression = "0 2 * * 0"
+			setting.Retention = model.DefaultBackupRetention()
 
 			// 序列化为 JSON
 			settingToJSON, err := jsonUtil.JSONMarshal(setting)
@@ -813,6 +1094,11 @@ func (settingService *SettingService) GetBackupScheduleSetting(
 			return err
 		}
 
+		// 旧版本数据没有保留策略，使用默认策略
+		if setting.Retention.IsZero() {
+			setting.Retention = model.DefaultBackupRetention()
+		}
+
 		return nil
 	})
 }
@@ -841,6 +1127,23 @@ func (settingService *SettingService) UpdateBackupScheduleSetting(
 			return errors.New(commonModel.INVALID_CRON_EXPRESSION)
 		}
 
+		// 未提交保留策略时沿用当前设置
+		if newSetting.Retention != nil {
+			setting.Retention = *newSetting.Retention
+		} else if current, err := settingService.keyvalueRepository.GetKeyValue(commonModel.BackupScheduleKey); err == nil {
+			var currentSetting model.BackupSchedule
+			if err := jsonUtil.JSONUnmarshal([]byte(current.(string)), &currentSetting); err == nil {
+				setting.Retention = currentSetting.Retention
+			}
+		}
+		if setting.Retention.KeepLast < 0 || setting.Retention.KeepDaily < 0 ||
+			setting.Retention.KeepWeekly < 0 || setting.Retention.KeepMonthly < 0 {
+			return errors.New(commonModel.INVALID_BACKUP_RETAIN)
+		}
+		if setting.Retention.IsZero() {
+			setting.Retention = model.DefaultBackupRetention()
+		}
+
 		settingToJSON, err := jsonUtil.JSONMarshal(setting)
 		if err != nil {
 			return err
@@ -870,6 +1173,240 @@ func (settingService *SettingService) UpdateBackupScheduleSetting(
 	})
 }
 
+// GetBackupDestinationSetting 获取异地备份设置（内部使用，不做鉴权）
+func (settingService *SettingService) GetBackupDestinationSetting(
+	setting *model.BackupDestination,
+) error {
+	backupDestination, err := settingService.keyvalueRepository.GetKeyValue(
+		commonModel.BackupDestinationKey,
+	)
+	if err != nil {
+		// 未配置时不启用异地备份
+		*setting = model.BackupDestination{Retention: model.DefaultBackupRetention()}
+		return nil
+	}
+
+	if err := jsonUtil.JSONUnmarshal([]byte(backupDestination.(string)), setting); err != nil {
+		return err
+	}
+	if setting.Retention.IsZero() {
+		setting.Retention = model.DefaultBackupRetention()
+	}
+
+	return nil
+}
+
+// GetBackupDestination 获取异地备份设置
+func (settingService *SettingService) GetBackupDestination(
+	userid uint,
+	setting *model.BackupDestination,
+) error {
+	// 鉴权
+	user, err := settingService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	if err := settingService.GetBackupDestinationSetting(setting); err != nil {
+		return err
+	}
+	if setting.SecretKey != "" {
+		setting.SecretKey = maskedSecret
+	}
+
+	return nil
+}
+
+// UpdateBackupDestination 更新异地备份设置
+func (settingService *SettingService) UpdateBackupDestination(
+	userid uint,
+	newSetting *model.BackupDestinationDto,
+) error {
+	// 鉴权
+	user, err := settingService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	setting := model.BackupDestination{
+		Type:       strings.TrimSpace(newSetting.Type),
+		LocalPath:  strings.TrimSpace(newSetting.LocalPath),
+		Provider:   strings.TrimSpace(newSetting.Provider),
+		Endpoint:   httpUtil.TrimURL(newSetting.Endpoint),
+		AccessKey:  strings.TrimSpace(newSetting.AccessKey),
+		SecretKey:  strings.TrimSpace(newSetting.SecretKey),
+		BucketName: strings.TrimSpace(newSetting.BucketName),
+		Region:     strings.TrimSpace(newSetting.Region),
+		UseSSL:     newSetting.UseSSL,
+		PathPrefix: strings.Trim(strings.TrimSpace(newSetting.PathPrefix), "/"),
+		Retention:  newSetting.Retention,
+	}
+	if setting.SecretKey == maskedSecret {
+		// 收到占位符时沿用已保存的密钥
+		var stored model.BackupDestination
+		if err := settingService.GetBackupDestinationSetting(&stored); err != nil {
+			return err
+		}
+		setting.SecretKey = stored.SecretKey
+	}
+
+	switch setting.Type {
+	case "":
+	case string(commonModel.LOCAL_FILE):
+		if !filepath.IsAbs(setting.LocalPath) {
+			return errors.New(commonModel.INVALID_BACKUP_DEST)
+		}
+	case string(commonModel.S3_FILE):
+		if setting.AccessKey == "" || setting.SecretKey == "" || setting.BucketName == "" {
+			return errors.New(commonModel.INVALID_BACKUP_DEST)
+		}
+		if setting.Endpoint == "" &&
+			(setting.Provider != string(commonModel.AWS) || setting.Region == "") {
+			return errors.New(commonModel.INVALID_BACKUP_DEST)
+		}
+	default:
+		return errors.New(commonModel.INVALID_BACKUP_DEST)
+	}
+
+	if setting.Retention.KeepLast < 0 || setting.Retention.KeepDaily < 0 ||
+		setting.Retention.KeepWeekly < 0 || setting.Retention.KeepMonthly < 0 {
+		return errors.New(commonModel.INVALID_BACKUP_RETAIN)
+	}
+	if setting.Retention.IsZero() {
+		setting.Retention = model.DefaultBackupRetention()
+	}
+
+	settingToJSON, err := jsonUtil.JSONMarshal(setting)
+	if err != nil {
+		return err
+	}
+
+	return settingService.txManager.Run(func(ctx context.Context) error {
+		if _, err := settingService.keyvalueRepository.GetKeyValue(commonModel.BackupDestinationKey); err != nil {
+			return settingService.keyvalueRepository.AddKeyValue(
+				ctx,
+				commonModel.BackupDestinationKey,
+				string(settingToJSON),
+			)
+		}
+		return settingService.keyvalueRepository.UpdateKeyValue(
+			ctx,
+			commonModel.BackupDestinationKey,
+			string(settingToJSON),
+		)
+	})
+}
+
+// GetBackupEncryptionSetting 获取备份加密设置（内部使用，不做鉴权）
+func (settingService *SettingService) GetBackupEncryptionSetting(
+	setting *model.BackupEncryption,
+) error {
+	backupEncryption, err := settingService.keyvalueRepository.GetKeyValue(
+		commonModel.BackupEncryptionKey,
+	)
+	if err != nil {
+		// 未配置时不加密
+		*setting = model.BackupEncryption{}
+		return nil
+	}
+
+	return jsonUtil.JSONUnmarshal([]byte(backupEncryption.(string)), setting)
+}
+
+// GetBackupEncryption 获取备份加密设置
+func (settingService *SettingService) GetBackupEncryption(
+	userid uint,
+	setting *model.BackupEncryption,
+) error {
+	// 鉴权
+	user, err := settingService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	if err := settingService.GetBackupEncryptionSetting(setting); err != nil {
+		return err
+	}
+	if setting.Passphrase != "" {
+		setting.Passphrase = maskedSecret
+	}
+
+	return nil
+}
+
+// UpdateBackupEncryption 更新备份加密设置
+func (settingService *SettingService) UpdateBackupEncryption(
+	userid uint,
+	newSetting *model.BackupEncryptionDto,
+) error {
+	// 鉴权
+	user, err := settingService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	setting := model.BackupEncryption{
+		Mode: strings.TrimSpace(newSetting.Mode),
+	}
+
+	switch setting.Mode {
+	case "":
+	case string(commonModel.BACKUP_ENCRYPT_PASSPHRASE):
+		setting.Passphrase = newSetting.Passphrase
+		if setting.Passphrase == maskedSecret {
+			// 收到占位符时沿用已保存的口令
+			var stored model.BackupEncryption
+			if err := settingService.GetBackupEncryptionSetting(&stored); err != nil {
+				return err
+			}
+			setting.Passphrase = stored.Passphrase
+		}
+		if setting.Passphrase == "" {
+			return errors.New(commonModel.INVALID_BACKUP_ENCRYPT)
+		}
+	case string(commonModel.BACKUP_ENCRYPT_X25519):
+		recipient, err := age.ParseX25519Recipient(strings.TrimSpace(newSetting.Recipient))
+		if err != nil {
+			return errors.New(commonModel.INVALID_BACKUP_ENCRYPT)
+		}
+		setting.Recipient = recipient.String()
+	default:
+		return errors.New(commonModel.INVALID_BACKUP_ENCRYPT)
+	}
+
+	settingToJSON, err := jsonUtil.JSONMarshal(setting)
+	if err != nil {
+		return err
+	}
+
+	return settingService.txManager.Run(func(ctx context.Context) error {
+		if _, err := settingService.keyvalueRepository.GetKeyValue(commonModel.BackupEncryptionKey); err != nil {
+			return settingService.keyvalueRepository.AddKeyValue(
+				ctx,
+				commonModel.BackupEncryptionKey,
+				string(settingToJSON),
+			)
+		}
+		return settingService.keyvalueRepository.UpdateKeyValue(
+			ctx,
+			commonModel.BackupEncryptionKey,
+			string(settingToJSON),
+		)
+	})
+}
+
 // GetAgentInfo 获取 Agent 信息
 func (settingService *SettingService) GetAgentInfo(setting *model.AgentSetting) error {
 	return settingService.txManager.Run(func(ctx context.Context) error {
@@ -1051,6 +1588,8 @@ func (settingService *SettingService) UpdateImageProcessSetting(
 			LocalFullParam:  newSetting.LocalFullParam,
 			S3ThumbParam:    newSetting.S3ThumbParam,
 			S3FullParam:     newSetting.S3FullParam,
+			CacheMaxBytes:   max(newSetting.CacheMaxBytes, 0),
+			SizePresets:     newSetting.SizePresets,
 		}
 
 		settingToJSON, err := jsonUtil.JSONMarshal(setting)
@@ -1064,3 +1603,62 @@ func (settingService *SettingService) UpdateImageProcessSetting(
 		return nil
 	})
 }
+
+// GetTrashSetting 获取回收站设置
+func (settingService *SettingService) GetTrashSetting(setting *model.TrashSetting) error {
+	return settingService.txManager.Run(func(ctx context.Context) error {
+		trashSetting, err := settingService.keyvalueRepository.GetKeyValue(commonModel.TrashSettingKey)
+		if err != nil {
+			// 数据库中不存在数据，手动添加初始数据
+			setting.RetentionDays = model.DefaultTrashRetentionDays
+
+			settingToJSON, err := jsonUtil.JSONMarshal(setting)
+			if err != nil {
+				return err
+			}
+			if err := settingService.keyvalueRepository.AddKeyValue(ctx, commonModel.TrashSettingKey, string(settingToJSON)); err != nil {
+				return err
+			}
+			return nil
+		}
+
+		if err := jsonUtil.JSONUnmarshal([]byte(trashSetting.(string)), setting); err != nil {
+			return err
+		}
+		if setting.RetentionDays <= 0 {
+			setting.RetentionDays = model.DefaultTrashRetentionDays
+		}
+		return nil
+	})
+}
+
+// UpdateTrashSetting 更新回收站设置
+func (settingService *SettingService) UpdateTrashSetting(userid uint, newSetting *model.TrashSettingDto) error {
+	user, err := settingService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	if newSetting.RetentionDays <= 0 {
+		return errors.New(commonModel.TRASH_RETENTION_INVALID)
+	}
+
+	return settingService.txManager.Run(func(ctx context.Context) error {
+		setting := &model.TrashSetting{
+			RetentionDays: newSetting.RetentionDays,
+		}
+
+		settingToJSON, err := jsonUtil.JSONMarshal(setting)
+		if err != nil {
+			return err
+		}
+
+		if err := settingService.keyvalueRepository.AddOrUpdateKeyValue(ctx, commonModel.TrashSettingKey, string(settingToJSON)); err != nil {
+			return err
+		}
+		return nil
+	})
+}
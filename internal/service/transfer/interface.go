@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"mime/multipart"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lin-snow/ech0/internal/transfer"
+)
+
+type TransferServiceInterface interface {
+	// ExportEchos 将全部 Echo 导出为可移植的压缩包，flavor 为 ndjson 或 markdown
+	ExportEchos(ctx *gin.Context, userid uint, flavor string) error
+
+	// ImportEchos 导入 Ech0 导出包或 Memos、Mastodon、Twitter/X 的归档，按内容指纹去重并重新映射用户
+	ImportEchos(
+		ctx *gin.Context,
+		userid uint,
+		source string,
+		file *multipart.FileHeader,
+	) (*transfer.ImportResult, error)
+
+	// RunImport 以系统管理员身份导入本地文件，供命令行使用
+	RunImport(ctx context.Context, source, filePath string) (*transfer.ImportResult, error)
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+	inboxModel "github.com/lin-snow/ech0/internal/model/inbox"
+	userModel "github.com/lin-snow/ech0/internal/model/user"
+	echoRepository "github.com/lin-snow/ech0/internal/repository/echo"
+	inboxRepository "github.com/lin-snow/ech0/internal/repository/inbox"
+	userRepository "github.com/lin-snow/ech0/internal/repository/user"
+	commonService "github.com/lin-snow/ech0/internal/service/common"
+	echoService "github.com/lin-snow/ech0/internal/service/echo"
+	"github.com/lin-snow/ech0/internal/transaction"
+	"github.com/lin-snow/ech0/internal/transfer"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	"go.uber.org/zap"
+)
+
+type TransferService struct {
+	txManager       transaction.TransactionManager
+	commonService   commonService.CommonServiceInterface
+	echoService     echoService.EchoServiceInterface
+	echoRepository  echoRepository.EchoRepositoryInterface
+	userRepository  userRepository.UserRepositoryInterface
+	inboxRepository inboxRepository.InboxRepositoryInterface
+}
+
+func NewTransferService(
+	tm transaction.TransactionManager,
+	commonService commonService.CommonServiceInterface,
+	echoService echoService.EchoServiceInterface,
+	echoRepository echoRepository.EchoRepositoryInterface,
+	userRepository userRepository.UserRepositoryInterface,
+	inboxRepository inboxRepository.InboxRepositoryInterface,
+) TransferServiceInterface {
+	return &TransferService{
+		txManager:       tm,
+		commonService:   commonService,
+		echoService:     echoService,
+		echoRepository:  echoRepository,
+		userRepository:  userRepository,
+		inboxRepository: inboxRepository,
+	}
+}
+
+// ExportEchos 将全部 Echo 导出为可移植的压缩包并触发下载
+func (transferService *TransferService) ExportEchos(ctx *gin.Context, userid uint, flavor string) error {
+	user, err := transferService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	if flavor == "" {
+		flavor = transfer.FlavorNDJSON
+	}
+
+	echos, err := transferService.echoRepository.GetAllEchos()
+	if err != nil {
+		return err
+	}
+
+	// 草稿与定时发布的 Echo 不导出，避免导入后被当作已发布
+	published := echos[:0]
+	for _, echo := range echos {
+		if echo.IsPublished() {
+			published = append(published, echo)
+		}
+	}
+	echos = published
+
+	if err := os.MkdirAll("./temp", 0o755); err != nil {
+		return err
+	}
+	exportPath := filepath.Join("temp", fmt.Sprintf("ech0_export_%d.zip", time.Now().UTC().UnixNano()))
+	defer func() {
+		_ = os.Remove(exportPath)
+	}()
+
+	if err := transfer.Export(exportPath, flavor, echos); err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("ech0-export-%s-%s.zip", flavor, time.Now().UTC().Format("2006-01-02-150405"))
+	ctx.Writer.Header().Set("Content-Type", "application/zip")
+	ctx.Writer.Header().
+		Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	ctx.Writer.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+
+	ctx.File(exportPath)
+
+	return nil
+}
+
+// ImportEchos 导入上传的文件，source 为数据来源（ech0/memos/mastodon/twitter），为空时自动识别
+// 内容指纹已存在的 Echo 会被跳过，因此重复导入同一个文件是安全的；
+// 导出包中的用户按用户名映射到本实例的同名用户，不存在时归属于执行导入的管理员。
+// 导入的是历史数据，不会触发 Echo 创建事件，也不会推送到联邦宇宙
+func (transferService *TransferService) ImportEchos(
+	ctx *gin.Context,
+	userid uint,
+	source string,
+	file *multipart.FileHeader,
+) (*transfer.ImportResult, error) {
+	user, err := transferService.commonService.CommonGetUserByUserId(userid)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin {
+		return nil, errors.New(commonModel.NO_PERMISSION_DENIED)
+	}
+
+	if err := os.MkdirAll("./temp", 0o755); err != nil {
+		return nil, err
+	}
+	tempFilePath := filepath.Join(
+		"temp",
+		fmt.Sprintf("ech0_import_%d%s", time.Now().UTC().UnixNano(), strings.ToLower(filepath.Ext(file.Filename))),
+	)
+	if err := ctx.SaveUploadedFile(file, tempFilePath); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.Remove(tempFilePath)
+	}()
+
+	return transferService.importFile(ctx.Request.Context(), user, source, tempFilePath)
+}
+
+// RunImport 以系统管理员身份导入本地文件，供命令行使用
+func (transferService *TransferService) RunImport(
+	ctx context.Context,
+	source, filePath string,
+) (*transfer.ImportResult, error) {
+	admin, err := transferService.commonService.GetSysAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	return transferService.importFile(ctx, admin, source, filePath)
+}
+
+// importFile 打开待导入的文件并逐条写入，开始与结束时向收件箱发送导入日志
+func (transferService *TransferService) importFile(
+	ctx context.Context,
+	user userModel.User,
+	source, filePath string,
+) (*transfer.ImportResult, error) {
+	if source == "" {
+		detected, err := transfer.DetectSource(filePath)
+		if err != nil {
+			return nil, err
+		}
+		source = detected
+	}
+
+	archive, err := transfer.Open(source, filePath)
+	if err != nil {
+		transferService.postImportLog(ctx, fmt.Sprintf("从 %s 导入失败：%s", source, err.Error()), nil)
+		return nil, err
+	}
+	defer func() {
+		_ = archive.Close()
+	}()
+
+	transferService.postImportLog(
+		ctx,
+		fmt.Sprintf("开始从 %s 导入，共 %d 条", source, len(archive.Records)),
+		nil,
+	)
+
+	result, err := transferService.importArchive(archive, user)
+	result.Source = source
+	result.Log = slices.Concat(archive.Notes, result.Log)
+	if err != nil {
+		result.Log = append(result.Log, err.Error())
+		transferService.postImportLog(
+			ctx,
+			fmt.Sprintf("从 %s 导入失败：已导入 %d 条，%s", source, result.Imported, err.Error()),
+			result,
+		)
+		return result, err
+	}
+
+	transferService.postImportLog(
+		ctx,
+		fmt.Sprintf(
+			"从 %s 导入完成：共 %d 条，新导入 %d 条，跳过 %d 条",
+			source, result.Total, result.Imported, result.Skipped+result.Invalid,
+		),
+		result,
+	)
+
+	logUtil.GetLogger().Info("Echos imported",
+		zap.String("source", source),
+		zap.Int("total", result.Total),
+		zap.Int("imported", result.Imported),
+		zap.Int("skipped", result.Skipped),
+		zap.Int("media", result.Media))
+
+	return result, nil
+}
+
+// importArchive 将记录逐条写入数据库，按内容指纹去重并重新映射用户
+func (transferService *TransferService) importArchive(
+	archive *transfer.Archive,
+	user userModel.User,
+) (*transfer.ImportResult, error) {
+	result := &transfer.ImportResult{
+		Total: len(archive.Records),
+		Users: make(map[string]uint),
+	}
+
+	// 已有 Echo 的内容指纹，用于去重
+	existing, err := transferService.echoRepository.GetAllEchos()
+	if err != nil {
+		return result, err
+	}
+	hashes := make(map[string]struct{}, len(existing))
+	for _, echo := range existing {
+		hashes[transfer.EchoHash(echo)] = struct{}{}
+	}
+
+	// 本实例的用户，按用户名映射
+	localUsers, err := transferService.userRepository.GetAllUsers()
+	if err != nil {
+		return result, err
+	}
+	usersByName := make(map[string]uint, len(localUsers))
+	for _, localUser := range localUsers {
+		usersByName[localUser.Username] = localUser.ID
+	}
+
+	for _, record := range archive.Records {
+		hash := record.Hash()
+		if _, ok := hashes[hash]; ok {
+			result.Skipped++
+			continue
+		}
+		if record.Content == "" && len(record.Media) == 0 &&
+			(record.Extension == "" || record.ExtensionType == "") {
+			result.Invalid++
+			continue
+		}
+
+		ownerID, ownerName := user.ID, user.Username
+		if id, ok := usersByName[record.Username]; ok {
+			ownerID, ownerName = id, record.Username
+		}
+		result.Users[record.Username] = ownerID
+
+		pending, err := archive.Prepare(record, ownerID, ownerName)
+		if err != nil {
+			return result, err
+		}
+
+		if err := transferService.createEcho(pending); err != nil {
+			pending.Discard()
+			return result, err
+		}
+
+		hashes[hash] = struct{}{}
+		result.Imported++
+		result.Media += len(pending.Files)
+		result.MissingMedia += pending.MissingMedia
+		if pending.MissingMedia > 0 {
+			result.Log = append(result.Log, fmt.Sprintf(
+				"%s 发布的记录缺少 %d 个媒体文件",
+				record.CreatedAt.Local().Format("2006-01-02 15:04:05"), pending.MissingMedia,
+			))
+		}
+	}
+
+	return result, nil
+}
+
+// postImportLog 向收件箱发送导入日志，发送失败只记录日志，不影响导入
+func (transferService *TransferService) postImportLog(
+	ctx context.Context,
+	content string,
+	result *transfer.ImportResult,
+) {
+	var meta string
+	if result != nil {
+		if data, err := json.Marshal(result); err == nil {
+			meta = string(data)
+		}
+	}
+
+	if err := transferService.inboxRepository.PostInbox(ctx, &inboxModel.Inbox{
+		Source:    string(commonModel.SystemSource),
+		Content:   content,
+		Type:      string(commonModel.NotificationInboxType),
+		Meta:      meta,
+		CreatedAt: time.Now().UTC().Unix(),
+	}); err != nil {
+		logUtil.GetLogger().Warn("Failed to post import log to inbox", zap.String("error", err.Error()))
+	}
+}
+
+// createEcho 在事务中写入 Echo、标签与实况照片关联
+func (transferService *TransferService) createEcho(pending *transfer.PendingEcho) error {
+	return transferService.txManager.Run(func(ctx context.Context) error {
+		if err := transferService.echoService.ProcessEchoTags(ctx, pending.Echo); err != nil {
+			return err
+		}
+
+		if err := transferService.echoRepository.CreateEcho(ctx, pending.Echo); err != nil {
+			return err
+		}
+
+		// 点赞数由点赞记录计算，导入的点赞数以来源不明的记录补齐
+		if err := transferService.echoRepository.CreateLegacyEchoLikes(
+			ctx,
+			pending.Echo.ID,
+			pending.Echo.FavCount,
+		); err != nil {
+			return err
+		}
+
+		for imageIndex, videoIndex := range pending.LivePairs {
+			image := &pending.Echo.Media[imageIndex]
+			video := pending.Echo.Media[videoIndex]
+			if image.MediaType != echoModel.MediaTypeImage || video.MediaType != echoModel.MediaTypeVideo {
+				continue
+			}
+			if err := transferService.echoRepository.UpdateMediaLiveVideoID(ctx, image.ID, video.ID); err != nil {
+				return err
+			}
+			image.LiveVideoID = &video.ID
+		}
+
+		return nil
+	})
+}
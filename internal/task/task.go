@@ -7,11 +7,13 @@ import (
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
-	"github.com/lin-snow/ech0/internal/backup"
+	"github.com/lin-snow/ech0/internal/cache"
 	"github.com/lin-snow/ech0/internal/event"
 	settingModel "github.com/lin-snow/ech0/internal/model/setting"
 	queueRepository "github.com/lin-snow/ech0/internal/repository/queue"
+	backupService "github.com/lin-snow/ech0/internal/service/backup"
 	commonService "github.com/lin-snow/ech0/internal/service/common"
+	echoService "github.com/lin-snow/ech0/internal/service/echo"
 	pwaService "github.com/lin-snow/ech0/internal/service/pwa"
 	settingService "github.com/lin-snow/ech0/internal/service/setting"
 	logUtil "github.com/lin-snow/ech0/internal/util/log"
@@ -22,18 +24,24 @@ import (
 type Tasker struct {
 	scheduler      gocron.Scheduler
 	commonService  commonService.CommonServiceInterface
+	echoService    echoService.EchoServiceInterface
 	settingService settingService.SettingServiceInterface
 	eventBus       event.IEventBus
 	queueRepo      queueRepository.QueueRepositoryInterface
 	pwaService     pwaService.PwaServiceInterface
+	backupService  backupService.BackupServiceInterface
+	imageCache     *cache.DiskCache
 }
 
 func NewTasker(
 	commonService commonService.CommonServiceInterface,
+	echoService echoService.EchoServiceInterface,
 	settingService settingService.SettingServiceInterface,
 	eventBusProvider func() event.IEventBus,
 	queueRepo queueRepository.QueueRepositoryInterface,
 	pwaService pwaService.PwaServiceInterface,
+	backupService backupService.BackupServiceInterface,
+	imageCache *cache.DiskCache,
 ) *Tasker {
 	scheduler, err := gocron.NewScheduler()
 	if err != nil {
@@ -46,18 +54,25 @@ func NewTasker(
 	return &Tasker{
 		scheduler:      scheduler,
 		commonService:  commonService,
+		echoService:    echoService,
 		settingService: settingService,
 		eventBus:       eventBusProvider(),
 		queueRepo:      queueRepo,
 		pwaService:     pwaService,
+		backupService:  backupService,
+		imageCache:     imageCache,
 	}
 }
 
 func (t *Tasker) Start() {
-	t.CleanupTempFilesTask()  // 启动清理临时文件任务
-	t.DeadLetterConsumeTask() // 启动死信任务消费任务
-	t.InboxTask()             // 启动Inbox任务
-	t.PwaPushTask()           // 启动PWA推送监控任务
+	t.CleanupTempFilesTask()       // 启动清理临时文件任务
+	t.DeadLetterConsumeTask()      // 启动死信任务消费任务
+	t.InboxTask()                  // 启动Inbox任务
+	t.PwaPushTask()                // 启动PWA推送监控任务
+	t.WebhookDeliveryCleanupTask() // 启动Webhook投递记录清理任务
+	t.ImageCacheEvictTask()        // 启动图片缓存淘汰任务
+	t.ScheduledEchoPublishTask()   // 启动定时发布Echo任务
+	t.TrashPurgeTask()             // 启动回收站清理任务
 
 	// 读取自动备份cron设置
 	var backupScheduleSetting settingModel.BackupSchedule
@@ -103,14 +118,14 @@ func (t *Tasker) CleanupTempFilesTask() {
 
 // DeadLetterConsumeTask 死信任务消费任务
 func (t *Tasker) DeadLetterConsumeTask() {
-	// 每天12点执行一次, 测试时为每30秒执行一次
+	// 每小时执行一次，只处理已到重试时间的任务, 测试时为每30秒执行一次
 	_, err := t.scheduler.NewJob(
-		gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(12, 0, 0))),
+		gocron.DurationJob(time.Hour),
 		// gocron.DurationJob(30*time.Second), // 测试时为每30秒执行一次
 		gocron.NewTask(
 			func() {
 				// 取出死信队列中的任务，逐个重试
-				deadLetters, err := t.queueRepo.ListDeadLetters(10)
+				deadLetters, err := t.queueRepo.ListDeadLetters(50)
 				if err != nil {
 					logUtil.GetLogger().
 						Error("Failed To Get DeadLetters!", zap.String("error", err.Error()))
@@ -156,26 +171,13 @@ func (t *Tasker) ScheduleBackupTask(cronExpression string) {
 		gocron.CronJob(cronExpression, withSeconds),
 		gocron.NewTask(
 			func() {
-				// 执行备份
-				if path, fileName, err := backup.ExecuteBackup(); err != nil {
-					logUtil.GetLogger().Error("Failed to execute scheduled backup",
-						zap.String("path", path),
-						zap.String("fileName", fileName),
-						zap.String("error", err.Error()))
-				}
-
-				// 发布备份完成事件
-				if err := t.eventBus.Publish(
+				// 执行备份，结果通过 system.backup 事件通知
+				if err := t.backupService.RunBackup(
 					context.Background(),
-					event.NewEvent(
-						event.EventTypeSystemBackup,
-						event.EventPayload{
-							event.EventPayloadInfo: "System scheduled backup completed",
-						},
-					),
+					"System scheduled backup completed",
 				); err != nil {
-					logUtil.GetLogger().
-						Error("Failed to publish backup completed event", zap.String("error", err.Error()))
+					logUtil.GetLogger().Error("Failed to execute scheduled backup",
+						zap.String("error", err.Error()))
 				}
 			},
 		),
@@ -244,3 +246,107 @@ func (t *Tasker) PwaPushTask() {
 		logUtil.GetLogger().Error("Failed to schedule PwaPushTask", zap.String("error", err.Error()))
 	}
 }
+
+// WebhookDeliveryCleanupTask 清理过期的 Webhook 投递记录
+func (t *Tasker) WebhookDeliveryCleanupTask() {
+	// 每天执行一次
+	_, err := t.scheduler.NewJob(
+		gocron.DurationJob(24*time.Hour),
+		gocron.NewTask(
+			func() {
+				if err := t.settingService.CleanupWebhookDeliveries(); err != nil {
+					logUtil.GetLogger().
+						Error("Failed to clean up webhook deliveries", zap.String("error", err.Error()))
+				}
+			},
+		),
+	)
+	if err != nil {
+		logUtil.GetLogger().
+			Error("Failed to schedule WebhookDeliveryCleanupTask", zap.String("error", err.Error()))
+	}
+}
+
+// ImageCacheEvictTask 按图片处理设置中的容量上限淘汰图片磁盘缓存
+func (t *Tasker) ImageCacheEvictTask() {
+	// 启动时执行一次，之后每 30 分钟执行一次
+	_, err := t.scheduler.NewJob(
+		gocron.DurationJob(30*time.Minute),
+		gocron.NewTask(
+			func() {
+				maxBytes := cache.DefaultImageCacheMaxBytes
+				var processSetting settingModel.ImageProcessSetting
+				if err := t.settingService.GetImageProcessSetting(&processSetting); err != nil {
+					logUtil.GetLogger().
+						Error("Failed to get image process setting", zap.String("error", err.Error()))
+				} else if processSetting.CacheMaxBytes > 0 {
+					maxBytes = processSetting.CacheMaxBytes
+				}
+				t.imageCache.SetMaxBytes(maxBytes)
+
+				if count, freed := t.imageCache.Evict(); count > 0 {
+					logUtil.GetLogger().Info("Image cache evicted",
+						zap.Int("files", count),
+						zap.Int64("bytes", freed))
+				}
+			},
+		),
+		gocron.WithStartAt(gocron.WithStartImmediately()),
+	)
+	if err != nil {
+		logUtil.GetLogger().
+			Error("Failed to schedule ImageCacheEvictTask", zap.String("error", err.Error()))
+	}
+}
+
+// ScheduledEchoPublishTask 发布已到发布时间的定时 Echo
+func (t *Tasker) ScheduledEchoPublishTask() {
+	// 启动时执行一次（补发停机期间到期的 Echo），之后每分钟执行一次
+	_, err := t.scheduler.NewJob(
+		gocron.DurationJob(time.Minute),
+		gocron.NewTask(
+			func() {
+				if err := t.echoService.PublishScheduledEchos(); err != nil {
+					logUtil.GetLogger().
+						Error("Failed to publish scheduled echos", zap.String("error", err.Error()))
+				}
+			},
+		),
+		gocron.WithStartAt(gocron.WithStartImmediately()),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	if err != nil {
+		logUtil.GetLogger().
+			Error("Failed to schedule ScheduledEchoPublishTask", zap.String("error", err.Error()))
+	}
+}
+
+// TrashPurgeTask 永久删除在回收站中超过保留期限的 Echo
+// 媒体文件随后交由 CleanupTempFilesTask 删除
+func (t *Tasker) TrashPurgeTask() {
+	// 每小时执行一次
+	_, err := t.scheduler.NewJob(
+		gocron.DurationJob(time.Hour),
+		gocron.NewTask(
+			func() {
+				var trashSetting settingModel.TrashSetting
+				if err := t.settingService.GetTrashSetting(&trashSetting); err != nil {
+					logUtil.GetLogger().
+						Error("Failed to get trash setting", zap.String("error", err.Error()))
+					return
+				}
+
+				retention := time.Duration(trashSetting.RetentionDays) * 24 * time.Hour
+				if err := t.echoService.PurgeExpiredTrash(retention); err != nil {
+					logUtil.GetLogger().
+						Error("Failed to purge expired trash", zap.String("error", err.Error()))
+				}
+			},
+		),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	if err != nil {
+		logUtil.GetLogger().
+			Error("Failed to schedule TrashPurgeTask", zap.String("error", err.Error()))
+	}
+}
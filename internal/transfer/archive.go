@@ -0,0 +1,177 @@
+package transfer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+)
+
+// Archive 已解析的导出包，其他平台的数据由对应的适配器转换为同样的结构
+type Archive struct {
+	Manifest Manifest
+	Records  []Record
+	Notes    []string // 解析过程中需要告知用户的信息，例如被跳过的内容
+
+	files     fs.FS                                    // 导出包的文件系统，记录中 Media.File 默认为其中的路径
+	openMedia func(name string) (io.ReadCloser, error) // 媒体不在文件系统中时（例如存放在数据库里）使用的读取方式
+	closer    io.Closer                                // 关闭导出包时需要释放的资源
+}
+
+// OpenArchive 打开导出包，支持 NDJSON 与 Markdown 两种风格的 zip，也支持单独的 .ndjson 文件
+func OpenArchive(filePath string) (*Archive, error) {
+	reader, err := zip.OpenReader(filePath)
+	if errors.Is(err, zip.ErrFormat) {
+		return openNDJSONFile(filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{files: reader, closer: reader}
+	if err := archive.load(); err != nil {
+		_ = reader.Close()
+		return nil, err
+	}
+	return archive, nil
+}
+
+// OpenMedia 打开导出包中的媒体文件
+func (a *Archive) OpenMedia(name string) (io.ReadCloser, error) {
+	if a.openMedia != nil {
+		return a.openMedia(name)
+	}
+	if a.files == nil {
+		return nil, fs.ErrNotExist
+	}
+	return a.files.Open(path.Clean(name))
+}
+
+// Close 关闭导出包
+func (a *Archive) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// notef 记录一条解析信息
+func (a *Archive) notef(format string, args ...any) {
+	a.Notes = append(a.Notes, fmt.Sprintf(format, args...))
+}
+
+// load 读取清单与记录
+func (a *Archive) load() error {
+	if err := a.readManifest(); err != nil {
+		return err
+	}
+
+	if file, err := a.files.Open(echosFileName); err == nil {
+		defer func() {
+			_ = file.Close()
+		}()
+		a.Records, err = decodeNDJSON(file)
+		return err
+	}
+
+	// 没有 echos.ndjson 时按 Markdown 风格读取，按文件名排序以保持导出顺序
+	names, err := fs.Glob(a.files, path.Join(markdownDir, "*.md"))
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return errors.New(commonModel.INVALID_EXPORT_FILE)
+	}
+
+	for _, name := range names {
+		data, err := a.readFile(name)
+		if err != nil {
+			return err
+		}
+		record, err := parseMarkdown(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		a.Records = append(a.Records, record)
+	}
+	return nil
+}
+
+// readManifest 读取并校验清单，清单缺失时视为当前版本
+func (a *Archive) readManifest() error {
+	data, err := a.readFile(manifestFileName)
+	if errors.Is(err, os.ErrNotExist) {
+		a.Manifest = Manifest{Format: FormatName, FormatVersion: FormatVersion}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &a.Manifest); err != nil {
+		return fmt.Errorf("%s: %w", commonModel.INVALID_EXPORT_FILE, err)
+	}
+	return a.Manifest.validate()
+}
+
+// readFile 读取压缩包中的文件内容
+func (a *Archive) readFile(name string) ([]byte, error) {
+	return fs.ReadFile(a.files, name)
+}
+
+// validate 校验清单的格式名称与版本
+func (m *Manifest) validate() error {
+	if m.Format != FormatName {
+		return errors.New(commonModel.INVALID_EXPORT_FILE)
+	}
+	if m.FormatVersion < 1 || m.FormatVersion > FormatVersion {
+		return fmt.Errorf("%s: %d", commonModel.UNSUPPORTED_EXPORT_VERSION, m.FormatVersion)
+	}
+	return nil
+}
+
+// openNDJSONFile 读取单独的 NDJSON 文件，此时没有媒体文件
+func openNDJSONFile(filePath string) (*Archive, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	records, err := decodeNDJSON(file)
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{
+		Manifest: Manifest{Format: FormatName, FormatVersion: FormatVersion, Flavor: FlavorNDJSON},
+		Records:  records,
+	}, nil
+}
+
+// decodeNDJSON 逐行解析记录
+func decodeNDJSON(r io.Reader) ([]Record, error) {
+	var records []Record
+	decoder := json.NewDecoder(r)
+	for {
+		var record Record
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", commonModel.INVALID_EXPORT_FILE, err)
+		}
+		record.Content = strings.TrimSpace(record.Content)
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,236 @@
+package transfer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lin-snow/ech0/internal/config"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+	fileUtil "github.com/lin-snow/ech0/internal/util/file"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	"go.uber.org/zap"
+)
+
+// Export 将 Echo 按指定风格导出为 zip 压缩包，本地媒体文件一并打包到 media 目录
+func Export(dest, flavor string, echos []echoModel.Echo) error {
+	if flavor != FlavorNDJSON && flavor != FlavorMarkdown {
+		return fmt.Errorf("%s: %s", commonModel.INVALID_EXPORT_FLAVOR, flavor)
+	}
+
+	records := make([]Record, 0, len(echos))
+	mediaFiles := make(map[string]string) // 压缩包内路径 -> 本地路径
+	users := make(map[uint]string)
+	for _, echo := range echos {
+		record, files := newRecord(echo)
+		records = append(records, record)
+		for name, localPath := range files {
+			mediaFiles[name] = localPath
+		}
+		users[echo.UserID] = echo.Username
+	}
+
+	manifest := Manifest{
+		Format:        FormatName,
+		FormatVersion: FormatVersion,
+		Flavor:        flavor,
+		AppVersion:    commonModel.FullVersion,
+		ExportedAt:    time.Now().UTC(),
+		Count:         len(records),
+		Users:         make([]User, 0, len(users)),
+	}
+	for id, username := range users {
+		manifest.Users = append(manifest.Users, User{ID: id, Username: username})
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = out.Close()
+	}()
+
+	zipWriter := zip.NewWriter(out)
+	if err := writeJSON(zipWriter, manifestFileName, manifest); err != nil {
+		return err
+	}
+
+	if flavor == FlavorMarkdown {
+		err = writeMarkdown(zipWriter, records)
+	} else {
+		err = writeNDJSON(zipWriter, records)
+	}
+	if err != nil {
+		return err
+	}
+
+	for name, localPath := range mediaFiles {
+		if err := addFile(zipWriter, name, localPath); err != nil {
+			return err
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// newRecord 将 Echo 转换为导出记录，并返回需要打包的本地媒体文件
+func newRecord(echo echoModel.Echo) (Record, map[string]string) {
+	record := Record{
+		ID:            echo.ID,
+		UserID:        echo.UserID,
+		Username:      echo.Username,
+		Content:       echo.Content,
+		Private:       echo.Private,
+		Layout:        echo.Layout,
+		ExtensionType: echo.ExtensionType,
+		Extension:     echo.Extension,
+		Tags:          make([]string, 0, len(echo.Tags)),
+		Media:         make([]Media, 0, len(echo.Media)),
+		FavCount:      echo.FavCount,
+		CreatedAt:     echo.CreatedAt.UTC(),
+	}
+	record.ContentHash = record.Hash()
+
+	for _, tag := range echo.Tags {
+		record.Tags = append(record.Tags, tag.Name)
+	}
+
+	files := make(map[string]string)
+	indexes := make(map[uint]int, len(echo.Media))
+	for i, m := range echo.Media {
+		indexes[m.ID] = i
+		media := Media{
+			Type:      m.MediaType,
+			Source:    m.MediaSource,
+			URL:       m.MediaURL,
+			ObjectKey: m.ObjectKey,
+			Width:     m.Width,
+			Height:    m.Height,
+		}
+
+		if m.MediaSource == echoModel.MediaSourceLocal {
+			if name, localPath, ok := localMediaFile(m.MediaURL); ok {
+				media.File = name
+				files[name] = localPath
+			} else {
+				logUtil.GetLogger().Warn("Local media file missing, exporting reference only",
+					zap.Uint("echo_id", echo.ID),
+					zap.String("url", m.MediaURL))
+			}
+		}
+		record.Media = append(record.Media, media)
+	}
+
+	// 实况照片的关联以下标表示，与数据库 ID 无关
+	for i, m := range echo.Media {
+		if m.LiveVideoID == nil {
+			continue
+		}
+		if index, ok := indexes[*m.LiveVideoID]; ok {
+			record.Media[i].LiveVideo = &index
+		}
+	}
+
+	return record, files
+}
+
+// localMediaFile 根据本地媒体 URL 找到对应文件及其在压缩包中的路径
+func localMediaFile(url string) (string, string, bool) {
+	var kind, baseDir string
+	switch {
+	case strings.HasPrefix(url, "/images/"):
+		kind, baseDir = "images", config.Config.Upload.ImagePath
+	case strings.HasPrefix(url, "/videos/"):
+		kind, baseDir = "videos", config.Config.Upload.VideoPath
+	default:
+		return "", "", false
+	}
+
+	fileName := path.Base(url)
+	localPath := filepath.Join(baseDir, fileName)
+	if !fileUtil.FileExists(localPath) {
+		return "", "", false
+	}
+
+	return path.Join(mediaDir, kind, fileName), localPath, true
+}
+
+// writeNDJSON 以每行一条记录的形式写入 echos.ndjson
+func writeNDJSON(zipWriter *zip.Writer, records []Record) error {
+	writer, err := zipWriter.Create(echosFileName)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMarkdown 为每条记录写入一个 Markdown 文件
+func writeMarkdown(zipWriter *zip.Writer, records []Record) error {
+	for _, record := range records {
+		data, err := renderMarkdown(record)
+		if err != nil {
+			return err
+		}
+
+		writer, err := zipWriter.Create(markdownFileName(record))
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSON 将对象以缩进格式写入压缩包
+func writeJSON(zipWriter *zip.Writer, name string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	writer, err := zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
+
+// addFile 将本地文件写入压缩包
+func addFile(zipWriter *zip.Writer, name, localPath string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	writer, err := zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, file)
+	return err
+}
@@ -0,0 +1,100 @@
+package transfer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+)
+
+// 导出格式说明见 docs/export-format.md
+const (
+	FormatName    = "ech0-export" // 导出格式名称
+	FormatVersion = 1             // 导出格式版本，不兼容的改动需要递增
+
+	FlavorNDJSON   = "ndjson"   // 每行一条 Echo 的 JSON
+	FlavorMarkdown = "markdown" // 每条 Echo 一个带 YAML front matter 的 Markdown 文件
+
+	manifestFileName = "ech0_export.json" // 导出清单文件名
+	echosFileName    = "echos.ndjson"     // NDJSON 数据文件名
+	markdownDir      = "echos"            // Markdown 文件所在目录
+	mediaDir         = "media"            // 本地媒体文件所在目录
+)
+
+// Manifest 导出清单，描述导出包的格式与来源
+type Manifest struct {
+	Format        string    `json:"format"`
+	FormatVersion int       `json:"format_version"`
+	Flavor        string    `json:"flavor"`
+	AppVersion    string    `json:"app_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+	Count         int       `json:"count"` // 导出的 Echo 数量
+	Users         []User    `json:"users"` // 导出数据中出现的用户，导入时据此重新映射
+}
+
+// User 导出数据中的用户
+type User struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+// Record 导出包中的一条 Echo
+type Record struct {
+	ID            uint      `json:"id"                       yaml:"id"`
+	ContentHash   string    `json:"content_hash"             yaml:"content_hash"`
+	UserID        uint      `json:"user_id"                  yaml:"user_id"`
+	Username      string    `json:"username"                 yaml:"username"`
+	Content       string    `json:"content"                  yaml:"-"` // Markdown 风格中作为正文
+	Private       bool      `json:"private"                  yaml:"private"`
+	Layout        string    `json:"layout,omitempty"         yaml:"layout,omitempty"`
+	ExtensionType string    `json:"extension_type,omitempty" yaml:"extension_type,omitempty"`
+	Extension     string    `json:"extension,omitempty"      yaml:"extension,omitempty"`
+	Tags          []string  `json:"tags"                     yaml:"tags,omitempty"`
+	Media         []Media   `json:"media"                    yaml:"media,omitempty"`
+	FavCount      int       `json:"fav_count"                yaml:"fav_count"`
+	CreatedAt     time.Time `json:"created_at"               yaml:"created_at"`
+}
+
+// Media 导出包中 Echo 引用的媒体
+type Media struct {
+	Type      string `json:"type"                 yaml:"type"`                 // 媒体类型: image/video
+	Source    string `json:"source"               yaml:"source"`               // 媒体来源: local/url/s3
+	File      string `json:"file,omitempty"       yaml:"file,omitempty"`       // 本地媒体在导出包中的路径
+	URL       string `json:"url,omitempty"        yaml:"url,omitempty"`        // 原始媒体地址
+	ObjectKey string `json:"object_key,omitempty" yaml:"object_key,omitempty"` // 对象存储的 Key
+	Width     int    `json:"width,omitempty"      yaml:"width,omitempty"`
+	Height    int    `json:"height,omitempty"     yaml:"height,omitempty"`
+	LiveVideo *int   `json:"live_video,omitempty" yaml:"live_video,omitempty"` // 实况照片对应视频在 media 中的下标
+}
+
+// ContentHash 计算用于去重的内容指纹
+// 指纹只取决于内容、扩展与创建时间（秒），与 ID、用户和媒体无关，
+// 因此同一条 Echo 在不同实例之间迁移、或部分媒体缺失时仍能被识别
+func ContentHash(content, extensionType, extension string, createdAt time.Time) string {
+	content = strings.TrimSpace(strings.ReplaceAll(content, "\r\n", "\n"))
+
+	hash := sha256.New()
+	for _, part := range []string{
+		content,
+		extensionType,
+		extension,
+		strconv.FormatInt(createdAt.UTC().Unix(), 10),
+	} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// EchoHash 计算已有 Echo 的内容指纹
+func EchoHash(echo echoModel.Echo) string {
+	return ContentHash(echo.Content, echo.ExtensionType, echo.Extension, echo.CreatedAt)
+}
+
+// Hash 计算记录的内容指纹，导入时以此为准而不信任文件中的 content_hash
+func (r *Record) Hash() string {
+	return ContentHash(r.Content, r.ExtensionType, r.Extension, r.CreatedAt)
+}
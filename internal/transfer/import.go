@@ -0,0 +1,158 @@
+package transfer
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+	logUtil "github.com/lin-snow/ech0/internal/util/log"
+	storageUtil "github.com/lin-snow/ech0/internal/util/storage"
+	"go.uber.org/zap"
+)
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Source       string          `json:"source"`        // 数据来源
+	Total        int             `json:"total"`         // 导出包中的 Echo 数量
+	Imported     int             `json:"imported"`      // 新导入的 Echo 数量
+	Skipped      int             `json:"skipped"`       // 内容指纹已存在而跳过的数量
+	Invalid      int             `json:"invalid"`       // 内容为空而跳过的数量
+	Media        int             `json:"media"`         // 复制到本实例的本地媒体文件数量
+	MissingMedia int             `json:"missing_media"` // 导出包中缺失或类型不允许而未能导入的本地媒体数量
+	Users        map[string]uint `json:"users"`         // 导出包中的用户名 -> 本实例用户 ID
+	Log          []string        `json:"log"`           // 导入过程中跳过的内容与缺失的媒体
+}
+
+// PendingEcho 由导出记录转换而来、等待写入数据库的 Echo
+type PendingEcho struct {
+	Echo         *echoModel.Echo
+	LivePairs    map[int]int // 实况照片在 Echo.Media 中的下标：图片 -> 视频
+	Files        []string    // 已复制到上传目录的文件，写入失败时需要删除
+	MissingMedia int
+}
+
+// Discard 删除已复制到上传目录的文件
+func (p *PendingEcho) Discard() {
+	for _, file := range p.Files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			logUtil.GetLogger().Warn("Failed to remove imported media file",
+				zap.String("path", file),
+				zap.String("error", err.Error()))
+		}
+	}
+}
+
+// Prepare 将记录转换为属于指定用户的 Echo，本地媒体文件复制到上传目录，
+// 对象存储与外链媒体保留原地址
+func (a *Archive) Prepare(record Record, userID uint, username string) (*PendingEcho, error) {
+	layout := record.Layout
+	if layout == "" {
+		layout = echoModel.LayoutWaterfall
+	}
+
+	pending := &PendingEcho{
+		Echo: &echoModel.Echo{
+			Content:       record.Content,
+			Username:      username,
+			Layout:        layout,
+			Private:       record.Private,
+			UserID:        userID,
+			Extension:     record.Extension,
+			ExtensionType: record.ExtensionType,
+			FavCount:      record.FavCount,
+			CreatedAt:     record.CreatedAt,
+		},
+		LivePairs: make(map[int]int),
+	}
+	if pending.Echo.Extension == "" || pending.Echo.ExtensionType == "" {
+		pending.Echo.Extension = ""
+		pending.Echo.ExtensionType = ""
+	}
+
+	for _, name := range record.Tags {
+		pending.Echo.Tags = append(pending.Echo.Tags, echoModel.Tag{Name: name})
+	}
+
+	// 记录中的媒体下标 -> Echo.Media 中的下标，缺失的媒体不占位
+	indexes := make(map[int]int, len(record.Media))
+	for i, m := range record.Media {
+		media := echoModel.Media{
+			MediaURL:    m.URL,
+			MediaType:   m.Type,
+			MediaSource: m.Source,
+			ObjectKey:   m.ObjectKey,
+			Width:       m.Width,
+			Height:      m.Height,
+		}
+		if media.MediaType == "" {
+			media.MediaType = echoModel.MediaTypeImage
+		}
+
+		if m.Source == echoModel.MediaSourceLocal {
+			url, file, err := a.extractMedia(m, userID)
+			if err != nil {
+				pending.Discard()
+				return nil, err
+			}
+			if url == "" {
+				logUtil.GetLogger().Warn("Media file missing in export, skipping",
+					zap.Uint("echo_id", record.ID),
+					zap.String("file", m.File))
+				pending.MissingMedia++
+				continue
+			}
+			media.MediaURL = url
+			pending.Files = append(pending.Files, file)
+		}
+
+		indexes[i] = len(pending.Echo.Media)
+		pending.Echo.Media = append(pending.Echo.Media, media)
+	}
+
+	for i, m := range record.Media {
+		if m.LiveVideo == nil {
+			continue
+		}
+		imageIndex, imageOK := indexes[i]
+		videoIndex, videoOK := indexes[*m.LiveVideo]
+		if imageOK && videoOK {
+			pending.LivePairs[imageIndex] = videoIndex
+		}
+	}
+
+	return pending, nil
+}
+
+// extractMedia 将导出包中的本地媒体复制到上传目录并返回新的 URL，文件缺失或类型不允许时返回空 URL
+func (a *Archive) extractMedia(m Media, userID uint) (string, string, error) {
+	if m.File == "" {
+		return "", "", nil
+	}
+
+	src, err := a.OpenMedia(m.File)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	fileType := commonModel.ImageType
+	if m.Type == echoModel.MediaTypeVideo {
+		fileType = commonModel.VideoType
+	}
+
+	url, destPath, err := storageUtil.SaveMediaToLocal(src, m.File, fileType, userID)
+	if err != nil && err.Error() == commonModel.FILE_TYPE_NOT_ALLOWED {
+		// 不允许上传的文件类型按缺失处理，不写入上传目录
+		logUtil.GetLogger().Warn("Media file type not allowed, skipping",
+			zap.String("file", m.File))
+		return "", "", nil
+	}
+	return url, destPath, err
+}
@@ -0,0 +1,61 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatterDelimiter 是 YAML front matter 的分隔行
+const frontMatterDelimiter = "---"
+
+// markdownFileName 返回记录对应的 Markdown 文件路径，形如 echos/2006-01-02-42.md
+func markdownFileName(record Record) string {
+	return path.Join(
+		markdownDir,
+		fmt.Sprintf("%s-%d.md", record.CreatedAt.UTC().Format("2006-01-02"), record.ID),
+	)
+}
+
+// renderMarkdown 将记录渲染为带 YAML front matter 的 Markdown，正文即 Echo 内容
+func renderMarkdown(record Record) ([]byte, error) {
+	frontMatter, err := yaml.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(frontMatter)
+	buf.WriteString(frontMatterDelimiter + "\n\n")
+	buf.WriteString(record.Content)
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// parseMarkdown 解析 renderMarkdown 生成的 Markdown 文件
+func parseMarkdown(data []byte) (Record, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, frontMatterDelimiter+"\n") {
+		return Record{}, errors.New(commonModel.INVALID_EXPORT_FILE)
+	}
+
+	text = strings.TrimPrefix(text, frontMatterDelimiter+"\n")
+	frontMatter, body, found := strings.Cut(text, "\n"+frontMatterDelimiter+"\n")
+	if !found {
+		return Record{}, errors.New(commonModel.INVALID_EXPORT_FILE)
+	}
+
+	var record Record
+	if err := yaml.Unmarshal([]byte(frontMatter), &record); err != nil {
+		return Record{}, err
+	}
+	record.Content = strings.TrimSpace(body)
+
+	return record, nil
+}
@@ -0,0 +1,246 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+	mdUtil "github.com/lin-snow/ech0/internal/util/md"
+)
+
+const (
+	mastodonOutboxFile = "outbox.json" // 账号归档中的嘟文列表
+	mastodonActorFile  = "actor.json"  // 账号归档中的账号信息
+	mastodonMediaDir   = "media_attachments/"
+)
+
+// activityStreamsPublic 是 ActivityPub 中表示公开的收件人
+var activityStreamsPublic = []string{
+	"https://www.w3.org/ns/activitystreams#Public",
+	"as:Public",
+	"Public",
+}
+
+// mastodonActivity 是 outbox.json 中的一条活动
+type mastodonActivity struct {
+	Type   string          `json:"type"`
+	Actor  string          `json:"actor"`
+	Object json.RawMessage `json:"object"` // 转嘟时为被转嘟的 URL
+}
+
+// mastodonNote 是活动中的嘟文
+type mastodonNote struct {
+	ID         string               `json:"id"`
+	Type       string               `json:"type"`
+	Summary    string               `json:"summary"`
+	InReplyTo  string               `json:"inReplyTo"`
+	Published  time.Time            `json:"published"`
+	Content    string               `json:"content"`
+	To         stringList           `json:"to"`
+	Cc         stringList           `json:"cc"`
+	Attachment []mastodonAttachment `json:"attachment"`
+	Tag        []mastodonTag        `json:"tag"`
+}
+
+// mastodonAttachment 是嘟文的附件
+type mastodonAttachment struct {
+	MediaType string `json:"mediaType"`
+	URL       string `json:"url"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+}
+
+// mastodonTag 是嘟文的标签，包括话题、提及与自定义表情
+type mastodonTag struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// stringList 兼容 ActivityPub 中单个字符串或字符串数组两种写法
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = stringList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// openMastodon 读取 Mastodon 账号归档，可以是归档 zip，也可以是解压后目录中的 outbox.json
+// 只导入自己发布的嘟文与自我回复（嘟文串），跳过转嘟、回复他人与私信
+func openMastodon(filePath string) (*Archive, error) {
+	archive := &Archive{Manifest: Manifest{Format: FormatName, FormatVersion: FormatVersion}}
+
+	outboxPath := mastodonOutboxFile
+	reader, err := openZipOrFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if reader != nil {
+		archive.files, archive.closer = reader, reader
+		if outboxPath, err = findFile(reader, mastodonOutboxFile); err != nil {
+			_ = reader.Close()
+			return nil, fmt.Errorf("%s: %s", commonModel.INVALID_IMPORT_FILE, mastodonOutboxFile)
+		}
+	} else {
+		// 解压后的归档，媒体文件与 outbox.json 位于同一目录
+		archive.files = os.DirFS(filepath.Dir(filePath))
+		outboxPath = filepath.Base(filePath)
+	}
+
+	if err := archive.loadMastodon(outboxPath); err != nil {
+		_ = archive.Close()
+		return nil, err
+	}
+	return archive, nil
+}
+
+// loadMastodon 将 outbox.json 中的嘟文转换为导出记录
+func (a *Archive) loadMastodon(outboxPath string) error {
+	data, err := fs.ReadFile(a.files, outboxPath)
+	if err != nil {
+		return err
+	}
+	var outbox struct {
+		OrderedItems []mastodonActivity `json:"orderedItems"`
+	}
+	if err := json.Unmarshal(data, &outbox); err != nil {
+		return fmt.Errorf("%s: %w", commonModel.INVALID_IMPORT_FILE, err)
+	}
+
+	base := path.Dir(outboxPath)
+	var actor struct {
+		ID                string `json:"id"`
+		PreferredUsername string `json:"preferredUsername"`
+	}
+	if data, err := fs.ReadFile(a.files, path.Join(base, mastodonActorFile)); err == nil {
+		_ = json.Unmarshal(data, &actor)
+	}
+
+	var boosts, replies, direct, unsupported int
+	for _, activity := range outbox.OrderedItems {
+		if activity.Type != "Create" {
+			boosts++
+			continue
+		}
+		var note mastodonNote
+		if err := json.Unmarshal(activity.Object, &note); err != nil || note.Type != "Note" {
+			continue
+		}
+
+		actorID := activity.Actor
+		if actorID == "" {
+			actorID = actor.ID
+		}
+		// 只保留回复自己的推文串，前缀带上分隔符以免误判前缀相同的其他账号
+		if note.InReplyTo != "" && (actorID == "" || !strings.HasPrefix(note.InReplyTo, actorID+"/")) {
+			replies++
+			continue
+		}
+
+		recipients := slices.Concat(note.To, note.Cc)
+		public := slices.ContainsFunc(recipients, func(r string) bool {
+			return slices.Contains(activityStreamsPublic, r)
+		})
+		followersOnly := slices.ContainsFunc(recipients, func(r string) bool {
+			return strings.HasSuffix(r, "/followers")
+		})
+		if !public && !followersOnly {
+			direct++
+			continue
+		}
+
+		username := actor.PreferredUsername
+		if username == "" {
+			username = path.Base(strings.TrimSuffix(actorID, "/"))
+		}
+
+		content := mdUtil.HTMLToText(note.Content)
+		if summary := strings.TrimSpace(note.Summary); summary != "" {
+			content = summary + "\n\n" + content
+		}
+
+		record := Record{
+			Username:  username,
+			Content:   content,
+			Private:   !public,
+			CreatedAt: note.Published.UTC(),
+		}
+		if id, err := strconv.ParseUint(path.Base(note.ID), 10, 64); err == nil {
+			record.ID = uint(id)
+		}
+		for _, tag := range note.Tag {
+			if tag.Type == "Hashtag" {
+				record.Tags = append(record.Tags, strings.TrimPrefix(tag.Name, "#"))
+			}
+		}
+		for _, attachment := range note.Attachment {
+			media, ok := a.mastodonMedia(base, attachment)
+			if !ok {
+				unsupported++
+				continue
+			}
+			record.Media = append(record.Media, media)
+		}
+
+		a.Records = append(a.Records, record)
+	}
+	sortRecords(a.Records)
+
+	if boosts > 0 {
+		a.notef("跳过了 %d 条转嘟", boosts)
+	}
+	if replies > 0 {
+		a.notef("跳过了 %d 条对他人的回复", replies)
+	}
+	if direct > 0 {
+		a.notef("跳过了 %d 条私信", direct)
+	}
+	if unsupported > 0 {
+		a.notef("跳过了 %d 个非图片或视频的附件", unsupported)
+	}
+	return nil
+}
+
+// mastodonMedia 将附件转换为导出记录中的媒体，归档中有文件时复制文件，否则保留原地址
+func (a *Archive) mastodonMedia(base string, attachment mastodonAttachment) (Media, bool) {
+	mediaType, ok := mediaTypeFromMIME(attachment.MediaType)
+	if !ok {
+		return Media{}, false
+	}
+
+	media := Media{Type: mediaType, Width: attachment.Width, Height: attachment.Height}
+
+	// 归档中的地址形如 /media_attachments/files/...，部分实例会带上完整域名与前缀
+	name := strings.TrimPrefix(attachment.URL, "/")
+	if index := strings.Index(attachment.URL, mastodonMediaDir); index >= 0 {
+		name = attachment.URL[index:]
+	}
+	name = path.Join(base, name)
+
+	isRemote := strings.HasPrefix(attachment.URL, "http://") || strings.HasPrefix(attachment.URL, "https://")
+	if _, err := fs.Stat(a.files, name); err == nil || !isRemote {
+		media.Source = echoModel.MediaSourceLocal
+		media.File = name
+	} else {
+		media.Source = echoModel.MediaSourceURL
+		media.URL = attachment.URL
+	}
+	return media, true
+}
@@ -0,0 +1,434 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lin-snow/ech0/internal/database"
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+	"gorm.io/gorm"
+)
+
+// Memos 中的可见性与状态
+const (
+	memosVisibilityPublic = "PUBLIC"
+	memosStatusArchived   = "ARCHIVED"
+	memosStorageDatabase  = "DATABASE"
+	memosStorageLocal     = "LOCAL"
+	memosRelationComment  = "COMMENT"
+	memosMediaPrefix      = "memos"
+)
+
+// openMemos 打开 Memos 的 SQLite 数据库或 API 导出的 JSON
+func openMemos(filePath string) (*Archive, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	head := make([]byte, len(sqliteHeader))
+	_, _ = io.ReadFull(file, head)
+	_ = file.Close()
+
+	if bytes.Equal(head, sqliteHeader) {
+		return openMemosDatabase(filePath)
+	}
+	return openMemosJSON(filePath)
+}
+
+// memosResource 是 Memos 数据库中的一个附件
+type memosResource struct {
+	id        int64
+	memoID    int64
+	filename  string
+	mimeType  string
+	localPath string // 存放在本地磁盘时的路径
+	url       string // 存放在外部（对象存储或外链）时的地址
+	inline    bool   // 存放在数据库 blob 字段中
+}
+
+// openMemosDatabase 读取 Memos 的 SQLite 数据库
+// 兼容不同版本的表结构：附件表在新版本中由 resource 更名为 attachment，
+// 旧版本使用 internal_path/external_link 记录附件位置，新版本使用 storage_type/reference
+func openMemosDatabase(filePath string) (*Archive, error) {
+	conn, err := database.OpenReadOnly(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{
+		Manifest: Manifest{Format: FormatName, FormatVersion: FormatVersion},
+		closer:   closerFunc(func() error { database.Close(conn); return nil }),
+	}
+	if err := archive.loadMemosDatabase(conn, filepath.Dir(filePath)); err != nil {
+		database.Close(conn)
+		return nil, err
+	}
+	return archive, nil
+}
+
+// loadMemosDatabase 将 Memos 数据库中的 memo 转换为导出记录
+func (a *Archive) loadMemosDatabase(conn *gorm.DB, dataDir string) error {
+	tables := sqliteColumns(conn)
+	memoColumns, ok := tables["memo"]
+	if !ok {
+		return fmt.Errorf("%s: memo", commonModel.INVALID_IMPORT_FILE)
+	}
+
+	// 用户
+	users := make(map[int64]string)
+	if _, ok := tables["user"]; ok {
+		var rows []map[string]any
+		if err := conn.Table("user").Select("id", "username").Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			users[asInt(row["id"])] = asString(row["username"])
+		}
+		for id, username := range users {
+			a.Manifest.Users = append(a.Manifest.Users, User{ID: uint(id), Username: username})
+		}
+	}
+
+	// 评论在 Ech0 中没有对应的结构，跳过
+	comments := make(map[int64]struct{})
+	if _, ok := tables["memo_relation"]; ok {
+		var ids []int64
+		if err := conn.Table("memo_relation").
+			Where("type = ?", memosRelationComment).
+			Pluck("memo_id", &ids).Error; err != nil {
+			return err
+		}
+		for _, id := range ids {
+			comments[id] = struct{}{}
+		}
+	}
+
+	resources, err := loadMemosResources(conn, tables, dataDir)
+	if err != nil {
+		return err
+	}
+	resourceByKey := make(map[string]memosResource)
+	resourcesByMemo := make(map[int64][]memosResource)
+	for _, resource := range resources {
+		resourcesByMemo[resource.memoID] = append(resourcesByMemo[resource.memoID], resource)
+		resourceByKey[memosMediaKey(resource)] = resource
+	}
+	resourceTable := memosResourceTable(tables)
+	a.openMedia = func(name string) (io.ReadCloser, error) {
+		resource, ok := resourceByKey[name]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		if !resource.inline {
+			return os.Open(resource.localPath)
+		}
+		var blobs [][]byte
+		if err := conn.Table(resourceTable).
+			Where("id = ?", resource.id).
+			Pluck("blob", &blobs).Error; err != nil {
+			return nil, err
+		}
+		if len(blobs) == 0 {
+			return nil, fs.ErrNotExist
+		}
+		return io.NopCloser(bytes.NewReader(blobs[0])), nil
+	}
+
+	// memo
+	var rows []map[string]any
+	if err := conn.Table("memo").
+		Select(strings.Join(presentColumns(memoColumns,
+			"id", "creator_id", "created_ts", "content", "visibility", "row_status", "payload"), ", ")).
+		Order("created_ts ASC, id ASC").
+		Find(&rows).Error; err != nil {
+		return err
+	}
+
+	var skippedComments, archived int
+	for _, row := range rows {
+		id := asInt(row["id"])
+		if _, ok := comments[id]; ok {
+			skippedComments++
+			continue
+		}
+
+		record := Record{
+			ID:        uint(id),
+			UserID:    uint(asInt(row["creator_id"])),
+			Username:  users[asInt(row["creator_id"])],
+			Content:   strings.TrimSpace(asString(row["content"])),
+			Private:   asString(row["visibility"]) != memosVisibilityPublic,
+			CreatedAt: time.Unix(asInt(row["created_ts"]), 0).UTC(),
+			Tags:      memosPayloadTags(asString(row["payload"])),
+		}
+		if len(record.Tags) == 0 {
+			record.Tags = extractTags(record.Content)
+		}
+		// 归档的 memo 以私密 Echo 导入
+		if asString(row["row_status"]) == memosStatusArchived {
+			record.Private = true
+			archived++
+		}
+
+		for _, resource := range resourcesByMemo[id] {
+			media, ok := memosMedia(resource)
+			if !ok {
+				a.notef("memo %d 的附件 %s（%s）不是图片或视频，已跳过", id, resource.filename, resource.mimeType)
+				continue
+			}
+			record.Media = append(record.Media, media)
+		}
+
+		a.Records = append(a.Records, record)
+	}
+
+	if skippedComments > 0 {
+		a.notef("跳过了 %d 条评论", skippedComments)
+	}
+	if archived > 0 {
+		a.notef("%d 条已归档的 memo 以私密 Echo 导入", archived)
+	}
+	return nil
+}
+
+// loadMemosResources 读取附件信息，不读取 blob 内容
+func loadMemosResources(conn *gorm.DB, tables map[string][]string, dataDir string) ([]memosResource, error) {
+	table := memosResourceTable(tables)
+	columns, ok := tables[table]
+	if !ok {
+		return nil, nil
+	}
+
+	selects := presentColumns(columns,
+		"id", "memo_id", "filename", "type", "storage_type", "reference", "internal_path", "external_link")
+	if slices.Contains(columns, "blob") {
+		selects = append(selects, "length(blob) > 0 AS has_blob")
+	}
+
+	var rows []map[string]any
+	if err := conn.Table(table).
+		Select(strings.Join(selects, ", ")).
+		Where("memo_id IS NOT NULL").
+		Order("id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	resources := make([]memosResource, 0, len(rows))
+	for _, row := range rows {
+		resource := memosResource{
+			id:       asInt(row["id"]),
+			memoID:   asInt(row["memo_id"]),
+			filename: asString(row["filename"]),
+			mimeType: asString(row["type"]),
+		}
+
+		storageType := asString(row["storage_type"])
+		reference := asString(row["reference"])
+		if reference == "" {
+			reference = asString(row["internal_path"])
+		}
+		externalLink := asString(row["external_link"])
+
+		switch {
+		case storageType == memosStorageDatabase || (storageType == "" && asInt(row["has_blob"]) > 0):
+			resource.inline = true
+		case storageType == memosStorageLocal || (storageType == "" && reference != ""):
+			// 相对路径相对于 Memos 的数据目录，即数据库文件所在目录
+			if !filepath.IsAbs(reference) {
+				reference = filepath.Join(dataDir, reference)
+			}
+			resource.localPath = reference
+		case externalLink != "":
+			resource.url = externalLink
+		default:
+			resource.url = reference
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// memosResourceTable 返回附件表名
+func memosResourceTable(tables map[string][]string) string {
+	if _, ok := tables["attachment"]; ok {
+		return "attachment"
+	}
+	return "resource"
+}
+
+// memosMediaKey 返回附件在导出记录中的路径
+func memosMediaKey(resource memosResource) string {
+	return path.Join(memosMediaPrefix, strconv.FormatInt(resource.id, 10), path.Base(resource.filename))
+}
+
+// memosMedia 将附件转换为导出记录中的媒体，非图片与视频的附件返回 false
+func memosMedia(resource memosResource) (Media, bool) {
+	mediaType, ok := mediaTypeFromMIME(resource.mimeType)
+	if !ok {
+		return Media{}, false
+	}
+
+	media := Media{Type: mediaType}
+	if resource.inline || resource.localPath != "" {
+		media.Source = echoModel.MediaSourceLocal
+		media.File = memosMediaKey(resource)
+	} else {
+		media.Source = echoModel.MediaSourceURL
+		media.URL = resource.url
+	}
+	return media, true
+}
+
+// memosPayloadTags 读取新版本 memo payload 中的标签
+func memosPayloadTags(payload string) []string {
+	if payload == "" {
+		return nil
+	}
+	var data struct {
+		Tags     []string `json:"tags"`
+		Property struct {
+			Tags []string `json:"tags"`
+		} `json:"property"`
+	}
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		return nil
+	}
+	if len(data.Tags) > 0 {
+		return data.Tags
+	}
+	return data.Property.Tags
+}
+
+// memosAPIMemo 是 Memos API（/api/v1/memos）返回的 memo
+type memosAPIMemo struct {
+	Name        string             `json:"name"`
+	Creator     string             `json:"creator"`
+	CreateTime  time.Time          `json:"createTime"`
+	Content     string             `json:"content"`
+	Visibility  string             `json:"visibility"`
+	State       string             `json:"state"`
+	RowStatus   string             `json:"rowStatus"`
+	Tags        []string           `json:"tags"`
+	Parent      string             `json:"parent"`
+	Resources   []memosAPIResource `json:"resources"`
+	Attachments []memosAPIResource `json:"attachments"`
+}
+
+// memosAPIResource 是 Memos API 返回的附件
+type memosAPIResource struct {
+	Filename     string `json:"filename"`
+	Type         string `json:"type"`
+	ExternalLink string `json:"externalLink"`
+	Content      []byte `json:"content"` // 部分版本会以 base64 内联附件内容
+}
+
+// openMemosJSON 读取 Memos API 导出的 JSON，可以是 {"memos": [...]} 或 memo 数组
+// API 导出的附件只有外链与内联内容可以导入，其余附件需要使用数据库导入
+func openMemosJSON(filePath string) (*Archive, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var memos []memosAPIMemo
+	var wrapped struct {
+		Memos []memosAPIMemo `json:"memos"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err == nil && wrapped.Memos != nil {
+		memos = wrapped.Memos
+	} else if err := json.Unmarshal(data, &memos); err != nil {
+		return nil, fmt.Errorf("%s: %w", commonModel.INVALID_IMPORT_FILE, err)
+	}
+
+	archive := &Archive{Manifest: Manifest{Format: FormatName, FormatVersion: FormatVersion}}
+	inline := make(map[string][]byte)
+	archive.openMedia = func(name string) (io.ReadCloser, error) {
+		content, ok := inline[name]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	var skippedComments, missing int
+	for i, memo := range memos {
+		if memo.Parent != "" {
+			skippedComments++
+			continue
+		}
+
+		record := Record{
+			Username:  memosAPIUsername(memo.Creator),
+			Content:   strings.TrimSpace(memo.Content),
+			Private:   memo.Visibility != memosVisibilityPublic,
+			CreatedAt: memo.CreateTime.UTC(),
+			Tags:      memo.Tags,
+		}
+		if id, err := strconv.ParseUint(path.Base(memo.Name), 10, 64); err == nil {
+			record.ID = uint(id)
+		}
+		if len(record.Tags) == 0 {
+			record.Tags = extractTags(record.Content)
+		}
+		if memo.State == memosStatusArchived || memo.RowStatus == memosStatusArchived {
+			record.Private = true
+		}
+
+		for j, resource := range slices.Concat(memo.Resources, memo.Attachments) {
+			mediaType, ok := mediaTypeFromMIME(resource.Type)
+			if !ok {
+				continue
+			}
+			switch {
+			case len(resource.Content) > 0:
+				name := path.Join(memosMediaPrefix, fmt.Sprintf("%d-%d", i, j), path.Base(resource.Filename))
+				inline[name] = resource.Content
+				record.Media = append(record.Media, Media{
+					Type:   mediaType,
+					Source: echoModel.MediaSourceLocal,
+					File:   name,
+				})
+			case resource.ExternalLink != "":
+				record.Media = append(record.Media, Media{
+					Type:   mediaType,
+					Source: echoModel.MediaSourceURL,
+					URL:    resource.ExternalLink,
+				})
+			default:
+				missing++
+			}
+		}
+
+		archive.Records = append(archive.Records, record)
+	}
+	sortRecords(archive.Records)
+
+	if skippedComments > 0 {
+		archive.notef("跳过了 %d 条评论", skippedComments)
+	}
+	if missing > 0 {
+		archive.notef("%d 个附件只存放在原 Memos 实例中，API 导出无法包含，请改用数据库文件导入", missing)
+	}
+	return archive, nil
+}
+
+// memosAPIUsername 从 users/{username} 形式的创建者中取出用户名，数字 ID 无法映射时返回空
+func memosAPIUsername(creator string) string {
+	name := path.Base(creator)
+	if _, err := strconv.Atoi(name); err == nil || name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,254 @@
+package transfer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+	"gorm.io/gorm"
+)
+
+// 支持导入的数据来源
+const (
+	SourceEch0     = "ech0"     // Ech0 导出包，见 docs/export-format.md
+	SourceMemos    = "memos"    // Memos 的 SQLite 数据库或 API 导出的 JSON
+	SourceMastodon = "mastodon" // Mastodon 账号归档（zip 或 outbox.json）
+	SourceTwitter  = "twitter"  // Twitter/X 账号归档（zip 或 tweets.js）
+)
+
+// sqliteHeader 是 SQLite 数据库文件的文件头
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// Open 按数据来源打开待导入的文件，source 为空时根据文件内容自动识别
+func Open(source, filePath string) (*Archive, error) {
+	if source == "" {
+		detected, err := DetectSource(filePath)
+		if err != nil {
+			return nil, err
+		}
+		source = detected
+	}
+
+	switch source {
+	case SourceEch0:
+		return OpenArchive(filePath)
+	case SourceMemos:
+		return openMemos(filePath)
+	case SourceMastodon:
+		return openMastodon(filePath)
+	case SourceTwitter:
+		return openTwitter(filePath)
+	default:
+		return nil, fmt.Errorf("%s: %s", commonModel.UNSUPPORTED_IMPORT_SOURCE, source)
+	}
+}
+
+// DetectSource 根据文件内容识别数据来源
+func DetectSource(filePath string) (string, error) {
+	if reader, err := zip.OpenReader(filePath); err == nil {
+		defer func() {
+			_ = reader.Close()
+		}()
+		return detectZipSource(reader)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	head := make([]byte, 4096)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, sqliteHeader):
+		return SourceMemos, nil
+	case bytes.HasPrefix(bytes.TrimSpace(head), []byte("window.YTD.")):
+		return SourceTwitter, nil
+	case bytes.Contains(head, []byte(`"orderedItems"`)):
+		return SourceMastodon, nil
+	case bytes.Contains(head, []byte(`"createTime"`)) || bytes.Contains(head, []byte(`"memos"`)):
+		return SourceMemos, nil
+	case bytes.Contains(head, []byte(`"content_hash"`)):
+		return SourceEch0, nil
+	}
+	return "", errors.New(commonModel.UNSUPPORTED_IMPORT_SOURCE)
+}
+
+// detectZipSource 根据压缩包中的文件识别数据来源
+func detectZipSource(files fs.FS) (string, error) {
+	for _, name := range []string{manifestFileName, echosFileName} {
+		if _, err := fs.Stat(files, name); err == nil {
+			return SourceEch0, nil
+		}
+	}
+	if names, _ := fs.Glob(files, path.Join(markdownDir, "*.md")); len(names) > 0 {
+		return SourceEch0, nil
+	}
+	if _, err := findFile(files, mastodonOutboxFile); err == nil {
+		return SourceMastodon, nil
+	}
+	for _, name := range twitterTweetFiles {
+		if _, err := findFile(files, name); err == nil {
+			return SourceTwitter, nil
+		}
+	}
+	return "", errors.New(commonModel.UNSUPPORTED_IMPORT_SOURCE)
+}
+
+// findFile 在压缩包中查找文件，兼容归档被额外包了一层目录的情况
+func findFile(files fs.FS, name string) (string, error) {
+	if _, err := fs.Stat(files, name); err == nil {
+		return name, nil
+	}
+
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		candidate := path.Join(entry.Name(), name)
+		if _, err := fs.Stat(files, candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fs.ErrNotExist
+}
+
+// openZipOrFile 打开压缩包；不是压缩包时返回 nil，由调用方按单独的数据文件处理
+func openZipOrFile(filePath string) (*zip.ReadCloser, error) {
+	reader, err := zip.OpenReader(filePath)
+	if errors.Is(err, zip.ErrFormat) {
+		return nil, nil
+	}
+	return reader, err
+}
+
+// extractTags 从正文中提取 #标签，标签以空白或标点结束
+func extractTags(content string) []string {
+	var tags []string
+	seen := make(map[string]struct{})
+	for _, field := range strings.Fields(content) {
+		if !strings.HasPrefix(field, "#") {
+			continue
+		}
+		name := strings.TrimFunc(strings.TrimPrefix(field, "#"), func(r rune) bool {
+			return strings.ContainsRune(",.;:!?，。；：！？、)]）】\"'", r)
+		})
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+// sortRecords 按创建时间升序排列记录，保证导入顺序与发布顺序一致
+func sortRecords(records []Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].CreatedAt.Before(records[j].CreatedAt)
+	})
+}
+
+// mediaTypeFromMIME 根据 MIME 类型判断媒体类型，非图片与视频返回 false
+func mediaTypeFromMIME(mimeType string) (string, bool) {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return echoModel.MediaTypeImage, true
+	case strings.HasPrefix(mimeType, "video/"):
+		return echoModel.MediaTypeVideo, true
+	}
+	return "", false
+}
+
+// sqliteColumns 返回 SQLite 数据库中各数据表的列名
+func sqliteColumns(conn *gorm.DB) map[string][]string {
+	var tables []string
+	conn.Raw("SELECT name FROM sqlite_master WHERE type = 'table'").Scan(&tables)
+
+	columns := make(map[string][]string, len(tables))
+	for _, table := range tables {
+		var names []string
+		conn.Raw("SELECT name FROM pragma_table_info(?)", table).Scan(&names)
+		columns[table] = names
+	}
+	return columns
+}
+
+// presentColumns 返回 wanted 中在表里实际存在的列，用于兼容不同版本的表结构
+func presentColumns(columns []string, wanted ...string) []string {
+	var present []string
+	for _, column := range wanted {
+		if slices.Contains(columns, column) {
+			present = append(present, column)
+		}
+	}
+	return present
+}
+
+// asInt 将数据库读取的值转换为整数
+func asInt(value any) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case bool:
+		if v {
+			return 1
+		}
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	case []byte:
+		n, _ := strconv.ParseInt(string(v), 10, 64)
+		return n
+	}
+	return 0
+}
+
+// asString 将数据库读取的值转换为字符串
+func asString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// closerFunc 将函数适配为 io.Closer
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
@@ -0,0 +1,280 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	commonModel "github.com/lin-snow/ech0/internal/model/common"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+)
+
+const (
+	twitterAccountFile = "data/account.js"   // 账号归档中的账号信息
+	twitterMediaDir    = "data/tweets_media" // 账号归档中的推文媒体
+)
+
+// twitterTweetFiles 是不同时期账号归档中的推文文件，较大的归档会拆分为 tweets-part1.js 等多个文件
+var twitterTweetFiles = []string{"data/tweets.js", "data/tweet.js"}
+
+// twitterTweet 是归档中的一条推文
+type twitterTweet struct {
+	ID               string          `json:"id_str"`
+	FullText         string          `json:"full_text"`
+	CreatedAt        string          `json:"created_at"`
+	InReplyToUserID  string          `json:"in_reply_to_user_id_str"`
+	Retweeted        bool            `json:"retweeted"`
+	Entities         twitterEntities `json:"entities"`
+	ExtendedEntities twitterEntities `json:"extended_entities"`
+}
+
+// twitterEntities 是推文中的话题、链接与媒体
+type twitterEntities struct {
+	Hashtags []struct {
+		Text string `json:"text"`
+	} `json:"hashtags"`
+	URLs []struct {
+		URL         string `json:"url"`
+		ExpandedURL string `json:"expanded_url"`
+	} `json:"urls"`
+	Media []twitterMedia `json:"media"`
+}
+
+// twitterMedia 是推文中的图片、视频或动图
+type twitterMedia struct {
+	URL           string `json:"url"` // 正文中的 t.co 短链
+	MediaURLHTTPS string `json:"media_url_https"`
+	Type          string `json:"type"` // photo/video/animated_gif
+	VideoInfo     struct {
+		Variants []struct {
+			Bitrate     flexInt `json:"bitrate"`
+			ContentType string  `json:"content_type"`
+			URL         string  `json:"url"`
+		} `json:"variants"`
+	} `json:"video_info"`
+	OriginalInfo struct {
+		Width  flexInt `json:"width"`
+		Height flexInt `json:"height"`
+	} `json:"original_info"`
+}
+
+// flexInt 兼容归档中以字符串或数字表示的整数
+type flexInt int
+
+func (n *flexInt) UnmarshalJSON(data []byte) error {
+	value, err := strconv.Atoi(strings.Trim(string(data), `"`))
+	if err != nil {
+		return nil
+	}
+	*n = flexInt(value)
+	return nil
+}
+
+// openTwitter 读取 Twitter/X 账号归档，可以是归档 zip，也可以是解压后目录中的 tweets.js
+// 只导入自己发布的推文与自我回复（推文串），跳过转推与回复他人的推文
+func openTwitter(filePath string) (*Archive, error) {
+	archive := &Archive{Manifest: Manifest{Format: FormatName, FormatVersion: FormatVersion}}
+
+	reader, err := openZipOrFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var tweetFiles []string
+	if reader != nil {
+		archive.files, archive.closer = reader, reader
+		for _, name := range twitterTweetFiles {
+			if found, err := findFile(reader, name); err == nil {
+				tweetFiles = append(tweetFiles, found)
+				parts, _ := fs.Glob(reader, path.Join(path.Dir(found), "tweets-part*.js"))
+				tweetFiles = append(tweetFiles, parts...)
+				break
+			}
+		}
+		if len(tweetFiles) == 0 {
+			_ = reader.Close()
+			return nil, fmt.Errorf("%s: %s", commonModel.INVALID_IMPORT_FILE, twitterTweetFiles[0])
+		}
+	} else {
+		// 解压后的归档，以 data 目录的上一级作为根目录
+		dataDir := filepath.Dir(filePath)
+		archive.files = os.DirFS(filepath.Dir(dataDir))
+		tweetFiles = []string{path.Join(filepath.Base(dataDir), filepath.Base(filePath))}
+	}
+
+	if err := archive.loadTwitter(tweetFiles); err != nil {
+		_ = archive.Close()
+		return nil, err
+	}
+	return archive, nil
+}
+
+// loadTwitter 将推文转换为导出记录
+func (a *Archive) loadTwitter(tweetFiles []string) error {
+	base := path.Dir(path.Dir(tweetFiles[0]))
+
+	var account struct {
+		Account struct {
+			AccountID string `json:"accountId"`
+			Username  string `json:"username"`
+		} `json:"account"`
+	}
+	if items, err := a.readYTD(path.Join(base, twitterAccountFile)); err == nil && len(items) > 0 {
+		_ = json.Unmarshal(items[0], &account)
+	}
+
+	var items []json.RawMessage
+	for _, name := range tweetFiles {
+		part, err := a.readYTD(name)
+		if err != nil {
+			return err
+		}
+		items = append(items, part...)
+	}
+
+	var retweets, replies int
+	for _, item := range items {
+		// 新版归档中每条推文包裹在 {"tweet": {...}} 中
+		var wrapped struct {
+			Tweet *twitterTweet `json:"tweet"`
+		}
+		var tweet twitterTweet
+		if err := json.Unmarshal(item, &wrapped); err == nil && wrapped.Tweet != nil {
+			tweet = *wrapped.Tweet
+		} else if err := json.Unmarshal(item, &tweet); err != nil {
+			continue
+		}
+
+		if tweet.Retweeted || strings.HasPrefix(tweet.FullText, "RT @") {
+			retweets++
+			continue
+		}
+		if tweet.InReplyToUserID != "" && tweet.InReplyToUserID != account.Account.AccountID {
+			replies++
+			continue
+		}
+
+		createdAt, err := time.Parse(time.RubyDate, tweet.CreatedAt)
+		if err != nil {
+			continue
+		}
+
+		media := tweet.ExtendedEntities.Media
+		if len(media) == 0 {
+			media = tweet.Entities.Media
+		}
+
+		record := Record{
+			Username:  account.Account.Username,
+			Content:   twitterText(tweet, media),
+			CreatedAt: createdAt.UTC(),
+		}
+		if id, err := strconv.ParseUint(tweet.ID, 10, 64); err == nil {
+			record.ID = uint(id)
+		}
+		for _, hashtag := range tweet.Entities.Hashtags {
+			record.Tags = append(record.Tags, hashtag.Text)
+		}
+		for _, m := range media {
+			if item, ok := a.twitterMedia(base, tweet.ID, m); ok {
+				record.Media = append(record.Media, item)
+			}
+		}
+
+		a.Records = append(a.Records, record)
+	}
+	sortRecords(a.Records)
+
+	if retweets > 0 {
+		a.notef("跳过了 %d 条转推", retweets)
+	}
+	if replies > 0 {
+		a.notef("跳过了 %d 条对他人的回复", replies)
+	}
+	return nil
+}
+
+// readYTD 读取归档中 window.YTD.xxx.part0 = [...] 形式的数据文件
+func (a *Archive) readYTD(name string) ([]json.RawMessage, error) {
+	data, err := fs.ReadFile(a.files, name)
+	if err != nil {
+		return nil, err
+	}
+	if index := bytes.IndexByte(data, '='); index >= 0 && bytes.HasPrefix(bytes.TrimSpace(data), []byte("window.")) {
+		data = data[index+1:]
+	}
+	data = bytes.TrimSuffix(bytes.TrimSpace(data), []byte(";"))
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", commonModel.INVALID_IMPORT_FILE, name, err)
+	}
+	return items, nil
+}
+
+// twitterText 还原推文正文：展开 t.co 短链、去掉指向媒体的短链并反转义 HTML 实体
+func twitterText(tweet twitterTweet, media []twitterMedia) string {
+	text := tweet.FullText
+	for _, link := range tweet.Entities.URLs {
+		if link.URL != "" && link.ExpandedURL != "" {
+			text = strings.ReplaceAll(text, link.URL, link.ExpandedURL)
+		}
+	}
+	for _, m := range media {
+		if m.URL != "" {
+			text = strings.ReplaceAll(text, m.URL, "")
+		}
+	}
+	return strings.TrimSpace(html.UnescapeString(text))
+}
+
+// twitterMedia 将推文媒体转换为导出记录中的媒体，归档中有文件时复制文件，否则保留原地址
+// 归档中的媒体文件名为 {推文ID}-{原文件名}
+func (a *Archive) twitterMedia(base, tweetID string, m twitterMedia) (Media, bool) {
+	media := Media{
+		Type:   echoModel.MediaTypeImage,
+		Width:  int(m.OriginalInfo.Width),
+		Height: int(m.OriginalInfo.Height),
+		URL:    m.MediaURLHTTPS,
+	}
+
+	if m.Type == "video" || m.Type == "animated_gif" {
+		// 选择码率最高的 mp4
+		var best flexInt = -1
+		for _, variant := range m.VideoInfo.Variants {
+			if variant.ContentType == "video/mp4" && variant.Bitrate > best {
+				best, media.URL = variant.Bitrate, variant.URL
+			}
+		}
+		if best < 0 {
+			return Media{}, false
+		}
+		media.Type = echoModel.MediaTypeVideo
+	}
+	if media.URL == "" {
+		return Media{}, false
+	}
+
+	fileName := media.URL
+	if parsed, err := url.Parse(media.URL); err == nil {
+		fileName = parsed.Path
+	}
+	name := path.Join(base, twitterMediaDir, tweetID+"-"+path.Base(fileName))
+	if _, err := fs.Stat(a.files, name); err == nil {
+		media.Source = echoModel.MediaSourceLocal
+		media.File = name
+		media.URL = ""
+	} else {
+		media.Source = echoModel.MediaSourceURL
+	}
+	return media, true
+}
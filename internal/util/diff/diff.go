@@ -0,0 +1,196 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffCells 限制 LCS 表的大小，超出时按整体替换输出，避免超长文本占用过多内存
+const maxDiffCells = 4 << 20
+
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// UnifiedDiff 按行比较两段文本，返回 unified diff 格式的差异，内容相同时返回空字符串
+// context 为每个变更块前后保留的上下文行数
+func UnifiedDiff(fromName, toName, from, to string, context int) string {
+	if from == to {
+		return ""
+	}
+
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromName, toName)
+
+	// 按上下文行数将变更分组为若干块
+	for start := 0; start < len(ops); {
+		// 找到下一处变更
+		for start < len(ops) && ops[start].kind == opEqual {
+			start++
+		}
+		if start >= len(ops) {
+			break
+		}
+
+		// 向后扩展，直到连续相同行超过两倍上下文
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == opEqual {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				break
+			}
+			end = run
+		}
+
+		hunkStart := max(start-context, 0)
+		hunkEnd := min(end+context, len(ops))
+		writeHunk(&builder, ops, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+
+	return builder.String()
+}
+
+// writeHunk 输出 ops[start:end] 对应的变更块
+func writeHunk(builder *strings.Builder, ops []op, start, end int) {
+	// 计算变更块在两段文本中的起始行号
+	fromLine, toLine := 1, 1
+	for _, o := range ops[:start] {
+		if o.kind != opInsert {
+			fromLine++
+		}
+		if o.kind != opDelete {
+			toLine++
+		}
+	}
+
+	fromCount, toCount := 0, 0
+	for _, o := range ops[start:end] {
+		if o.kind != opInsert {
+			fromCount++
+		}
+		if o.kind != opDelete {
+			toCount++
+		}
+	}
+	// 按 unified diff 约定，空范围的起始行号为前一行
+	if fromCount == 0 {
+		fromLine--
+	}
+	if toCount == 0 {
+		toLine--
+	}
+
+	fmt.Fprintf(builder, "@@ -%s +%s @@\n", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount))
+	for _, o := range ops[start:end] {
+		builder.WriteByte(byte(o.kind))
+		builder.WriteString(o.line)
+		builder.WriteByte('\n')
+	}
+}
+
+func hunkRange(line, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+// diffLines 基于最长公共子序列计算逐行差异
+func diffLines(a, b []string) []op {
+	// 去掉相同的首尾，缩小比较范围
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{opEqual, line})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{opEqual, line})
+	}
+	return ops
+}
+
+func diffMiddle(a, b []string) []op {
+	ops := make([]op, 0, len(a)+len(b))
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, op{opDelete, line})
+		}
+		for _, line := range b {
+			ops = append(ops, op{opInsert, line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	width := len(b) + 1
+	lcs := make([]int, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{opEqual, a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			ops = append(ops, op{opDelete, a[i]})
+			i++
+		default:
+			ops = append(ops, op{opInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{opDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{opInsert, b[j]})
+	}
+	return ops
+}
+
+// splitLines 按行拆分文本，忽略末尾换行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
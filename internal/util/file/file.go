@@ -2,10 +2,13 @@ package util
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	echoModel "github.com/lin-snow/ech0/internal/model/echo"
@@ -22,6 +25,12 @@ type ZipOptions struct {
 	ExcludePatterns []string
 	// 进度回调函数
 	ProgressCallback func(current, total int64, filename string)
+	// 目录遍历完成后额外写入的文件（ZIP 内路径 -> 本地文件路径）
+	ExtraFiles map[string]string
+	// 每个文件写入后的回调，返回 ZIP 内路径、大小与 SHA-256
+	FileCallback func(name string, size int64, checksum string)
+	// 所有文件写入后、关闭 ZIP 前的回调，可用于追加清单等内容
+	Finalize func(zipWriter *zip.Writer) error
 }
 
 // DefaultZipOptions 默认压缩选项
@@ -55,11 +64,6 @@ func ZipDirectoryWithOptions(sourceDir string, zipPath string, options ZipOption
 		return fmt.Errorf("源路径 %s 不是一个目录", sourceDir)
 	}
 
-	// 清空目标目录下的所有文件
-	if err := cleanBackupDir("backup"); err != nil {
-		return err // 或者带提示信息
-	}
-
 	// 确保目标目录存在
 	if err := os.MkdirAll(filepath.Dir(zipPath), 0o755); err != nil {
 		return fmt.Errorf("无法创建目标目录: %w", err)
@@ -105,7 +109,7 @@ func ZipDirectoryWithOptions(sourceDir string, zipPath string, options ZipOption
 	sourceDir = filepath.Clean(sourceDir)
 
 	// 遍历目录中的所有文件和子目录
-	return filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+	err = filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("遍历文件 %s 时出错: %w", path, err)
 		}
@@ -135,36 +139,13 @@ func ZipDirectoryWithOptions(sourceDir string, zipPath string, options ZipOption
 			return nil
 		}
 
-		// 创建文件条目
-		header := &zip.FileHeader{
-			Name:     relPath,
-			Method:   options.CompressionLevel,
-			Modified: info.ModTime(),
-		}
-
-		// 设置文件权限
-		header.SetMode(info.Mode())
-
-		zipEntry, err := zipWriter.CreateHeader(header)
-		if err != nil {
-			return fmt.Errorf("创建 ZIP 条目 %s 失败: %w", relPath, err)
-		}
-
-		// 打开原始文件
-		file, err := os.Open(path)
-		if err != nil {
-			return fmt.Errorf("打开文件 %s 失败: %w", path, err)
+		// 被额外文件替换的条目跳过
+		if _, replaced := options.ExtraFiles[relPath]; replaced {
+			return nil
 		}
-		defer func() {
-			if closeErr := file.Close(); closeErr != nil {
-				fmt.Printf("警告: 关闭文件 %s 时出错: %v\n", path, closeErr)
-			}
-		}()
 
-		// 拷贝文件内容到 zip 条目中
-		_, err = io.Copy(zipEntry, file)
-		if err != nil {
-			return fmt.Errorf("复制文件内容 %s 失败: %w", path, err)
+		if err := addFileToZip(zipWriter, path, relPath, info, options); err != nil {
+			return err
 		}
 
 		// 更新进度
@@ -175,6 +156,79 @@ func ZipDirectoryWithOptions(sourceDir string, zipPath string, options ZipOption
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// 写入额外文件
+	extraNames := make([]string, 0, len(options.ExtraFiles))
+	for name := range options.ExtraFiles {
+		extraNames = append(extraNames, name)
+	}
+	sort.Strings(extraNames)
+	for _, name := range extraNames {
+		path := options.ExtraFiles[name]
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("无法访问文件 %s: %w", path, err)
+		}
+		if err := addFileToZip(zipWriter, path, name, info, options); err != nil {
+			return err
+		}
+	}
+
+	if options.Finalize != nil {
+		return options.Finalize(zipWriter)
+	}
+
+	return nil
+}
+
+// addFileToZip 将单个文件写入 ZIP，并计算 SHA-256
+func addFileToZip(
+	zipWriter *zip.Writer,
+	path, name string,
+	info os.FileInfo,
+	options ZipOptions,
+) error {
+	// 创建文件条目
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   options.CompressionLevel,
+		Modified: info.ModTime(),
+	}
+
+	// 设置文件权限
+	header.SetMode(info.Mode())
+
+	zipEntry, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("创建 ZIP 条目 %s 失败: %w", name, err)
+	}
+
+	// 打开原始文件
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("打开文件 %s 失败: %w", path, err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			fmt.Printf("警告: 关闭文件 %s 时出错: %v\n", path, closeErr)
+		}
+	}()
+
+	// 拷贝文件内容到 zip 条目中，同时计算校验和
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(zipEntry, hash), file)
+	if err != nil {
+		return fmt.Errorf("复制文件内容 %s 失败: %w", path, err)
+	}
+
+	if options.FileCallback != nil {
+		options.FileCallback(name, size, hex.EncodeToString(hash.Sum(nil)))
+	}
+
+	return nil
 }
 
 // shouldIncludeFile 判断是否应该包含文件
@@ -376,6 +430,15 @@ func CopyDirectory(src, dest string) error {
 	})
 }
 
+// CopyFile 复制单个文件到目标路径，保留文件权限
+func CopyFile(src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("无法访问源文件 %s: %w", src, err)
+	}
+	return copyFile(src, dest, info.Mode())
+}
+
 func ensureDir(dir string) error {
 	return os.MkdirAll(dir, 0o755)
 }
@@ -418,23 +481,6 @@ func copyFile(src, dest string, perm os.FileMode) error {
 	return nil
 }
 
-// cleanBackupDir 清理备份目录
-func cleanBackupDir(path string) error {
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		return fmt.Errorf("读取备份目录失败: %w", err)
-	}
-
-	for _, entry := range entries {
-		fullPath := filepath.Join(path, entry.Name())
-		if err := os.RemoveAll(fullPath); err != nil {
-			return fmt.Errorf("删除旧备份失败: %w", err)
-		}
-	}
-
-	return nil
-}
-
 // GetMediaURL 获取媒体 URL（原GetImageURL）
 func GetMediaURL(media echoModel.Media, serverURL string) string {
 	switch media.MediaSource {
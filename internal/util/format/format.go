@@ -37,6 +37,11 @@ func FormatMetrics(m *model.Metrics) *model.Metrics {
 	// 系统指标：Uptime 保留小时数（float）
 	formatted.System.Uptime = hoursDuration(m.System.Uptime)
 
+	// 图片缓存大小转 MB + 命中率精度控制
+	formatted.ImageCache.Size = bytesToMB(m.ImageCache.Size)
+	formatted.ImageCache.MaxSize = bytesToMB(m.ImageCache.MaxSize)
+	formatted.ImageCache.HitRate = round(m.ImageCache.HitRate, 2)
+
 	return &formatted
 }
 
@@ -6,11 +6,15 @@ import (
 	"crypto/sha256"
 	"crypto/tls"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/lin-snow/ech0/internal/config"
@@ -47,6 +51,86 @@ func ExtractDomain(url string) string {
 	return url
 }
 
+// ErrNonPublicAddress 目标地址不是公网地址
+var ErrNonPublicAddress = errors.New("refusing to connect to non-public address")
+
+// maxPublicRedirects 访问远端提供的 URL 时允许的最大重定向次数
+const maxPublicRedirects = 5
+
+// carrierGradeNAT 运营商级 NAT 地址段（100.64.0.0/10），同样不可从公网访问
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// NewPublicClient 创建只能访问公网地址的 HTTP 客户端，用于请求由远端提供的 URL，防止 SSRF
+//
+// 地址在建立连接时校验，域名解析到内网、回环或链路本地地址时拒绝连接，重定向同样受限。
+func NewPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !IsPublicIP(net.ParseIP(host)) {
+				return fmt.Errorf("%w: %s", ErrNonPublicAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// 不使用环境变量中的代理，否则校验的是代理地址而不是目标地址
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: 4,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxPublicRedirects {
+				return errors.New("stopped after too many redirects")
+			}
+			return ValidateRemoteURL(req.URL.String())
+		},
+	}
+}
+
+// ValidateRemoteURL 校验远端 URL 只使用 http(s) 协议且包含主机名
+func ValidateRemoteURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme: %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return errors.New("url host is empty")
+	}
+	return nil
+}
+
+// IsPublicIP 判断 IP 是否为可从公网访问的单播地址
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!carrierGradeNAT.Contains(ip) &&
+		!(ip.To4() != nil && ip.To4()[0] == 0)
+}
+
+// activityClient 投递 Activity 使用的 HTTP 客户端
+var activityClient = NewPublicClient(10 * time.Second)
+
 // Header 自定义请求头结构体
 type Header struct {
 	Header  string
@@ -179,11 +263,8 @@ func PostActivity(activity []byte, inboxURL string, actorID string) error {
 		return fmt.Errorf("failed to sign request: %w", err)
 	}
 
-	// 发送请求
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-	resp, err := client.Do(req)
+	// 发送请求，收件箱地址来自远端 Actor 文档，只允许投递到公网地址
+	resp, err := activityClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
 	}
@@ -193,13 +274,30 @@ func PostActivity(activity []byte, inboxURL string, actorID string) error {
 
 	// 检查响应
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("received non-2xx response: %d - %s", resp.StatusCode, string(body))
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &ActivityDeliveryError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	return nil
 }
 
+// ActivityDeliveryError 投递 Activity 时远端返回的非 2xx 响应
+type ActivityDeliveryError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ActivityDeliveryError) Error() string {
+	return fmt.Sprintf("received non-2xx response: %d - %s", e.StatusCode, e.Body)
+}
+
+// Retryable 判断该响应是否值得重试（服务端错误、限流或超时）
+func (e *ActivityDeliveryError) Retryable() bool {
+	return e.StatusCode >= 500 ||
+		e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode == http.StatusRequestTimeout
+}
+
 // SignRequest 对请求进行签名
 func SignRequest(req *http.Request, priv *rsa.PrivateKey, keyID string, body []byte) error {
 	// 1. Digest
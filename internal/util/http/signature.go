@@ -0,0 +1,211 @@
+package util
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPSignature 解析后的 Signature 请求头
+type HTTPSignature struct {
+	KeyID     string   // 公钥 ID，通常为 Actor ID + "#main-key"
+	Algorithm string   // 签名算法
+	Headers   []string // 参与签名的请求头（小写）
+	Signature []byte   // 签名原文
+	Created   int64    // 签名创建时间（Unix 秒），对应 (created) 伪头
+}
+
+// ParseSignatureHeader 解析 Signature 请求头
+func ParseSignatureHeader(header string) (*HTTPSignature, error) {
+	if header == "" {
+		return nil, errors.New("missing signature header")
+	}
+
+	params := make(map[string]string)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		params[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+
+	sig := &HTTPSignature{
+		KeyID:     params["keyid"],
+		Algorithm: params["algorithm"],
+	}
+	if sig.KeyID == "" {
+		return nil, errors.New("signature header missing keyId")
+	}
+
+	// 未声明 headers 时按规范默认为 date
+	headers := params["headers"]
+	if headers == "" {
+		headers = "date"
+	}
+	sig.Headers = strings.Fields(strings.ToLower(headers))
+
+	if created := params["created"]; created != "" {
+		value, err := strconv.ParseInt(created, 10, 64)
+		if err != nil {
+			return nil, errors.New("signature header has invalid created")
+		}
+		sig.Created = value
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil || len(raw) == 0 {
+		return nil, errors.New("signature header has invalid signature")
+	}
+	sig.Signature = raw
+
+	return sig, nil
+}
+
+// VerifyDigest 校验 Digest 请求头与请求体是否一致
+func VerifyDigest(req *http.Request, body []byte) error {
+	header := req.Header.Get("Digest")
+	if header == "" {
+		return errors.New("missing digest header")
+	}
+
+	digest := sha256.Sum256(body)
+	expected := base64.StdEncoding.EncodeToString(digest[:])
+
+	// Digest 可能包含多个算法，只要 SHA-256 匹配即可
+	for _, part := range strings.Split(header, ",") {
+		algo, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && strings.EqualFold(algo, "SHA-256") && value == expected {
+			return nil
+		}
+	}
+
+	return errors.New("digest mismatch")
+}
+
+// VerifyDate 校验 Date 请求头是否在允许的时间偏差内
+func VerifyDate(req *http.Request, maxSkew time.Duration) error {
+	date := req.Header.Get("Date")
+	if date == "" {
+		return errors.New("missing date header")
+	}
+
+	t, err := http.ParseTime(date)
+	if err != nil {
+		return fmt.Errorf("invalid date header: %w", err)
+	}
+
+	diff := time.Since(t)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > maxSkew {
+		return errors.New("date header outside allowed window")
+	}
+
+	return nil
+}
+
+// VerifyCreated 校验签名的 created 参数是否在允许的时间偏差内
+func VerifyCreated(sig *HTTPSignature, maxSkew time.Duration) error {
+	if sig.Created == 0 {
+		return errors.New("missing signature created")
+	}
+
+	diff := time.Since(time.Unix(sig.Created, 0))
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > maxSkew {
+		return errors.New("signature created outside allowed window")
+	}
+
+	return nil
+}
+
+// VerifySignature 使用公钥校验请求签名（rsa-sha256 / hs2019）
+func VerifySignature(req *http.Request, sig *HTTPSignature, pub *rsa.PublicKey) error {
+	switch strings.ToLower(sig.Algorithm) {
+	case "", "rsa-sha256", "hs2019":
+	default:
+		return fmt.Errorf("unsupported signature algorithm: %s", sig.Algorithm)
+	}
+
+	signingString, err := buildSigningString(req, sig.Headers, sig)
+	if err != nil {
+		return err
+	}
+
+	hashed := sha256.Sum256([]byte(signingString))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, hashed[:], sig.Signature); err != nil {
+		return errors.New("signature verification failed")
+	}
+
+	return nil
+}
+
+// BuildSigningString 按签名头顺序构造待签名字符串
+func BuildSigningString(req *http.Request, headers []string) (string, error) {
+	return buildSigningString(req, headers, nil)
+}
+
+// buildSigningString 构造待签名字符串，校验时由 sig 提供 (created) 伪头的取值
+func buildSigningString(req *http.Request, headers []string, sig *HTTPSignature) (string, error) {
+	lines := make([]string, 0, len(headers))
+	for _, h := range headers {
+		switch h {
+		case "(created)":
+			if sig == nil || sig.Created == 0 {
+				return "", errors.New("signed header (created) is missing")
+			}
+			lines = append(lines, "(created): "+strconv.FormatInt(sig.Created, 10))
+		case "(request-target)":
+			lines = append(lines, fmt.Sprintf("(request-target): %s %s",
+				strings.ToLower(req.Method),
+				req.URL.RequestURI(),
+			))
+		case "host":
+			host := req.Host
+			if host == "" {
+				host = req.Header.Get("Host")
+			}
+			lines = append(lines, "host: "+host)
+		default:
+			value := req.Header.Get(h)
+			if value == "" {
+				return "", fmt.Errorf("signed header %s is missing", h)
+			}
+			lines = append(lines, h+": "+value)
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// ParsePublicKeyPem 解析 PEM 格式的 RSA 公钥
+func ParsePublicKeyPem(publicKeyPem string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKeyPem))
+	if block == nil {
+		return nil, errors.New("invalid public key pem")
+	}
+
+	// 优先按 PKIX 解析，兼容 PKCS#1
+	if pub, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		rsaPub, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("public key is not rsa")
+		}
+		return rsaPub, nil
+	}
+
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
@@ -0,0 +1,133 @@
+package util
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+// blurhashChars 是 BlurHash 使用的 Base83 字符表
+const blurhashChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// EncodeBlurhash 按 BlurHash 算法（https://blurha.sh）编码图片，xComponents/yComponents 取值 1-9
+// 计算量与像素数成正比，调用方应先将图片缩小到几十像素
+func EncodeBlurhash(img image.Image, xComponents, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", fmt.Errorf("blurhash components out of range: %dx%d", xComponents, yComponents)
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return "", fmt.Errorf("blurhash: empty image")
+	}
+
+	// 预先转换为线性 RGB
+	linear := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			linear[y*width+x] = [3]float64{srgbToLinear(r >> 8), srgbToLinear(g >> 8), srgbToLinear(b >> 8)}
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+			var factor [3]float64
+			for y := 0; y < height; y++ {
+				basisY := math.Cos(math.Pi * float64(j) * float64(y) / float64(height))
+				for x := 0; x < width; x++ {
+					basis := math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) * basisY
+					pixel := linear[y*width+x]
+					factor[0] += basis * pixel[0]
+					factor[1] += basis * pixel[1]
+					factor[2] += basis * pixel[2]
+				}
+			}
+			scale := normalisation / float64(width*height)
+			factors = append(factors, [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale})
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	dc, ac := factors[0], factors[1:]
+	maxValue := 1.0
+	if len(ac) > 0 {
+		actualMax := 0.0
+		for _, factor := range ac {
+			actualMax = math.Max(actualMax, math.Max(math.Abs(factor[0]), math.Max(math.Abs(factor[1]), math.Abs(factor[2]))))
+		}
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maxValue = float64(quantisedMax+1) / 166
+		builder.WriteString(encode83(quantisedMax, 1))
+	} else {
+		builder.WriteString(encode83(0, 1))
+	}
+
+	builder.WriteString(encode83(linearToSRGB(dc[0])<<16+linearToSRGB(dc[1])<<8+linearToSRGB(dc[2]), 4))
+	for _, factor := range ac {
+		quant := func(value float64) int {
+			return int(math.Max(0, math.Min(18, math.Floor(signPow(value/maxValue, 0.5)*9+9.5))))
+		}
+		builder.WriteString(encode83(quant(factor[0])*19*19+quant(factor[1])*19+quant(factor[2]), 2))
+	}
+
+	return builder.String(), nil
+}
+
+// AverageColor 返回图片的平均颜色，格式为 #rrggbb
+func AverageColor(img image.Image) string {
+	bounds := img.Bounds()
+	var r, g, b, count uint64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pr, pg, pb, _ := img.At(x, y).RGBA()
+			r += uint64(pr >> 8)
+			g += uint64(pg >> 8)
+			b += uint64(pb >> 8)
+			count++
+		}
+	}
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r/count, g/count, b/count)
+}
+
+// encode83 将整数编码为定长的 Base83 字符串
+func encode83(value, length int) string {
+	result := make([]byte, length)
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		result[i-1] = blurhashChars[digit]
+	}
+	return string(result)
+}
+
+func srgbToLinear(value uint32) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxProcessSize 是按需处理允许的最大目标边长
+const maxProcessSize = 4096
+
+// ParseProcessOptions 从查询参数 w/h/q/fmt/mode 解析并规范化图片处理选项，没有任何处理参数时返回 false
+// 质量按 5 取整，格式与模式只接受已知取值，避免同一张图因参数差异产生大量缓存
+func ParseProcessOptions(query url.Values) (ImageProcessOptions, bool) {
+	wStr, hStr, qStr, fmtStr := query.Get("w"), query.Get("h"), query.Get("q"), query.Get("fmt")
+	if wStr == "" && hStr == "" && qStr == "" && fmtStr == "" {
+		return ImageProcessOptions{}, false
+	}
+
+	w, _ := strconv.Atoi(wStr)
+	h, _ := strconv.Atoi(hStr)
+	q, _ := strconv.Atoi(qStr)
+
+	// 安全限制
+	if w < 0 || w > maxProcessSize {
+		w = 0
+	}
+	if h < 0 || h > maxProcessSize {
+		h = 0
+	}
+	if q <= 0 || q > 100 {
+		q = 75
+	}
+	q = max((q+2)/5*5, 5)
+
+	switch fmtStr {
+	case "jpeg":
+		fmtStr = "jpg"
+	case "", "jpg", "png", "webp":
+	default:
+		fmtStr = ""
+	}
+
+	mode := query.Get("mode")
+	if mode != "mfit" && mode != "fill" {
+		mode = "lfit"
+	}
+
+	return ImageProcessOptions{Width: w, Height: h, Quality: q, Format: fmtStr, Mode: mode}, true
+}
+
+// Fingerprint 返回源文件与处理选项的指纹，用作 ETag 与缓存文件名，source 需能唯一标识源文件的版本
+func (opts ImageProcessOptions) Fingerprint(source string) string {
+	id := fmt.Sprintf("imgproc-%s-%d-%d-%d-%s-%s",
+		source, opts.Width, opts.Height, opts.Quality, opts.Format, opts.Mode)
+	return fmt.Sprintf("%x", md5.Sum([]byte(id)))
+}
+
+// OutputExt 返回处理结果的扩展名，未指定输出格式时沿用源格式
+func (opts ImageProcessOptions) OutputExt(srcFormat string) string {
+	ext := opts.Format
+	if ext == "" {
+		ext = strings.ToLower(strings.TrimPrefix(srcFormat, "."))
+	}
+	if ext == "jpeg" {
+		ext = "jpg"
+	}
+	return ext
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// posterTimeout 是截取视频封面的超时时间
+const posterTimeout = 30 * time.Second
+
+// ErrFFmpegNotFound 表示系统中未安装 ffmpeg，无法截取视频封面
+var ErrFFmpegNotFound = errors.New("ffmpeg not found")
+
+// ExtractPoster 使用 ffmpeg 截取视频封面，返回 PNG 字节流
+// 从开头若干帧中挑选最有代表性的一帧，避免截到黑屏的首帧
+func ExtractPoster(ctx context.Context, videoPath string) ([]byte, error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, ErrFFmpegNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, posterTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, ffmpeg,
+		"-v", "error",
+		"-i", videoPath,
+		"-vf", "thumbnail=n=50",
+		"-frames:v", "1",
+		"-f", "image2pipe",
+		"-vcodec", "png",
+		"pipe:1",
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("extract poster failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if stdout.Len() == 0 {
+		return nil, errors.New("extract poster failed: no frame decoded")
+	}
+	return stdout.Bytes(), nil
+}
@@ -31,10 +31,9 @@ func ProcessImage(src io.Reader, srcFormat string, opts ImageProcessOptions) ([]
 		return nil, "", fmt.Errorf("read image source failed: %w", err)
 	}
 
-	// 1. 尝试只读取头部信息获取图片尺寸，进行防爆检测
-	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	srcImg, format, err := decodeImage(data)
 	if err != nil {
-		return nil, "", fmt.Errorf("decode image config failed: %w", err)
+		return nil, "", err
 	}
 
 	// 如果前端未提供图片原格式，我们优先尝试根据文件头嗅探
@@ -42,19 +41,6 @@ func ProcessImage(src io.Reader, srcFormat string, opts ImageProcessOptions) ([]
 		srcFormat = format
 	}
 
-	// 防 OOM 熔断保护：限制最高处理像素为 3600 万（例如 6000x6000）
-	// 大于该值的很可能是炸弹图，如果此时进入 image.Decode() 会瞬间吃掉 140MB 以上的峰值内存。
-	const maxPixels = 36000000
-	if int64(config.Width)*int64(config.Height) > maxPixels {
-		return nil, "", fmt.Errorf("image pixels too large: %dx%d exceeds absolute limit %d pixels", config.Width, config.Height, maxPixels)
-	}
-
-	// 2. 真正进行像素级解码 (安全的)
-	srcImg, _, err := image.Decode(bytes.NewReader(data))
-	if err != nil {
-		return nil, "", fmt.Errorf("decode image failed: %w", err)
-	}
-
 	bounds := srcImg.Bounds()
 	srcW, srcH := bounds.Dx(), bounds.Dy()
 
@@ -90,6 +76,29 @@ func ProcessImage(src io.Reader, srcFormat string, opts ImageProcessOptions) ([]
 	return encodeImage(resultImg, outFormat, quality)
 }
 
+// decodeImage 先读取头部信息进行防爆检测，再进行像素级解码，返回图片与嗅探到的格式
+func decodeImage(data []byte) (image.Image, string, error) {
+	// 1. 尝试只读取头部信息获取图片尺寸，进行防爆检测
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image config failed: %w", err)
+	}
+
+	// 防 OOM 熔断保护：限制最高处理像素为 3600 万（例如 6000x6000）
+	// 大于该值的很可能是炸弹图，如果此时进入 image.Decode() 会瞬间吃掉 140MB 以上的峰值内存。
+	const maxPixels = 36000000
+	if int64(config.Width)*int64(config.Height) > maxPixels {
+		return nil, "", fmt.Errorf("image pixels too large: %dx%d exceeds absolute limit %d pixels", config.Width, config.Height, maxPixels)
+	}
+
+	// 2. 真正进行像素级解码 (安全的)
+	srcImg, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image failed: %w", err)
+	}
+	return srcImg, format, nil
+}
+
 // calcDimensions 根据源尺寸、目标尺寸和缩放模式计算最终尺寸
 func calcDimensions(srcW, srcH, targetW, targetH int, mode string) (int, int) {
 	if targetW <= 0 && targetH <= 0 {
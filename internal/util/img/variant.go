@@ -0,0 +1,77 @@
+package util
+
+import (
+	"fmt"
+	"image"
+	"io"
+)
+
+const (
+	VariantFormat  = "webp" // 响应式尺寸的编码格式
+	variantQuality = 75     // 响应式尺寸的编码质量
+
+	blurhashSize        = 32 // 计算 BlurHash 前缩小到的最大边长
+	blurhashXComponents = 4
+	blurhashYComponents = 3
+)
+
+// VariantWidths 上传图片后生成的响应式宽度
+var VariantWidths = []int{320, 640, 1280}
+
+// Variant 缩放后的一个尺寸
+type Variant struct {
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// VariantSet 图片的响应式尺寸与占位信息
+type VariantSet struct {
+	Variants      []Variant // 按宽度升序，只包含小于原图宽度的尺寸
+	Blurhash      string
+	DominantColor string // #rrggbb
+}
+
+// GenerateVariants 解码一次图片，生成各响应式宽度的 WebP、BlurHash 与主色调
+// 动图只计算占位信息，不生成尺寸，避免丢失动画
+func GenerateVariants(src io.Reader, widths []int) (*VariantSet, error) {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("read image source failed: %w", err)
+	}
+
+	srcImg, format, err := decodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+	bounds := srcImg.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	set := &VariantSet{}
+	if format != "gif" {
+		for _, width := range widths {
+			if width >= srcW {
+				break
+			}
+			dstW, dstH := calcLfit(srcW, srcH, width, 0)
+			encoded, _, err := encodeImage(resizeImage(srcImg, dstW, dstH), VariantFormat, variantQuality)
+			if err != nil {
+				return nil, err
+			}
+			set.Variants = append(set.Variants, Variant{Width: dstW, Height: dstH, Data: encoded})
+		}
+	}
+
+	// 占位信息基于缩略图计算，结果与原图几乎一致
+	thumbW, thumbH := calcLfit(srcW, srcH, blurhashSize, blurhashSize)
+	var thumb image.Image = srcImg
+	if thumbW != srcW || thumbH != srcH {
+		thumb = resizeImage(srcImg, thumbW, thumbH)
+	}
+	if set.Blurhash, err = EncodeBlurhash(thumb, blurhashXComponents, blurhashYComponents); err != nil {
+		return nil, err
+	}
+	set.DominantColor = AverageColor(thumb)
+
+	return set, nil
+}
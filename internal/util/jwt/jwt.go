@@ -27,6 +27,7 @@ func CreateClaims(user userModel.User) jwt.Claims {
 	claims := authModel.MyClaims{
 		Userid:   user.ID,
 		Username: user.Username,
+		Type:     authModel.TokenTypeSession,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:   config.Config.Auth.Jwt.Issuer,
 			Subject:  user.Username,
@@ -65,6 +66,20 @@ func CreateClaimsWithExpiry(user userModel.User, expiry int64) jwt.Claims {
 	return claims
 }
 
+// CreateAccessTokenClaims 创建访问令牌的 Claims，expiry 为秒数，0 表示永不过期
+func CreateAccessTokenClaims(
+	user userModel.User,
+	expiry int64,
+	jti string,
+	scopes []string,
+) jwt.Claims {
+	claims := CreateClaimsWithExpiry(user, expiry).(authModel.MyClaims)
+	claims.Type = authModel.TokenTypeAccess
+	claims.Scopes = scopes
+	claims.ID = jti
+	return claims
+}
+
 // GenerateToken 生成JWT Token
 func GenerateToken(claim jwt.Claims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
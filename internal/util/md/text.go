@@ -0,0 +1,66 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// blankLines 匹配连续的空行
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// HTMLToText 将嘟文等 ActivityPub 内容的 HTML 转换为纯文本
+// 段落与换行保留为换行；提及与话题保留文字，其他链接使用完整地址（Mastodon 会截断链接的显示文字）
+func HTMLToText(content string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	var builder strings.Builder
+	inLink, keepLinkText := false, false
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+
+		switch tokenType {
+		case html.TextToken:
+			if !inLink || keepLinkText {
+				builder.Write(tokenizer.Text())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "br":
+				builder.WriteString("\n")
+			case "p":
+				if builder.Len() > 0 {
+					builder.WriteString("\n\n")
+				}
+			case "a":
+				var href, class string
+				for _, attr := range token.Attr {
+					switch attr.Key {
+					case "href":
+						href = attr.Val
+					case "class":
+						class = attr.Val
+					}
+				}
+				inLink = tokenType == html.StartTagToken
+				keepLinkText = href == "" ||
+					strings.Contains(class, "mention") ||
+					strings.Contains(class, "hashtag")
+				if !keepLinkText {
+					builder.WriteString(href)
+				}
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "a" {
+				inLink = false
+			}
+		}
+	}
+
+	return strings.TrimSpace(blankLines.ReplaceAllString(builder.String(), "\n\n"))
+}
@@ -1,13 +1,16 @@
 package util
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -193,6 +196,88 @@ func UploadVideoToLocal(file *multipart.FileHeader, userID uint) (string, error)
 	return videoURL, nil
 }
 
+// SaveMediaToLocal 校验文件类型后将文件流保存到本地上传目录，返回访问 URL 与保存路径
+//
+// 扩展名对应的 MIME 必须在允许上传的类型中，且文件头嗅探出的类型不能与之矛盾。
+func SaveMediaToLocal(
+	src io.Reader,
+	fileName string,
+	fileType commonModel.UploadFileType,
+	userID uint,
+) (string, string, error) {
+	var baseDir, kind string
+	switch fileType {
+	case commonModel.ImageType:
+		baseDir, kind = config.Config.Upload.ImagePath, "images"
+	case commonModel.VideoType:
+		baseDir, kind = config.Config.Upload.VideoPath, "videos"
+	default:
+		return "", "", errors.New(commonModel.FILE_TYPE_NOT_ALLOWED)
+	}
+
+	// 按扩展名校验类型
+	ext := strings.ToLower(filepath.Ext(fileName))
+	contentType, _, _ := strings.Cut(mime.TypeByExtension(ext), ";")
+	if !strings.HasPrefix(contentType, string(fileType)+"/") ||
+		!IsAllowedType(contentType, config.Config.Upload.AllowedTypes) {
+		return "", "", errors.New(commonModel.FILE_TYPE_NOT_ALLOWED)
+	}
+
+	// 嗅探文件头，拒绝内容与扩展名不符的文件
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", "", err
+	}
+	head = head[:n]
+	if !sniffedTypeMatches(http.DetectContentType(head), contentType, fileType) {
+		return "", "", errors.New(commonModel.FILE_TYPE_NOT_ALLOWED)
+	}
+
+	newFileName, err := GenerateRandomFilename(userID, ext)
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.MkdirAll(baseDir, 0o750); err != nil {
+		return "", "", err
+	}
+
+	savePath := filepath.Join(baseDir, newFileName)
+	out, err := os.Create(savePath)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := io.Copy(out, io.MultiReader(bytes.NewReader(head), src)); err != nil {
+		_ = out.Close()
+		_ = os.Remove(savePath)
+		return "", "", err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(savePath)
+		return "", "", err
+	}
+
+	return fmt.Sprintf("/%s/%s", kind, newFileName), savePath, nil
+}
+
+// sniffedTypeMatches 判断嗅探出的类型是否与扩展名对应的类型一致，无法识别的格式（如 HEIC）放行
+func sniffedTypeMatches(sniffed, contentType string, fileType commonModel.UploadFileType) bool {
+	sniffed, _, _ = strings.Cut(sniffed, ";")
+	switch {
+	case sniffed == "application/octet-stream":
+		return true
+	case strings.HasPrefix(sniffed, string(fileType)+"/"):
+		return true
+	case sniffed == "application/ogg":
+		return fileType == commonModel.VideoType
+	case sniffed == "text/xml" || sniffed == "text/plain":
+		// SVG 会被识别为 XML 或纯文本
+		return contentType == "image/svg+xml"
+	default:
+		return false
+	}
+}
+
 // DeleteFileFromLocal 删除本地文件
 func DeleteFileFromLocal(filePath string) error {
 	err := os.Remove(filePath)
@@ -0,0 +1,155 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// localObjectStorage 以本地目录模拟的对象存储，用于挂载的 NAS、外接硬盘等
+type localObjectStorage struct {
+	root string
+}
+
+// NewLocalObjectStorage 创建基于本地目录的对象存储
+func NewLocalObjectStorage(root string) (ObjectStorage, error) {
+	if root == "" || !filepath.IsAbs(root) {
+		return nil, fmt.Errorf("invalid local storage path: %s", root)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local storage directory: %w", err)
+	}
+	return &localObjectStorage{root: filepath.Clean(root)}, nil
+}
+
+// resolve 将对象名转换为本地路径，防止越出根目录
+func (l *localObjectStorage) resolve(objectName string) (string, error) {
+	path := filepath.Join(l.root, filepath.FromSlash(objectName))
+	if !strings.HasPrefix(path, l.root+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid object name: %s", objectName)
+	}
+	return path, nil
+}
+
+// Upload implements storage.ObjectStorage.
+// 先写入临时文件再重命名，避免中断的上传留下不完整的对象
+func (l *localObjectStorage) Upload(
+	ctx context.Context,
+	objectName string,
+	r io.Reader,
+	contentType string,
+) error {
+	path, err := l.resolve(objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tempPath := path + ".uploading"
+	out, err := os.Create(tempPath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
+
+// Download implements storage.ObjectStorage.
+func (l *localObjectStorage) Download(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	path, err := l.resolve(objectName)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// ListObjects implements storage.ObjectStorage.
+func (l *localObjectStorage) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	var objects []string
+	err := filepath.Walk(l.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasSuffix(path, ".uploading") {
+			return nil
+		}
+		relPath, err := filepath.Rel(l.root, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(relPath)
+		if strings.HasPrefix(name, prefix) {
+			objects = append(objects, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// ListObjectStream implements storage.ObjectStorage.
+func (l *localObjectStorage) ListObjectStream(ctx context.Context, prefix string) (<-chan string, error) {
+	objects, err := l.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	resultCh := make(chan string)
+	go func() {
+		defer close(resultCh)
+		for _, object := range objects {
+			select {
+			case resultCh <- object:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return resultCh, nil
+}
+
+// DeleteObject implements storage.ObjectStorage.
+func (l *localObjectStorage) DeleteObject(ctx context.Context, objectName string) error {
+	path, err := l.resolve(objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// PresignURL implements storage.ObjectStorage.
+func (l *localObjectStorage) PresignURL(
+	ctx context.Context,
+	objectName string,
+	expiry time.Duration,
+	method string,
+) (string, error) {
+	return "", errors.New("presigned URL is not supported by local storage")
+}
@@ -0,0 +1,94 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lin-snow/ech0/internal/config"
+	echoModel "github.com/lin-snow/ech0/internal/model/echo"
+)
+
+// imageVariantDir 是本地图片响应式尺寸所在的子目录，通过 /api/images/variants/ 访问
+const imageVariantDir = "variants"
+
+// ImageVariantManifest 记录本地图片的响应式尺寸与占位信息
+// 上传后异步生成并写入清单文件，发布或更新 Echo 时据此回填媒体
+type ImageVariantManifest struct {
+	Blurhash      string                   `json:"blurhash"`
+	DominantColor string                   `json:"dominant_color"`
+	Variants      []echoModel.MediaVariant `json:"variants"`
+}
+
+// ImageVariantFile 返回本地图片某个尺寸的存储路径与访问 URL，fileName 为原图文件名
+func ImageVariantFile(fileName string, width int, format string) (string, string) {
+	name := fmt.Sprintf("%s_%d.%s", fileStem(fileName), width, format)
+	return filepath.Join(config.Config.Upload.ImagePath, imageVariantDir, name),
+		fmt.Sprintf("/images/%s/%s", imageVariantDir, name)
+}
+
+// ReadImageVariantManifest 读取本地图片的响应式尺寸清单，尚未生成时返回 os.ErrNotExist
+func ReadImageVariantManifest(fileName string) (*ImageVariantManifest, error) {
+	data, err := os.ReadFile(imageVariantManifestPath(fileName))
+	if err != nil {
+		return nil, err
+	}
+	var manifest ImageVariantManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// WriteImageVariantManifest 写入本地图片的响应式尺寸清单，需在所有尺寸写入后调用
+func WriteImageVariantManifest(fileName string, manifest *ImageVariantManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	manifestPath := imageVariantManifestPath(fileName)
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0o750); err != nil {
+		return err
+	}
+	tmpPath := manifestPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, manifestPath)
+}
+
+// DeleteImageVariants 删除本地图片的全部响应式尺寸与清单
+func DeleteImageVariants(fileName string) error {
+	matches, err := filepath.Glob(filepath.Join(
+		config.Config.Upload.ImagePath, imageVariantDir, escapeGlob(fileStem(fileName))+"_*",
+	))
+	if err != nil {
+		return err
+	}
+	for _, match := range append(matches, imageVariantManifestPath(fileName)) {
+		if err := DeleteFileFromLocal(match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// imageVariantManifestPath 返回清单文件的路径
+func imageVariantManifestPath(fileName string) string {
+	return filepath.Join(config.Config.Upload.ImagePath, imageVariantDir, fileStem(fileName)+".json")
+}
+
+// fileStem 返回去掉扩展名的文件名
+func fileStem(fileName string) string {
+	base := filepath.Base(fileName)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// escapeGlob 转义文件名中的通配符
+func escapeGlob(name string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`)
+	return replacer.Replace(name)
+}
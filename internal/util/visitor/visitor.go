@@ -0,0 +1,65 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lin-snow/ech0/internal/config"
+)
+
+// CookieName 访客标识 Cookie 的名称
+const CookieName = "ech0_visitor"
+
+// NewVisitorToken 生成新的访客标识，返回标识与带签名的 Cookie 值，Cookie 中记录签发时间
+func NewVisitorToken() (string, string) {
+	id := rand.Text()
+	payload := id + "." + strconv.FormatInt(time.Now().Unix(), 10)
+	return id, payload + "." + sign(payload)
+}
+
+// ParseVisitorToken 校验 Cookie 值的签名，返回访客标识与签发时间
+//
+// 旧版 Cookie 不含签发时间，返回零值时间。
+func ParseVisitorToken(token string) (string, time.Time, bool) {
+	payload, signature, ok := cutLast(token, ".")
+	if !ok || payload == "" || !hmac.Equal([]byte(signature), []byte(sign(payload))) {
+		return "", time.Time{}, false
+	}
+
+	id, issued, hasIssued := strings.Cut(payload, ".")
+	if !hasIssued {
+		return id, time.Time{}, true
+	}
+	unix, err := strconv.ParseInt(issued, 10, 64)
+	if err != nil || id == "" {
+		return "", time.Time{}, false
+	}
+	return id, time.Unix(unix, 0), true
+}
+
+// Fingerprint 根据 IP 与浏览器特征生成访客指纹，用于识别不携带 Cookie 的访客
+func Fingerprint(ip, userAgent, acceptLanguage string) string {
+	sum := sha256.Sum256([]byte(ip + "\n" + userAgent + "\n" + acceptLanguage))
+	return hex.EncodeToString(sum[:16])
+}
+
+// cutLast 按最后一个分隔符切分字符串
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+// sign 使用服务端密钥对访客标识签名
+func sign(id string) string {
+	mac := hmac.New(sha256.New, config.JWT_SECRET)
+	mac.Write([]byte("visitor:" + id))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
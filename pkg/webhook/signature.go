@@ -0,0 +1,124 @@
+// Package webhook 提供 Ech0 Webhook 签名的生成与校验工具，供接收方直接引用。
+//
+// 每次投递都会携带以下请求头：
+//
+//	X-Ech0-Timestamp: 1712345678
+//	X-Ech0-Signature: sha256=<hex(HMAC-SHA256(secret, timestamp + "." + body))>
+//
+// 接收方应当：
+//  1. 使用原始请求体（未经反序列化）与时间戳重新计算签名，并以常量时间比较；
+//  2. 拒绝时间戳与本地时间相差超过容忍窗口（默认 5 分钟）的请求，以防止重放；
+//  3. 结合 X-Ech0-Event-ID 做幂等去重，窗口内重复的事件 ID 直接忽略。
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderSignature 签名请求头
+	HeaderSignature = "X-Ech0-Signature"
+	// HeaderTimestamp 签名时间戳请求头（Unix 秒）
+	HeaderTimestamp = "X-Ech0-Timestamp"
+	// HeaderEventID 事件 ID 请求头
+	HeaderEventID = "X-Ech0-Event-ID"
+	// HeaderEvent 事件类型请求头
+	HeaderEvent = "X-Ech0-Event"
+
+	// SignaturePrefix 签名值前缀，标识所用算法
+	SignaturePrefix = "sha256="
+
+	// DefaultTolerance 默认允许的时间偏差（重放窗口）
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook: missing signature header")
+	ErrMissingTimestamp = errors.New("webhook: missing timestamp header")
+	ErrInvalidTimestamp = errors.New("webhook: invalid timestamp header")
+	ErrTimestampExpired = errors.New("webhook: timestamp outside tolerance window")
+	ErrInvalidSignature = errors.New("webhook: signature mismatch")
+)
+
+// Sign 计算签名，返回带算法前缀的签名值
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest 为请求设置时间戳与签名头，secret 为空时不做任何处理
+func SignRequest(req *http.Request, secret string, body []byte, now time.Time) {
+	if secret == "" {
+		return
+	}
+	ts := now.Unix()
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, ts, body))
+}
+
+// Verify 校验签名与时间戳，tolerance <= 0 时使用 DefaultTolerance
+func Verify(
+	secret, signature, timestamp string,
+	body []byte,
+	tolerance time.Duration,
+	now time.Time,
+) error {
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	if timestamp == "" {
+		return ErrMissingTimestamp
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	diff := now.Sub(time.Unix(ts, 0))
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > tolerance {
+		return ErrTimestampExpired
+	}
+
+	if !strings.HasPrefix(signature, SignaturePrefix) {
+		return ErrInvalidSignature
+	}
+	expected := Sign(secret, ts, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifyRequest 从请求头中读取签名信息并校验，body 需为原始请求体
+func VerifyRequest(
+	req *http.Request,
+	secret string,
+	body []byte,
+	tolerance time.Duration,
+) error {
+	return Verify(
+		secret,
+		req.Header.Get(HeaderSignature),
+		req.Header.Get(HeaderTimestamp),
+		body,
+		tolerance,
+		time.Now(),
+	)
+}
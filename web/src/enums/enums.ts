@@ -40,6 +40,7 @@ export enum CommentProvider {
   ARTALK = 'artalk',
   WALINE = 'waline',
   GISCUS = 'giscus',
+  ECH0 = 'ech0',
 }
 
 // S3 Service Provider
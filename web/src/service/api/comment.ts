@@ -0,0 +1,44 @@
+import { request } from '../request'
+
+// 获取Echo的评论（按楼层组织）
+export function fetchGetEchoComments(echoId: number) {
+  return request<App.Api.Comment.Comment[]>({
+    url: `/echo/${echoId}/comments`,
+    method: 'GET',
+  })
+}
+
+// 发表评论
+export function fetchAddComment(echoId: number, comment: App.Api.Comment.CommentToAdd) {
+  return request<App.Api.Comment.Comment>({
+    url: `/echo/${echoId}/comments`,
+    method: 'POST',
+    data: comment,
+  })
+}
+
+// 获取评论管理列表
+export function fetchGetComments(params: App.Api.Comment.CommentListParams) {
+  return request<App.Api.Comment.CommentListResult>({
+    url: `/comments`,
+    method: 'GET',
+    query: params,
+  })
+}
+
+// 审核评论
+export function fetchUpdateCommentStatus(id: number, status: App.Api.Comment.CommentStatus) {
+  return request({
+    url: `/comments/${id}/status`,
+    method: 'PUT',
+    data: { status },
+  })
+}
+
+// 删除评论
+export function fetchDeleteComment(id: number) {
+  return request({
+    url: `/comments/${id}`,
+    method: 'DELETE',
+  })
+}
@@ -37,12 +37,20 @@ export function fetchUpdateEcho(echo: App.Api.Ech0.EchoToUpdate) {
 
 // 点赞Echo
 export function fetchLikeEcho(echoId: number) {
-  return request({
+  return request<App.Api.Ech0.EchoLikeResult>({
     url: `/echo/like/${echoId}`,
     method: 'PUT',
   })
 }
 
+// 取消点赞Echo
+export function fetchUnlikeEcho(echoId: number) {
+  return request<App.Api.Ech0.EchoLikeResult>({
+    url: `/echo/like/${echoId}`,
+    method: 'DELETE',
+  })
+}
+
 // 获取Echo详情
 export function fetchGetEchoById(echoId: string) {
   return request<App.Api.Ech0.Echo>({
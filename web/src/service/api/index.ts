@@ -8,3 +8,4 @@ export * from './todo.ts'
 export * from './other.ts'
 export * from './agent.ts'
 export * from './inbox.ts'
+export * from './comment.ts'
@@ -51,17 +51,25 @@ export function fetchExportBackup() {
   })
 }
 
-// 导入备份
-export function fetchImportBackup(file: File) {
+// 预检备份，返回备份与当前数据的对比，不改动当前数据
+export function fetchPreviewRestore(file: File) {
   const formData = new FormData()
   formData.append('file', file)
-  return request({
-    url: '/backup/import',
+  return request<App.Api.Backup.RestorePreview>({
+    url: '/backup/import/preview',
     method: 'POST',
     data: formData,
   })
 }
 
+// 确认恢复预检通过的备份
+export function fetchCommitRestore(token: string) {
+  return request({
+    url: `/backup/import/${token}/commit`,
+    method: 'POST',
+  })
+}
+
 // 获取网站标题
 export function fetchGetWebsiteTitle(websiteURL: string) {
   return request<string>({
@@ -43,6 +43,7 @@ export const useSettingStore = defineStore('settingStore', () => {
     enable_comment: false,
     provider: CommentProvider.TWIKOO,
     comment_api: '',
+    auto_approve: false,
   })
   const S3Setting = ref<App.Api.Setting.S3Setting>({
     enable: false,
@@ -99,7 +99,10 @@ declare namespace App {
         extension_type: string
         tags?: Tag[]
         fav_count: number
+        status?: EchoStatus // 发布状态（旧版本服务器无此字段，视为已发布）
+        publish_at?: string // 定时发布时间
         created_at: string
+        deleted_at?: string | null // 移入回收站的时间
         user?: {
           id: number
           username: string
@@ -117,6 +120,16 @@ declare namespace App {
         width?: number // 图片宽度
         height?: number // 图片高度
         live_video_id?: number // 实况照片关联的视频Media ID
+        blurhash?: string // 图片的 BlurHash 占位
+        dominant_color?: string // 图片主色调 (#rrggbb)
+        variants?: MediaVariant[] // 响应式尺寸，按宽度升序
+      }
+
+      type MediaVariant = {
+        url: string
+        width: number
+        height: number
+        format: string
       }
 
       type Tag = {
@@ -157,6 +170,47 @@ declare namespace App {
         extension?: string | null
         extension_type?: string | null
         private: boolean
+        status?: EchoStatus
+        publish_at?: string | null // 定时发布时间（status 为 scheduled 时必填）
+      }
+
+      type EchoStatus = 'published' | 'draft' | 'scheduled'
+
+      type EchoRevision = {
+        id: number
+        echo_id: number
+        content: string
+        tags: string[]
+        media: Media[]
+        layout?: string
+        extension?: string
+        extension_type?: string
+        created_at: string
+      }
+
+      type EchoRevisionDiff = {
+        from: number
+        to: number // 0 表示当前版本
+        diff: string // unified diff
+      }
+
+      type EchoLikeResult = {
+        liked: boolean
+        fav_count: number
+      }
+
+      type EchoLikeKind = 'user' | 'visitor' | 'fingerprint' | 'legacy'
+
+      type EchoLike = {
+        id: number
+        echo_id: number
+        identity: string
+        kind: EchoLikeKind
+        user_id: number
+        fingerprint: string
+        ip: string
+        user_agent: string
+        created_at: string
       }
 
       type EchoToUpdate = {
@@ -170,6 +224,8 @@ declare namespace App {
         user_id: number
         extension?: string | null
         extension_type?: string | null
+        status?: EchoStatus
+        publish_at?: string | null
         created_at: string
       }
 
@@ -245,6 +301,7 @@ declare namespace App {
         enable_comment: boolean
         provider: string // 评论提供者
         comment_api: string // 评论 API 地址
+        auto_approve: boolean // 内置评论是否免审核
       }
 
       type S3Setting = {
@@ -270,6 +327,10 @@ declare namespace App {
         s3_full_param: string
       }
 
+      type TrashSetting = {
+        retention_days: number
+      }
+
       type OAuth2Setting = {
         enable: boolean
         provider: string
@@ -545,6 +606,52 @@ declare namespace App {
       }
     }
 
+    namespace Comment {
+      type CommentStatus = 'pending' | 'approved' | 'spam'
+
+      type Comment = {
+        id: number
+        echo_id: number
+        parent_id: number // 0 表示直接评论 Echo
+        user_id: number
+        author_name: string
+        author_email?: string // 仅管理员可见
+        author_url: string
+        author_avatar: string
+        content: string
+        status: CommentStatus
+        source: 'local' | 'fediverse'
+        remote_id?: string
+        actor_url?: string
+        ip?: string
+        user_agent?: string
+        created_at: string
+        updated_at: string
+        replies?: Comment[]
+      }
+
+      type CommentToAdd = {
+        parent_id?: number
+        author_name?: string
+        author_email?: string
+        author_url?: string
+        content: string
+      }
+
+      type CommentListParams = {
+        status?: CommentStatus
+        echo_id?: number
+        source?: 'local' | 'fediverse'
+        page: number
+        pageSize: number
+      }
+
+      type CommentListResult = {
+        items: Comment[]
+        total: number
+      }
+    }
+
     namespace Inbox {
       type Inbox = {
         id: number
@@ -569,5 +676,30 @@ declare namespace App {
         search?: string
       }
     }
+
+    namespace Backup {
+      type RestoreCount = {
+        name: string
+        backup: number
+        current: number
+      }
+
+      type RestorePreview = {
+        token: string
+        expires_at: string
+        encrypted: boolean
+        verified: boolean // 是否通过清单校验，旧版本备份没有清单
+        app_version: string
+        current_version: string
+        backup_created_at?: string
+        added_tables: string[] | null
+        added_columns: string[] | null
+        unknown_tables: string[] | null
+        unknown_columns: string[] | null
+        counts: RestoreCount[]
+        files: number
+        files_size: number
+      }
+    }
   }
 }
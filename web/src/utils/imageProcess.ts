@@ -8,6 +8,7 @@
  */
 
 import { useSettingStore } from '@/stores'
+import { getApiUrl } from '@/service/request/shared'
 
 /**
  * 构建包含图片处理参数的 URL
@@ -15,12 +16,14 @@ import { useSettingStore } from '@/stores'
  * @param originalUrl - 原始图片 URL
  * @param source - 图片来源 ('local' | 's3' | 'url')
  * @param scene - 处理场景 ('thumb' | 'full')
+ * @param objectKey - 对象存储的 Key，S3 图片由 Ech0 代理处理时使用
  * @returns 拼接处理参数后的 URL
  */
 export function buildProcessedImageUrl(
   originalUrl: string,
   source: string,
   scene: 'thumb' | 'full',
+  objectKey?: string,
 ): string {
   if (!originalUrl) return originalUrl
 
@@ -38,6 +41,11 @@ export function buildProcessedImageUrl(
   } else if (source === 's3') {
     if (!settings.s3_process) return originalUrl
     param = scene === 'thumb' ? settings.s3_thumb_param : settings.s3_full_param
+    // 由 Ech0 代理处理：从存储拉取原图处理，适用于不支持图片处理的 MinIO / R2 等
+    if (settings.s3_process === 'proxy') {
+      if (!objectKey) return originalUrl
+      originalUrl = `${getApiUrl()}/media/s3/${objectKey.replace(/^\/+/, '')}`
+    }
   }
 
   // 如果没有具体参数，直接返回原链接
@@ -46,7 +46,7 @@ export const getMediaUrl = (media: App.Api.Ech0.Media, scene?: 'thumb' | 'full')
 
   // 仅对图片类型应用图片处理
   if (scene && media.media_type === 'image') {
-    url = buildProcessedImageUrl(url, media.media_source, scene)
+    url = buildProcessedImageUrl(url, media.media_source, scene, media.object_key)
   }
 
   return url
@@ -67,7 +67,7 @@ export const getImageUrl = (image: App.Api.Ech0.Image, scene?: 'thumb' | 'full')
   }
 
   if (scene) {
-    url = buildProcessedImageUrl(url, image.media_source, scene)
+    url = buildProcessedImageUrl(url, image.media_source, scene, image.object_key)
   }
 
   return url
@@ -88,7 +88,7 @@ export const getMediaToAddUrl = (media: App.Api.Ech0.MediaToAdd, scene?: 'thumb'
   }
 
   if (scene && media.media_type === 'image') {
-    url = buildProcessedImageUrl(url, media.media_source, scene)
+    url = buildProcessedImageUrl(url, media.media_source, scene, media.object_key)
   }
 
   return url
@@ -109,7 +109,7 @@ export const getImageToAddUrl = (image: App.Api.Ech0.ImageToAdd, scene?: 'thumb'
   }
 
   if (scene) {
-    url = buildProcessedImageUrl(url, image.media_source, scene)
+    url = buildProcessedImageUrl(url, image.media_source, scene, image.object_key)
   }
 
   return url